@@ -15,8 +15,10 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
+	"strings"
 	"time"
 
 	svcstatus "github.com/release-argus/Argus/service/status"
@@ -104,6 +106,26 @@ func (c *Command) Exec(logFrom *util.LogFrom) error {
 	return err
 }
 
+// Output executes this Command and returns its stdout,
+// killing it if it hasn't finished within `timeout`.
+func (c *Command) Output(timeout time.Duration, logFrom *util.LogFrom) ([]byte, error) {
+	jLog.Verbose(fmt.Sprintf("Executing '%s'", c), logFrom, true)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, (*c)[0], (*c)[1:]...).Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("'%s' timed out after %s", c, timeout)
+	} else if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) != 0 {
+		err = fmt.Errorf("'%s' failed with %w - %s",
+			c, err, strings.TrimSpace(string(exitErr.Stderr)))
+	} else if err != nil {
+		err = fmt.Errorf("'%s' failed with %w", c, err)
+	}
+
+	return out, err
+}
+
 func (c *Command) ApplyTemplate(serviceStatus *svcstatus.Status) (command Command) {
 	if serviceStatus == nil {
 		return *c
@@ -111,7 +133,11 @@ func (c *Command) ApplyTemplate(serviceStatus *svcstatus.Status) (command Comman
 
 	command = Command(make([]string, len(*c)))
 	copy(command, *c)
-	serviceInfo := util.ServiceInfo{LatestVersion: serviceStatus.LatestVersion()}
+	serviceInfo := util.ServiceInfo{
+		LatestVersion: serviceStatus.LatestVersion(),
+		DockerDigest:  serviceStatus.DockerDigest(),
+		ReleaseNotes:  serviceStatus.ReleaseNotes(),
+		Changelog:     serviceStatus.Changelog()}
 	for i := range command {
 		command[i] = util.TemplateString(command[i], serviceInfo)
 	}
@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 
 	svcstatus "github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/test"
@@ -113,6 +114,60 @@ func TestCommand_Exec(t *testing.T) {
 	}
 }
 
+func TestCommand_Output(t *testing.T) {
+	// GIVEN different Command's to execute
+	tests := map[string]struct {
+		cmd      Command
+		timeout  time.Duration
+		want     string
+		errRegex string
+	}{
+		"stdout is returned": {
+			cmd:      Command{"echo", "v1.2.3"},
+			timeout:  time.Second,
+			want:     "v1.2.3\n",
+			errRegex: "^$",
+		},
+		"stderr is only in the error": {
+			cmd:      Command{"sh", "-c", "echo stdout; echo oops >&2; exit 2"},
+			timeout:  time.Second,
+			want:     "stdout\n",
+			errRegex: `^'sh -c .*' failed with exit status 2 - oops$`,
+		},
+		"unknown command": {
+			cmd:      Command{"argus-command-that-does-not-exist"},
+			timeout:  time.Second,
+			errRegex: `^'argus-command-that-does-not-exist' failed with .*executable file not found`,
+		},
+		"command that times out": {
+			cmd:      Command{"sleep", "5"},
+			timeout:  50 * time.Millisecond,
+			errRegex: `^'sleep 5' timed out after 50ms$`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN Output is called on it
+			got, err := tc.cmd.Output(tc.timeout, &util.LogFrom{})
+
+			// THEN the stdout is returned
+			if string(got) != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, string(got))
+			}
+			// AND the err is as expected
+			e := util.ErrorToString(err)
+			if !regexp.MustCompile(tc.errRegex).MatchString(e) {
+				t.Errorf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
 func TestController_ExecIndex(t *testing.T) {
 	// GIVEN a Controller with different Command's to execute
 	announce := make(chan []byte, 8)
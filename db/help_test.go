@@ -131,7 +131,11 @@ func queryRow(t *testing.T, db *sql.DB, serviceID string) *svcstatus.Status {
 		latest_version_timestamp,
 		deployed_version,
 		deployed_version_timestamp,
-		approved_version
+		approved_version,
+		pending_version,
+		pending_version_timestamp,
+		release_notes,
+		release_notes_version
 	FROM status
 	WHERE id = ?;`
 	// Retry up-to 10 times incase 'database is locked'
@@ -156,9 +160,13 @@ func queryRow(t *testing.T, db *sql.DB, serviceID string) *svcstatus.Status {
 		dv  string
 		dvt string
 		av  string
+		pv  string
+		pvt string
+		rn  string
+		rnv string
 	)
 	for row.Next() {
-		err = row.Scan(&id, &lv, &lvt, &dv, &dvt, &av)
+		err = row.Scan(&id, &lv, &lvt, &dv, &dvt, &av, &pv, &pvt, &rn, &rnv)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -173,6 +181,8 @@ func queryRow(t *testing.T, db *sql.DB, serviceID string) *svcstatus.Status {
 	status.SetDeployedVersion(dv, false)
 	status.SetDeployedVersionTimestamp(dvt)
 	status.SetApprovedVersion(av, false)
+	status.SetPendingVersion(pv, pvt, "", false)
+	status.SetReleaseNotes(rnv, rn, false)
 
 	return &status
 }
@@ -16,12 +16,14 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/release-argus/Argus/config"
+	svcstatus "github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/util"
 )
 
@@ -96,7 +98,12 @@ func (api *api) initialise() {
 			latest_version_timestamp   DATETIME DEFAULT  (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
 			deployed_version           TEXT     DEFAULT  '',
 			deployed_version_timestamp DATETIME DEFAULT  (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
-			approved_version           TEXT     DEFAULT  ''
+			approved_version           TEXT     DEFAULT  '',
+			pending_version            TEXT     DEFAULT  '',
+			pending_version_timestamp  TEXT     DEFAULT  '',
+			release_notes              TEXT     DEFAULT  '',
+			release_notes_version      TEXT     DEFAULT  '',
+			deployed_targets           TEXT     DEFAULT  ''
 		);`
 	_, err = db.Exec(sqlStmt)
 	jLog.Fatal(util.ErrorToString(err), logFrom, err != nil)
@@ -140,7 +147,12 @@ func (api *api) extractServiceStatus() {
 		latest_version_timestamp,
 		deployed_version,
 		deployed_version_timestamp,
-		approved_version
+		approved_version,
+		pending_version,
+		pending_version_timestamp,
+		release_notes,
+		release_notes_version,
+		deployed_targets
 	FROM status;`)
 	jLog.Fatal(err, logFrom, err != nil)
 	defer rows.Close()
@@ -155,8 +167,13 @@ func (api *api) extractServiceStatus() {
 			dv  string
 			dvt string
 			av  string
+			pv  string
+			pvt string
+			rn  string
+			rnv string
+			dt  string
 		)
-		err = rows.Scan(&id, &lv, &lvt, &dv, &dvt, &av)
+		err = rows.Scan(&id, &lv, &lvt, &dv, &dvt, &av, &pv, &pvt, &rn, &rnv, &dt)
 		jLog.Fatal(
 			fmt.Sprintf("extractServiceStatus row: %s", util.ErrorToString(err)),
 			logFrom,
@@ -166,6 +183,17 @@ func (api *api) extractServiceStatus() {
 		api.config.Service[id].Status.SetDeployedVersion(dv, false)
 		api.config.Service[id].Status.SetDeployedVersionTimestamp(dvt)
 		api.config.Service[id].Status.SetApprovedVersion(av, false)
+		api.config.Service[id].Status.SetPendingVersion(pv, pvt, "", false)
+		api.config.Service[id].Status.SetReleaseNotes(rnv, rn, false)
+		if dt != "" {
+			var deployedTargets []svcstatus.DeployedTarget
+			if err := json.Unmarshal([]byte(dt), &deployedTargets); err != nil {
+				jLog.Error(
+					fmt.Sprintf("extractServiceStatus deployed_targets of %q: %s", id, err),
+					logFrom, true)
+			}
+			api.config.Service[id].Status.SetDeployedTargets(deployedTargets)
+		}
 	}
 	err = rows.Err()
 	jLog.Fatal(
@@ -186,6 +214,35 @@ func updateTable(db *sql.DB) {
 		updateColumnTypes(db)
 		jLog.Verbose("Finished updating column types", logFrom, true)
 	}
+
+	addMissingColumns(db)
+}
+
+// addMissingColumns will add the columns that were added after the table was created
+func addMissingColumns(db *sql.DB) {
+	columns := []struct {
+		name       string
+		definition string
+	}{
+		{name: "pending_version", definition: "TEXT DEFAULT ''"},
+		{name: "pending_version_timestamp", definition: "TEXT DEFAULT ''"},
+		{name: "release_notes", definition: "TEXT DEFAULT ''"},
+		{name: "release_notes_version", definition: "TEXT DEFAULT ''"},
+		{name: "deployed_targets", definition: "TEXT DEFAULT ''"},
+	}
+
+	for _, column := range columns {
+		var count int
+		err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('status') WHERE name = ?", column.name).Scan(&count)
+		jLog.Fatal(fmt.Sprintf("addMissingColumns: %s", util.ErrorToString(err)), logFrom, err != nil)
+		if count != 0 {
+			continue
+		}
+
+		jLog.Verbose(fmt.Sprintf("Adding column %q", column.name), logFrom, true)
+		_, err = db.Exec(fmt.Sprintf("ALTER TABLE status ADD COLUMN %s %s;", column.name, column.definition))
+		jLog.Fatal(fmt.Sprintf("addMissingColumns - %s: %s", column.name, util.ErrorToString(err)), logFrom, err != nil)
+	}
 }
 
 // updateColumnTypes will recreate the table with the correct column types
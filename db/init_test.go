@@ -18,9 +18,11 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"os"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -315,6 +317,7 @@ func TestAPI_extractServiceStatus(t *testing.T) {
 	tAPI.initialise()
 	go tAPI.handler()
 	wantStatus := make([]svcstatus.Status, len(cfg.Service))
+	wantDeployedTargets := make(map[string][]svcstatus.DeployedTarget, len(cfg.Service))
 	// push a random Status for each Service to the DB
 	index := 0
 	for id, svc := range tAPI.config.Service {
@@ -325,6 +328,13 @@ func TestAPI_extractServiceStatus(t *testing.T) {
 		wantStatus[index].SetDeployedVersion(fmt.Sprintf("%d.%d.%d", rand.Intn(10), rand.Intn(10), rand.Intn(10)), false)
 		wantStatus[index].SetDeployedVersionTimestamp(time.Now().UTC().Format(time.RFC3339))
 		wantStatus[index].SetApprovedVersion(fmt.Sprintf("%d.%d.%d", rand.Intn(10), rand.Intn(10), rand.Intn(10)), false)
+		wantStatus[index].SetPendingVersion(fmt.Sprintf("%d.%d.%d", rand.Intn(10), rand.Intn(10), rand.Intn(10)),
+			time.Now().UTC().Format(time.RFC3339), "", false)
+		wantStatus[index].SetReleaseNotes(wantStatus[index].LatestVersion(), "notes for "+id, false)
+		wantDeployedTargets[id] = []svcstatus.DeployedTarget{
+			{Name: "eu", Version: wantStatus[index].DeployedVersion(), Timestamp: wantStatus[index].DeployedVersionTimestamp()},
+			{Name: "us", Version: wantStatus[index].LatestVersion(), Timestamp: wantStatus[index].LatestVersionTimestamp()}}
+		deployedTargets, _ := json.Marshal(wantDeployedTargets[id])
 
 		*tAPI.config.DatabaseChannel <- dbtype.Message{
 			ServiceID: id,
@@ -334,11 +344,17 @@ func TestAPI_extractServiceStatus(t *testing.T) {
 				{Column: "latest_version_timestamp", Value: wantStatus[index].LatestVersionTimestamp()},
 				{Column: "deployed_version", Value: wantStatus[index].DeployedVersion()},
 				{Column: "deployed_version_timestamp", Value: wantStatus[index].DeployedVersionTimestamp()},
-				{Column: "approved_version", Value: wantStatus[index].ApprovedVersion()}}}
+				{Column: "approved_version", Value: wantStatus[index].ApprovedVersion()},
+				{Column: "pending_version", Value: wantStatus[index].PendingVersion()},
+				{Column: "pending_version_timestamp", Value: wantStatus[index].PendingVersionTimestamp()},
+				{Column: "release_notes", Value: wantStatus[index].ReleaseNotes()},
+				{Column: "release_notes_version", Value: wantStatus[index].ReleaseNotesVersion()},
+				{Column: "deployed_targets", Value: string(deployedTargets)}}}
 		// Clear the Status in the Config
 		svc.Status = *svcstatus.New(
 			svc.Status.AnnounceChannel, svc.Status.DatabaseChannel, svc.Status.SaveChannel,
 			"", "", "", "", "", "")
+		svc.Status.InitDeployedTargets("all", []string{"eu", "us"})
 		index++
 	}
 	time.Sleep(250 * time.Millisecond)
@@ -370,6 +386,29 @@ func TestAPI_extractServiceStatus(t *testing.T) {
 			t.Errorf(errMsg,
 				"approved_version", row.ApprovedVersion(), row, wantStatus[i].String())
 		}
+		if row.PendingVersion() != wantStatus[i].PendingVersion() {
+			t.Errorf(errMsg,
+				"pending_version", row.PendingVersion(), row, wantStatus[i].String())
+		}
+		if row.PendingVersionTimestamp() != wantStatus[i].PendingVersionTimestamp() {
+			t.Errorf(errMsg,
+				"pending_version_timestamp", row.PendingVersionTimestamp(), row, wantStatus[i].String())
+		}
+		if row.ReleaseNotes() != wantStatus[i].ReleaseNotes() {
+			t.Errorf(errMsg,
+				"release_notes", row.ReleaseNotes(), row, wantStatus[i].String())
+		}
+		if row.ReleaseNotesVersion() != wantStatus[i].ReleaseNotesVersion() {
+			t.Errorf(errMsg,
+				"release_notes_version", row.ReleaseNotesVersion(), row, wantStatus[i].String())
+		}
+		// AND the deployed targets are restored to the Service
+		id := *wantStatus[i].ServiceID
+		gotDeployedTargets := tAPI.config.Service[id].Status.DeployedTargets()
+		if !reflect.DeepEqual(gotDeployedTargets, wantDeployedTargets[id]) {
+			t.Errorf("deployed_targets of %q\nwant: %v\ngot:  %v",
+				id, wantDeployedTargets[id], gotDeployedTargets)
+		}
 	}
 }
 
@@ -452,6 +491,18 @@ func Test_UpdateColumnTypes(t *testing.T) {
 					latest_version, latest_version_timestamp, deployed_version, deployed_version_timestamp, approved_version,
 					got.LatestVersion(), got.LatestVersionTimestamp(), got.DeployedVersion(), got.DeployedVersionTimestamp(), got.ApprovedVersion())
 			}
+			// AND the pending_version and release_notes columns were added
+			for _, column := range []string{
+				"pending_version", "pending_version_timestamp",
+				"release_notes", "release_notes_version",
+				"deployed_targets"} {
+				var count int
+				db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('status') WHERE name = ?", column).Scan(&count)
+				if count != 1 {
+					t.Errorf("Expected the %q column to have been added",
+						column)
+				}
+			}
 			// AND the conversion was printed to stdout
 			stdout := releaseStdout()
 			want := "Finished updating column types"
@@ -0,0 +1,35 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployedver
+
+import (
+	"bytes"
+
+	"github.com/release-argus/Argus/util"
+)
+
+// commandOutput runs the Command of the Lookup, returning its stdout.
+func (l *Lookup) commandOutput(logFrom *util.LogFrom) ([]byte, error) {
+	cmd := l.Command.ApplyTemplate(l.Status)
+	out, err := cmd.Output(l.GetTimeout(), logFrom)
+	if err != nil {
+		jLog.Warn(err, logFrom, true)
+		//nolint:wrapcheck
+		return nil, err
+	}
+
+	// Trim the trailing newline (and any surrounding whitespace) from the output.
+	return bytes.TrimSpace(out), nil
+}
@@ -0,0 +1,236 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployedver
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/release-argus/Argus/util"
+)
+
+var (
+	dockerHostSchemes       = []string{"unix", "tcp", "http", "https"}
+	containerVersionSources = []string{"image_tag", "label", "env"}
+	defaultDockerHost       = "unix:///var/run/docker.sock"
+	defaultDockerLabel      = "org.opencontainers.image.version"
+	dockerTimeout           = 10 * time.Second
+)
+
+// dockerContainer is the part of a Docker Engine API container inspect response that we use.
+type dockerContainer struct {
+	ID     string `json:"Id"`
+	Name   string `json:"Name"`
+	Config struct {
+		Image  string            `json:"Image"`
+		Labels map[string]string `json:"Labels"`
+		Env    []string          `json:"Env"`
+	} `json:"Config"`
+}
+
+// dockerContainerSummary is the part of a Docker Engine API container list response that we use.
+type dockerContainerSummary struct {
+	ID    string   `json:"Id"`
+	Names []string `json:"Names"`
+}
+
+// dockerAPI is a HTTP client for a Docker Engine API.
+type dockerAPI struct {
+	client  *http.Client
+	baseURL string // Base URL to send requests to.
+	key     string // Host and allow_invalid_certs that the client was created for.
+}
+
+// dockerClient returns the HTTP client for the Docker Engine API at `host`,
+// and the base URL to send requests to.
+//
+// The client is reused until the host or allow_invalid_certs changes.
+func (l *Lookup) dockerClient(host string) (*http.Client, string, error) {
+	allowInvalidCerts := l.GetAllowInvalidCerts()
+	key := fmt.Sprintf("%s|%t", host, allowInvalidCerts)
+
+	l.clientMutex.Lock()
+	defer l.clientMutex.Unlock()
+	if l.docker != nil && l.docker.key == key {
+		return l.docker.client, l.docker.baseURL, nil
+	}
+
+	client, baseURL, err := newDockerClient(host, allowInvalidCerts)
+	if err != nil {
+		return nil, "", err
+	}
+	if l.docker != nil {
+		l.docker.client.CloseIdleConnections()
+	}
+	l.docker = &dockerAPI{client: client, baseURL: baseURL, key: key}
+	return client, baseURL, nil
+}
+
+// newDockerClient returns a HTTP client for the Docker Engine API at `host`,
+// and the base URL to send requests to.
+func newDockerClient(host string, allowInvalidCerts bool) (*http.Client, string, error) {
+	parsedHost, err := url.Parse(host)
+	if err != nil {
+		//nolint:wrapcheck
+		return nil, "", err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	baseURL := "http://" + parsedHost.Host
+	switch parsedHost.Scheme {
+	case "unix":
+		socket := parsedHost.Path
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+		baseURL = "http://docker"
+	case "https":
+		baseURL = "https://" + parsedHost.Host
+		if allowInvalidCerts {
+			//#nosec G402 -- explicitly wanted InsecureSkipVerify
+			transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		}
+	case "tcp", "http":
+	default:
+		return nil, "", fmt.Errorf("unsupported Docker host scheme %q (only [%s] are allowed)",
+			parsedHost.Scheme, strings.Join(dockerHostSchemes, ", "))
+	}
+
+	return &http.Client{Transport: transport, Timeout: dockerTimeout}, baseURL, nil
+}
+
+// dockerGet sends a GET request for `path` to the Docker Engine API, decoding the JSON response into `target`.
+func dockerGet(client *http.Client, baseURL string, path string, target interface{}) error {
+	resp, err := client.Get(baseURL + path)
+	if err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		// Errors are returned as {"message": "..."}.
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("docker engine api: %s (%d)", apiErr.Message, resp.StatusCode)
+		}
+		return fmt.Errorf("docker engine api: non-200 response code: %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("docker engine api: failed to unmarshal %s: %w", path, err)
+	}
+	return nil
+}
+
+// dockerContainerID returns the ID of the container to inspect,
+// finding the first container with the ContainerLabel if there's no Container.
+func (l *Lookup) dockerContainerID(client *http.Client, baseURL string) (string, error) {
+	if container := l.GetContainer(); container != "" {
+		return container, nil
+	}
+
+	label := l.GetContainerLabel()
+	filters, _ := json.Marshal(map[string][]string{"label": {label}})
+	var containers []dockerContainerSummary
+	if err := dockerGet(client, baseURL, "/containers/json?filters="+url.QueryEscape(string(filters)), &containers); err != nil {
+		return "", err
+	}
+	if len(containers) == 0 {
+		return "", fmt.Errorf("no running container has the label %q", label)
+	}
+	return containers[0].ID, nil
+}
+
+// dockerVersion queries the Docker Engine API for the container of the Lookup,
+// returning the version from its image tag, a label, or an env var.
+func (l *Lookup) dockerVersion(logFrom *util.LogFrom) ([]byte, error) {
+	client, baseURL, err := l.dockerClient(l.GetHost())
+	if err != nil {
+		jLog.Error(err, logFrom, true)
+		return nil, err
+	}
+
+	id, err := l.dockerContainerID(client, baseURL)
+	if err != nil {
+		jLog.Warn(err, logFrom, true)
+		return nil, err
+	}
+	var container dockerContainer
+	if err := dockerGet(client, baseURL, "/containers/"+url.PathEscape(id)+"/json", &container); err != nil {
+		jLog.Warn(err, logFrom, true)
+		return nil, err
+	}
+
+	version, err := container.version(l.GetVersionSource(), l.GetVersionKey())
+	if err != nil {
+		jLog.Warn(err, logFrom, true)
+		return nil, err
+	}
+	return []byte(version), nil
+}
+
+// version of the container from its image tag, a label, or an env var.
+func (c *dockerContainer) version(source string, key string) (string, error) {
+	name := strings.TrimPrefix(c.Name, "/")
+	switch source {
+	case "label":
+		if version, ok := c.Config.Labels[key]; ok {
+			return version, nil
+		}
+		return "", fmt.Errorf("container %q doesn't have the label %q", name, key)
+	case "env":
+		for _, env := range c.Config.Env {
+			if envKey, version, _ := strings.Cut(env, "="); envKey == key {
+				return version, nil
+			}
+		}
+		return "", fmt.Errorf("container %q doesn't have the env var %q", name, key)
+	default:
+		tag := imageTag(c.Config.Image)
+		if tag == "" {
+			return "", fmt.Errorf("image %q of container %q doesn't have a tag", c.Config.Image, name)
+		}
+		return tag, nil
+	}
+}
+
+// imageTag returns the tag of the `image` reference, e.g. "1.2.3" for "ghcr.io/owner/repo:1.2.3@sha256:...".
+func imageTag(image string) string {
+	// Remove the digest.
+	image, _, _ = strings.Cut(image, "@")
+	// A ':' before the last '/' is a registry port.
+	lastColon := strings.LastIndex(image, ":")
+	if lastColon == -1 || lastColon < strings.LastIndex(image, "/") {
+		return ""
+	}
+	return image[lastColon+1:]
+}
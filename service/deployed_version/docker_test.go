@@ -0,0 +1,383 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package deployedver
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+// fakeDockerEngine serves a fake Docker Engine API with `containers` on a unix socket,
+// returning the host to use to reach it.
+func fakeDockerEngine(t *testing.T, containers []dockerContainer) string {
+	// Unix socket paths have a short length limit, so avoid the long t.TempDir.
+	dir, err := os.MkdirTemp("", "argus")
+	if err != nil {
+		t.Fatalf("failed to create a temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen on %q: %v", socket, err)
+	}
+
+	writeJSON := func(w http.ResponseWriter, status int, body interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(body)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /containers/json", func(w http.ResponseWriter, r *http.Request) {
+		var filters map[string][]string
+		json.Unmarshal([]byte(r.URL.Query().Get("filters")), &filters)
+		summaries := []dockerContainerSummary{}
+		for _, container := range containers {
+			matches := true
+			for _, label := range filters["label"] {
+				key, value, hasValue := strings.Cut(label, "=")
+				got, ok := container.Config.Labels[key]
+				if !ok || (hasValue && got != value) {
+					matches = false
+				}
+			}
+			if matches {
+				summaries = append(summaries, dockerContainerSummary{
+					ID: container.ID, Names: []string{container.Name}})
+			}
+		}
+		writeJSON(w, http.StatusOK, summaries)
+	})
+	mux.HandleFunc("GET /containers/{id}/json", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		for _, container := range containers {
+			if container.ID == id || container.Name == "/"+id {
+				writeJSON(w, http.StatusOK, container)
+				return
+			}
+		}
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			"message": "No such container: " + id})
+	})
+
+	server := httptest.NewUnstartedServer(mux)
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return "unix://" + socket
+}
+
+func testDockerContainer(id string, name string, image string, labels map[string]string, env []string) dockerContainer {
+	container := dockerContainer{ID: id, Name: "/" + name}
+	container.Config.Image = image
+	container.Config.Labels = labels
+	container.Config.Env = env
+	return container
+}
+
+func TestLookup_QueryDocker(t *testing.T) {
+	// GIVEN a fake Docker Engine API
+	host := fakeDockerEngine(t, []dockerContainer{
+		testDockerContainer("aaa111", "app", "ghcr.io/release-argus/argus:0.18.0",
+			map[string]string{
+				"org.opencontainers.image.version": "0.18.0",
+				"com.docker.compose.service":       "app"},
+			[]string{"PATH=/usr/bin", "APP_VERSION=v0.18.0"}),
+		testDockerContainer("bbb222", "db", "registry.local:5000/postgres:16.2@sha256:abc123",
+			map[string]string{
+				"com.docker.compose.service": "db",
+				"build":                      "1.2.3-4"},
+			nil),
+		testDockerContainer("ccc333", "untagged", "registry.local:5000/untagged",
+			nil, nil),
+	})
+	// AND a docker Lookup
+	tests := map[string]struct {
+		host           string
+		container      string
+		containerLabel string
+		versionSource  string
+		versionKey     string
+		regex          string
+		wantVersion    string
+		errRegex       string
+	}{
+		"image_tag of container by name": {
+			container:   "app",
+			wantVersion: "0.18.0",
+		},
+		"image_tag of container by ID": {
+			container:   "bbb222",
+			wantVersion: "16.2",
+		},
+		"image_tag of container by label": {
+			containerLabel: "com.docker.compose.service=db",
+			wantVersion:    "16.2",
+		},
+		"image_tag of container without a tag": {
+			container: "untagged",
+			errRegex:  `image "registry.local:5000/untagged" of container "untagged" doesn't have a tag$`,
+		},
+		"label with the default key": {
+			container:     "app",
+			versionSource: "label",
+			wantVersion:   "0.18.0",
+		},
+		"label with a key": {
+			container:     "db",
+			versionSource: "label",
+			versionKey:    "build",
+			regex:         `^([0-9.]+)-`,
+			wantVersion:   "1.2.3",
+		},
+		"label that's missing": {
+			container:     "db",
+			versionSource: "label",
+			errRegex:      `container "db" doesn't have the label "org.opencontainers.image.version"$`,
+		},
+		"env": {
+			containerLabel: "com.docker.compose.service",
+			versionSource:  "env",
+			versionKey:     "APP_VERSION",
+			regex:          `v(.+)`,
+			wantVersion:    "0.18.0",
+		},
+		"env that's missing": {
+			container:     "app",
+			versionSource: "env",
+			versionKey:    "VERSION",
+			errRegex:      `container "app" doesn't have the env var "VERSION"$`,
+		},
+		"unknown container": {
+			container: "unknown",
+			errRegex:  `No such container: unknown \(404\)$`,
+		},
+		"no container with the label": {
+			containerLabel: "com.docker.compose.service=unknown",
+			errRegex:       `no running container has the label "com.docker.compose.service=unknown"$`,
+		},
+		"unreachable host": {
+			host:      "unix:///does/not/exist.sock",
+			container: "app",
+			errRegex:  `connect: no such file or directory$`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dvl := testLookup()
+			dvl.Type = "docker"
+			dvl.Method = ""
+			dvl.URL = ""
+			dvl.JSON = ""
+			dvl.Host = util.FirstNonDefault(tc.host, host)
+			dvl.Container = tc.container
+			dvl.ContainerLabel = tc.containerLabel
+			dvl.VersionSource = tc.versionSource
+			dvl.VersionKey = tc.versionKey
+			dvl.Regex = tc.regex
+			if err := dvl.CheckValues(""); err != nil {
+				t.Fatalf("invalid Lookup: %v", err)
+			}
+
+			// WHEN Query is called on it
+			version, err := dvl.Query(false, &util.LogFrom{})
+
+			// THEN any err is expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			if !regexp.MustCompile(tc.errRegex).MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the version is retrieved from the container
+			if version != tc.wantVersion {
+				t.Errorf("want version=%q\ngot  version=%q",
+					tc.wantVersion, version)
+			}
+		})
+	}
+}
+
+func TestLookup_dockerClient(t *testing.T) {
+	// GIVEN a Lookup that already has a Docker Engine API client
+	tests := map[string]struct {
+		host              string
+		allowInvalidCerts bool
+		wantSameClient    bool
+		errRegex          string
+	}{
+		"same host": {
+			host:           "tcp://docker:2375",
+			wantSameClient: true,
+		},
+		"different host": {
+			host:           "tcp://other:2375",
+			wantSameClient: false,
+		},
+		"different allow_invalid_certs": {
+			host:              "tcp://docker:2375",
+			allowInvalidCerts: true,
+			wantSameClient:    false,
+		},
+		"invalid scheme": {
+			host:     "ftp://docker:2375",
+			errRegex: `^unsupported Docker host scheme`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dvl := testLookup()
+			dvl.AllowInvalidCerts = test.BoolPtr(false)
+			firstClient, _, err := dvl.dockerClient("tcp://docker:2375")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			dvl.AllowInvalidCerts = test.BoolPtr(tc.allowInvalidCerts)
+
+			// WHEN dockerClient is called again
+			client, baseURL, err := dvl.dockerClient(tc.host)
+
+			// THEN any err is expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			if !regexp.MustCompile(tc.errRegex).MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if err != nil {
+				// AND the cached client is kept
+				if dvl.docker.client != firstClient {
+					t.Errorf("the cached client was replaced by a failed dockerClient call")
+				}
+				return
+			}
+			// AND the client is only reused when nothing has changed
+			if got := client == firstClient; got != tc.wantSameClient {
+				t.Errorf("want same client=%t, got %t",
+					tc.wantSameClient, got)
+			}
+			// AND the base URL is for the host
+			wantBaseURL := "http://" + strings.TrimPrefix(tc.host, "tcp://")
+			if baseURL != wantBaseURL {
+				t.Errorf("want baseURL=%q, got %q",
+					wantBaseURL, baseURL)
+			}
+		})
+	}
+}
+
+func TestImageTag(t *testing.T) {
+	// GIVEN an image reference
+	tests := map[string]struct {
+		image string
+		want  string
+	}{
+		"name and tag": {
+			image: "nginx:1.25.4",
+			want:  "1.25.4"},
+		"registry, name and tag": {
+			image: "ghcr.io/release-argus/argus:0.18.0",
+			want:  "0.18.0"},
+		"registry with a port": {
+			image: "registry.local:5000/app:v1.2.3",
+			want:  "v1.2.3"},
+		"registry with a port and no tag": {
+			image: "registry.local:5000/app",
+			want:  ""},
+		"tag and digest": {
+			image: "nginx:1.25.4@sha256:0123456789abcdef",
+			want:  "1.25.4"},
+		"digest only": {
+			image: "nginx@sha256:0123456789abcdef",
+			want:  ""},
+		"no tag": {
+			image: "nginx",
+			want:  ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN imageTag is called on it
+			got := imageTag(tc.image)
+
+			// THEN the tag is returned
+			if got != tc.want {
+				t.Errorf("want %q, not %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestLookup_GetHost(t *testing.T) {
+	// GIVEN a Lookup with a Host and the DOCKER_HOST env var
+	tests := map[string]struct {
+		host       string
+		dockerHost string
+		want       string
+	}{
+		"host": {
+			host:       "tcp://docker:2375",
+			dockerHost: "tcp://other:2375",
+			want:       "tcp://docker:2375"},
+		"DOCKER_HOST": {
+			dockerHost: "tcp://other:2375",
+			want:       "tcp://other:2375"},
+		"default": {
+			want: "unix:///var/run/docker.sock"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// t.Setenv is incompatible with t.Parallel.
+			t.Setenv("DOCKER_HOST", tc.dockerHost)
+			dvl := &Lookup{Host: tc.host}
+
+			// WHEN GetHost is called
+			got := dvl.GetHost()
+
+			// THEN the expected host is returned
+			if got != tc.want {
+				t.Errorf("want %q, not %q",
+					tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployedver
+
+import (
+	"bytes"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/release-argus/Argus/util"
+)
+
+// readFile reads the file at the Path of the Lookup, returning its content.
+func (l *Lookup) readFile(logFrom *util.LogFrom) ([]byte, error) {
+	content, err := os.ReadFile(l.GetPath())
+	if err != nil {
+		jLog.Warn(err, logFrom, true)
+		//nolint:wrapcheck
+		return nil, err
+	}
+
+	// Trim the trailing newline (and any surrounding whitespace) from the file.
+	return bytes.TrimSpace(content), nil
+}
+
+// fileWatcher sends on Changes when the watched file may have changed.
+type fileWatcher struct {
+	Changes chan struct{} // Closed when the watcher stops.
+
+	stop     func() error // Stop watching.
+	stopOnce sync.Once
+}
+
+// newFileWatcher returns a new fileWatcher that calls `stop` when closed.
+func newFileWatcher(stop func() error) *fileWatcher {
+	return &fileWatcher{
+		Changes: make(chan struct{}, 1),
+		stop:    stop}
+}
+
+// notify of a change, without blocking if there's already one pending.
+func (w *fileWatcher) notify() {
+	select {
+	case w.Changes <- struct{}{}:
+	default:
+	}
+}
+
+// Close the watcher.
+func (w *fileWatcher) Close() (err error) {
+	w.stopOnce.Do(func() {
+		err = w.stop()
+	})
+	return
+}
+
+// waitForChange blocks until the watched file changes, or the Service is being deleted.
+//
+// Returns nil if the watcher has stopped.
+func (l *Lookup) waitForChange(changes <-chan struct{}) <-chan struct{} {
+	// Check for deletion every second.
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case _, ok := <-changes:
+			if !ok {
+				return nil
+			}
+			return changes
+		case <-ticker.C:
+			if l.Status.Deleting() {
+				return changes
+			}
+		}
+	}
+}
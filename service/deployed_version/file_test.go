@@ -0,0 +1,241 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package deployedver
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+	opt "github.com/release-argus/Argus/service/options"
+	svcstatus "github.com/release-argus/Argus/service/status"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+func testLookupFile(path string) *Lookup {
+	lookup := testLookup()
+	lookup.Type = "file"
+	lookup.Method = ""
+	lookup.URL = ""
+	lookup.JSON = ""
+	lookup.Path = path
+	return lookup
+}
+
+func TestLookup_QueryFile(t *testing.T) {
+	// GIVEN a file Lookup
+	tests := map[string]struct {
+		content       *string
+		json          string
+		regex         string
+		regexTemplate *string
+		wantVersion   string
+		errRegex      string
+	}{
+		"VERSION file": {
+			content:     test.StringPtr("1.2.3\n"),
+			wantVersion: "1.2.3",
+		},
+		"JSON manifest": {
+			content:     test.StringPtr(`{"app":{"version":"1.2.3"}}`),
+			json:        "app.version",
+			wantVersion: "1.2.3",
+		},
+		"regex": {
+			content:     test.StringPtr("name: myapp\nversion: v1.2.3\n"),
+			regex:       `version: v([0-9.]+)`,
+			wantVersion: "1.2.3",
+		},
+		"regex_template": {
+			content:       test.StringPtr("1_2_3"),
+			regex:         `([0-9]+)_([0-9]+)_([0-9]+)`,
+			regexTemplate: test.StringPtr("$1.$2.$3"),
+			wantVersion:   "1.2.3",
+		},
+		"not JSON": {
+			content:  test.StringPtr("1.2.3"),
+			json:     "version",
+			errRegex: `failed to unmarshal the following from ".+VERSION" into json`,
+		},
+		"missing file": {
+			errRegex: `open .+VERSION: no such file or directory$`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "VERSION")
+			if tc.content != nil {
+				if err := os.WriteFile(path, []byte(*tc.content), 0600); err != nil {
+					t.Fatalf("failed to write %q: %v", path, err)
+				}
+			}
+			dvl := testLookupFile(path)
+			dvl.JSON = tc.json
+			dvl.Regex = tc.regex
+			dvl.RegexTemplate = tc.regexTemplate
+
+			// WHEN Query is called on it
+			version, err := dvl.Query(false, &util.LogFrom{})
+
+			// THEN any err is expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			if !regexp.MustCompile(tc.errRegex).MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the version is extracted from the file
+			if version != tc.wantVersion {
+				t.Errorf("want version=%q\ngot  version=%q",
+					tc.wantVersion, version)
+			}
+		})
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	// GIVEN a file being watched
+	tests := map[string]struct {
+		change func(path string) error
+	}{
+		"written to": {
+			change: func(path string) error {
+				return os.WriteFile(path, []byte("1.2.4"), 0600)
+			},
+		},
+		"replaced with a rename": {
+			change: func(path string) error {
+				tmp := path + ".tmp"
+				if err := os.WriteFile(tmp, []byte("1.2.4"), 0600); err != nil {
+					return err
+				}
+				return os.Rename(tmp, path)
+			},
+		},
+		"removed": {
+			change: os.Remove,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "VERSION")
+			if err := os.WriteFile(path, []byte("1.2.3"), 0600); err != nil {
+				t.Fatalf("failed to write %q: %v", path, err)
+			}
+			watcher, err := watchFile(path)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			// Ensure the modification time differs when polling.
+			time.Sleep(10 * time.Millisecond)
+
+			// WHEN the file is changed
+			if err := tc.change(path); err != nil {
+				t.Fatalf("failed to change %q: %v", path, err)
+			}
+
+			// THEN the change is notified
+			select {
+			case <-watcher.Changes:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("no change was notified")
+			}
+			// AND Changes is closed when the watcher is closed
+			watcher.Close()
+			timeout := time.After(5 * time.Second)
+			for {
+				select {
+				case _, ok := <-watcher.Changes:
+					if ok {
+						continue
+					}
+				case <-timeout:
+					t.Fatalf("Changes wasn't closed")
+				}
+				break
+			}
+		})
+	}
+}
+
+func TestLookup_TrackWatchFile(t *testing.T) {
+	// GIVEN a file Lookup watching a file, with an interval longer than the test
+	path := filepath.Join(t.TempDir(), "VERSION")
+	if err := os.WriteFile(path, []byte("1.2.3\n"), 0600); err != nil {
+		t.Fatalf("failed to write %q: %v", path, err)
+	}
+	dvl := testLookupFile(path)
+	dvl.Watch = test.BoolPtr(true)
+	dvl.Options = opt.New(
+		nil, "1h", test.BoolPtr(true),
+		&opt.OptionsDefaults{}, &opt.OptionsDefaults{})
+	dbChannel := make(chan dbtype.Message, 4)
+	announceChannel := make(chan []byte, 4)
+	dvl.Status = svcstatus.New(
+		&announceChannel, &dbChannel, nil,
+		"", "", "", "", "", "")
+	dvl.Status.ServiceID = test.StringPtr("TestLookup_TrackWatchFile")
+	dvl.Status.WebURL = test.StringPtr("")
+	dvl.InitMetrics()
+	t.Cleanup(dvl.DeleteMetrics)
+	didFinish := make(chan bool, 1)
+	go func() {
+		dvl.Track()
+		didFinish <- true
+	}()
+	waitForDeployedVersion := func(want string) {
+		t.Helper()
+		for i := 0; i < 50; i++ {
+			if dvl.Status.DeployedVersion() == want {
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Fatalf("want DeployedVersion %q, not %q",
+			want, dvl.Status.DeployedVersion())
+	}
+	waitForDeployedVersion("1.2.3")
+
+	// WHEN the file is changed
+	time.Sleep(10 * time.Millisecond)
+	if err := os.WriteFile(path, []byte("1.2.4\n"), 0600); err != nil {
+		t.Fatalf("failed to write %q: %v", path, err)
+	}
+
+	// THEN the new version is picked up without waiting for the interval
+	waitForDeployedVersion("1.2.4")
+
+	// AND Track stops when the Service is deleted
+	dvl.Status.SetDeleting()
+	select {
+	case <-didFinish:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected Track to finish after the Service was deleted")
+	}
+}
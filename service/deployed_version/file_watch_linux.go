@@ -0,0 +1,63 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package deployedver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// inotifyMask of the events that may change the file,
+// e.g. written to, replaced with a rename (atomic write), or removed.
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM | syscall.IN_DELETE
+
+// watchFile watches the directory of `path` with inotify, notifying of any changes in it.
+//
+// The directory is watched rather than the file, as the file may be replaced
+// (e.g. a rename, or a Kubernetes ConfigMap symlink swap).
+func watchFile(path string) (*fileWatcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify_init1 failed: %w", err)
+	}
+	dir := filepath.Dir(path)
+	if _, err = syscall.InotifyAddWatch(fd, dir, inotifyMask); err != nil {
+		syscall.Close(fd)
+		return nil, fmt.Errorf("failed to watch %q: %w", dir, err)
+	}
+
+	// Non-blocking, so Close will interrupt the Read.
+	file := os.NewFile(uintptr(fd), "inotify")
+	watcher := newFileWatcher(file.Close)
+	go func() {
+		defer close(watcher.Changes)
+
+		buf := make([]byte, 4096)
+		for {
+			n, err := file.Read(buf)
+			if err != nil {
+				return
+			}
+			if n != 0 {
+				watcher.notify()
+			}
+		}
+	}()
+	return watcher, nil
+}
@@ -0,0 +1,73 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+
+package deployedver
+
+import (
+	"os"
+	"time"
+)
+
+// watchPollInterval is how often to check the file for changes.
+var watchPollInterval = time.Second
+
+// fileState is the state of a file to check for changes.
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// statFile returns the fileState of the file at `path`.
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{
+		exists:  true,
+		modTime: info.ModTime(),
+		size:    info.Size()}
+}
+
+// watchFile watches `path` by checking its modification time and size every watchPollInterval,
+// as inotify isn't available.
+func watchFile(path string) (*fileWatcher, error) {
+	stop := make(chan struct{})
+	watcher := newFileWatcher(func() error {
+		close(stop)
+		return nil
+	})
+	go func() {
+		defer close(watcher.Changes)
+
+		last := statFile(path)
+		ticker := time.NewTicker(watchPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if state := statFile(path); state != last {
+					last = state
+					watcher.notify()
+				}
+			}
+		}
+	}()
+	return watcher, nil
+}
@@ -16,7 +16,9 @@ package deployedver
 
 import (
 	"io"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/release-argus/Argus/util"
 )
@@ -29,6 +31,78 @@ func (l *Lookup) GetAllowInvalidCerts() bool {
 		l.HardDefaults.AllowInvalidCerts)
 }
 
+// GetType returns the type of the Lookup (default: url).
+func (l *Lookup) GetType() string {
+	return util.FirstNonDefault(l.Type, "url")
+}
+
+// GetTimeout returns how long to wait for the Command of the Lookup to finish.
+func (l *Lookup) GetTimeout() time.Duration {
+	timeout, _ := time.ParseDuration(util.FirstNonDefault(l.Timeout, defaultCommandTimeout))
+	return timeout
+}
+
+// GetPath returns the Path of the file to read.
+func (l *Lookup) GetPath() string {
+	return util.EvalEnvVars(l.Path)
+}
+
+// GetWatch returns whether the file should be watched for changes.
+func (l *Lookup) GetWatch() bool {
+	return util.DefaultIfNil(l.Watch)
+}
+
+// GetHost returns the Docker Engine API to query.
+func (l *Lookup) GetHost() string {
+	return util.FirstNonDefault(
+		util.EvalEnvVars(l.Host),
+		os.Getenv("DOCKER_HOST"),
+		defaultDockerHost)
+}
+
+// GetKubeconfig returns the path of the kubeconfig to use.
+func (l *Lookup) GetKubeconfig() string {
+	return util.EvalEnvVars(l.Kubeconfig)
+}
+
+// GetKind returns the kind of the workload (default: deployment).
+func (l *Lookup) GetKind() string {
+	return util.FirstNonDefault(l.Kind, "deployment")
+}
+
+// GetNamespace returns the namespace of the workload.
+func (l *Lookup) GetNamespace() string {
+	return util.EvalEnvVars(l.Namespace)
+}
+
+// GetWorkload returns the name of the workload.
+func (l *Lookup) GetWorkload() string {
+	return util.EvalEnvVars(l.Workload)
+}
+
+// GetContainer returns the name (or ID) of the container.
+func (l *Lookup) GetContainer() string {
+	return util.EvalEnvVars(l.Container)
+}
+
+// GetContainerLabel returns the label to find the container with.
+func (l *Lookup) GetContainerLabel() string {
+	return util.EvalEnvVars(l.ContainerLabel)
+}
+
+// GetVersionSource returns where on the container to get the version from (default: image_tag).
+func (l *Lookup) GetVersionSource() string {
+	return util.FirstNonDefault(l.VersionSource, "image_tag")
+}
+
+// GetVersionKey returns the label/env var holding the version.
+func (l *Lookup) GetVersionKey() string {
+	if l.VersionKey == "" && l.GetVersionSource() == "label" {
+		return defaultDockerLabel
+	}
+	return l.VersionKey
+}
+
 // GetURL will return the URL of the Lookup.
 func (l *Lookup) GetURL() string {
 	return util.EvalEnvVars(l.URL)
@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/release-argus/Argus/test"
 )
@@ -69,6 +70,70 @@ func TestLookup_GetAllowInvalidCerts(t *testing.T) {
 	}
 }
 
+func TestLookup_GetType(t *testing.T) {
+	// GIVEN a Lookup
+	tests := map[string]struct {
+		lookupType string
+		want       string
+	}{
+		"defaults to url": {
+			want: "url"},
+		"returns Type": {
+			lookupType: "command",
+			want:       "command"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookup()
+			lookup.Type = tc.lookupType
+
+			// WHEN GetType is called
+			got := lookup.GetType()
+
+			// THEN the function returns the correct result
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestLookup_GetTimeout(t *testing.T) {
+	// GIVEN a Lookup
+	tests := map[string]struct {
+		timeout string
+		want    time.Duration
+	}{
+		"defaults to 10s": {
+			want: 10 * time.Second},
+		"returns Timeout": {
+			timeout: "1m30s",
+			want:    90 * time.Second},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookup()
+			lookup.Timeout = tc.timeout
+
+			// WHEN GetTimeout is called
+			got := lookup.GetTimeout()
+
+			// THEN the function returns the correct result
+			if got != tc.want {
+				t.Errorf("want: %s\ngot:  %s",
+					tc.want, got)
+			}
+		})
+	}
+}
+
 func TestLookup_GetURL(t *testing.T) {
 	// GIVEN a Lookup
 	tests := map[string]struct {
@@ -20,6 +20,7 @@ import (
 	"os"
 	"testing"
 
+	command "github.com/release-argus/Argus/commands"
 	opt "github.com/release-argus/Argus/service/options"
 	svcstatus "github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/test"
@@ -30,6 +31,7 @@ func TestMain(m *testing.M) {
 	// initialize jLog
 	jLog = util.NewJLog("DEBUG", false)
 	jLog.Testing = true
+	command.LogInit(jLog)
 
 	// run other tests
 	exitCode := m.Run()
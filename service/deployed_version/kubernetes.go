@@ -0,0 +1,457 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployedver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/release-argus/Argus/util"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	kubernetesKinds = map[string]string{
+		"deployment":  "deployments",
+		"statefulset": "statefulsets",
+		"daemonset":   "daemonsets"}
+	kubernetesServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	kubernetesTimeout           = 10 * time.Second
+)
+
+// kubeconfig is the part of a kubeconfig file that we use.
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster   string `yaml:"cluster"`
+			User      string `yaml:"user"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string    `yaml:"token"`
+			TokenFile             string    `yaml:"tokenFile"`
+			ClientCertificate     string    `yaml:"client-certificate"`
+			ClientCertificateData string    `yaml:"client-certificate-data"`
+			ClientKey             string    `yaml:"client-key"`
+			ClientKeyData         string    `yaml:"client-key-data"`
+			Username              string    `yaml:"username"`
+			Password              string    `yaml:"password"`
+			Exec                  yaml.Node `yaml:"exec"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+// kubernetesClient for the Kubernetes API.
+type kubernetesClient struct {
+	client    *http.Client
+	server    string // e.g. https://kubernetes.default.svc:443
+	namespace string // Namespace to default to.
+	token     string // Bearer token.
+	tokenFile string // File holding the bearer token (re-read as it may be rotated).
+	username  string // Basic auth username.
+	password  string // Basic auth password.
+	key       string // Kubeconfig and allow_invalid_certs that the client was created for.
+}
+
+// kubernetesWorkload is the part of a Deployment/StatefulSet/DaemonSet that we use.
+type kubernetesWorkload struct {
+	Spec struct {
+		Template struct {
+			Metadata struct {
+				Labels map[string]string `json:"labels"`
+			} `json:"metadata"`
+			Spec struct {
+				Containers []kubernetesContainer `json:"containers"`
+			} `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+}
+
+// kubernetesContainer in the Pod template of a workload.
+type kubernetesContainer struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+	Env   []struct {
+		Name      string          `json:"name"`
+		Value     string          `json:"value"`
+		ValueFrom json.RawMessage `json:"valueFrom"`
+	} `json:"env"`
+}
+
+// kubernetesClient returns a client for the Kubernetes API, using the Kubeconfig if set,
+// otherwise the in-cluster service account, otherwise the default kubeconfig.
+//
+// The client is reused until the kubeconfig or allow_invalid_certs changes.
+func (l *Lookup) kubernetesClient() (*kubernetesClient, error) {
+	allowInvalidCerts := l.GetAllowInvalidCerts()
+	inCluster := l.Kubeconfig == "" && os.Getenv("KUBERNETES_SERVICE_HOST") != ""
+	var path, key string
+	if inCluster {
+		key = fmt.Sprintf("in-cluster|%s|%s|%t",
+			os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT"), allowInvalidCerts)
+	} else {
+		var err error
+		if path, err = l.kubeconfigPath(); err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			//nolint:wrapcheck
+			return nil, err
+		}
+		key = fmt.Sprintf("%s|%s|%d|%t",
+			path, info.ModTime(), info.Size(), allowInvalidCerts)
+	}
+
+	l.clientMutex.Lock()
+	defer l.clientMutex.Unlock()
+	if l.kubernetes != nil && l.kubernetes.key == key {
+		return l.kubernetes, nil
+	}
+
+	var client *kubernetesClient
+	var err error
+	if inCluster {
+		client, err = l.kubernetesInClusterClient()
+	} else {
+		client, err = l.kubernetesKubeconfigClient(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if l.kubernetes != nil {
+		l.kubernetes.client.CloseIdleConnections()
+	}
+	client.key = key
+	l.kubernetes = client
+	return client, nil
+}
+
+// kubeconfigPath returns the path of the kubeconfig to use, which is the Kubeconfig if set,
+// otherwise the first path in the KUBECONFIG env var, otherwise ~/.kube/config.
+func (l *Lookup) kubeconfigPath() (string, error) {
+	if path := l.GetKubeconfig(); path != "" {
+		return path, nil
+	}
+	if paths := filepath.SplitList(os.Getenv("KUBECONFIG")); len(paths) != 0 {
+		return paths[0], nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("no kubeconfig to use: %w", err)
+	}
+	return filepath.Join(home, ".kube", "config"), nil
+}
+
+// kubernetesInClusterClient returns a client using the service account of the Pod Argus is running in.
+func (l *Lookup) kubernetesInClusterClient() (*kubernetesClient, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	caData, err := os.ReadFile(filepath.Join(kubernetesServiceAccountDir, "ca.crt"))
+	if err != nil {
+		//nolint:wrapcheck
+		return nil, err
+	}
+	if tlsConfig.RootCAs, err = certPool(caData); err != nil {
+		return nil, err
+	}
+	if l.GetAllowInvalidCerts() {
+		//#nosec G402 -- explicitly wanted InsecureSkipVerify
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	server := net.JoinHostPort(
+		os.Getenv("KUBERNETES_SERVICE_HOST"),
+		util.FirstNonDefault(os.Getenv("KUBERNETES_SERVICE_PORT"), "443"))
+	namespace, _ := os.ReadFile(filepath.Join(kubernetesServiceAccountDir, "namespace"))
+	return &kubernetesClient{
+		client:    newKubernetesHTTPClient(tlsConfig),
+		server:    "https://" + server,
+		namespace: strings.TrimSpace(string(namespace)),
+		tokenFile: filepath.Join(kubernetesServiceAccountDir, "token")}, nil
+}
+
+// kubernetesKubeconfigClient returns a client using the current-context of the kubeconfig at `path`.
+func (l *Lookup) kubernetesKubeconfigClient(path string) (*kubernetesClient, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		//nolint:wrapcheck
+		return nil, err
+	}
+	var config kubeconfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig %q: %w", path, err)
+	}
+	// Relative paths in the kubeconfig are relative to the kubeconfig.
+	dir := filepath.Dir(path)
+	resolve := func(file string) string {
+		if file == "" || filepath.IsAbs(file) {
+			return file
+		}
+		return filepath.Join(dir, file)
+	}
+
+	// Context
+	contextIndex := -1
+	for i := range config.Contexts {
+		if config.Contexts[i].Name == config.CurrentContext {
+			contextIndex = i
+			break
+		}
+	}
+	if contextIndex == -1 {
+		return nil, fmt.Errorf("current-context %q not found in kubeconfig %q", config.CurrentContext, path)
+	}
+	kubeContext := config.Contexts[contextIndex].Context
+	client := &kubernetesClient{namespace: kubeContext.Namespace}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	// Cluster
+	clusterFound := false
+	for _, cluster := range config.Clusters {
+		if cluster.Name != kubeContext.Cluster {
+			continue
+		}
+		clusterFound = true
+		client.server = strings.TrimSuffix(cluster.Cluster.Server, "/")
+		caData, err := fileOrData(resolve(cluster.Cluster.CertificateAuthority), cluster.Cluster.CertificateAuthorityData)
+		if err != nil {
+			return nil, err
+		}
+		if len(caData) != 0 {
+			if tlsConfig.RootCAs, err = certPool(caData); err != nil {
+				return nil, err
+			}
+		}
+		if cluster.Cluster.InsecureSkipTLSVerify || l.GetAllowInvalidCerts() {
+			//#nosec G402 -- explicitly wanted InsecureSkipVerify
+			tlsConfig.InsecureSkipVerify = true
+		}
+		break
+	}
+	if !clusterFound {
+		return nil, fmt.Errorf("cluster %q not found in kubeconfig %q", kubeContext.Cluster, path)
+	}
+
+	// User
+	for _, user := range config.Users {
+		if user.Name != kubeContext.User {
+			continue
+		}
+		if !user.User.Exec.IsZero() {
+			return nil, fmt.Errorf("user %q in kubeconfig %q uses an exec plugin, which isn't supported", user.Name, path)
+		}
+		client.token = user.User.Token
+		client.tokenFile = resolve(user.User.TokenFile)
+		client.username = user.User.Username
+		client.password = user.User.Password
+		certData, err := fileOrData(resolve(user.User.ClientCertificate), user.User.ClientCertificateData)
+		if err != nil {
+			return nil, err
+		}
+		keyData, err := fileOrData(resolve(user.User.ClientKey), user.User.ClientKeyData)
+		if err != nil {
+			return nil, err
+		}
+		if len(certData) != 0 {
+			cert, err := tls.X509KeyPair(certData, keyData)
+			if err != nil {
+				return nil, fmt.Errorf("invalid client certificate for user %q in kubeconfig %q: %w", user.Name, path, err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		break
+	}
+
+	client.client = newKubernetesHTTPClient(tlsConfig)
+	return client, nil
+}
+
+// newKubernetesHTTPClient returns a HTTP client using `tlsConfig`.
+func newKubernetesHTTPClient(tlsConfig *tls.Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport, Timeout: kubernetesTimeout}
+}
+
+// fileOrData returns the content of `file` if set, otherwise the base64 decoded `data`.
+func fileOrData(file string, data string) ([]byte, error) {
+	if file != "" {
+		//nolint:wrapcheck
+		return os.ReadFile(file)
+	}
+	if data == "" {
+		return nil, nil
+	}
+	//nolint:wrapcheck
+	return base64.StdEncoding.DecodeString(data)
+}
+
+// certPool returns a pool containing the PEM encoded certificates in `pem`.
+func certPool(pem []byte) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no valid certificates found in the certificate-authority")
+	}
+	return pool, nil
+}
+
+// get the resource at `path` from the Kubernetes API, decoding the JSON response into `target`.
+func (c *kubernetesClient) get(path string, target interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, c.server+path, nil)
+	if err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	token := c.token
+	if c.tokenFile != "" {
+		data, err := os.ReadFile(c.tokenFile)
+		if err != nil {
+			//nolint:wrapcheck
+			return err
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		//nolint:wrapcheck
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		// Errors are returned as a Status, e.g. {"kind": "Status", "message": "..."}.
+		var status struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &status) == nil && status.Message != "" {
+			return fmt.Errorf("kubernetes api: %s (%d)", status.Message, resp.StatusCode)
+		}
+		return fmt.Errorf("kubernetes api: non-200 response code: %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("kubernetes api: failed to unmarshal %s: %w", path, err)
+	}
+	return nil
+}
+
+// kubernetesVersion queries the Kubernetes API for the workload of the Lookup,
+// returning the version from the image tag of its container, a label on its Pods, or an env var.
+func (l *Lookup) kubernetesVersion(logFrom *util.LogFrom) ([]byte, error) {
+	client, err := l.kubernetesClient()
+	if err != nil {
+		jLog.Error(err, logFrom, true)
+		return nil, err
+	}
+
+	namespace := util.FirstNonDefault(l.GetNamespace(), client.namespace, "default")
+	path := fmt.Sprintf("/apis/apps/v1/namespaces/%s/%s/%s",
+		url.PathEscape(namespace), kubernetesKinds[l.GetKind()], url.PathEscape(l.GetWorkload()))
+	var workload kubernetesWorkload
+	if err := client.get(path, &workload); err != nil {
+		jLog.Warn(err, logFrom, true)
+		return nil, err
+	}
+
+	version, err := workload.version(l.GetContainer(), l.GetVersionSource(), l.GetVersionKey())
+	if err != nil {
+		err = fmt.Errorf("%s %s/%s: %w", l.GetKind(), namespace, l.GetWorkload(), err)
+		jLog.Warn(err, logFrom, true)
+		return nil, err
+	}
+	return []byte(version), nil
+}
+
+// version of the workload from the image tag of `containerName` (default: first container),
+// a label on its Pods, or an env var of `containerName`.
+func (w *kubernetesWorkload) version(containerName string, source string, key string) (string, error) {
+	if source == "label" {
+		if version, ok := w.Spec.Template.Metadata.Labels[key]; ok {
+			return version, nil
+		}
+		return "", fmt.Errorf("pods don't have the label %q", key)
+	}
+
+	// Container
+	containers := w.Spec.Template.Spec.Containers
+	var container *kubernetesContainer
+	for i := range containers {
+		if containerName == "" || containers[i].Name == containerName {
+			container = &containers[i]
+			break
+		}
+	}
+	if container == nil {
+		return "", fmt.Errorf("no container named %q", containerName)
+	}
+
+	if source == "env" {
+		for _, env := range container.Env {
+			if env.Name != key {
+				continue
+			}
+			if len(env.ValueFrom) != 0 {
+				return "", fmt.Errorf("env var %q of container %q is set with valueFrom, which isn't supported",
+					key, container.Name)
+			}
+			return env.Value, nil
+		}
+		return "", fmt.Errorf("container %q doesn't have the env var %q", container.Name, key)
+	}
+
+	tag := imageTag(container.Image)
+	if tag == "" {
+		return "", fmt.Errorf("image %q of container %q doesn't have a tag", container.Image, container.Name)
+	}
+	return tag, nil
+}
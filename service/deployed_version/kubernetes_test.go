@@ -0,0 +1,492 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package deployedver
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+// fakeKubernetesAPI serves a fake Kubernetes API with `workloads` (keyed by namespace/resource/name),
+// requiring the bearer `token`.
+func fakeKubernetesAPI(t *testing.T, tlsServer bool, token string, workloads map[string]interface{}) *httptest.Server {
+	writeJSON := func(w http.ResponseWriter, status int, body interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(body)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /apis/apps/v1/namespaces/{namespace}/{resource}/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			writeJSON(w, http.StatusUnauthorized, map[string]interface{}{
+				"kind": "Status", "message": "Unauthorized", "code": http.StatusUnauthorized})
+			return
+		}
+		key := r.PathValue("namespace") + "/" + r.PathValue("resource") + "/" + r.PathValue("name")
+		if workload, ok := workloads[key]; ok {
+			writeJSON(w, http.StatusOK, workload)
+			return
+		}
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{
+			"kind":    "Status",
+			"message": fmt.Sprintf("%s.apps %q not found", r.PathValue("resource"), r.PathValue("name")),
+			"code":    http.StatusNotFound})
+	})
+
+	var server *httptest.Server
+	if tlsServer {
+		server = httptest.NewTLSServer(mux)
+	} else {
+		server = httptest.NewServer(mux)
+	}
+	t.Cleanup(server.Close)
+	return server
+}
+
+// testKubernetesWorkload returns a workload with the Pod `labels` and `containers`.
+func testKubernetesWorkload(labels map[string]string, containers ...map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": labels},
+				"spec": map[string]interface{}{
+					"containers": containers}}}}
+}
+
+// writeKubeconfig writes a kubeconfig for `server` to a temp dir, returning its path.
+func writeKubeconfig(t *testing.T, server string, namespace string, user string) string {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	content := fmt.Sprintf(`
+apiVersion: v1
+kind: Config
+current-context: test
+clusters:
+- name: test
+  cluster:
+    server: %s
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+    namespace: %s
+users:
+- name: test
+  user:
+%s
+`,
+		server, namespace, user)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write %q: %v", path, err)
+	}
+	return path
+}
+
+func TestLookup_QueryKubernetes(t *testing.T) {
+	// GIVEN a fake Kubernetes API
+	server := fakeKubernetesAPI(t, false, "secret-token", map[string]interface{}{
+		"apps/deployments/argus": testKubernetesWorkload(
+			map[string]string{
+				"app.kubernetes.io/name":    "argus",
+				"app.kubernetes.io/version": "0.18.0"},
+			map[string]interface{}{
+				"name":  "argus",
+				"image": "ghcr.io/release-argus/argus:0.18.0",
+				"env": []map[string]interface{}{
+					{"name": "APP_VERSION", "value": "v0.18.0"},
+					{"name": "SECRET_VERSION", "valueFrom": map[string]interface{}{
+						"secretKeyRef": map[string]string{"name": "argus", "key": "version"}}}}},
+			map[string]interface{}{
+				"name":  "sidecar",
+				"image": "registry.local:5000/sidecar:1.2.3@sha256:abc123"}),
+		"apps/statefulsets/postgres": testKubernetesWorkload(
+			nil,
+			map[string]interface{}{
+				"name":  "postgres",
+				"image": "postgres:16.2"}),
+		"other/deployments/argus": testKubernetesWorkload(
+			nil,
+			map[string]interface{}{
+				"name":  "argus",
+				"image": "ghcr.io/release-argus/argus:0.17.0"}),
+	})
+	kubeconfig := writeKubeconfig(t, server.URL, "apps", "    token: secret-token")
+	// AND a kubernetes Lookup
+	tests := map[string]struct {
+		kubeconfig    string
+		kind          string
+		namespace     string
+		workload      string
+		container     string
+		versionSource string
+		versionKey    string
+		regex         string
+		wantVersion   string
+		errRegex      string
+	}{
+		"image_tag of the first container": {
+			workload:    "argus",
+			wantVersion: "0.18.0",
+		},
+		"image_tag of a named container": {
+			workload:    "argus",
+			container:   "sidecar",
+			wantVersion: "1.2.3",
+		},
+		"image_tag of a statefulset": {
+			kind:        "statefulset",
+			workload:    "postgres",
+			regex:       `^([0-9]+)\.`,
+			wantVersion: "16",
+		},
+		"image_tag in another namespace": {
+			namespace:   "other",
+			workload:    "argus",
+			wantVersion: "0.17.0",
+		},
+		"label with the default key": {
+			workload:      "argus",
+			versionSource: "label",
+			versionKey:    "app.kubernetes.io/version",
+			wantVersion:   "0.18.0",
+		},
+		"label that's missing": {
+			workload:      "argus",
+			versionSource: "label",
+			errRegex:      `deployment apps/argus: pods don't have the label "org.opencontainers.image.version"$`,
+		},
+		"env": {
+			workload:      "argus",
+			versionSource: "env",
+			versionKey:    "APP_VERSION",
+			regex:         `v(.+)`,
+			wantVersion:   "0.18.0",
+		},
+		"env from valueFrom": {
+			workload:      "argus",
+			versionSource: "env",
+			versionKey:    "SECRET_VERSION",
+			errRegex:      `env var "SECRET_VERSION" of container "argus" is set with valueFrom, which isn't supported$`,
+		},
+		"unknown container": {
+			workload:  "argus",
+			container: "unknown",
+			errRegex:  `deployment apps/argus: no container named "unknown"$`,
+		},
+		"unknown workload": {
+			workload: "unknown",
+			errRegex: `kubernetes api: deployments.apps "unknown" not found \(404\)$`,
+		},
+		"wrong token": {
+			kubeconfig: writeKubeconfig(t, server.URL, "apps", "    token: wrong-token"),
+			workload:   "argus",
+			errRegex:   `kubernetes api: Unauthorized \(401\)$`,
+		},
+		"missing kubeconfig": {
+			kubeconfig: filepath.Join(t.TempDir(), "missing"),
+			workload:   "argus",
+			errRegex:   `no such file or directory$`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dvl := testLookup()
+			dvl.Type = "kubernetes"
+			dvl.Method = ""
+			dvl.URL = ""
+			dvl.JSON = ""
+			dvl.Kubeconfig = util.FirstNonDefault(tc.kubeconfig, kubeconfig)
+			dvl.Kind = tc.kind
+			dvl.Namespace = tc.namespace
+			dvl.Workload = tc.workload
+			dvl.Container = tc.container
+			dvl.VersionSource = tc.versionSource
+			dvl.VersionKey = tc.versionKey
+			dvl.Regex = tc.regex
+			if err := dvl.CheckValues(""); err != nil {
+				t.Fatalf("invalid Lookup: %v", err)
+			}
+
+			// WHEN Query is called on it
+			version, err := dvl.Query(false, &util.LogFrom{})
+
+			// THEN any err is expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			if !regexp.MustCompile(tc.errRegex).MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the version is retrieved from the workload
+			if version != tc.wantVersion {
+				t.Errorf("want version=%q\ngot  version=%q",
+					tc.wantVersion, version)
+			}
+		})
+	}
+}
+
+func TestLookup_QueryKubernetesInCluster(t *testing.T) {
+	// GIVEN a fake Kubernetes API served over TLS
+	server := fakeKubernetesAPI(t, true, "service-account-token", map[string]interface{}{
+		"argus/deployments/argus": testKubernetesWorkload(
+			nil,
+			map[string]interface{}{
+				"name":  "argus",
+				"image": "ghcr.io/release-argus/argus:0.18.0"}),
+	})
+	// AND Argus is running in-cluster with a service account
+	serviceAccountDir := t.TempDir()
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	for file, content := range map[string][]byte{
+		"ca.crt":    caCert,
+		"token":     []byte("service-account-token\n"),
+		"namespace": []byte("argus")} {
+		if err := os.WriteFile(filepath.Join(serviceAccountDir, file), content, 0600); err != nil {
+			t.Fatalf("failed to write %q: %v", file, err)
+		}
+	}
+	originalServiceAccountDir := kubernetesServiceAccountDir
+	kubernetesServiceAccountDir = serviceAccountDir
+	t.Cleanup(func() { kubernetesServiceAccountDir = originalServiceAccountDir })
+	serverURL, _ := url.Parse(server.URL)
+	t.Setenv("KUBERNETES_SERVICE_HOST", serverURL.Hostname())
+	t.Setenv("KUBERNETES_SERVICE_PORT", serverURL.Port())
+	// AND a kubernetes Lookup without a kubeconfig or namespace
+	dvl := testLookup()
+	dvl.Type = "kubernetes"
+	dvl.Method = ""
+	dvl.URL = ""
+	dvl.JSON = ""
+	dvl.Workload = "argus"
+
+	// WHEN Query is called on it
+	version, err := dvl.Query(false, &util.LogFrom{})
+
+	// THEN the version is retrieved from the workload in the namespace of the service account
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	want := "0.18.0"
+	if version != want {
+		t.Errorf("want version=%q\ngot  version=%q",
+			want, version)
+	}
+}
+
+func TestLookup_KubernetesKubeconfigClient(t *testing.T) {
+	// GIVEN a kubeconfig
+	tests := map[string]struct {
+		content       string
+		tokenFile     bool
+		wantServer    string
+		wantNamespace string
+		wantToken     string
+		wantUsername  string
+		errRegex      string
+	}{
+		"token": {
+			content:       "    token: abc",
+			wantServer:    "https://cluster.example.com",
+			wantNamespace: "apps",
+			wantToken:     "abc",
+		},
+		"tokenFile relative to the kubeconfig": {
+			content:       "    tokenFile: token",
+			tokenFile:     true,
+			wantServer:    "https://cluster.example.com",
+			wantNamespace: "apps",
+		},
+		"basic auth": {
+			content:       "    username: admin\n    password: hunter2",
+			wantServer:    "https://cluster.example.com",
+			wantNamespace: "apps",
+			wantUsername:  "admin",
+		},
+		"exec plugin": {
+			content:  "    exec:\n      command: aws",
+			errRegex: `user "test" in kubeconfig ".+" uses an exec plugin, which isn't supported$`,
+		},
+		"invalid client certificate": {
+			content:  "    client-certificate-data: bm90IGEgY2VydA==\n    client-key-data: bm90IGEga2V5",
+			errRegex: `invalid client certificate for user "test" in kubeconfig ".+": tls: `,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := writeKubeconfig(t, "https://cluster.example.com/", "apps", tc.content)
+			dvl := testLookup()
+
+			// WHEN kubernetesKubeconfigClient is called on it
+			client, err := dvl.kubernetesKubeconfigClient(path)
+
+			// THEN any err is expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			if !regexp.MustCompile(tc.errRegex).MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if err != nil {
+				return
+			}
+			// AND the client is configured from the current-context
+			if client.server != tc.wantServer {
+				t.Errorf("want server=%q, not %q",
+					tc.wantServer, client.server)
+			}
+			if client.namespace != tc.wantNamespace {
+				t.Errorf("want namespace=%q, not %q",
+					tc.wantNamespace, client.namespace)
+			}
+			if client.token != tc.wantToken {
+				t.Errorf("want token=%q, not %q",
+					tc.wantToken, client.token)
+			}
+			if client.username != tc.wantUsername {
+				t.Errorf("want username=%q, not %q",
+					tc.wantUsername, client.username)
+			}
+			wantTokenFile := ""
+			if tc.tokenFile {
+				wantTokenFile = filepath.Join(filepath.Dir(path), "token")
+			}
+			if client.tokenFile != wantTokenFile {
+				t.Errorf("want tokenFile=%q, not %q",
+					wantTokenFile, client.tokenFile)
+			}
+		})
+	}
+}
+
+func TestLookup_KubernetesClient(t *testing.T) {
+	// GIVEN a Lookup that already has a Kubernetes API client for a kubeconfig
+	tests := map[string]struct {
+		kubeconfigChange  func(t *testing.T, path string)
+		kubeconfig        string
+		allowInvalidCerts bool
+		wantSameClient    bool
+		wantToken         string
+		errRegex          string
+	}{
+		"same kubeconfig": {
+			wantSameClient: true,
+			wantToken:      "abc",
+		},
+		"kubeconfig modified": {
+			kubeconfigChange: func(t *testing.T, path string) {
+				content, _ := os.ReadFile(path)
+				content = []byte(strings.Replace(string(content), "token: abc", "token: xyz", 1))
+				if err := os.WriteFile(path, content, 0600); err != nil {
+					t.Fatalf("failed to write %q: %v", path, err)
+				}
+				modTime := time.Now().Add(time.Minute)
+				if err := os.Chtimes(path, modTime, modTime); err != nil {
+					t.Fatalf("failed to set the modification time of %q: %v", path, err)
+				}
+			},
+			wantSameClient: false,
+			wantToken:      "xyz",
+		},
+		"different allow_invalid_certs": {
+			allowInvalidCerts: true,
+			wantSameClient:    false,
+			wantToken:         "abc",
+		},
+		"different kubeconfig": {
+			kubeconfig: "other",
+			errRegex:   `no such file or directory$`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := writeKubeconfig(t, "https://cluster.example.com/", "apps", "    token: abc")
+			dvl := testLookup()
+			dvl.Type = "kubernetes"
+			dvl.Kubeconfig = path
+			dvl.AllowInvalidCerts = test.BoolPtr(false)
+			firstClient, err := dvl.kubernetesClient()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.kubeconfigChange != nil {
+				tc.kubeconfigChange(t, path)
+			}
+			if tc.kubeconfig != "" {
+				dvl.Kubeconfig = filepath.Join(filepath.Dir(path), tc.kubeconfig)
+			}
+			dvl.AllowInvalidCerts = test.BoolPtr(tc.allowInvalidCerts)
+
+			// WHEN kubernetesClient is called again
+			client, err := dvl.kubernetesClient()
+
+			// THEN any err is expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			if !regexp.MustCompile(tc.errRegex).MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if err != nil {
+				// AND the cached client is kept
+				if dvl.kubernetes != firstClient {
+					t.Errorf("the cached client was replaced by a failed kubernetesClient call")
+				}
+				return
+			}
+			// AND the client is only reused when nothing has changed
+			if got := client == firstClient; got != tc.wantSameClient {
+				t.Errorf("want same client=%t, got %t",
+					tc.wantSameClient, got)
+			}
+			// AND the client uses the current kubeconfig
+			if client.token != tc.wantToken {
+				t.Errorf("want token=%q, not %q",
+					tc.wantToken, client.token)
+			}
+		})
+	}
+}
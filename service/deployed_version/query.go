@@ -23,7 +23,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/Masterminds/semver/v3"
+	opt "github.com/release-argus/Argus/service/options"
 	"github.com/release-argus/Argus/util"
 	metric "github.com/release-argus/Argus/web/metrics"
 )
@@ -35,6 +35,20 @@ func (l *Lookup) Track() {
 	}
 	logFrom := util.LogFrom{Primary: *l.Status.ServiceID}
 
+	// Watch the file for changes rather than querying every interval.
+	var changes <-chan struct{}
+	if l.GetType() == "file" && l.GetWatch() {
+		watcher, err := watchFile(l.GetPath())
+		if err != nil {
+			jLog.Warn(
+				fmt.Sprintf("Reading the file every interval instead - %s", err),
+				&logFrom, true)
+		} else {
+			defer watcher.Close()
+			changes = watcher.Changes
+		}
+	}
+
 	// Track forever.
 	for {
 		// If we're deleting this Service, stop tracking it.
@@ -46,14 +60,40 @@ func (l *Lookup) Track() {
 		deployedVersion, _ := l.Query(true, &logFrom)
 		// If new release found by ^ query.
 		l.HandleNewVersion(deployedVersion, true)
-		// Sleep interval between queries.
-		time.Sleep(l.Options.GetIntervalDuration())
+		if changes != nil {
+			// Wait for the file to change.
+			changes = l.waitForChange(changes)
+		} else {
+			// Sleep interval between queries.
+			time.Sleep(l.Options.GetIntervalDuration())
+		}
 	}
 }
 
 // query the deployed version (DeployedVersion) of the Service.
 func (l *Lookup) query(logFrom *util.LogFrom) (string, error) {
-	rawBody, err := l.httpRequest(logFrom)
+	var (
+		rawBody []byte
+		from    string
+		err     error
+	)
+	switch l.GetType() {
+	case "command":
+		rawBody, err = l.commandOutput(logFrom)
+		from = l.Command.String()
+	case "file":
+		rawBody, err = l.readFile(logFrom)
+		from = l.GetPath()
+	case "docker":
+		rawBody, err = l.dockerVersion(logFrom)
+		from = util.FirstNonDefault(l.GetContainer(), l.GetContainerLabel())
+	case "kubernetes":
+		rawBody, err = l.kubernetesVersion(logFrom)
+		from = l.GetWorkload()
+	default:
+		rawBody, err = l.httpRequest(logFrom)
+		from = l.GetURL()
+	}
 	if err != nil {
 		return "", err
 	}
@@ -61,7 +101,7 @@ func (l *Lookup) query(logFrom *util.LogFrom) (string, error) {
 	var version string
 	// If JSON is provided, use it to extract the version.
 	if l.JSON != "" {
-		version, err = util.GetValueByKey(rawBody, l.JSON, l.GetURL())
+		version, err = util.GetValueByKey(rawBody, l.JSON, from)
 		if err != nil {
 			jLog.Error(err, logFrom, true)
 			//nolint:wrapcheck
@@ -88,10 +128,16 @@ func (l *Lookup) query(logFrom *util.LogFrom) (string, error) {
 		version = util.RegexTemplate(regexMatches, l.RegexTemplate)
 	}
 
-	// If semantic versioning is enabled, check that the version is in the correct format.
-	if l.Options.GetSemanticVersioning() {
-		_, err = semver.NewVersion(version)
-		if err != nil {
+	// If semantic versioning is enabled, check that the version is in the version_scheme format.
+	if versionScheme := l.Options.GetVersionScheme(); versionScheme != "" {
+		_, err = opt.ParseVersion(versionScheme, version)
+		if err != nil && versionScheme != opt.VersionSchemeSemVer {
+			err = fmt.Errorf("%w. If all versions are in this style, consider adding json/regex to get the version "+
+				"into the %s style, or changing the version_scheme",
+				err, versionScheme)
+			jLog.Error(err, logFrom, true)
+			return "", err
+		} else if err != nil {
 			err = fmt.Errorf("failed converting %q to a semantic version. If all "+
 				"versions are in this style, consider adding json/regex to get the version into the "+
 				"style of 'MAJOR.MINOR.PATCH' (https://semver.org/), or disabling semantic versioning "+
@@ -143,43 +189,54 @@ func (l *Lookup) queryMetrics(successfulQuery bool) {
 // checks whether this is later than LatestVersion and announces and updates `Status` accordingly.
 func (l *Lookup) HandleNewVersion(version string, writeToDB bool) {
 	// If the new version is the same as what we had, do nothing.
-	if version == "" || version == l.Status.DeployedVersion() {
+	if version == "" || version == l.deployedVersion() {
 		return
 	}
 
 	// Set the new Deployed version.
-	l.Status.SetDeployedVersion(version, writeToDB)
+	if l.Target != "" {
+		l.Status.SetDeployedTargetVersion(l.Target, version, writeToDB)
+	} else {
+		l.Status.SetDeployedVersion(version, writeToDB)
+	}
 
 	// If this new version isn't LatestVersion
 	// Check that it's not a later version than LatestVersion
 	latestVersion := l.Status.LatestVersion()
 	if latestVersion == "" {
-		l.Status.SetLatestVersion(l.Status.DeployedVersion(), writeToDB)
+		l.Status.SetLatestVersion(version, writeToDB)
 		l.Status.SetLatestVersionTimestamp(l.Status.DeployedVersionTimestamp())
 		l.Status.AnnounceQueryNewVersion()
-	} else if version != latestVersion &&
-		l.Options.GetSemanticVersioning() {
-		//#nosec G104 -- Disregard as deployedVersion will always be semantic if GetSemanticVersioning
-		deployedVersionSV, _ := semver.NewVersion(version)
-		//#nosec G104 -- Disregard as LatestVersion will always be semantic if GetSemanticVersioning
-		latestVersionSV, _ := semver.NewVersion(latestVersion)
-
+	} else if versionScheme := l.Options.GetVersionScheme(); version != latestVersion &&
+		versionScheme != "" {
 		// Update LatestVersion to DeployedVersion if it's newer
-		if latestVersionSV.LessThan(deployedVersionSV) {
-			l.Status.SetLatestVersion(l.Status.DeployedVersion(), writeToDB)
+		if cmp, err := opt.CompareVersions(versionScheme, latestVersion, version); err == nil && cmp < 0 {
+			l.Status.SetLatestVersion(version, writeToDB)
 			l.Status.SetLatestVersionTimestamp(l.Status.DeployedVersionTimestamp())
 			l.Status.AnnounceQueryNewVersion()
 		}
 	}
 
 	// Announce version change to WebSocket clients.
+	msg := fmt.Sprintf("Updated to %q", version)
+	if l.Target != "" {
+		msg = fmt.Sprintf("Updated %q to %q", l.Target, version)
+	}
 	jLog.Info(
-		fmt.Sprintf("Updated to %q", version),
+		msg,
 		&util.LogFrom{Primary: *l.Status.ServiceID},
 		true)
 	l.Status.AnnounceUpdate()
 }
 
+// deployedVersion returns the version last found by this Lookup.
+func (l *Lookup) deployedVersion() string {
+	if l.Target != "" {
+		return l.Status.DeployedTargetVersion(l.Target)
+	}
+	return l.Status.DeployedVersion()
+}
+
 func (l *Lookup) httpRequest(logFrom *util.LogFrom) (rawBody []byte, err error) {
 	// HTTPS insecure skip verify.
 	customTransport := &http.Transport{}
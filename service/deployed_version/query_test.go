@@ -17,12 +17,15 @@
 package deployedver
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
 	"testing"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	command "github.com/release-argus/Argus/commands"
 	dbtype "github.com/release-argus/Argus/db/types"
 	opt "github.com/release-argus/Argus/service/options"
 	svcstatus "github.com/release-argus/Argus/service/status"
@@ -248,6 +251,248 @@ func TestLookup_Query(t *testing.T) {
 	}
 }
 
+func TestLookup_QueryCommand(t *testing.T) {
+	// GIVEN a command Lookup
+	tests := map[string]struct {
+		command       command.Command
+		timeout       string
+		json          string
+		regex         string
+		regexTemplate *string
+		wantVersion   string
+		errRegex      string
+	}{
+		"stdout is the version": {
+			command:     command.Command{"echo", "1.2.3"},
+			wantVersion: "1.2.3",
+		},
+		"json": {
+			command:     command.Command{"echo", `{"app":{"version":"1.2.3"}}`},
+			json:        "app.version",
+			wantVersion: "1.2.3",
+		},
+		"regex": {
+			command:     command.Command{"echo", "myapp version v1.2.3 (linux/amd64)"},
+			regex:       `v([0-9.]+)`,
+			wantVersion: "1.2.3",
+		},
+		"regex_template": {
+			command:       command.Command{"echo", "ii  myapp  1.2-3  amd64"},
+			regex:         `([0-9]+)\.([0-9]+)-([0-9]+)`,
+			regexTemplate: test.StringPtr("$1.$2.$3"),
+			wantVersion:   "1.2.3",
+		},
+		"templated command": {
+			command:     command.Command{"echo", "{{ version }}"},
+			wantVersion: "1.2.4",
+		},
+		"json not found": {
+			command:  command.Command{"echo", `{"app":{}}`},
+			json:     "app.version",
+			errRegex: `failed to find value for "app.version" in `,
+		},
+		"stdout isn't json": {
+			command:  command.Command{"echo", "1.2.3 (linux)"},
+			json:     "version",
+			errRegex: `failed to unmarshal the following from "echo 1.2.3 \(linux\)" into json`,
+		},
+		"command fails": {
+			command:  command.Command{"sh", "-c", "echo 'myapp: not found' >&2; exit 127"},
+			errRegex: `failed with exit status 127 - myapp: not found$`,
+		},
+		"command times out": {
+			command:  command.Command{"sleep", "5"},
+			timeout:  "100ms",
+			errRegex: `'sleep 5' timed out after 100ms$`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dvl := testLookup()
+			*dvl.Status.ServiceID = name
+			dvl.Status.SetLatestVersion("1.2.4", false)
+			dvl.Type = "command"
+			dvl.Method = ""
+			dvl.URL = ""
+			dvl.Command = tc.command
+			dvl.Timeout = tc.timeout
+			dvl.JSON = tc.json
+			dvl.Regex = tc.regex
+			dvl.RegexTemplate = tc.regexTemplate
+			if err := dvl.CheckValues(""); err != nil {
+				t.Fatalf("unexpected CheckValues err: %v", err)
+			}
+			dvl.InitMetrics()
+
+			// WHEN Query is called on it
+			version, err := dvl.Query(true, &util.LogFrom{})
+
+			// THEN any err is expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			if !regexp.MustCompile(tc.errRegex).MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the version is extracted from the stdout
+			if version != tc.wantVersion {
+				t.Errorf("want version=%q\ngot  version=%q",
+					tc.wantVersion, version)
+			}
+			// AND the query metrics are updated
+			wantPass, wantFail := 1.0, 0.0
+			if err != nil {
+				wantPass, wantFail = 0, 1
+			}
+			passQ := testutil.ToFloat64(metric.DeployedVersionQueryMetric.WithLabelValues(*dvl.Status.ServiceID, "SUCCESS"))
+			failQ := testutil.ToFloat64(metric.DeployedVersionQueryMetric.WithLabelValues(*dvl.Status.ServiceID, "FAIL"))
+			if passQ != wantPass || failQ != wantFail {
+				t.Errorf("want SUCCESS=%v, FAIL=%v metrics, got SUCCESS=%v, FAIL=%v",
+					wantPass, wantFail, passQ, failQ)
+			}
+			dvl.DeleteMetrics()
+		})
+	}
+}
+
+func TestLookup_QueryVersionScheme(t *testing.T) {
+	// GIVEN a Lookup with a version_scheme
+	tests := map[string]struct {
+		body          string
+		versionScheme string
+		errRegex      string
+	}{
+		"valid pep440 version": {
+			body:          "2.0rc1",
+			versionScheme: "pep440",
+		},
+		"valid debian version": {
+			body:          "1:2.30-1ubuntu1",
+			versionScheme: "debian",
+		},
+		"invalid calver version": {
+			body:          "1.2.3",
+			versionScheme: "calver",
+			errRegex:      `failed converting "1.2.3" to a calendar version: .* or changing the version_scheme`,
+		},
+		"semver error unchanged": {
+			body:          "2.0rc1",
+			versionScheme: "semver",
+			errRegex:      `failed converting "2.0rc1" to a semantic version. If all versions`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"version": "` + tc.body + `"}`))
+			}))
+			defer server.Close()
+			dvl := testLookup()
+			dvl.URL = server.URL
+			dvl.Options.VersionScheme = tc.versionScheme
+
+			// WHEN Query is called on it
+			version, err := dvl.Query(false, &util.LogFrom{})
+
+			// THEN any err is expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			if !regexp.MustCompile(tc.errRegex).MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the version is returned when valid
+			if err == nil && version != tc.body {
+				t.Errorf("want version=%q\ngot  version=%q",
+					tc.body, version)
+			}
+		})
+	}
+}
+
+func TestLookup_HandleNewVersion(t *testing.T) {
+	// GIVEN a Lookup with a LatestVersion
+	tests := map[string]struct {
+		latestVersion      string
+		version            string
+		versionScheme      string
+		semanticVersioning bool
+		wantLatestVersion  string
+	}{
+		"first version found": {
+			version:            "1.2.3",
+			semanticVersioning: true,
+			wantLatestVersion:  "1.2.3",
+		},
+		"newer deployed version becomes the latest": {
+			latestVersion:      "1.2.3",
+			version:            "1.2.4",
+			semanticVersioning: true,
+			wantLatestVersion:  "1.2.4",
+		},
+		"older deployed version doesn't change the latest": {
+			latestVersion:      "1.2.3",
+			version:            "1.2.2",
+			semanticVersioning: true,
+			wantLatestVersion:  "1.2.3",
+		},
+		"newer deployed version in the version_scheme": {
+			latestVersion:      "2.30-1ubuntu1",
+			version:            "1:1.0-1",
+			versionScheme:      "debian",
+			semanticVersioning: true,
+			wantLatestVersion:  "1:1.0-1",
+		},
+		"older deployed version in the version_scheme": {
+			latestVersion:      "1.0",
+			version:            "1.0rc1",
+			versionScheme:      "pep440",
+			semanticVersioning: true,
+			wantLatestVersion:  "1.0",
+		},
+		"no comparison without semantic_versioning": {
+			latestVersion:     "1.2.3",
+			version:           "1.2.4",
+			wantLatestVersion: "1.2.3",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dvl := testLookup()
+			*dvl.Options.SemanticVersioning = tc.semanticVersioning
+			dvl.Options.VersionScheme = tc.versionScheme
+			dvl.Status.SetLatestVersion(tc.latestVersion, false)
+
+			// WHEN HandleNewVersion is called on it
+			dvl.HandleNewVersion(tc.version, false)
+
+			// THEN the DeployedVersion is set
+			if got := dvl.Status.DeployedVersion(); got != tc.version {
+				t.Errorf("want DeployedVersion=%q\ngot  DeployedVersion=%q",
+					tc.version, got)
+			}
+			// AND the LatestVersion is only changed to newer versions
+			if got := dvl.Status.LatestVersion(); got != tc.wantLatestVersion {
+				t.Errorf("want LatestVersion=%q\ngot  LatestVersion=%q",
+					tc.wantLatestVersion, got)
+			}
+		})
+	}
+}
+
 func TestLookup_Track(t *testing.T) {
 	plainStableVersion := "1.2.1"
 	plainNonSemanticVersionAsSemantic := "1.2.2"
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	command "github.com/release-argus/Argus/commands"
 	opt "github.com/release-argus/Argus/service/options"
 	svcstatus "github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/util"
@@ -28,13 +29,27 @@ func (l *Lookup) applyOverrides(
 	allowInvalidCerts *string,
 	basicAuth *string,
 	body *string,
+	command *string,
+	container *string,
+	containerLabel *string,
 	headers *string,
+	host *string,
 	json *string,
+	kind *string,
+	kubeconfig *string,
 	method *string,
+	namespace *string,
+	path *string,
 	regex *string,
 	regexTemplate *string,
 	semanticVersioning *string,
+	timeout *string,
+	lookupType *string,
 	url *string,
+	versionKey *string,
+	versionSource *string,
+	watch *string,
+	workload *string,
 	serviceID *string,
 	logFrom *util.LogFrom,
 ) (*Lookup, error) {
@@ -51,6 +66,11 @@ func (l *Lookup) applyOverrides(
 		logFrom)
 	// body
 	useBody := util.FirstNonNilPtr(body, l.Body)
+	// command
+	useCommand := commandFromString(
+		command,
+		l.Command,
+		logFrom)
 	// headers
 	useHeaders := headersFromString(
 		headers,
@@ -70,6 +90,11 @@ func (l *Lookup) applyOverrides(
 	}
 	// url
 	useURL := util.PtrValueOrValue(url, l.URL)
+	// watch
+	useWatch := l.Watch
+	if watch != nil {
+		useWatch = util.StringToBoolPtr(*watch)
+	}
 
 	// options
 	options := opt.New(
@@ -77,6 +102,7 @@ func (l *Lookup) applyOverrides(
 		useSemanticVersioning,
 		l.Options.Defaults,
 		l.Options.HardDefaults)
+	options.VersionScheme = l.Options.VersionScheme
 
 	// Create a new lookup with the overrides.
 	lookup := New(
@@ -93,6 +119,20 @@ func (l *Lookup) applyOverrides(
 		useURL,
 		l.Defaults,
 		l.HardDefaults)
+	lookup.Type = util.PtrValueOrValue(lookupType, l.Type)
+	lookup.Command = useCommand
+	lookup.Timeout = util.PtrValueOrValue(timeout, l.Timeout)
+	lookup.Path = util.PtrValueOrValue(path, l.Path)
+	lookup.Watch = useWatch
+	lookup.Host = util.PtrValueOrValue(host, l.Host)
+	lookup.Kubeconfig = util.PtrValueOrValue(kubeconfig, l.Kubeconfig)
+	lookup.Kind = util.PtrValueOrValue(kind, l.Kind)
+	lookup.Namespace = util.PtrValueOrValue(namespace, l.Namespace)
+	lookup.Workload = util.PtrValueOrValue(workload, l.Workload)
+	lookup.Container = util.PtrValueOrValue(container, l.Container)
+	lookup.ContainerLabel = util.PtrValueOrValue(containerLabel, l.ContainerLabel)
+	lookup.VersionSource = util.PtrValueOrValue(versionSource, l.VersionSource)
+	lookup.VersionKey = util.PtrValueOrValue(versionKey, l.VersionKey)
 	if err := lookup.CheckValues(""); err != nil {
 		jLog.Error(err, logFrom, true)
 		return nil, fmt.Errorf("values failed validity check:\n%w", err)
@@ -110,13 +150,27 @@ func (l *Lookup) Refresh(
 	allowInvalidCerts *string,
 	basicAuth *string,
 	body *string,
+	command *string,
+	container *string,
+	containerLabel *string,
 	headers *string,
+	host *string,
 	json *string,
+	kind *string,
+	kubeconfig *string,
 	method *string,
+	namespace *string,
+	path *string,
 	regex *string,
 	regexTemplate *string,
 	semanticVersioning *string,
+	timeout *string,
+	lookupType *string,
 	url *string,
+	versionKey *string,
+	versionSource *string,
+	watch *string,
+	workload *string,
 ) (version string, announceUpdate bool, err error) {
 	serviceID := *l.Status.ServiceID
 	logFrom := &util.LogFrom{Primary: "deployed_version/refresh", Secondary: serviceID}
@@ -126,13 +180,27 @@ func (l *Lookup) Refresh(
 		allowInvalidCerts,
 		basicAuth,
 		body,
+		command,
+		container,
+		containerLabel,
 		headers,
+		host,
 		json,
+		kind,
+		kubeconfig,
 		method,
+		namespace,
+		path,
 		regex,
 		regexTemplate,
 		semanticVersioning,
+		timeout,
+		lookupType,
 		url,
+		versionKey,
+		versionSource,
+		watch,
+		workload,
 		&serviceID,
 		logFrom)
 	if err != nil {
@@ -152,7 +220,19 @@ func (l *Lookup) Refresh(
 		url != nil ||
 		json != nil ||
 		regex != nil ||
-		regexTemplate != nil
+		regexTemplate != nil ||
+		lookupType != nil ||
+		command != nil ||
+		path != nil ||
+		host != nil ||
+		kubeconfig != nil ||
+		kind != nil ||
+		namespace != nil ||
+		workload != nil ||
+		container != nil ||
+		containerLabel != nil ||
+		versionSource != nil ||
+		versionKey != nil
 
 	// Query the lookup.
 	version, err = lookup.Query(!overrides, logFrom)
@@ -204,6 +284,24 @@ func basicAuthFromString(jsonStr *string, previous *BasicAuth, logFrom *util.Log
 	return basicAuth
 }
 
+func commandFromString(jsonStr *string, previous command.Command, logFrom *util.LogFrom) command.Command {
+	// jsonStr == nil when it hasn't been changed, so return the previous
+	if jsonStr == nil {
+		return previous
+	}
+
+	var cmd command.Command
+	err := json.Unmarshal([]byte(*jsonStr), &cmd)
+	// Ignore the JSON if it failed to unmarshal
+	if err != nil {
+		jLog.Error(fmt.Sprintf("Failed converting JSON - %q\n%s", *jsonStr, util.ErrorToString(err)),
+			logFrom, true)
+		return previous
+	}
+
+	return cmd
+}
+
 func headersFromString(jsonStr *string, previous *[]Header, logFrom *util.LogFrom) *[]Header {
 	// jsonStr == nil when it hasn't been changed, so return the previous
 	if jsonStr == nil {
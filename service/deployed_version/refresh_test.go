@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	command "github.com/release-argus/Argus/commands"
 	opt "github.com/release-argus/Argus/service/options"
 	svcstatus "github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/test"
@@ -146,6 +147,57 @@ func TestHeadersFromString(t *testing.T) {
 	}
 }
 
+func TestCommandFromString(t *testing.T) {
+	// GIVEN we have a string of a command
+	exampleCommand := command.Command{"myapp", "--version"}
+	tests := map[string]struct {
+		command  *string
+		previous command.Command
+		want     command.Command
+	}{
+		"nil string uses previous": {
+			command:  nil,
+			previous: exampleCommand,
+			want:     exampleCommand,
+		},
+		"empty string uses previous": {
+			command:  test.StringPtr(""),
+			previous: exampleCommand,
+			want:     exampleCommand,
+		},
+		"invalid JSON uses previous": {
+			command:  test.StringPtr(`["echo", "1.2.3"`),
+			previous: exampleCommand,
+			want:     exampleCommand,
+		},
+		"command set": {
+			command:  test.StringPtr(`["echo", "1.2.3"]`),
+			previous: exampleCommand,
+			want:     command.Command{"echo", "1.2.3"},
+		},
+		"command set, no previous": {
+			command:  test.StringPtr(`["echo", "1.2.3"]`),
+			previous: nil,
+			want:     command.Command{"echo", "1.2.3"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN commandFromString is called
+			got := commandFromString(tc.command, tc.previous, &util.LogFrom{})
+
+			// THEN the result is expected
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("want: %v\ngot:  %v",
+					tc.want, got)
+			}
+		})
+	}
+}
+
 func TestLookup_ApplyOverrides(t *testing.T) {
 	testL := testLookup()
 	// GIVEN various json strings to parse as parts of a Lookup
@@ -153,13 +205,27 @@ func TestLookup_ApplyOverrides(t *testing.T) {
 		allowInvalidCerts  *string
 		basicAuth          *string
 		body               *string
+		command            *string
+		container          *string
+		containerLabel     *string
 		headers            *string
+		host               *string
 		json               *string
+		kind               *string
+		kubeconfig         *string
 		method             *string
+		namespace          *string
+		path               *string
 		regex              *string
 		regexTemplate      *string
 		semanticVersioning *string
+		timeout            *string
+		lookupType         *string
 		url                *string
+		versionKey         *string
+		versionSource      *string
+		watch              *string
+		workload           *string
 		previous           *Lookup
 		previousRegex      string
 		errRegex           string
@@ -349,6 +415,87 @@ func TestLookup_ApplyOverrides(t *testing.T) {
 				"https://valid.release-argus.io/json", // URL
 				nil, nil),
 		},
+		"type command": {
+			lookupType: test.StringPtr("command"),
+			url:        test.StringPtr(""),
+			command:    test.StringPtr(`["echo", "1.2.3"]`),
+			timeout:    test.StringPtr("5s"),
+
+			previous: testLookup(),
+			want: func() *Lookup {
+				lookup := testLookup()
+				lookup.Type = "command"
+				lookup.URL = ""
+				lookup.Command = []string{"echo", "1.2.3"}
+				lookup.Timeout = "5s"
+				return lookup
+			}(),
+		},
+		"type file": {
+			lookupType: test.StringPtr("file"),
+			url:        test.StringPtr(""),
+			path:       test.StringPtr("/data/VERSION"),
+			watch:      test.StringPtr("true"),
+
+			previous: testLookup(),
+			want: func() *Lookup {
+				lookup := testLookup()
+				lookup.Type = "file"
+				lookup.URL = ""
+				lookup.Path = "/data/VERSION"
+				lookup.Watch = test.BoolPtr(true)
+				return lookup
+			}(),
+		},
+		"type docker": {
+			lookupType:     test.StringPtr("docker"),
+			url:            test.StringPtr(""),
+			host:           test.StringPtr("tcp://docker:2375"),
+			containerLabel: test.StringPtr("com.docker.compose.service=app"),
+			versionSource:  test.StringPtr("label"),
+			versionKey:     test.StringPtr("org.opencontainers.image.version"),
+
+			previous: testLookup(),
+			want: func() *Lookup {
+				lookup := testLookup()
+				lookup.Type = "docker"
+				lookup.URL = ""
+				lookup.Host = "tcp://docker:2375"
+				lookup.ContainerLabel = "com.docker.compose.service=app"
+				lookup.VersionSource = "label"
+				lookup.VersionKey = "org.opencontainers.image.version"
+				return lookup
+			}(),
+		},
+		"type kubernetes": {
+			lookupType: test.StringPtr("kubernetes"),
+			url:        test.StringPtr(""),
+			kubeconfig: test.StringPtr("/config/kubeconfig"),
+			kind:       test.StringPtr("statefulset"),
+			namespace:  test.StringPtr("apps"),
+			workload:   test.StringPtr("app"),
+			container:  test.StringPtr("server"),
+
+			previous: testLookup(),
+			want: func() *Lookup {
+				lookup := testLookup()
+				lookup.Type = "kubernetes"
+				lookup.URL = ""
+				lookup.Kubeconfig = "/config/kubeconfig"
+				lookup.Kind = "statefulset"
+				lookup.Namespace = "apps"
+				lookup.Workload = "app"
+				lookup.Container = "server"
+				return lookup
+			}(),
+		},
+		"override with invalid type": {
+			lookupType: test.StringPtr("foo"),
+
+			previous: testLookup(),
+			want:     nil,
+			errRegex: `type: "foo" <invalid>`,
+		},
 		"override with invalid (empty) url": {
 			url: test.StringPtr(""),
 
@@ -377,13 +524,27 @@ func TestLookup_ApplyOverrides(t *testing.T) {
 				tc.allowInvalidCerts,
 				tc.basicAuth,
 				tc.body,
+				tc.command,
+				tc.container,
+				tc.containerLabel,
 				tc.headers,
+				tc.host,
 				tc.json,
+				tc.kind,
+				tc.kubeconfig,
 				tc.method,
+				tc.namespace,
+				tc.path,
 				tc.regex,
 				tc.regexTemplate,
 				tc.semanticVersioning,
+				tc.timeout,
+				tc.lookupType,
 				tc.url,
+				tc.versionKey,
+				tc.versionSource,
+				tc.watch,
+				tc.workload,
 				&name,
 				&util.LogFrom{Primary: name})
 
@@ -423,12 +584,14 @@ func TestLookup_Refresh(t *testing.T) {
 		allowInvalidCerts        *string
 		basicAuth                *string
 		body                     *string
+		command                  *string
 		headers                  *string
 		json                     *string
 		method                   *string
 		regex                    *string
 		regexTemplate            *string
 		semanticVersioning       *string
+		lookupType               *string
 		url                      *string
 		lookup                   *Lookup
 		deployedVersion          string
@@ -454,6 +617,13 @@ func TestLookup_Refresh(t *testing.T) {
 			lookup:             testLookup(),
 			want:               testVersion + "-beta",
 		},
+		"Change of type": {
+			lookupType: test.StringPtr("command"),
+			command:    test.StringPtr(`["echo", "1.2.3"]`),
+			url:        test.StringPtr(""),
+			lookup:     testLookup(),
+			want:       "1.2.3",
+		},
 		"Change of vars that fail Query": {
 			allowInvalidCerts: test.StringPtr("false"),
 			lookup:            testLookup(),
@@ -503,13 +673,21 @@ func TestLookup_Refresh(t *testing.T) {
 				tc.allowInvalidCerts,
 				tc.basicAuth,
 				tc.body,
+				tc.command,
+				nil, nil,
 				tc.headers,
+				nil,
 				tc.json,
+				nil, nil,
 				tc.method,
+				nil, nil,
 				tc.regex,
 				tc.regexTemplate,
 				tc.semanticVersioning,
-				tc.url)
+				nil,
+				tc.lookupType,
+				tc.url,
+				nil, nil, nil, nil)
 
 			// THEN we get an error if expected
 			if tc.errRegex != "" || err != nil {
@@ -536,7 +714,8 @@ func TestLookup_Refresh(t *testing.T) {
 			}
 			// AND the timestamp only changes if the version changed
 			// and the possible query-changing overrides are nil
-			if tc.headers == nil && tc.json == nil && tc.regex == nil && tc.semanticVersioning == nil && tc.url == nil {
+			if tc.headers == nil && tc.json == nil && tc.regex == nil && tc.semanticVersioning == nil && tc.url == nil &&
+				tc.lookupType == nil && tc.command == nil {
 				// If the version changed
 				if previousStatus.DeployedVersion() != tc.lookup.Status.DeployedVersion() {
 					// then so should the timestamp
@@ -0,0 +1,162 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployedver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	opt "github.com/release-argus/Argus/service/options"
+	svcstatus "github.com/release-argus/Argus/service/status"
+	"github.com/release-argus/Argus/util"
+)
+
+var (
+	targetAggregates = []string{"all", "any", "oldest"}
+)
+
+// Targets are the named Lookups of the places a Service is deployed to.
+type Targets struct {
+	Aggregate string             `yaml:"aggregate,omitempty" json:"aggregate,omitempty"` // OPTIONAL: How the versions of the Targets give the deployed_version - "all"/"any"/"oldest" (default: all).
+	Targets   map[string]*Lookup `yaml:"targets,omitempty" json:"targets,omitempty"`     // REQUIRED: Lookups of the deployed versions, keyed by target name.
+}
+
+// String returns a string representation of the Targets.
+func (t *Targets) String(prefix string) (str string) {
+	if t != nil {
+		str = util.ToYAMLString(t, prefix)
+	}
+	return
+}
+
+// IsEqual will return a bool of whether these Targets are the same as `other` (excluding status).
+func (t *Targets) IsEqual(other *Targets) bool {
+	return t.String("") == other.String("")
+}
+
+// Copy returns a deep copy of the Targets (without their Status).
+func (t *Targets) Copy() *Targets {
+	if t == nil {
+		return nil
+	}
+
+	data, _ := json.Marshal(t)
+	var targets Targets
+	_ = json.Unmarshal(data, &targets)
+	return &targets
+}
+
+// GetAggregate returns how the versions of the Targets give the deployed_version (default: all).
+func (t *Targets) GetAggregate() string {
+	return util.FirstNonDefault(t.Aggregate, "all")
+}
+
+// Init the Targets, and the deployed targets in the `status`.
+func (t *Targets) Init(
+	defaults *LookupDefaults,
+	hardDefaults *LookupDefaults,
+	status *svcstatus.Status,
+	options *opt.Options,
+) {
+	if t == nil {
+		return
+	}
+
+	for name, lookup := range t.Targets {
+		if lookup == nil {
+			lookup = &Lookup{}
+			t.Targets[name] = lookup
+		}
+		lookup.Target = name
+		lookup.Init(
+			defaults, hardDefaults,
+			status,
+			options)
+	}
+	status.InitDeployedTargets(t.GetAggregate(), util.SortedKeys(t.Targets))
+}
+
+// CheckValues of the Targets.
+func (t *Targets) CheckValues(prefix string) (errs error) {
+	if t == nil {
+		return
+	}
+
+	// Aggregate
+	if t.Aggregate != "" && !util.Contains(targetAggregates, t.Aggregate) {
+		errs = fmt.Errorf("%s%s  aggregate: %q <invalid> (only [%s] are allowed)\\",
+			util.ErrorToString(errs), prefix, t.Aggregate, strings.Join(targetAggregates, ", "))
+	}
+
+	// Targets
+	if len(t.Targets) == 0 {
+		errs = fmt.Errorf("%s%s  targets: <required> (at least one target is required)\\",
+			util.ErrorToString(errs), prefix)
+	}
+	var targetErrs error
+	for _, name := range util.SortedKeys(t.Targets) {
+		if t.Targets[name] == nil {
+			t.Targets[name] = &Lookup{}
+		}
+		if err := t.Targets[name].checkValues(prefix + "    "); err != nil {
+			targetErrs = fmt.Errorf("%s%s    %s:\\%w",
+				util.ErrorToString(targetErrs), prefix, name, err)
+		}
+	}
+	if targetErrs != nil {
+		errs = fmt.Errorf("%s%s  targets:\\%w",
+			util.ErrorToString(errs), prefix, targetErrs)
+	}
+
+	if errs != nil {
+		errs = fmt.Errorf("%sdeployed_versions:\\%w",
+			prefix, errs)
+	}
+	return
+}
+
+// Track the deployed versions of the Targets.
+func (t *Targets) Track() {
+	if t == nil {
+		return
+	}
+
+	for _, lookup := range t.Targets {
+		go lookup.Track()
+	}
+}
+
+// InitMetrics for the Targets.
+func (t *Targets) InitMetrics() {
+	if t == nil {
+		return
+	}
+
+	for _, lookup := range t.Targets {
+		lookup.InitMetrics()
+	}
+}
+
+// DeleteMetrics for the Targets.
+func (t *Targets) DeleteMetrics() {
+	if t == nil {
+		return
+	}
+
+	for _, lookup := range t.Targets {
+		lookup.DeleteMetrics()
+	}
+}
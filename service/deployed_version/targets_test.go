@@ -0,0 +1,221 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package deployedver
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	opt "github.com/release-argus/Argus/service/options"
+	svcstatus "github.com/release-argus/Argus/service/status"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+func testTargets() *Targets {
+	eu := testLookup()
+	eu.URL = "https://eu.example.com/version"
+	us := testLookup()
+	us.URL = "https://us.example.com/version"
+	return &Targets{
+		Targets: map[string]*Lookup{
+			"eu": eu,
+			"us": us}}
+}
+
+func TestTargets_CheckValues(t *testing.T) {
+	// GIVEN Targets
+	tests := map[string]struct {
+		targets  *Targets
+		errRegex []string
+	}{
+		"nil": {
+			targets:  nil,
+			errRegex: []string{`^$`},
+		},
+		"valid": {
+			targets:  testTargets(),
+			errRegex: []string{`^$`},
+		},
+		"valid aggregate": {
+			targets: func() *Targets {
+				targets := testTargets()
+				targets.Aggregate = "oldest"
+				return targets
+			}(),
+			errRegex: []string{`^$`},
+		},
+		"invalid aggregate": {
+			targets: func() *Targets {
+				targets := testTargets()
+				targets.Aggregate = "newest"
+				return targets
+			}(),
+			errRegex: []string{
+				`^deployed_versions:$`,
+				`^  aggregate: "newest" <invalid> \(only \[all, any, oldest\] are allowed\)$`},
+		},
+		"no targets": {
+			targets: &Targets{},
+			errRegex: []string{
+				`^deployed_versions:$`,
+				`^  targets: <required>`},
+		},
+		"invalid target": {
+			targets: func() *Targets {
+				targets := testTargets()
+				targets.Targets["us"].Type = "command"
+				return targets
+			}(),
+			errRegex: []string{
+				`^deployed_versions:$`,
+				`^  targets:$`,
+				`^    us:$`,
+				`^      command: <required>`},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN CheckValues is called on them
+			err := tc.targets.CheckValues("")
+
+			// THEN the err is expected
+			e := util.ErrorToString(err)
+			lines := strings.Split(e, "\\")
+			for i := range tc.errRegex {
+				re := regexp.MustCompile(tc.errRegex[i])
+				if !re.MatchString(lines[i]) {
+					t.Fatalf("want match for %q on line %d\nnot: %q",
+						tc.errRegex[i], i, e)
+				}
+			}
+		})
+	}
+}
+
+func TestTargets_Init(t *testing.T) {
+	// GIVEN Targets
+	targets := testTargets()
+	targets.Aggregate = "any"
+	targets.Targets["ap"] = nil
+	status := &svcstatus.Status{ServiceID: test.StringPtr("TestTargets_Init")}
+	options := &opt.Options{}
+	defaults := &LookupDefaults{}
+	hardDefaults := &LookupDefaults{}
+
+	// WHEN Init is called on them
+	targets.Init(
+		defaults, hardDefaults,
+		status,
+		options)
+
+	// THEN each Lookup is given its target name, Status, Options and Defaults
+	for name, lookup := range targets.Targets {
+		if lookup == nil {
+			t.Fatalf("want %q to be initialised, not nil", name)
+		}
+		if lookup.Target != name {
+			t.Errorf("want Target=%q, not %q",
+				name, lookup.Target)
+		}
+		if lookup.Status != status || lookup.Options != options ||
+			lookup.Defaults != defaults || lookup.HardDefaults != hardDefaults {
+			t.Errorf("%q wasn't given the Status/Options/Defaults",
+				name)
+		}
+	}
+	// AND the Status knows of the targets
+	got := status.DeployedTargets()
+	if len(got) != 3 || got[0].Name != "ap" || got[1].Name != "eu" || got[2].Name != "us" {
+		t.Errorf("want targets [ap, eu, us] in the Status, not %v",
+			got)
+	}
+}
+
+func TestTargets_Copy(t *testing.T) {
+	// GIVEN Targets
+	targets := testTargets()
+	targets.Aggregate = "oldest"
+
+	// WHEN Copy is called on them
+	got := targets.Copy()
+
+	// THEN the copy is equal
+	if !got.IsEqual(targets) {
+		t.Fatalf("want copy:\n%s\nnot:\n%s",
+			targets.String(""), got.String(""))
+	}
+	// AND doesn't share the Lookups
+	got.Targets["eu"].URL = "https://other.example.com"
+	if targets.Targets["eu"].URL == got.Targets["eu"].URL {
+		t.Errorf("copy shares the Lookups of the original")
+	}
+}
+
+func TestLookup_HandleNewVersion_Target(t *testing.T) {
+	// GIVEN Targets tracking a Service on LatestVersion 1.2.3
+	targets := testTargets()
+	status := svcstatus.New(
+		nil, nil, nil,
+		"", "", "", "1.2.3", "", "")
+	status.ServiceID = test.StringPtr("TestLookup_HandleNewVersion_Target")
+	targets.Init(
+		&LookupDefaults{}, &LookupDefaults{},
+		status,
+		opt.New(
+			nil, "", test.BoolPtr(true),
+			&opt.OptionsDefaults{},
+			opt.NewDefaults("", test.BoolPtr(true))))
+
+	// WHEN HandleNewVersion is called on one target
+	targets.Targets["eu"].HandleNewVersion("1.2.3", false)
+	// AND an older version is found on the other
+	targets.Targets["us"].HandleNewVersion("1.2.2", false)
+
+	// THEN the version of each target is set
+	if got := status.DeployedTargetVersion("eu"); got != "1.2.3" {
+		t.Errorf("want eu on %q, not %q",
+			"1.2.3", got)
+	}
+	if got := status.DeployedTargetVersion("us"); got != "1.2.2" {
+		t.Errorf("want us on %q, not %q",
+			"1.2.2", got)
+	}
+	// AND the DeployedVersion is that of the target behind
+	if got := status.DeployedVersion(); got != "1.2.2" {
+		t.Errorf("want DeployedVersion=%q, not %q",
+			"1.2.2", got)
+	}
+
+	// WHEN a newer version than the LatestVersion is found on a target
+	targets.Targets["us"].HandleNewVersion("1.2.4", false)
+
+	// THEN the LatestVersion is updated
+	if got := status.LatestVersion(); got != "1.2.4" {
+		t.Errorf("want LatestVersion=%q, not %q",
+			"1.2.4", got)
+	}
+	// AND the DeployedVersion is that of the target now behind
+	if got := status.DeployedVersion(); got != "1.2.3" {
+		t.Errorf("want DeployedVersion=%q, not %q",
+			"1.2.3", got)
+	}
+}
@@ -15,14 +15,19 @@
 package deployedver
 
 import (
+	"sync"
+
+	command "github.com/release-argus/Argus/commands"
 	opt "github.com/release-argus/Argus/service/options"
 	svcstatus "github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/util"
 )
 
 var (
-	jLog           *util.JLog
-	supportedTypes = []string{"GET", "POST"}
+	jLog                  *util.JLog
+	supportedTypes        = []string{"GET", "POST"}
+	lookupTypes           = []string{"url", "command", "file", "docker", "kubernetes"}
+	defaultCommandTimeout = "10s"
 )
 
 // LookupBase is the base struct for the Lookup struct.
@@ -46,21 +51,40 @@ func NewDefaults(
 
 // Lookup the deployed version of the service.
 type Lookup struct {
-	Method        string `yaml:"method,omitempty" json:"method,omitempty"` // REQUIRED: HTTP method.
-	URL           string `yaml:"url,omitempty" json:"url,omitempty"`       // REQUIRED: URL to query.
-	LookupBase    `yaml:",inline" json:",inline"`
-	BasicAuth     *BasicAuth `yaml:"basic_auth,omitempty" json:"basic_auth,omitempty"`         // OPTIONAL: Basic Auth credentials.
-	Headers       []Header   `yaml:"headers,omitempty" json:"headers,omitempty"`               // OPTIONAL: Request Headers.
-	Body          *string    `yaml:"body,omitempty" json:"body,omitempty"`                     // OPTIONAL: Request Body.
-	JSON          string     `yaml:"json,omitempty" json:"json,omitempty"`                     // OPTIONAL: JSON key to use e.g. version_current.
-	Regex         string     `yaml:"regex,omitempty" json:"regex,omitempty"`                   // OPTIONAL: RegEx for the version.
-	RegexTemplate *string    `yaml:"regex_template,omitempty" json:"regex_template,omitempty"` // OPTIONAL: Template to apply to the RegEx match.
+	Type           string          `yaml:"type,omitempty" json:"type,omitempty"`                       // OPTIONAL: "url"/"command"/"file"/"docker"/"kubernetes" (default: url).
+	Method         string          `yaml:"method,omitempty" json:"method,omitempty"`                   // type:url - REQUIRED: HTTP method.
+	URL            string          `yaml:"url,omitempty" json:"url,omitempty"`                         // type:url - REQUIRED: URL to query.
+	Command        command.Command `yaml:"command,omitempty" json:"command,omitempty"`                 // type:command - REQUIRED: Command to run, e.g. ["myapp", "--version"].
+	Timeout        string          `yaml:"timeout,omitempty" json:"timeout,omitempty"`                 // type:command - OPTIONAL: Time to wait for the command to finish (default: 10s).
+	Path           string          `yaml:"path,omitempty" json:"path,omitempty"`                       // type:file - REQUIRED: Path of the file to read, e.g. "/data/VERSION".
+	Watch          *bool           `yaml:"watch,omitempty" json:"watch,omitempty"`                     // type:file - OPTIONAL: Watch the file for changes rather than reading it every interval (default: false).
+	Host           string          `yaml:"host,omitempty" json:"host,omitempty"`                       // type:docker - OPTIONAL: Docker Engine API to query, e.g. "tcp://docker:2375" (default: $DOCKER_HOST, or unix:///var/run/docker.sock).
+	Kubeconfig     string          `yaml:"kubeconfig,omitempty" json:"kubeconfig,omitempty"`           // type:kubernetes - OPTIONAL: Path of the kubeconfig to use (default: in-cluster service account, or $KUBECONFIG, or ~/.kube/config).
+	Kind           string          `yaml:"kind,omitempty" json:"kind,omitempty"`                       // type:kubernetes - OPTIONAL: "deployment"/"statefulset"/"daemonset" (default: deployment).
+	Namespace      string          `yaml:"namespace,omitempty" json:"namespace,omitempty"`             // type:kubernetes - OPTIONAL: Namespace of the workload (default: namespace of the service account/kubeconfig context, or default).
+	Workload       string          `yaml:"workload,omitempty" json:"workload,omitempty"`               // type:kubernetes - REQUIRED: Name of the workload.
+	Container      string          `yaml:"container,omitempty" json:"container,omitempty"`             // type:docker - REQUIRED (or container_label): Name or ID of the container. type:kubernetes - OPTIONAL: Name of the container in the workload (default: first container).
+	ContainerLabel string          `yaml:"container_label,omitempty" json:"container_label,omitempty"` // type:docker - REQUIRED (or container): Label of the container, e.g. "com.docker.compose.service=app".
+	VersionSource  string          `yaml:"version_source,omitempty" json:"version_source,omitempty"`   // type:docker/kubernetes - OPTIONAL: "image_tag"/"label"/"env" (default: image_tag).
+	VersionKey     string          `yaml:"version_key,omitempty" json:"version_key,omitempty"`         // type:docker/kubernetes - OPTIONAL: Label/env var holding the version (default label: org.opencontainers.image.version).
+	LookupBase     `yaml:",inline" json:",inline"`
+	BasicAuth      *BasicAuth `yaml:"basic_auth,omitempty" json:"basic_auth,omitempty"`         // OPTIONAL: Basic Auth credentials.
+	Headers        []Header   `yaml:"headers,omitempty" json:"headers,omitempty"`               // OPTIONAL: Request Headers.
+	Body           *string    `yaml:"body,omitempty" json:"body,omitempty"`                     // OPTIONAL: Request Body.
+	JSON           string     `yaml:"json,omitempty" json:"json,omitempty"`                     // OPTIONAL: JSON key to use e.g. version_current.
+	Regex          string     `yaml:"regex,omitempty" json:"regex,omitempty"`                   // OPTIONAL: RegEx for the version.
+	RegexTemplate  *string    `yaml:"regex_template,omitempty" json:"regex_template,omitempty"` // OPTIONAL: Template to apply to the RegEx match.
 
+	Target  string            `yaml:"-" json:"-"` // Name of the target in the Targets (empty if not a target).
 	Options *opt.Options      `yaml:"-" json:"-"` // Options for the lookups
 	Status  *svcstatus.Status `yaml:"-" json:"-"` // Service Status
 
 	Defaults     *LookupDefaults `yaml:"-" json:"-"` // Default values.
 	HardDefaults *LookupDefaults `yaml:"-" json:"-"` // Hardcoded default values.
+
+	clientMutex sync.Mutex        // Mutex for the cached API clients.
+	docker      *dockerAPI        // type:docker - Cached client for the Docker Engine API.
+	kubernetes  *kubernetesClient // type:kubernetes - Cached client for the Kubernetes API.
 }
 
 // New returns a new Lookup struct.
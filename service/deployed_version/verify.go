@@ -16,8 +16,11 @@ package deployedver
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/release-argus/Argus/util"
 )
@@ -28,6 +31,57 @@ func (l *Lookup) CheckValues(prefix string) (errs error) {
 		return
 	}
 
+	if errs = l.checkValues(prefix); errs != nil {
+		errs = fmt.Errorf("%sdeployed_version:\\%w",
+			prefix, errs)
+	}
+	return
+}
+
+// checkValues of the Lookup, with the fields at `prefix`+"  ".
+func (l *Lookup) checkValues(prefix string) (errs error) {
+	// Type
+	if l.Type != "" && !util.Contains(lookupTypes, l.Type) {
+		errs = fmt.Errorf("%s%s  type: %q <invalid> (only [%s] are allowed)\\",
+			util.ErrorToString(errs), prefix, l.Type, strings.Join(lookupTypes, ", "))
+	}
+	switch l.GetType() {
+	case "command":
+		errs = l.checkValuesCommand(prefix, errs)
+	case "file":
+		errs = l.checkValuesFile(prefix, errs)
+	case "docker":
+		errs = l.checkValuesDocker(prefix, errs)
+	case "kubernetes":
+		errs = l.checkValuesKubernetes(prefix, errs)
+	default:
+		errs = l.checkValuesURL(prefix, errs)
+	}
+	errs = l.checkValuesOtherTypes(prefix, errs)
+
+	// JSON
+	_, err := util.ParseKeys(l.JSON)
+	if err != nil {
+		errs = fmt.Errorf("%s%s  json: %q <invalid> - %s\\",
+			util.ErrorToString(errs), prefix, l.JSON, err.Error())
+	}
+
+	// RegEx
+	_, err = regexp.Compile(l.Regex)
+	if err != nil {
+		errs = fmt.Errorf("%s%s  regex: %q <invalid>\\",
+			util.ErrorToString(errs), prefix, l.Regex)
+	}
+	// Remove the RegExTemplate if empty or no RegEx.
+	if l.Regex == "" || util.DefaultIfNil(l.RegexTemplate) == "" {
+		l.RegexTemplate = nil
+	}
+
+	return
+}
+
+// checkValuesURL checks the values used by the url type.
+func (l *Lookup) checkValuesURL(prefix string, errs error) error {
 	// Method
 	l.Method = strings.ToUpper(l.Method)
 	if l.Method == "" {
@@ -46,28 +100,150 @@ func (l *Lookup) CheckValues(prefix string) (errs error) {
 		errs = fmt.Errorf("%s%s  url: <required> (URL to get the deployed_version is required)\\",
 			util.ErrorToString(errs), prefix)
 	}
+	return errs
+}
 
-	// JSON
-	_, err := util.ParseKeys(l.JSON)
-	if err != nil {
-		errs = fmt.Errorf("%s%s  json: %q <invalid> - %s\\",
-			util.ErrorToString(errs), prefix, l.JSON, err.Error())
+// checkValuesCommand checks the values used by the command type.
+func (l *Lookup) checkValuesCommand(prefix string, errs error) error {
+	// Command
+	if len(l.Command) == 0 {
+		errs = fmt.Errorf("%s%s  command: <required> (command to get the deployed_version is required)\\",
+			util.ErrorToString(errs), prefix)
+	} else if err := l.Command.CheckValues(); err != nil {
+		errs = fmt.Errorf("%s%s  command: %w",
+			util.ErrorToString(errs), prefix, err)
 	}
 
-	// RegEx
-	_, err = regexp.Compile(l.Regex)
-	if err != nil {
-		errs = fmt.Errorf("%s%s  regex: %q <invalid>\\",
-			util.ErrorToString(errs), prefix, l.Regex)
+	// Timeout
+	if l.Timeout != "" {
+		// Default to seconds when an integer is provided
+		if _, err := strconv.Atoi(l.Timeout); err == nil {
+			l.Timeout += "s"
+		}
+		if timeout, err := time.ParseDuration(l.Timeout); err != nil || timeout <= 0 {
+			errs = fmt.Errorf("%s%s  timeout: %q <invalid> (Use 'AhBmCs' duration format)\\",
+				util.ErrorToString(errs), prefix, l.Timeout)
+		}
 	}
-	// Remove the RegExTemplate if empty or no RegEx.
-	if l.Regex == "" || util.DefaultIfNil(l.RegexTemplate) == "" {
-		l.RegexTemplate = nil
+	return errs
+}
+
+// checkValuesFile checks the values used by the file type.
+func (l *Lookup) checkValuesFile(prefix string, errs error) error {
+	// Path
+	if l.Path == "" {
+		errs = fmt.Errorf("%s%s  path: <required> (path of the file to get the deployed_version from is required)\\",
+			util.ErrorToString(errs), prefix)
 	}
+	return errs
+}
 
-	if errs != nil {
-		errs = fmt.Errorf("%sdeployed_version:\\%w",
-			prefix, errs)
+// checkValuesDocker checks the values used by the docker type.
+func (l *Lookup) checkValuesDocker(prefix string, errs error) error {
+	// Host
+	if l.Host != "" {
+		if host, err := url.Parse(l.GetHost()); err != nil || !util.Contains(dockerHostSchemes, host.Scheme) {
+			errs = fmt.Errorf("%s%s  host: %q <invalid> (only [%s] schemes are allowed, e.g. \"tcp://docker:2375\")\\",
+				util.ErrorToString(errs), prefix, l.Host, strings.Join(dockerHostSchemes, ", "))
+		}
 	}
-	return
+
+	// Container/ContainerLabel
+	if l.Container == "" && l.ContainerLabel == "" {
+		errs = fmt.Errorf("%s%s  container: <required> (container or container_label to get the deployed_version from is required)\\",
+			util.ErrorToString(errs), prefix)
+	} else if l.Container != "" && l.ContainerLabel != "" {
+		errs = fmt.Errorf("%s%s  container_label: %q <invalid> (only one of container or container_label can be used)\\",
+			util.ErrorToString(errs), prefix, l.ContainerLabel)
+	}
+
+	return l.checkValuesVersionSource(prefix, errs)
+}
+
+// checkValuesKubernetes checks the values used by the kubernetes type.
+func (l *Lookup) checkValuesKubernetes(prefix string, errs error) error {
+	// Kind
+	if _, ok := kubernetesKinds[l.GetKind()]; !ok {
+		kinds := util.SortedKeys(kubernetesKinds)
+		errs = fmt.Errorf("%s%s  kind: %q <invalid> (only [%s] are allowed)\\",
+			util.ErrorToString(errs), prefix, l.Kind, strings.Join(kinds, ", "))
+	}
+
+	// Workload
+	if l.Workload == "" {
+		errs = fmt.Errorf("%s%s  workload: <required> (name of the workload to get the deployed_version from is required)\\",
+			util.ErrorToString(errs), prefix)
+	}
+
+	return l.checkValuesVersionSource(prefix, errs)
+}
+
+// checkValuesVersionSource checks the values used to get the version from a container.
+func (l *Lookup) checkValuesVersionSource(prefix string, errs error) error {
+	// VersionSource
+	if l.VersionSource != "" && !util.Contains(containerVersionSources, l.VersionSource) {
+		errs = fmt.Errorf("%s%s  version_source: %q <invalid> (only [%s] are allowed)\\",
+			util.ErrorToString(errs), prefix, l.VersionSource, strings.Join(containerVersionSources, ", "))
+	}
+	// VersionKey
+	switch l.GetVersionSource() {
+	case "env":
+		if l.VersionKey == "" {
+			errs = fmt.Errorf("%s%s  version_key: <required> (env var holding the version is required)\\",
+				util.ErrorToString(errs), prefix)
+		}
+	case "image_tag":
+		if l.VersionKey != "" {
+			errs = fmt.Errorf("%s%s  version_key: %q <invalid> (unused with the %q version_source)\\",
+				util.ErrorToString(errs), prefix, l.VersionKey, "image_tag")
+		}
+	}
+	return errs
+}
+
+// checkValuesOtherTypes checks that no values only used by other types are set.
+func (l *Lookup) checkValuesOtherTypes(prefix string, errs error) error {
+	lookupType := l.GetType()
+	// Unknown type, so can't know which are unused.
+	if !util.Contains(lookupTypes, lookupType) {
+		return errs
+	}
+
+	var watch string
+	if l.Watch != nil {
+		watch = fmt.Sprint(*l.Watch)
+	}
+	fields := []struct {
+		name        string
+		value       string
+		lookupTypes []string
+	}{
+		{name: "url", value: l.URL, lookupTypes: []string{"url"}},
+		{name: "command", value: l.Command.String(), lookupTypes: []string{"command"}},
+		{name: "timeout", value: l.Timeout, lookupTypes: []string{"command"}},
+		{name: "path", value: l.Path, lookupTypes: []string{"file"}},
+		{name: "watch", value: watch, lookupTypes: []string{"file"}},
+		{name: "host", value: l.Host, lookupTypes: []string{"docker"}},
+		{name: "kubeconfig", value: l.Kubeconfig, lookupTypes: []string{"kubernetes"}},
+		{name: "kind", value: l.Kind, lookupTypes: []string{"kubernetes"}},
+		{name: "namespace", value: l.Namespace, lookupTypes: []string{"kubernetes"}},
+		{name: "workload", value: l.Workload, lookupTypes: []string{"kubernetes"}},
+		{name: "container", value: l.Container, lookupTypes: []string{"docker", "kubernetes"}},
+		{name: "container_label", value: l.ContainerLabel, lookupTypes: []string{"docker"}},
+		{name: "version_source", value: l.VersionSource, lookupTypes: []string{"docker", "kubernetes"}},
+		{name: "version_key", value: l.VersionKey, lookupTypes: []string{"docker", "kubernetes"}},
+	}
+	for _, field := range fields {
+		if field.value == "" || util.Contains(field.lookupTypes, lookupType) {
+			continue
+		}
+		if len(field.lookupTypes) == 1 {
+			errs = fmt.Errorf("%s%s  %s: %q <invalid> (only used by the %q type)\\",
+				util.ErrorToString(errs), prefix, field.name, field.value, field.lookupTypes[0])
+		} else {
+			errs = fmt.Errorf("%s%s  %s: %q <invalid> (only used by the [%s] types)\\",
+				util.ErrorToString(errs), prefix, field.name, field.value, strings.Join(field.lookupTypes, ", "))
+		}
+	}
+	return errs
 }
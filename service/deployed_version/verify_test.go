@@ -21,6 +21,7 @@ import (
 	"strings"
 	"testing"
 
+	command "github.com/release-argus/Argus/commands"
 	"github.com/release-argus/Argus/test"
 	"github.com/release-argus/Argus/util"
 )
@@ -28,15 +29,28 @@ import (
 func TestLookup_CheckValues(t *testing.T) {
 	// GIVEN a Lookup
 	tests := map[string]struct {
-		method        string
-		url           string
-		body          *string
-		json          string
-		regex         string
-		regexTemplate *string
-		defaults      *LookupDefaults
-		errRegex      string
-		nilService    bool
+		lookupType     string
+		method         string
+		url            string
+		command        command.Command
+		timeout        string
+		wantTimeout    string
+		path           string
+		watch          *bool
+		host           string
+		container      string
+		containerLabel string
+		versionSource  string
+		versionKey     string
+		kind           string
+		workload       string
+		body           *string
+		json           string
+		regex          string
+		regexTemplate  *string
+		defaults       *LookupDefaults
+		errRegex       string
+		nilService     bool
 	}{
 		"nil service": {
 			errRegex:   `^$`,
@@ -115,6 +129,200 @@ func TestLookup_CheckValues(t *testing.T) {
 			regex:    "[0-",
 			defaults: &LookupDefaults{},
 		},
+		"type - invalid": {
+			errRegex:   `type: "foo" <invalid> \(only \[url, command, file, docker, kubernetes\] are allowed\)`,
+			lookupType: "foo",
+			method:     "GET",
+			url:        "https://example.com",
+		},
+		"type - url with a command": {
+			errRegex:   `command: "myapp --version" <invalid> \(only used by the "command" type\)`,
+			lookupType: "url",
+			method:     "GET",
+			url:        "https://example.com",
+			command:    command.Command{"myapp", "--version"},
+		},
+		"type - command": {
+			errRegex:   `^$`,
+			lookupType: "command",
+			command:    command.Command{"myapp", "--version"},
+			regex:      "[0-9.]+",
+			defaults:   &LookupDefaults{},
+		},
+		"type - command without a command": {
+			errRegex:   `command: <required>`,
+			lookupType: "command",
+			defaults:   &LookupDefaults{},
+		},
+		"type - command with an invalid template": {
+			errRegex:   `command: .* <invalid> \(didn't pass templating\)`,
+			lookupType: "command",
+			command:    command.Command{"myapp", "{{ version }"},
+			defaults:   &LookupDefaults{},
+		},
+		"type - command with a url": {
+			errRegex:   `url: "https://example.com" <invalid> \(only used by the "url" type\)`,
+			lookupType: "command",
+			command:    command.Command{"myapp", "--version"},
+			url:        "https://example.com",
+			defaults:   &LookupDefaults{},
+		},
+		"type - file": {
+			errRegex:   `^$`,
+			lookupType: "file",
+			path:       "/data/VERSION",
+			watch:      test.BoolPtr(true),
+			defaults:   &LookupDefaults{},
+		},
+		"type - file without a path": {
+			errRegex:   `path: <required>`,
+			lookupType: "file",
+			defaults:   &LookupDefaults{},
+		},
+		"type - file with a command": {
+			errRegex:   `command: "cat /data/VERSION" <invalid> \(only used by the "command" type\)`,
+			lookupType: "file",
+			path:       "/data/VERSION",
+			command:    command.Command{"cat", "/data/VERSION"},
+			defaults:   &LookupDefaults{},
+		},
+		"type - url with a path and watch": {
+			errRegex: `path: "/data/VERSION" <invalid> \(only used by the "file" type\)\\  watch: "false" <invalid> \(only used by the "file" type\)`,
+			method:   "GET",
+			url:      "https://example.com",
+			path:     "/data/VERSION",
+			watch:    test.BoolPtr(false),
+		},
+		"type - docker": {
+			errRegex:   `^$`,
+			lookupType: "docker",
+			host:       "tcp://docker:2375",
+			container:  "app",
+			defaults:   &LookupDefaults{},
+		},
+		"type - docker with a label": {
+			errRegex:       `^$`,
+			lookupType:     "docker",
+			containerLabel: "com.docker.compose.service=app",
+			versionSource:  "label",
+			defaults:       &LookupDefaults{},
+		},
+		"type - docker without a container": {
+			errRegex:   `container: <required>`,
+			lookupType: "docker",
+			defaults:   &LookupDefaults{},
+		},
+		"type - docker with a container and container_label": {
+			errRegex:       `container_label: "app=true" <invalid> \(only one of container or container_label can be used\)`,
+			lookupType:     "docker",
+			container:      "app",
+			containerLabel: "app=true",
+			defaults:       &LookupDefaults{},
+		},
+		"type - docker with an invalid host": {
+			errRegex:   `host: "docker:2375" <invalid> \(only \[unix, tcp, http, https\] schemes are allowed`,
+			lookupType: "docker",
+			host:       "docker:2375",
+			container:  "app",
+			defaults:   &LookupDefaults{},
+		},
+		"type - docker with an invalid version_source": {
+			errRegex:      `version_source: "digest" <invalid> \(only \[image_tag, label, env\] are allowed\)`,
+			lookupType:    "docker",
+			container:     "app",
+			versionSource: "digest",
+			defaults:      &LookupDefaults{},
+		},
+		"type - docker with env version_source without a version_key": {
+			errRegex:      `version_key: <required>`,
+			lookupType:    "docker",
+			container:     "app",
+			versionSource: "env",
+			defaults:      &LookupDefaults{},
+		},
+		"type - docker with image_tag version_source and a version_key": {
+			errRegex:   `version_key: "VERSION" <invalid> \(unused with the "image_tag" version_source\)`,
+			lookupType: "docker",
+			container:  "app",
+			versionKey: "VERSION",
+			defaults:   &LookupDefaults{},
+		},
+		"type - url with a container": {
+			errRegex:  `container: "app" <invalid> \(only used by the \[docker, kubernetes\] types\)`,
+			method:    "GET",
+			url:       "https://example.com",
+			container: "app",
+		},
+		"type - kubernetes": {
+			errRegex:   `^$`,
+			lookupType: "kubernetes",
+			kind:       "statefulset",
+			workload:   "argus",
+			container:  "argus",
+			defaults:   &LookupDefaults{},
+		},
+		"type - kubernetes without a workload": {
+			errRegex:   `workload: <required>`,
+			lookupType: "kubernetes",
+			defaults:   &LookupDefaults{},
+		},
+		"type - kubernetes with an invalid kind": {
+			errRegex:   `kind: "pod" <invalid> \(only \[daemonset, deployment, statefulset\] are allowed\)`,
+			lookupType: "kubernetes",
+			kind:       "pod",
+			workload:   "argus",
+			defaults:   &LookupDefaults{},
+		},
+		"type - kubernetes with env version_source without a version_key": {
+			errRegex:      `version_key: <required>`,
+			lookupType:    "kubernetes",
+			workload:      "argus",
+			versionSource: "env",
+			defaults:      &LookupDefaults{},
+		},
+		"type - kubernetes with a host and container_label": {
+			errRegex:       `host: "tcp://docker:2375" <invalid> \(only used by the "docker" type\)\\  container_label: "app=argus" <invalid> \(only used by the "docker" type\)`,
+			lookupType:     "kubernetes",
+			workload:       "argus",
+			host:           "tcp://docker:2375",
+			containerLabel: "app=argus",
+			defaults:       &LookupDefaults{},
+		},
+		"type - docker with a workload": {
+			errRegex:   `workload: "argus" <invalid> \(only used by the "kubernetes" type\)`,
+			lookupType: "docker",
+			container:  "argus",
+			workload:   "argus",
+			defaults:   &LookupDefaults{},
+		},
+		"timeout - valid": {
+			errRegex:    `^$`,
+			lookupType:  "command",
+			command:     command.Command{"myapp", "--version"},
+			timeout:     "1m",
+			wantTimeout: "1m",
+		},
+		"timeout - integer is seconds": {
+			errRegex:    `^$`,
+			lookupType:  "command",
+			command:     command.Command{"myapp", "--version"},
+			timeout:     "30",
+			wantTimeout: "30s",
+		},
+		"timeout - invalid": {
+			errRegex:    `timeout: "1x" <invalid>`,
+			lookupType:  "command",
+			command:     command.Command{"myapp", "--version"},
+			timeout:     "1x",
+			wantTimeout: "1x",
+		},
+		"timeout - not positive": {
+			errRegex:    `timeout: "0s" <invalid>`,
+			lookupType:  "command",
+			command:     command.Command{"myapp", "--version"},
+			timeout:     "0s",
+			wantTimeout: "0s",
+		},
 		"no url doesn't fail for Lookup Defaults": {
 			errRegex: `^$`,
 			method:   "GET",
@@ -129,8 +337,20 @@ func TestLookup_CheckValues(t *testing.T) {
 
 			lookup := &Lookup{}
 			lookup = testLookup()
+			lookup.Type = tc.lookupType
 			lookup.Method = tc.method
 			lookup.URL = tc.url
+			lookup.Command = tc.command
+			lookup.Timeout = tc.timeout
+			lookup.Path = tc.path
+			lookup.Watch = tc.watch
+			lookup.Host = tc.host
+			lookup.Container = tc.container
+			lookup.ContainerLabel = tc.containerLabel
+			lookup.VersionSource = tc.versionSource
+			lookup.VersionKey = tc.versionKey
+			lookup.Kind = tc.kind
+			lookup.Workload = tc.workload
 			lookup.Body = tc.body
 			lookup.JSON = tc.json
 			lookup.Regex = tc.regex
@@ -172,6 +392,14 @@ func TestLookup_CheckValues(t *testing.T) {
 			if lookup.Method == "POST" && hadBody != nil && lookup.Body == nil {
 				t.Fatalf("Body should be kept when Method is POST")
 			}
+			// AND the Timeout is in the duration format
+			if lookup.Timeout != tc.wantTimeout {
+				t.Fatalf("Timeout:\nwant: %q\ngot:  %q",
+					tc.wantTimeout, lookup.Timeout)
+			}
+			if lookup.GetType() != "url" {
+				return
+			}
 			// AND Method is uppercased
 			wantMethod := strings.ToUpper(tc.method)
 			if wantMethod == "" {
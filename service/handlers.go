@@ -22,7 +22,7 @@ import (
 )
 
 // UpdatedVersion will register the version change, setting `s.Status.DeployedVersion`
-// to `s.Status.LatestVersion` if there's no DeployedVersionLookup and announce the change.
+// to `s.Status.LatestVersion` if there's no deployed version lookup and announce the change.
 func (s *Service) UpdatedVersion(writeToDB bool) {
 	if s.Status.DeployedVersion() == s.Status.LatestVersion() {
 		return
@@ -37,7 +37,7 @@ func (s *Service) UpdatedVersion(writeToDB bool) {
 		return
 	}
 	// Don't update DeployedVersion to LatestVersion if we have a lookup check
-	if s.DeployedVersionLookup != nil {
+	if s.HasDeployedVersionLookup() {
 		//nolint:typecheck
 		if (s.Command != nil && len(s.Command) != 0) ||
 			s.WebHook != nil {
@@ -221,6 +221,20 @@ func (s *Service) HandleWebHook(webhookID string) {
 	}
 }
 
+// HandleIgnore will add the LatestVersion to the ignore_versions of the LatestVersion lookup,
+// saving it to the config so that it's never used again, and skip it.
+func (s *Service) HandleIgnore() {
+	version := s.Status.LatestVersion()
+	if version == "" {
+		return
+	}
+
+	if s.LatestVersion.IgnoreVersion(version) {
+		s.Status.SendSave()
+	}
+	s.HandleSkip()
+}
+
 // HandleSkip will set `version` to skipped and announce it to the websocket.
 func (s *Service) HandleSkip() {
 	// Ignore skips if latest version is deployed
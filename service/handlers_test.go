@@ -18,6 +18,7 @@ package service
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -1051,6 +1052,70 @@ func TestService_HandleSkip(t *testing.T) {
 	}
 }
 
+func TestService_HandleIgnore(t *testing.T) {
+	// GIVEN a Service
+	latestVersion := "1.2.3"
+	tests := map[string]struct {
+		latestVersion      string
+		ignoreVersions     []string
+		wantIgnoreVersions []string
+		wantApproved       string
+		wantSaves          int
+	}{
+		"ignores and skips the latest version": {
+			latestVersion:      latestVersion,
+			wantIgnoreVersions: []string{latestVersion},
+			wantApproved:       "SKIP_" + latestVersion,
+			wantSaves:          1,
+		},
+		"already ignored latest version is only skipped": {
+			latestVersion:      latestVersion,
+			ignoreVersions:     []string{"semver:~1.2"},
+			wantIgnoreVersions: []string{"semver:~1.2"},
+			wantApproved:       "SKIP_" + latestVersion,
+			wantSaves:          0,
+		},
+		"no latest version does nothing": {
+			latestVersion: "",
+			wantSaves:     0,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := testService(name, "url")
+			saveChannel := make(chan bool, 5)
+			svc.Status.SaveChannel = &saveChannel
+			svc.Status.SetDeployedVersion("1.0.0", false)
+			svc.Status.SetApprovedVersion("", false)
+			svc.Status.SetLatestVersion(tc.latestVersion, false)
+			svc.LatestVersion.IgnoreVersions = tc.ignoreVersions
+
+			// WHEN HandleIgnore is called on it
+			svc.HandleIgnore()
+
+			// THEN the version is added to the ignore_versions
+			got := strings.Join(svc.LatestVersion.GetIgnoreVersions(), ",")
+			if want := strings.Join(tc.wantIgnoreVersions, ","); got != want {
+				t.Errorf("IgnoreVersions should be %q, not %q",
+					want, got)
+			}
+			// AND the version is skipped
+			if got := svc.Status.ApprovedVersion(); got != tc.wantApproved {
+				t.Errorf("ApprovedVersion should be %q, not %q",
+					tc.wantApproved, got)
+			}
+			// AND a save of the config is requested when the ignore_versions changed
+			if len(saveChannel) != tc.wantSaves {
+				t.Errorf("Expecting %d save message but got %d",
+					tc.wantSaves, len(saveChannel))
+			}
+		})
+	}
+}
+
 func TestService_ShouldRetryAll(t *testing.T) {
 	// GIVEN a Service
 	tests := map[string]struct {
@@ -60,6 +60,7 @@ func (s *Service) Init(
 	// Options
 	s.Options.Defaults = &s.Defaults.Options
 	s.Options.HardDefaults = &s.HardDefaults.Options
+	s.Status.SetVersionScheme(s.Options.GetVersionScheme())
 
 	// Notify
 	// use defaults?
@@ -117,6 +118,11 @@ func (s *Service) Init(
 		&s.Defaults.DeployedVersionLookup, &s.HardDefaults.DeployedVersionLookup,
 		&s.Status,
 		&s.Options)
+	// DeployedVersionTargets
+	s.DeployedVersionTargets.Init(
+		&s.Defaults.DeployedVersionLookup, &s.HardDefaults.DeployedVersionLookup,
+		&s.Status,
+		&s.Options)
 
 }
 
@@ -127,6 +133,9 @@ func (s *Service) ServiceInfo() *util.ServiceInfo {
 		URL:           s.LatestVersion.ServiceURL(true),
 		WebURL:        s.Status.GetWebURL(),
 		LatestVersion: s.Status.LatestVersion(),
+		DockerDigest:  s.Status.DockerDigest(),
+		ReleaseNotes:  s.Status.ReleaseNotes(),
+		Changelog:     s.Status.Changelog(),
 	}
 }
 
@@ -156,6 +165,7 @@ func (s *Service) IconURL() (icon string) {
 func (s *Service) InitMetrics() {
 	s.LatestVersion.InitMetrics()
 	s.DeployedVersionLookup.InitMetrics()
+	s.DeployedVersionTargets.InitMetrics()
 	s.Notify.InitMetrics()
 	s.CommandController.InitMetrics()
 	s.WebHook.InitMetrics()
@@ -166,6 +176,7 @@ func (s *Service) InitMetrics() {
 func (s *Service) DeleteMetrics() {
 	s.LatestVersion.DeleteMetrics()
 	s.DeployedVersionLookup.DeleteMetrics()
+	s.DeployedVersionTargets.DeleteMetrics()
 	s.Notify.DeleteMetrics()
 	s.CommandController.DeleteMetrics()
 	s.WebHook.DeleteMetrics()
@@ -39,6 +39,8 @@ func TestService_ServiceInfo(t *testing.T) {
 	svc.Dashboard.WebURL = webURL
 	latestVersion := "latest.version"
 	svc.Status.SetLatestVersion(latestVersion, false)
+	dockerDigest := "sha256:abc"
+	svc.Status.SetDockerDigest(latestVersion, dockerDigest)
 	time.Sleep(10 * time.Millisecond)
 	time.Sleep(time.Second)
 
@@ -49,6 +51,7 @@ func TestService_ServiceInfo(t *testing.T) {
 		URL:           url,
 		WebURL:        webURL,
 		LatestVersion: latestVersion,
+		DockerDigest:  dockerDigest,
 	}
 
 	// THEN we get the correct ServiceInfo
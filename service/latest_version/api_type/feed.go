@@ -0,0 +1,104 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"strings"
+
+	"github.com/release-argus/Argus/util"
+)
+
+// Feed is the format of an RSS 2.0 (or RSS 1.0) or Atom feed.
+type Feed struct {
+	Channel FeedChannel `xml:"channel"` // RSS 2.0
+	Items   []FeedItem  `xml:"item"`    // RSS 1.0 (RDF)
+	Entries []FeedEntry `xml:"entry"`   // Atom
+}
+
+// FeedChannel is the format of a channel on an RSS 2.0 Feed.
+type FeedChannel struct {
+	Items []FeedItem `xml:"item"`
+}
+
+// FeedItem is the format of an item on an RSS Feed.
+type FeedItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`     // RSS 2.0
+	Date        string `xml:"date"`        // RSS 1.0 (dc:date)
+	Description string `xml:"description"` // Summary of the item
+}
+
+// FeedEntry is the format of an entry on an Atom Feed.
+type FeedEntry struct {
+	Title     string     `xml:"title"`
+	Links     []FeedLink `xml:"link"`
+	ID        string     `xml:"id"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+	Summary   string     `xml:"summary"`
+	Content   string     `xml:"content"`
+}
+
+// FeedLink is the format of a link on an Atom FeedEntry.
+type FeedLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+// Releases converts the items/entries of the Feed to Releases (in feed order).
+func (f *Feed) Releases() (releases []Release) {
+	items := make([]FeedItem, 0, len(f.Channel.Items)+len(f.Items))
+	items = append(append(items, f.Channel.Items...), f.Items...)
+	releases = make([]Release, 0, len(items)+len(f.Entries))
+	for i := range items {
+		releases = append(releases, items[i].Release())
+	}
+	for i := range f.Entries {
+		releases = append(releases, f.Entries[i].Release())
+	}
+	return
+}
+
+// Release converts the FeedItem to a Release.
+func (i *FeedItem) Release() Release {
+	link := strings.TrimSpace(i.Link)
+	if link == "" && strings.HasPrefix(strings.TrimSpace(i.GUID), "http") {
+		link = strings.TrimSpace(i.GUID)
+	}
+	return Release{
+		URL:         link,
+		TagName:     strings.TrimSpace(i.Title),
+		PublishedAt: strings.TrimSpace(util.FirstNonDefault(i.PubDate, i.Date)),
+		Body:        strings.TrimSpace(i.Description)}
+}
+
+// Release converts the FeedEntry to a Release.
+func (e *FeedEntry) Release() Release {
+	var link string
+	for _, l := range e.Links {
+		// The alternate link is the entry's web page.
+		if l.Rel == "" || l.Rel == "alternate" {
+			link = strings.TrimSpace(l.Href)
+			break
+		}
+	}
+	return Release{
+		URL:         link,
+		TagName:     strings.TrimSpace(e.Title),
+		PublishedAt: strings.TrimSpace(util.FirstNonDefault(e.Published, e.Updated)),
+		Body:        strings.TrimSpace(util.FirstNonDefault(e.Summary, e.Content))}
+}
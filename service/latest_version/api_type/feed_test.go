@@ -0,0 +1,105 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package types
+
+import (
+	"testing"
+)
+
+func TestFeed_Releases(t *testing.T) {
+	// GIVEN a Feed
+	tests := map[string]struct {
+		feed          Feed
+		wantTags      []string
+		wantLinks     []string
+		wantPublished []string
+		wantBodies    []string
+	}{
+		"empty": {
+			feed: Feed{},
+		},
+		"rss 2.0": {
+			feed: Feed{Channel: FeedChannel{Items: []FeedItem{
+				{Title: " 1.0.0 ", Link: " https://example.com/1.0.0 ", PubDate: " Tue, 02 Jan 2024 03:04:05 +0000 ",
+					Description: " Fixed a bug "},
+				{Title: "0.9.0", GUID: "https://example.com/0.9.0"},
+				{Title: "0.8.0", GUID: "0.8.0"}}}},
+			wantTags:      []string{"1.0.0", "0.9.0", "0.8.0"},
+			wantLinks:     []string{"https://example.com/1.0.0", "https://example.com/0.9.0", ""},
+			wantPublished: []string{"Tue, 02 Jan 2024 03:04:05 +0000", "", ""},
+			wantBodies:    []string{"Fixed a bug", "", ""},
+		},
+		"rss 1.0": {
+			feed: Feed{Items: []FeedItem{
+				{Title: "1.0.0", Link: "https://example.com/1.0.0", Date: "2024-01-02T03:04:05Z"}}},
+			wantTags:      []string{"1.0.0"},
+			wantLinks:     []string{"https://example.com/1.0.0"},
+			wantPublished: []string{"2024-01-02T03:04:05Z"},
+			wantBodies:    []string{""},
+		},
+		"atom": {
+			feed: Feed{Entries: []FeedEntry{
+				{Title: "1.0.0", Links: []FeedLink{
+					{Href: "https://example.com/1.0.0.tgz", Rel: "enclosure"},
+					{Href: "https://example.com/1.0.0", Rel: "alternate"}},
+					Published: "2024-01-02T03:04:05Z", Updated: "2024-01-03T03:04:05Z",
+					Summary: "Summary of 1.0.0", Content: "Content of 1.0.0"},
+				{Title: "0.9.0", Links: []FeedLink{
+					{Href: "https://example.com/0.9.0"}},
+					Updated: "2023-12-02T03:04:05Z",
+					Content: " Content of 0.9.0 "},
+				{Title: "0.8.0"}}},
+			wantTags:      []string{"1.0.0", "0.9.0", "0.8.0"},
+			wantLinks:     []string{"https://example.com/1.0.0", "https://example.com/0.9.0", ""},
+			wantPublished: []string{"2024-01-02T03:04:05Z", "2023-12-02T03:04:05Z", ""},
+			wantBodies:    []string{"Summary of 1.0.0", "Content of 0.9.0", ""},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN Releases is called on it
+			releases := tc.feed.Releases()
+
+			// THEN the items/entries are converted in order
+			if len(releases) != len(tc.wantTags) {
+				t.Fatalf("want %d releases, not %d:\n%v",
+					len(tc.wantTags), len(releases), releases)
+			}
+			for i := range releases {
+				if releases[i].TagName != tc.wantTags[i] {
+					t.Errorf("release %d: want TagName %q, not %q",
+						i, tc.wantTags[i], releases[i].TagName)
+				}
+				if releases[i].URL != tc.wantLinks[i] {
+					t.Errorf("release %d: want URL %q, not %q",
+						i, tc.wantLinks[i], releases[i].URL)
+				}
+				if releases[i].PublishedAt != tc.wantPublished[i] {
+					t.Errorf("release %d: want PublishedAt %q, not %q",
+						i, tc.wantPublished[i], releases[i].PublishedAt)
+				}
+				if releases[i].Body != tc.wantBodies[i] {
+					t.Errorf("release %d: want Body %q, not %q",
+						i, tc.wantBodies[i], releases[i].Body)
+				}
+			}
+		})
+	}
+}
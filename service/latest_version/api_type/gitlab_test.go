@@ -0,0 +1,118 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package types
+
+import (
+	"testing"
+)
+
+func TestGitLabRelease_Release(t *testing.T) {
+	// GIVEN a GitLabRelease
+	tests := map[string]struct {
+		release GitLabRelease
+		want    string
+	}{
+		"empty": {
+			release: GitLabRelease{},
+			want:    "{}"},
+		"no assets": {
+			release: GitLabRelease{
+				Name:    "Release 1.2.3",
+				TagName: "v1.2.3",
+				Links: GitLabReleaseLinks{
+					Self: "https://gitlab.com/group/project/-/releases/v1.2.3"}},
+			want: `
+				{
+					"url": "https://gitlab.com/group/project/-/releases/v1.2.3",
+					"name": "Release 1.2.3",
+					"tag_name": "v1.2.3"
+				}`},
+		"upcoming release is a prerelease": {
+			release: GitLabRelease{
+				TagName:         "v1.2.3",
+				UpcomingRelease: true},
+			want: `
+				{
+					"tag_name": "v1.2.3",
+					"prerelease": true
+				}`},
+		"released_at is the publish time": {
+			release: GitLabRelease{
+				TagName:    "v1.2.3",
+				ReleasedAt: "2024-01-02T03:04:05.000Z"},
+			want: `
+				{
+					"tag_name": "v1.2.3",
+					"published_at": "2024-01-02T03:04:05.000Z"
+				}`},
+		"description is the body": {
+			release: GitLabRelease{
+				TagName:     "v1.2.3",
+				Description: "## Changes\n- Fixed a bug"},
+			want: `
+				{
+					"tag_name": "v1.2.3",
+					"body": "## Changes\n- Fixed a bug"
+				}`},
+		"assets use the direct_asset_url if available": {
+			release: GitLabRelease{
+				TagName: "v1.2.3",
+				Assets: GitLabReleaseAssets{
+					Links: []GitLabAssetLink{
+						{ID: 1, Name: "direct", URL: "https://example.com/a", DirectAssetURL: "https://example.com/direct"},
+						{ID: 2, Name: "url", URL: "https://example.com/b"}}}},
+			want: `
+				{
+					"tag_name": "v1.2.3",
+					"assets": [
+						{"id": 1, "name": "direct", "url": "https://example.com/a", "browser_download_url": "https://example.com/direct"},
+						{"id": 2, "name": "url", "url": "https://example.com/b", "browser_download_url": "https://example.com/b"}
+					]
+				}`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN Release is called on it
+			release := tc.release.Release()
+
+			// THEN the Release is converted correctly
+			got := release.String()
+			tc.want = trimJSON(tc.want)
+			if got != tc.want {
+				t.Errorf("want:\n%q\ngot:\n%q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestGitLabTag_Release(t *testing.T) {
+	// GIVEN a GitLabTag
+	tag := GitLabTag{Name: "v1.2.3"}
+
+	// WHEN Release is called on it
+	got := tag.Release()
+
+	// THEN the TagName is the tag's Name
+	if got.TagName != tag.Name {
+		t.Errorf("want TagName %q, not %q",
+			tag.Name, got.TagName)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "strings"
+
+// HelmIndex is the format of the index.yaml of a Helm chart repository.
+type HelmIndex struct {
+	Entries map[string][]HelmChartVersion `yaml:"entries"`
+}
+
+// HelmChartVersion is the format of a version of a chart in a HelmIndex.
+type HelmChartVersion struct {
+	Version    string   `yaml:"version"`
+	AppVersion string   `yaml:"appVersion,omitempty"`
+	URLs       []string `yaml:"urls,omitempty"`
+	Created    string   `yaml:"created,omitempty"`
+}
+
+// Release converts the HelmChartVersion to a Release,
+// using the appVersion as the TagName if `appVersion`.
+func (c *HelmChartVersion) Release(appVersion bool) (release Release) {
+	release = Release{
+		Name:        c.Version,
+		TagName:     c.Version,
+		PublishedAt: c.Created}
+	if appVersion {
+		release.TagName = c.AppVersion
+	}
+
+	if len(c.URLs) != 0 {
+		release.Assets = make([]Asset, len(c.URLs))
+		for i, url := range c.URLs {
+			release.Assets[i] = Asset{
+				ID:                 uint(i),
+				Name:               url[strings.LastIndex(url, "/")+1:],
+				URL:                url,
+				BrowserDownloadURL: url}
+		}
+	}
+	return
+}
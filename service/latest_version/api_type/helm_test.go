@@ -0,0 +1,75 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package types
+
+import (
+	"testing"
+)
+
+func TestHelmChartVersion_Release(t *testing.T) {
+	// GIVEN a HelmChartVersion
+	chartVersion := HelmChartVersion{
+		Version:    "1.2.3",
+		AppVersion: "0.18.0",
+		URLs: []string{
+			"https://charts.example.com/argus-1.2.3.tgz",
+			"argus-1.2.3.tgz"},
+		Created: "2024-01-02T03:04:05.123456789Z"}
+	tests := map[string]struct {
+		appVersion bool
+		want       string
+	}{
+		"chart version": {
+			appVersion: false,
+			want:       "1.2.3"},
+		"app version": {
+			appVersion: true,
+			want:       "0.18.0"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN Release is called on it
+			got := chartVersion.Release(tc.appVersion)
+
+			// THEN the TagName is the wanted version
+			if got.TagName != tc.want {
+				t.Errorf("want TagName %q, not %q",
+					tc.want, got.TagName)
+			}
+			// AND the Name is the chart version
+			if got.Name != chartVersion.Version {
+				t.Errorf("want Name %q, not %q",
+					chartVersion.Version, got.Name)
+			}
+			// AND the chart URLs are the Assets
+			if len(got.Assets) != 2 ||
+				got.Assets[0].Name != "argus-1.2.3.tgz" || got.Assets[1].Name != "argus-1.2.3.tgz" ||
+				got.Assets[0].BrowserDownloadURL != chartVersion.URLs[0] {
+				t.Errorf("unexpected assets %v",
+					got.Assets)
+			}
+			// AND the created time is the publish time
+			if got.PublishedAt != chartVersion.Created {
+				t.Errorf("want PublishedAt %q, not %q",
+					chartVersion.Created, got.PublishedAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// PyPIProject is the format of a Project on /pypi/:project/json.
+type PyPIProject struct {
+	Message  string                `json:"message,omitempty"` // Error message
+	Releases map[string][]PyPIFile `json:"releases"`
+}
+
+// PyPIFile is the format of a file of a Release on a PyPIProject.
+type PyPIFile struct {
+	Yanked     bool   `json:"yanked"`
+	UploadTime string `json:"upload_time_iso_8601,omitempty"`
+}
+
+// NPMPackage is the format of a Package on the npm registry (/:package).
+type NPMPackage struct {
+	Error    string                `json:"error,omitempty"` // Error message
+	Versions map[string]NPMVersion `json:"versions"`
+	Time     map[string]string     `json:"time,omitempty"` // Publish time of each version (not in the abbreviated metadata)
+}
+
+// NPMVersion is the format of a Version on an NPMPackage.
+type NPMVersion struct {
+	Deprecated json.RawMessage `json:"deprecated,omitempty"` // Deprecation message (or false)
+}
+
+// IsDeprecated returns whether the NPMVersion has been deprecated.
+func (v *NPMVersion) IsDeprecated() bool {
+	deprecated := strings.TrimSpace(string(v.Deprecated))
+	return deprecated != "" && deprecated != "false" && deprecated != `""` && deprecated != "null"
+}
+
+// CratesVersions is the format of /api/v1/crates/:crate/versions on crates.io.
+type CratesVersions struct {
+	Errors   []CratesError   `json:"errors,omitempty"`
+	Versions []CratesVersion `json:"versions"`
+}
+
+// CratesError is the format of an error on the crates.io API.
+type CratesError struct {
+	Detail string `json:"detail"`
+}
+
+// CratesVersion is the format of a Version on CratesVersions.
+type CratesVersion struct {
+	Num       string `json:"num"`
+	Yanked    bool   `json:"yanked"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
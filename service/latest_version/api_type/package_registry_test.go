@@ -0,0 +1,58 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNPMVersion_IsDeprecated(t *testing.T) {
+	// GIVEN an NPMVersion
+	tests := map[string]struct {
+		json string
+		want bool
+	}{
+		"no deprecated":      {json: `{}`, want: false},
+		"deprecated false":   {json: `{"deprecated":false}`, want: false},
+		"deprecated empty":   {json: `{"deprecated":""}`, want: false},
+		"deprecated null":    {json: `{"deprecated":null}`, want: false},
+		"deprecated message": {json: `{"deprecated":"use v2"}`, want: true},
+		"deprecated true":    {json: `{"deprecated":true}`, want: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var version NPMVersion
+			if err := json.Unmarshal([]byte(tc.json), &version); err != nil {
+				t.Fatalf("failed to unmarshal %q: %v",
+					tc.json, err)
+			}
+
+			// WHEN IsDeprecated is called on it
+			got := version.IsDeprecated()
+
+			// THEN deprecated versions are identified
+			if got != tc.want {
+				t.Errorf("want %t, not %t",
+					tc.want, got)
+			}
+		})
+	}
+}
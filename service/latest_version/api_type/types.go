@@ -15,7 +15,10 @@
 package types
 
 import (
+	"time"
+
 	"github.com/Masterminds/semver/v3"
+	opt "github.com/release-argus/Argus/service/options"
 	"github.com/release-argus/Argus/util"
 )
 
@@ -24,9 +27,12 @@ type Release struct {
 	URL             string          `json:"url,omitempty"`
 	AssetsURL       string          `json:"assets_url,omitempty"`
 	SemanticVersion *semver.Version `json:"-"`
+	SchemeVersion   opt.Version     `json:"-"`              // Version parsed with the version_scheme
 	Name            string          `json:"name,omitempty"` // This is the tag name on /tags queries
 	TagName         string          `json:"tag_name,omitempty"`
 	PreRelease      bool            `json:"prerelease,omitempty"`
+	PublishedAt     string          `json:"published_at,omitempty"` // Time the release was published
+	Body            string          `json:"body,omitempty"`         // Release notes
 	Assets          []Asset         `json:"assets,omitempty"`
 }
 
@@ -38,6 +44,32 @@ func (r *Release) String() (str string) {
 	return
 }
 
+// publishTimeFormats are the formats that a PublishedAt time may be in.
+var publishTimeFormats = []string{
+	time.RFC3339,  // GitHub/Gitea/GitLab/Atom/Helm/package registries
+	time.RFC1123Z, // RSS
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+}
+
+// PublishTime returns the time that the Release was published,
+// or the zero time if it's unknown.
+func (r *Release) PublishTime() time.Time {
+	if r.PublishedAt == "" {
+		return time.Time{}
+	}
+
+	for _, format := range publishTimeFormats {
+		if t, err := time.Parse(format, r.PublishedAt); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
 // Asset is the format of an Asset on api.github.com/repos/OWNER/REPO/releases.
 type Asset struct {
 	ID                 uint   `json:"id"`
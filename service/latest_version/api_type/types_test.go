@@ -18,6 +18,7 @@ package types
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/release-argus/Argus/test"
@@ -88,6 +89,54 @@ func TestRelease_String(t *testing.T) {
 	}
 }
 
+func TestRelease_PublishTime(t *testing.T) {
+	// GIVEN a Release with a PublishedAt time
+	want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := map[string]struct {
+		publishedAt string
+		want        time.Time
+	}{
+		"unknown": {
+			publishedAt: "",
+			want:        time.Time{}},
+		"invalid": {
+			publishedAt: "yesterday",
+			want:        time.Time{}},
+		"RFC3339": {
+			publishedAt: "2024-01-02T03:04:05Z",
+			want:        want},
+		"RFC3339 with fractional seconds and offset": {
+			publishedAt: "2024-01-02T04:04:05.000+01:00",
+			want:        want},
+		"RSS (RFC1123Z)": {
+			publishedAt: "Tue, 02 Jan 2024 03:04:05 +0000",
+			want:        want},
+		"RSS (RFC1123)": {
+			publishedAt: "Tue, 02 Jan 2024 03:04:05 GMT",
+			want:        want},
+		"RSS single digit day": {
+			publishedAt: "Tue, 2 Jan 2024 03:04:05 +0000",
+			want:        want},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			release := Release{PublishedAt: tc.publishedAt}
+
+			// WHEN PublishTime is called on it
+			got := release.PublishTime()
+
+			// THEN the time is parsed
+			if !got.Equal(tc.want) {
+				t.Errorf("want: %s\ngot:  %s",
+					tc.want, got)
+			}
+		})
+	}
+}
+
 func TestAsset_String(t *testing.T) {
 	tests := map[string]struct {
 		asset *Asset
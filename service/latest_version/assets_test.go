@@ -0,0 +1,128 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//go:build unit
+
+package latestver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync/atomic"
+	"testing"
+
+	"github.com/release-argus/Argus/service/latest_version/filter"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestLookup_QueryAssets(t *testing.T) {
+	// GIVEN a Lookup with a require.assets
+	withAssets := `{"tag_name":"v1.2.0","assets":{"links":[
+		{"id":1,"name":"myapp_1.2.0_linux_amd64.tar.gz"},
+		{"id":2,"name":"checksums.txt"}]}}`
+	withoutAssets := `{"tag_name":"v1.2.0","assets":{"links":[
+		{"id":2,"name":"checksums.txt"}]}}`
+	older := `{"tag_name":"v1.1.0","assets":{"links":[
+		{"id":3,"name":"myapp_1.1.0_linux_amd64.tar.gz"},
+		{"id":4,"name":"checksums.txt"}]}}`
+	tests := map[string]struct {
+		releases          []string
+		latestVersion     string
+		wantLatestVersion string
+		wantNewVersion    []bool
+		errRegex          string
+	}{
+		"every asset found": {
+			releases:          []string{`[` + withAssets + `,` + older + `]`},
+			latestVersion:     "1.1.0",
+			wantLatestVersion: "1.2.0",
+			wantNewVersion:    []bool{true},
+		},
+		"stays on the latest version until the assets are uploaded": {
+			releases:          []string{`[` + withoutAssets + `,` + older + `]`},
+			latestVersion:     "1.1.0",
+			wantLatestVersion: "1.1.0",
+			wantNewVersion:    []bool{false},
+		},
+		"release is retried once the assets are uploaded": {
+			releases: []string{
+				`[` + withoutAssets + `,` + older + `]`,
+				`[` + withAssets + `,` + older + `]`},
+			latestVersion:     "1.1.0",
+			wantLatestVersion: "1.2.0",
+			wantNewVersion:    []bool{false, true},
+		},
+		"older release with the assets is used without a latest version": {
+			releases:          []string{`[` + withoutAssets + `,` + older + `]`},
+			wantLatestVersion: "1.1.0",
+			wantNewVersion:    []bool{false},
+		},
+		"no release has the assets": {
+			releases: []string{`[` + withoutAssets + `]`},
+			errRegex: `asset "myapp_1.2.0_linux_amd64\\\\.tar\\\\.gz" not found on the release of version "1.2.0"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var query atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tc.releases[query.Load()]))
+			}))
+			defer server.Close()
+			lookup := testLookupGitLab(server.URL)
+			lookup.UsePreRelease = test.BoolPtr(false)
+			lookup.URLCommands = filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`v?([0-9.]+.*)`)}}
+			lookup.Require = &filter.Require{
+				Assets: []string{
+					`myapp_{{ version }}_linux_amd64\.tar\.gz`,
+					`checksums\.txt`}}
+			lookup.Status.SetLatestVersion(tc.latestVersion, false)
+			lookup.Status.SetDeployedVersion(tc.latestVersion, false)
+
+			for i := range tc.releases {
+				query.Store(int32(i))
+
+				// WHEN Query is called on it
+				newVersion, err := lookup.Query(false, &util.LogFrom{})
+
+				// THEN it err's when expected
+				if tc.errRegex == "" {
+					tc.errRegex = "^$"
+				}
+				e := util.ErrorToString(err)
+				re := regexp.MustCompile(tc.errRegex)
+				match := re.MatchString(e)
+				if !match {
+					t.Fatalf("query %d - want match for %q\nnot: %q",
+						i, tc.errRegex, e)
+				}
+				// AND a new version is only found once it has the assets
+				if i < len(tc.wantNewVersion) && newVersion != tc.wantNewVersion[i] {
+					t.Errorf("query %d - want newVersion=%t, not %t",
+						i, tc.wantNewVersion[i], newVersion)
+				}
+			}
+			// AND the latest version is as expected
+			if got := lookup.Status.LatestVersion(); got != tc.wantLatestVersion {
+				t.Errorf("want LatestVersion %q, not %q",
+					tc.wantLatestVersion, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	opt "github.com/release-argus/Argus/service/options"
+	svcstatus "github.com/release-argus/Argus/service/status"
+	"github.com/release-argus/Argus/util"
+)
+
+var changelogTypes = []string{"gitea", "github", "gitlab"} // Types whose releases have notes to build a changelog from
+
+// releaseVersion returns the version of the filtered `release` in the `versionScheme`.
+func (l *Lookup) releaseVersion(release *github_types.Release, versionScheme string) string {
+	if versionScheme == opt.VersionSchemeSemVer && l.Type != "url" {
+		return release.SemanticVersion.String()
+	}
+	return release.TagName
+}
+
+// setChangelog gives the Status the release notes of `version` and the releases before it,
+// (newest first) from the filtered `releases`.
+func (l *Lookup) setChangelog(releases []github_types.Release, version string) {
+	if !util.Contains(changelogTypes, l.Type) {
+		return
+	}
+
+	versionScheme := l.Options.GetVersionScheme()
+	var entries []svcstatus.ChangelogEntry
+	for i := range releases {
+		releaseVersion := l.releaseVersion(&releases[i], versionScheme)
+		// Skip the releases newer than `version`.
+		if len(entries) == 0 && releaseVersion != version {
+			continue
+		}
+
+		entries = append(entries, svcstatus.ChangelogEntry{
+			Version: releaseVersion,
+			Notes:   releases[i].Body})
+	}
+	l.Status.SetChangelog(version, entries)
+}
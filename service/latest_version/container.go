@@ -0,0 +1,151 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/service/latest_version/filter"
+	"github.com/release-argus/Argus/util"
+)
+
+// containerTagsJSON is the format of the tags of a container image.
+type containerTagsJSON struct {
+	Tags []string `json:"tags"`
+}
+
+// containerImage returns the registry type, registry URL and image of the container image in URL,
+// or of the chart for OCI-hosted helm charts.
+func (l *Lookup) containerImage() (registryType string, registryURL string, image string) {
+	ref := util.EvalEnvVars(l.URL)
+	if l.Type == "helm" {
+		// oci://registry/path + chart
+		ref = fmt.Sprintf("%s/%s",
+			strings.TrimSuffix(strings.TrimPrefix(ref, "oci://"), "/"), util.EvalEnvVars(l.Chart))
+	}
+	return parseImageReference(ref)
+}
+
+// parseImageReference returns the registry type, registry URL and image of a container image reference.
+//
+// e.g. "nginx" = "hub", "", "library/nginx"
+// and "ghcr.io/release-argus/argus:latest" = "ghcr", "https://ghcr.io", "release-argus/argus"
+// and "registry.example.com:5000/team/app" = "", "https://registry.example.com:5000", "team/app"
+func parseImageReference(ref string) (registryType string, registryURL string, image string) {
+	// Remove any digest/tag.
+	ref, _, _ = strings.Cut(ref, "@")
+	if colonIndex := strings.LastIndex(ref, ":"); colonIndex > strings.LastIndex(ref, "/") {
+		ref = ref[:colonIndex]
+	}
+
+	// Registry given with a scheme, e.g. http://localhost:5000/team/app
+	if strings.Contains(ref, "://") {
+		registryURL, image, _ = projectFromURL(ref)
+	} else if host, path, found := strings.Cut(ref, "/"); found &&
+		(strings.ContainsAny(host, ".:") || host == "localhost") {
+		registryURL, image = "https://"+host, path
+	} else {
+		image = ref
+	}
+
+	switch strings.TrimPrefix(strings.TrimPrefix(registryURL, "https://"), "http://") {
+	case "", "docker.io", "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com", "hub.docker.com":
+		registryType, registryURL = "hub", ""
+		// e.g. nginx = library/nginx on the docker hub api
+		if !strings.Contains(image, "/") {
+			image = "library/" + image
+		}
+	case "ghcr.io":
+		registryType = "ghcr"
+	case "quay.io":
+		registryType = "quay"
+	}
+	return
+}
+
+// containerWebURL returns the web page of the container image in URL.
+func (l *Lookup) containerWebURL() string {
+	registryType, registryURL, image := l.containerImage()
+	switch registryType {
+	case "hub":
+		if strings.HasPrefix(image, "library/") {
+			return "https://hub.docker.com/_/" + strings.TrimPrefix(image, "library/")
+		}
+		return "https://hub.docker.com/r/" + image
+	case "quay":
+		return "https://quay.io/repository/" + image
+	}
+	return registryURL + "/" + image
+}
+
+// containerRequest lists the tags of the container image (or OCI-hosted helm chart) and returns them as a JSON body.
+func (l *Lookup) containerRequest(logFrom *util.LogFrom) (rawBody *[]byte, err error) {
+	registryType, registryURL, image := l.containerImage()
+	if l.dockerCheck == nil || l.dockerCheck.Image != image || l.dockerCheck.Type != registryType {
+		// Token from this service, falling back to the registry tokens in require.docker's defaults.
+		l.dockerCheck = filter.NewDockerCheck(
+			registryType,
+			image,
+			"",
+			util.EvalEnvVars(l.Username),
+			l.accessToken(),
+			"", time.Time{},
+			l.containerDockerDefaults())
+		l.dockerCheck.SetHTTPClient(l.httpClient())
+	}
+
+	tags, err := l.dockerCheck.ListTags(registryURL, logFrom)
+	if err != nil {
+		err = fmt.Errorf("failed listing tags of %q: %w",
+			l.URL, err)
+		jLog.Error(err, logFrom, true)
+		return
+	}
+
+	body, _ := json.Marshal(containerTagsJSON{Tags: tags})
+	rawBody = &body
+	return
+}
+
+// containerDockerDefaults returns the require.docker defaults to use for the registry tokens.
+func (l *Lookup) containerDockerDefaults() *filter.DockerCheckDefaults {
+	if l.Defaults == nil {
+		return nil
+	}
+	return &l.Defaults.Require.Docker
+}
+
+// checkContainerTagsBody will convert the tags in body to Releases.
+func (l *Lookup) checkContainerTagsBody(body *[]byte, logFrom *util.LogFrom) (releases []github_types.Release, err error) {
+	var tagsJSON containerTagsJSON
+	if err = json.Unmarshal(*body, &tagsJSON); err != nil {
+		err = fmt.Errorf("unmarshal of container tags failed\n%w",
+			err)
+		jLog.Error(err, logFrom, true)
+		return
+	}
+
+	releases = make([]github_types.Release, len(tagsJSON.Tags))
+	for i, tag := range tagsJSON.Tags {
+		releases[i] = github_types.Release{
+			TagName:    tag,
+			PreRelease: l.isPreRelease(tag)}
+	}
+	return
+}
@@ -0,0 +1,348 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/release-argus/Argus/service/latest_version/filter"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+func testLookupContainer(url string) *Lookup {
+	lookup := testLookup(false, false)
+	lookup.Type = "container"
+	lookup.URL = url
+	lookup.AccessToken = nil
+	lookup.GitHubData = &GitHubData{}
+	return lookup
+}
+
+func TestLookup_ContainerImage(t *testing.T) {
+	// GIVEN a container image reference
+	tests := map[string]struct {
+		url                    string
+		env                    map[string]string
+		wantType, wantRegistry string
+		wantImage, wantWebURL  string
+	}{
+		"official hub image": {
+			url:        "nginx",
+			wantType:   "hub",
+			wantImage:  "library/nginx",
+			wantWebURL: "https://hub.docker.com/_/nginx",
+		},
+		"hub image with tag": {
+			url:        "releaseargus/argus:latest",
+			wantType:   "hub",
+			wantImage:  "releaseargus/argus",
+			wantWebURL: "https://hub.docker.com/r/releaseargus/argus",
+		},
+		"hub image with docker.io registry": {
+			url:        "docker.io/releaseargus/argus",
+			wantType:   "hub",
+			wantImage:  "releaseargus/argus",
+			wantWebURL: "https://hub.docker.com/r/releaseargus/argus",
+		},
+		"ghcr image with digest": {
+			url:          "ghcr.io/release-argus/argus@sha256:abc",
+			wantType:     "ghcr",
+			wantRegistry: "https://ghcr.io",
+			wantImage:    "release-argus/argus",
+			wantWebURL:   "https://ghcr.io/release-argus/argus",
+		},
+		"quay image": {
+			url:          "quay.io/argoproj/argocd",
+			wantType:     "quay",
+			wantRegistry: "https://quay.io",
+			wantImage:    "argoproj/argocd",
+			wantWebURL:   "https://quay.io/repository/argoproj/argocd",
+		},
+		"private registry with port and tag": {
+			url:          "registry.example.com:5000/team/app:1.2.3",
+			wantRegistry: "https://registry.example.com:5000",
+			wantImage:    "team/app",
+			wantWebURL:   "https://registry.example.com:5000/team/app",
+		},
+		"localhost registry": {
+			url:          "localhost/app",
+			wantRegistry: "https://localhost",
+			wantImage:    "app",
+			wantWebURL:   "https://localhost/app",
+		},
+		"registry with scheme": {
+			url:          "http://127.0.0.1:5000/team/app",
+			wantRegistry: "http://127.0.0.1:5000",
+			wantImage:    "team/app",
+			wantWebURL:   "http://127.0.0.1:5000/team/app",
+		},
+		"env var": {
+			url:          "${TEST_LOOKUP_CONTAINER_IMAGE}",
+			env:          map[string]string{"TEST_LOOKUP_CONTAINER_IMAGE": "ghcr.io/release-argus/argus"},
+			wantType:     "ghcr",
+			wantRegistry: "https://ghcr.io",
+			wantImage:    "release-argus/argus",
+			wantWebURL:   "https://ghcr.io/release-argus/argus",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			lookup := testLookupContainer(tc.url)
+
+			// WHEN containerImage is called on it
+			gotType, gotRegistry, gotImage := lookup.containerImage()
+
+			// THEN the registry and image are split out
+			if gotType != tc.wantType {
+				t.Errorf("want type %q, not %q",
+					tc.wantType, gotType)
+			}
+			if gotRegistry != tc.wantRegistry {
+				t.Errorf("want registry %q, not %q",
+					tc.wantRegistry, gotRegistry)
+			}
+			if gotImage != tc.wantImage {
+				t.Errorf("want image %q, not %q",
+					tc.wantImage, gotImage)
+			}
+			// AND the ServiceURL is the web page of the image
+			if got := lookup.ServiceURL(true); got != tc.wantWebURL {
+				t.Errorf("want ServiceURL %q, not %q",
+					tc.wantWebURL, got)
+			}
+		})
+	}
+}
+
+func TestLookup_CheckContainerTagsBody(t *testing.T) {
+	// GIVEN a body
+	tests := map[string]struct {
+		body     string
+		wantTags []string
+		wantPre  []bool
+		errRegex string
+	}{
+		"invalid json": {
+			body:     `{"tags":"1.0.0"}`,
+			errRegex: `unmarshal of container tags failed`,
+		},
+		"tags": {
+			body:     `{"tags":["1.0.0","1.1.0-rc.1","1.1-alpine","latest"]}`,
+			wantTags: []string{"1.0.0", "1.1.0-rc.1", "1.1-alpine", "latest"},
+			wantPre:  []bool{false, true, true, false},
+		},
+		"no tags": {
+			body: `{"tags":[]}`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookupContainer("team/app")
+			body := []byte(tc.body)
+
+			// WHEN checkContainerTagsBody is called on it
+			releases, err := lookup.checkContainerTagsBody(&body, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the tags are converted to releases
+			if len(releases) != len(tc.wantTags) {
+				t.Fatalf("want %d releases, not %d:\n%v",
+					len(tc.wantTags), len(releases), releases)
+			}
+			for i := range releases {
+				if releases[i].TagName != tc.wantTags[i] {
+					t.Errorf("release %d: want TagName %q, not %q",
+						i, tc.wantTags[i], releases[i].TagName)
+				}
+				if releases[i].PreRelease != tc.wantPre[i] {
+					t.Errorf("release %d: want PreRelease %t, not %t",
+						i, tc.wantPre[i], releases[i].PreRelease)
+				}
+			}
+		})
+	}
+}
+
+func TestLookup_QueryContainer(t *testing.T) {
+	// GIVEN an OCI distribution registry
+	tests := map[string]struct {
+		tags              string
+		image             string
+		username          string
+		accessToken       *string
+		usePreRelease     bool
+		urlCommands       filter.URLCommandSlice
+		selfSigned        bool
+		bearer            bool
+		allowInvalidCerts bool
+		wantLatestVersion string
+		errRegex          string
+	}{
+		"semantic sort of tags": {
+			tags:              `["1.0.0","1.10.0","1.9.0","latest"]`,
+			wantLatestVersion: "1.10.0",
+		},
+		"ignore prereleases": {
+			tags:              `["1.0.0","1.1.0-rc.1"]`,
+			wantLatestVersion: "1.0.0",
+		},
+		"use_prerelease": {
+			tags:              `["1.0.0","1.1.0-rc.1"]`,
+			usePreRelease:     true,
+			wantLatestVersion: "1.1.0-rc.1",
+		},
+		"url_commands": {
+			tags: `["v1.0.0-alpine","v1.2.0-alpine","v1.3.0"]`,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`^v([0-9.]+)-alpine$`)}},
+			usePreRelease:     true,
+			wantLatestVersion: "1.2.0",
+		},
+		"basic auth": {
+			tags:              `["1.0.0"]`,
+			username:          "user",
+			accessToken:       test.StringPtr("pass"),
+			wantLatestVersion: "1.0.0",
+		},
+		"basic auth missing": {
+			tags:     `["1.0.0"]`,
+			username: "user",
+			errRegex: `registry requires basic auth, but no token was given`,
+		},
+		"self-signed registry": {
+			tags:       `["1.0.0"]`,
+			selfSigned: true,
+			errRegex:   `tags request fail: .*certificate`,
+		},
+		"self-signed registry with allow_invalid_certs": {
+			tags:              `["1.0.0"]`,
+			selfSigned:        true,
+			allowInvalidCerts: true,
+			wantLatestVersion: "1.0.0",
+		},
+		"self-signed registry with a bearer token realm and allow_invalid_certs": {
+			tags:              `["1.0.0"]`,
+			selfSigned:        true,
+			bearer:            true,
+			allowInvalidCerts: true,
+			wantLatestVersion: "1.0.0",
+		},
+		"no tags": {
+			tags:     `[]`,
+			errRegex: "no releases were found matching the url_commands",
+		},
+		"unknown image": {
+			image:    "team/unknown",
+			errRegex: `failed listing tags of .*team/unknown.*404 Not Found`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var server *httptest.Server
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.bearer && r.URL.Path == "/token" {
+					fmt.Fprint(w, `{"token":"registry-token"}`)
+					return
+				}
+				if r.URL.Path != "/v2/team/app/tags/list" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				if tc.bearer && r.Header.Get("Authorization") != "Bearer registry-token" {
+					w.Header().Set("WWW-Authenticate",
+						fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, server.URL))
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				if tc.username != "" {
+					if user, pass, _ := r.BasicAuth(); user != tc.username || pass != util.DefaultIfNil(tc.accessToken) {
+						w.Header().Set("WWW-Authenticate", `Basic realm="Registry"`)
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+				}
+				fmt.Fprintf(w, `{"name":"team/app","tags":%s}`, tc.tags)
+			})
+			if tc.selfSigned {
+				server = httptest.NewTLSServer(handler)
+			} else {
+				server = httptest.NewServer(handler)
+			}
+			defer server.Close()
+			if tc.image == "" {
+				tc.image = "team/app"
+			}
+			lookup := testLookupContainer(server.URL + "/" + tc.image)
+			lookup.Username = tc.username
+			lookup.AccessToken = tc.accessToken
+			lookup.UsePreRelease = &tc.usePreRelease
+			lookup.URLCommands = tc.urlCommands
+			lookup.AllowInvalidCerts = &tc.allowInvalidCerts
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the latest version is found
+			if got := lookup.Status.LatestVersion(); got != tc.wantLatestVersion {
+				t.Errorf("want LatestVersion %q, not %q",
+					tc.wantLatestVersion, got)
+			}
+			// AND the tags are stored as releases
+			if tc.errRegex == "^$" && !strings.Contains(tc.tags, lookup.GitHubData.Releases()[0].TagName) {
+				t.Errorf("want releases from %s, not %v",
+					tc.tags, lookup.GitHubData.Releases())
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/util"
+)
+
+// checkFeedBody will convert the entries of the RSS/Atom feed in body to Releases.
+//
+// The TagName is the title of the entry (or its link if only that has the version
+// for the url_commands), and the URL is its link.
+func (l *Lookup) checkFeedBody(body *[]byte, logFrom *util.LogFrom) (releases []github_types.Release, err error) {
+	var feed github_types.Feed
+	if err = xml.Unmarshal(*body, &feed); err != nil {
+		err = fmt.Errorf("unmarshal of RSS/Atom feed failed\n%w",
+			err)
+		jLog.Error(err, logFrom, true)
+		return
+	}
+
+	releases = feed.Releases()
+	if len(releases) == 0 {
+		err = fmt.Errorf("no entries found in the feed at %s",
+			l.GetURL())
+		jLog.Error(err, logFrom, true)
+		return
+	}
+
+	// Feeds have no pre-release flag, so use the version of each entry.
+	entries := releases
+	releases = make([]github_types.Release, 0, len(entries))
+	for _, entry := range entries {
+		version, runErr := l.URLCommands.Run(entry.TagName, logFrom)
+		// Feed entries may only have the version in their link.
+		if runErr != nil && entry.URL != "" {
+			if version, runErr = l.URLCommands.Run(entry.URL, logFrom); runErr == nil {
+				entry.TagName = entry.URL
+			}
+		}
+		if runErr != nil {
+			continue
+		}
+		entry.PreRelease = l.isPreRelease(version)
+		releases = append(releases, entry)
+	}
+	return
+}
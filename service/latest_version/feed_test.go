@@ -0,0 +1,306 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/release-argus/Argus/service/latest_version/filter"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+var (
+	testFeedRSS = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Releases</title>
+    <item>
+      <title>Release 1.2.0-rc.1</title>
+      <link>https://example.com/releases/1.2.0-rc.1</link>
+    </item>
+    <item>
+      <title>Release 1.10.0</title>
+      <link>https://example.com/releases/1.10.0</link>
+    </item>
+    <item>
+      <title>Release 1.9.0</title>
+      <link>https://example.com/releases/1.9.0</link>
+    </item>
+    <item>
+      <title>Security advisory</title>
+      <guid>https://example.com/releases/1.11.0</guid>
+    </item>
+  </channel>
+</rss>`
+	testFeedAtom = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Releases</title>
+  <entry>
+    <title>v2.0.0</title>
+    <link rel="alternate" type="text/html" href="https://example.com/releases/tag/v2.0.0"/>
+    <id>tag:example.com,2024:2.0.0</id>
+  </entry>
+  <entry>
+    <title>v1.0.0</title>
+    <link rel="enclosure" href="https://example.com/download/v1.0.0.tgz"/>
+    <link href="https://example.com/releases/tag/v1.0.0"/>
+  </entry>
+</feed>`
+	testFeedRDF = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/">
+  <channel><title>Releases</title></channel>
+  <item>
+    <title>3.0.0</title>
+    <link>https://example.com/3.0.0</link>
+  </item>
+</rdf:RDF>`
+)
+
+func testLookupFeed(url string) *Lookup {
+	lookup := testLookup(false, false)
+	lookup.Type = "feed"
+	lookup.URL = url
+	lookup.AccessToken = nil
+	lookup.URLCommands = nil
+	lookup.GitHubData = &GitHubData{}
+	return lookup
+}
+
+func TestLookup_CheckFeedBody(t *testing.T) {
+	// GIVEN a feed
+	tests := map[string]struct {
+		body            string
+		urlCommands     filter.URLCommandSlice
+		wantTags        []string
+		wantLinks       []string
+		wantPreReleases []bool
+		errRegex        string
+	}{
+		"rss": {
+			body:      testFeedRSS,
+			wantTags:  []string{"Release 1.2.0-rc.1", "Release 1.10.0", "Release 1.9.0", "Security advisory"},
+			wantLinks: []string{"https://example.com/releases/1.2.0-rc.1", "https://example.com/releases/1.10.0", "https://example.com/releases/1.9.0", "https://example.com/releases/1.11.0"},
+		},
+		"rss with url_commands": {
+			body: testFeedRSS,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`^Release (.+)$`)}},
+			wantTags:        []string{"Release 1.2.0-rc.1", "Release 1.10.0", "Release 1.9.0"},
+			wantLinks:       []string{"https://example.com/releases/1.2.0-rc.1", "https://example.com/releases/1.10.0", "https://example.com/releases/1.9.0"},
+			wantPreReleases: []bool{true, false, false},
+		},
+		"rss with the version in the link": {
+			body: testFeedRSS,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`/releases/([^/]+)$`)}},
+			wantTags:        []string{"https://example.com/releases/1.2.0-rc.1", "https://example.com/releases/1.10.0", "https://example.com/releases/1.9.0", "https://example.com/releases/1.11.0"},
+			wantLinks:       []string{"https://example.com/releases/1.2.0-rc.1", "https://example.com/releases/1.10.0", "https://example.com/releases/1.9.0", "https://example.com/releases/1.11.0"},
+			wantPreReleases: []bool{true, false, false, false},
+		},
+		"atom": {
+			body:      testFeedAtom,
+			wantTags:  []string{"v2.0.0", "v1.0.0"},
+			wantLinks: []string{"https://example.com/releases/tag/v2.0.0", "https://example.com/releases/tag/v1.0.0"},
+		},
+		"rss 1.0": {
+			body:      testFeedRDF,
+			wantTags:  []string{"3.0.0"},
+			wantLinks: []string{"https://example.com/3.0.0"},
+		},
+		"no entries": {
+			body:     `<rss version="2.0"><channel><title>Releases</title></channel></rss>`,
+			errRegex: `no entries found in the feed at https://example.com/feed$`,
+		},
+		"invalid xml": {
+			body:     `<rss><channel>`,
+			errRegex: `unmarshal of RSS/Atom feed failed`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookupFeed("https://example.com/feed")
+			lookup.URLCommands = tc.urlCommands
+			body := []byte(tc.body)
+
+			// WHEN checkFeedBody is called on it
+			releases, err := lookup.checkFeedBody(&body, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the entries are converted to releases
+			if len(releases) != len(tc.wantTags) {
+				t.Fatalf("want %d releases, not %d:\n%v",
+					len(tc.wantTags), len(releases), releases)
+			}
+			for i := range releases {
+				if releases[i].TagName != tc.wantTags[i] {
+					t.Errorf("release %d: want TagName %q, not %q",
+						i, tc.wantTags[i], releases[i].TagName)
+				}
+				if releases[i].URL != tc.wantLinks[i] {
+					t.Errorf("release %d: want URL %q, not %q",
+						i, tc.wantLinks[i], releases[i].URL)
+				}
+				// AND pre-releases are identified from their version
+				if tc.wantPreReleases != nil && releases[i].PreRelease != tc.wantPreReleases[i] {
+					t.Errorf("release %d: want PreRelease %t, not %t",
+						i, tc.wantPreReleases[i], releases[i].PreRelease)
+				}
+			}
+		})
+	}
+}
+
+func TestLookup_QueryFeed(t *testing.T) {
+	// GIVEN a feed
+	tests := map[string]struct {
+		body               string
+		urlCommands        filter.URLCommandSlice
+		usePreRelease      bool
+		versionConstraint  string
+		semanticVersioning *bool
+		latestVersion      string
+		wantLatestVersion  string
+		wantWebURL         string
+		errRegex           string
+	}{
+		"rss sorted semantically": {
+			body: testFeedRSS,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`^Release ([0-9.]+)$`)}},
+			wantLatestVersion: "1.10.0",
+			wantWebURL:        "https://example.com/releases/1.10.0",
+		},
+		"version from the link": {
+			body: testFeedRSS,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`/releases/([0-9.]+)$`)}},
+			wantLatestVersion: "1.11.0",
+			wantWebURL:        "https://example.com/releases/1.11.0",
+		},
+		"use_prerelease": {
+			body: testFeedRSS,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`^Release (.+)$`)}},
+			usePreRelease:     true,
+			wantLatestVersion: "1.10.0",
+			wantWebURL:        "https://example.com/releases/1.10.0",
+		},
+		"prerelease skipped without semantic versioning": {
+			body: testFeedRSS,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`^Release (.+)$`)}},
+			semanticVersioning: test.BoolPtr(false),
+			wantLatestVersion:  "1.10.0",
+			wantWebURL:         "https://example.com/releases/1.10.0",
+		},
+		"atom": {
+			body: testFeedAtom,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`^v(.+)$`)}},
+			wantLatestVersion: "2.0.0",
+			wantWebURL:        "https://example.com/releases/tag/v2.0.0",
+		},
+		"version_constraint": {
+			body: testFeedRSS,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`^Release ([0-9.]+)$`)}},
+			versionConstraint: "~1.9",
+			wantLatestVersion: "1.9.0",
+			wantWebURL:        "https://example.com/releases/1.9.0",
+		},
+		"older version keeps the release page of the latest version": {
+			body: testFeedRSS,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`^Release ([0-9.]+)$`)}},
+			latestVersion:     "1.12.0",
+			wantLatestVersion: "1.12.0",
+			wantWebURL:        "https://example.com/releases/1.12.0",
+			errRegex:          `queried version "1.10.0" is less than the deployed version "1.12.0"`,
+		},
+		"no matching entries": {
+			body: testFeedAtom,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`^release-(.+)$`)}},
+			errRegex: `no releases were found matching the url_commands`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/xml")
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+			lookup := testLookupFeed(server.URL)
+			lookup.URLCommands = tc.urlCommands
+			lookup.UsePreRelease = &tc.usePreRelease
+			lookup.VersionConstraint = tc.versionConstraint
+			lookup.Options.SemanticVersioning = tc.semanticVersioning
+			lookup.Status.WebURL = test.StringPtr("")
+			if tc.latestVersion != "" {
+				lookup.Status.SetLatestVersion(tc.latestVersion, false)
+				lookup.Status.SetDeployedVersion(tc.latestVersion, false)
+				lookup.Status.SetReleaseURL(tc.latestVersion, "https://example.com/releases/"+tc.latestVersion)
+			}
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the latest version is found
+			if got := lookup.Status.LatestVersion(); got != tc.wantLatestVersion {
+				t.Errorf("want LatestVersion %q, not %q",
+					tc.wantLatestVersion, got)
+			}
+			// AND the entry link is the web_url
+			if got := lookup.Status.GetWebURL(); got != tc.wantWebURL {
+				t.Errorf("want web_url %q, not %q",
+					tc.wantWebURL, got)
+			}
+		})
+	}
+}
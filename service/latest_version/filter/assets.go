@@ -0,0 +1,85 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"fmt"
+	"regexp"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/util"
+)
+
+// AssetsCheck returns an error if any of the `assets` regexes (templated with `version`)
+// don't match the name of an asset on the release.
+func (r *Require) AssetsCheck(
+	version string,
+	assets []github_types.Asset,
+	logFrom *util.LogFrom,
+) error {
+	if r == nil {
+		return nil
+	}
+
+	for _, re := range r.Assets {
+		found := false
+		for i := range assets {
+			if util.RegexCheckWithParams(re, assets[i].Name, version) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			regexStr := util.TemplateString(re, util.ServiceInfo{LatestVersion: version})
+			err := fmt.Errorf("asset %q not found on the release of version %q",
+				regexStr, version)
+			// Assets may still be uploading, so will be checked again on the next query.
+			jLog.Verbose(err, logFrom, true)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkAssets returns an error if any of the `assets` are not valid templated regexes.
+func checkAssets(assets []string, prefix string) (errs error) {
+	for i, asset := range assets {
+		var err error
+		switch {
+		case asset == "":
+			err = fmt.Errorf("%s    item_%d: <required> (RegEx of the asset name)\\",
+				prefix, i)
+		case !util.CheckTemplate(asset):
+			err = fmt.Errorf("%s    item_%d: %q <invalid> (didn't pass templating)\\",
+				prefix, i, asset)
+		default:
+			if _, regexErr := regexp.Compile(asset); regexErr != nil {
+				err = fmt.Errorf("%s    item_%d: %q <invalid> (Invalid RegEx)\\",
+					prefix, i, asset)
+			}
+		}
+		if err != nil {
+			errs = fmt.Errorf("%s%w",
+				util.ErrorToString(errs), err)
+		}
+	}
+
+	if errs != nil {
+		errs = fmt.Errorf("%s  assets:\\%s",
+			prefix, util.ErrorToString(errs))
+	}
+	return
+}
@@ -0,0 +1,91 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package filter
+
+import (
+	"regexp"
+	"testing"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestRequire_AssetsCheck(t *testing.T) {
+	assets := []github_types.Asset{
+		{Name: "checksums.txt"},
+		{Name: "myapp_1.2.3_linux_amd64.tar.gz"},
+		{Name: "myapp_1.2.3_darwin_arm64.tar.gz"}}
+	// GIVEN a Require and the assets of a release
+	tests := map[string]struct {
+		require  *Require
+		assets   []github_types.Asset
+		errRegex string
+	}{
+		"nil require": {
+			require:  nil,
+			assets:   assets,
+			errRegex: "^$"},
+		"no assets required": {
+			require:  &Require{},
+			assets:   nil,
+			errRegex: "^$"},
+		"all assets found": {
+			require: &Require{
+				Assets: []string{
+					`myapp_{{ version }}_linux_amd64\.tar\.gz`,
+					`myapp_{{ version }}_darwin_arm64\.tar\.gz`,
+					`^checksums\.txt$`}},
+			assets:   assets,
+			errRegex: "^$"},
+		"asset missing": {
+			require: &Require{
+				Assets: []string{
+					`myapp_{{ version }}_linux_amd64\.tar\.gz`,
+					`myapp_{{ version }}_windows_amd64\.zip`}},
+			assets:   assets,
+			errRegex: `^asset "myapp_1.2.3_windows_amd64\\\\.zip" not found on the release of version "1.2.3"$`},
+		"asset of another version": {
+			require: &Require{
+				Assets: []string{`myapp_{{ version }}_linux_amd64\.tar\.gz`}},
+			assets: []github_types.Asset{
+				{Name: "myapp_1.2.2_linux_amd64.tar.gz"}},
+			errRegex: `^asset "[^"]+" not found on the release of version "1.2.3"$`},
+		"release without assets": {
+			require: &Require{
+				Assets: []string{`checksums\.txt`}},
+			assets:   nil,
+			errRegex: `^asset "checksums\\\\.txt" not found`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN AssetsCheck is called on it
+			err := tc.require.AssetsCheck("1.2.3", tc.assets, &util.LogFrom{})
+
+			// THEN the err is what we expect
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
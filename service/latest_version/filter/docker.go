@@ -30,7 +30,10 @@ import (
 )
 
 var dockerCheckTypes = []string{
-	"hub", "quay", "ghcr"}
+	"hub", "quay", "ghcr", "registry"}
+
+// platformRegex matches an os/arch[/variant] platform, e.g. linux/arm/v7.
+var platformRegex = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9_]+(/[a-z0-9]+)?$`)
 
 // DockerCheckRegistryBase is the base for checking a Docker registry for an image:tag.
 type DockerCheckRegistryBase struct {
@@ -157,11 +160,17 @@ func (d *DockerCheckDefaults) CheckValues(prefix string) (errs error) {
 // DockerCheck will verify that Tag exists for Image
 type DockerCheck struct {
 	Type                    string `yaml:"type,omitempty" json:"type,omitempty"`         // Type of the Docker registry
+	Registry                string `yaml:"registry,omitempty" json:"registry,omitempty"` // URL of the OCI distribution (v2) registry, for the "registry" type
 	Username                string `yaml:"username,omitempty" json:"username,omitempty"` // Username to get a new token
 	DockerCheckRegistryBase `yaml:",inline" json:",inline"`
 
-	Image string `yaml:"image,omitempty" json:"image,omitempty"` // Image to check
-	Tag   string `yaml:"tag,omitempty" json:"tag,omitempty"`     // Tag to check for
+	Image     string   `yaml:"image,omitempty" json:"image,omitempty"`         // Image to check
+	Tag       string   `yaml:"tag,omitempty" json:"tag,omitempty"`             // Tag to check for
+	Platforms []string `yaml:"platforms,omitempty" json:"platforms,omitempty"` // Platforms the Tag must have images for, e.g. linux/arm64
+
+	digest     string       // Digest of digestTag
+	digestTag  string       // Tag that digest was resolved for
+	httpClient *http.Client // Client for the registry token/tags requests (nil for the default client)
 
 	Defaults *DockerCheckDefaults `yaml:"-" json:"-"` // Default values for DockerCheck
 }
@@ -197,60 +206,47 @@ func (d *DockerCheck) String(prefix string) (str string) {
 	return
 }
 
-// DockerTagCheck will verify that Tag exists for Image and return an error if not.
+// DockerTagCheck will verify that Tag exists for Image (with all of the Platforms)
+// and return an error if not.
 func (r *Require) DockerTagCheck(
 	version string,
 ) error {
 	if r == nil || r.Docker == nil {
 		return nil
 	}
-	var url string
 	tag := r.Docker.GetTag(version)
-	var req *http.Request
-	queryToken, err := r.Docker.getQueryToken()
-	if err != nil {
-		return fmt.Errorf("%s:%s - %w",
-			r.Docker.Image, tag, err)
-	}
-	switch r.Docker.GetType() {
-	case "hub":
-		url = fmt.Sprintf("https://registry.hub.docker.com/v2/repositories/%s/tags/%s",
-			r.Docker.Image, tag)
-	case "ghcr":
-		url = fmt.Sprintf("https://ghcr.io/v2/%s/manifests/%s",
-			r.Docker.Image, tag)
-	case "quay":
-		url = fmt.Sprintf("https://quay.io/api/v1/repository/%s/tag/?onlyActiveTags=true&specificTag=%s",
-			r.Docker.Image, tag)
-	}
-	req, _ = http.NewRequest(http.MethodGet, url, nil)
-	if queryToken != "" {
-		req.Header.Set("Authorization", "Bearer "+queryToken)
-	}
-	req.Header.Set("Connection", "close")
 
-	// Do the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	manifest, err := r.Docker.getManifest(tag, len(r.Docker.Platforms) != 0)
+	if err == nil {
+		err = manifest.checkPlatforms(r.Docker.Platforms)
+	}
 	if err != nil {
 		return fmt.Errorf("%s:%s - %w",
 			r.Docker.Image, tag, err)
 	}
 
-	// Parse the body
-	defer resp.Body.Close()
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("%s:%s - %s",
-			r.Docker.Image, tag, string(body))
-	}
-	// Quay will give a 200 even when the tag doesn't exist
-	if r.Docker.GetType() == "quay" && strings.Contains(string(body), `"tags": []`) {
-		return fmt.Errorf("%s:%s - tag not found",
-			r.Docker.Image, tag)
+	r.Docker.mutex.Lock()
+	defer r.Docker.mutex.Unlock()
+	r.Docker.digest = manifest.Digest
+	r.Docker.digestTag = tag
+	return nil
+}
+
+// DockerDigest returns the digest that DockerTagCheck resolved for the tag of `version`.
+//
+// empty string if it hasn't been resolved.
+func (r *Require) DockerDigest(version string) string {
+	if r == nil || r.Docker == nil {
+		return ""
 	}
+	tag := r.Docker.GetTag(version)
 
-	return nil
+	r.Docker.mutex.RLock()
+	defer r.Docker.mutex.RUnlock()
+	if r.Docker.digestTag != tag {
+		return ""
+	}
+	return r.Docker.digest
 }
 
 // CheckValues of the DockerCheck.
@@ -284,6 +280,19 @@ func (d *DockerCheck) CheckValues(prefix string) (errs error) {
 		}
 	}
 
+	if d.GetType() == "registry" {
+		if d.Registry == "" {
+			errs = fmt.Errorf("%s%sregistry: <required> (URL of the registry to check)\\",
+				util.ErrorToString(errs), prefix)
+		} else if _, err := net_url.ParseRequestURI(d.registryURL()); err != nil {
+			errs = fmt.Errorf("%s%sregistry: %q <invalid> (invalid URL)\\",
+				util.ErrorToString(errs), prefix, d.Registry)
+		}
+	} else if d.Registry != "" {
+		errs = fmt.Errorf("%s%sregistry: %q <invalid> (only used by the \"registry\" type)\\",
+			util.ErrorToString(errs), prefix, d.Registry)
+	}
+
 	if d.Tag == "" {
 		errs = fmt.Errorf("%s%stag: <required> (tag of image to check for existence)",
 			util.ErrorToString(errs), prefix)
@@ -292,6 +301,11 @@ func (d *DockerCheck) CheckValues(prefix string) (errs error) {
 			util.ErrorToString(errs), prefix, d.Tag)
 	}
 
+	if err := checkPlatforms(d.Platforms, prefix); err != nil {
+		errs = fmt.Errorf("%s%w",
+			util.ErrorToString(errs), err)
+	}
+
 	if err := d.checkToken(); err != nil {
 		errs = fmt.Errorf("%s%s%w\\",
 			util.ErrorToString(errs), prefix, err)
@@ -300,6 +314,22 @@ func (d *DockerCheck) CheckValues(prefix string) (errs error) {
 	return
 }
 
+// checkPlatforms are os/arch[/variant].
+func checkPlatforms(platforms []string, prefix string) (errs error) {
+	for i, platform := range platforms {
+		if !platformRegex.MatchString(platform) {
+			errs = fmt.Errorf("%s%s  item_%d: %q <invalid> (expected os/arch[/variant], e.g. linux/arm64)\\",
+				util.ErrorToString(errs), prefix, i, platform)
+		}
+	}
+
+	if errs != nil {
+		errs = fmt.Errorf("%splatforms:\\%s",
+			prefix, util.ErrorToString(errs))
+	}
+	return
+}
+
 // checkToken is provided for registries that require one.
 func (d *DockerCheck) checkToken() (err error) {
 	if d == nil {
@@ -324,6 +354,15 @@ func (d *DockerCheck) checkToken() (err error) {
 	return
 }
 
+// registryURL returns the URL of Registry, defaulting the scheme to https.
+func (d *DockerCheck) registryURL() string {
+	registry := util.EvalEnvVars(d.Registry)
+	if registry != "" && !strings.Contains(registry, "://") {
+		registry = "https://" + registry
+	}
+	return strings.TrimSuffix(registry, "/")
+}
+
 // GetTag to search for on Image
 func (d *DockerCheck) GetTag(version string) string {
 	return util.TemplateString(d.Tag, util.ServiceInfo{LatestVersion: version})
@@ -605,6 +644,19 @@ func (d *DockerCheckDefaults) setQueryToken(dType, token *string, queryToken *st
 	d.defaults.setQueryToken(dType, token, queryToken, validUntil)
 }
 
+// SetHTTPClient to use for the registry token/tags requests, e.g. to allow invalid certs.
+func (d *DockerCheck) SetHTTPClient(client *http.Client) {
+	d.httpClient = client
+}
+
+// client returns the HTTP client to use for the registry token/tags requests.
+func (d *DockerCheck) client() *http.Client {
+	if d.httpClient != nil {
+		return d.httpClient
+	}
+	return &http.Client{}
+}
+
 // SetQueryToken and validUntil for this DockerCheck's type at the given token.
 func (d *DockerCheck) SetQueryToken(token, queryToken *string, validUntil *time.Time) {
 	if d == nil {
@@ -0,0 +1,315 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// manifestAccept is the Accept header for a manifest, preferring manifest lists.
+var manifestAccept = strings.Join([]string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json"},
+	", ")
+
+// dockerManifest is what was resolved for an image:tag.
+type dockerManifest struct {
+	Digest    string   // Digest of the manifest (list).
+	Platforms []string // Platforms of the images in the manifest (list), e.g. linux/arm/v7.
+	isList    bool     // Whether the manifest is a manifest list/image index.
+}
+
+// manifestPlatformJSON is the platform of an image in a manifest list/image index.
+type manifestPlatformJSON struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant"`
+}
+
+// String returns the platform as os/arch[/variant].
+func (p manifestPlatformJSON) String() string {
+	platform := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		platform += "/" + p.Variant
+	}
+	return platform
+}
+
+// manifestJSON is the format of a manifest list/image index, or of an image manifest.
+type manifestJSON struct {
+	Manifests []struct {
+		Digest   string                `json:"digest"`
+		Platform *manifestPlatformJSON `json:"platform"`
+	} `json:"manifests"`
+	Config *struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+}
+
+// hubTagJSON is the format of /v2/repositories/<name>/tags/<tag> on the Docker Hub API.
+type hubTagJSON struct {
+	Digest string                 `json:"digest"`
+	Images []manifestPlatformJSON `json:"images"`
+}
+
+// quayTagJSON is the format of /api/v1/repository/<name>/tag/?specificTag=<tag> on the Quay API.
+type quayTagJSON struct {
+	Tags []struct {
+		ManifestDigest string `json:"manifest_digest"`
+		IsManifestList bool   `json:"is_manifest_list"`
+	} `json:"tags"`
+}
+
+// quayManifestJSON is the format of /api/v1/repository/<name>/manifest/<digest> on the Quay API.
+type quayManifestJSON struct {
+	ManifestData string `json:"manifest_data"`
+}
+
+// getManifest resolves the digest (and platforms if wanted) of Image:tag.
+func (d *DockerCheck) getManifest(tag string, wantPlatforms bool) (manifest *dockerManifest, err error) {
+	switch d.GetType() {
+	case "hub":
+		return d.getManifestHub(tag)
+	case "quay":
+		return d.getManifestQuay(tag, wantPlatforms)
+	case "ghcr":
+		return d.getManifestRegistry("https://ghcr.io", tag, wantPlatforms)
+	default:
+		return d.getManifestRegistry(d.registryURL(), tag, wantPlatforms)
+	}
+}
+
+// getManifestHub resolves Image:tag on the Docker Hub API.
+func (d *DockerCheck) getManifestHub(tag string) (*dockerManifest, error) {
+	queryToken, err := d.getQueryToken()
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("https://registry.hub.docker.com/v2/repositories/%s/tags/%s",
+		d.Image, tag)
+	body, _, err := d.getManifestPage(url, "Bearer "+queryToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var tagJSON hubTagJSON
+	if err = json.Unmarshal(body, &tagJSON); err != nil {
+		return nil, fmt.Errorf("unmarshal of tag failed: %w", err)
+	}
+	manifest := &dockerManifest{
+		Digest: tagJSON.Digest}
+	for _, image := range tagJSON.Images {
+		manifest.Platforms = append(manifest.Platforms, image.String())
+	}
+	return manifest, nil
+}
+
+// getManifestQuay resolves Image:tag on the Quay API.
+func (d *DockerCheck) getManifestQuay(tag string, wantPlatforms bool) (*dockerManifest, error) {
+	queryToken, err := d.getQueryToken()
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("https://quay.io/api/v1/repository/%s/tag/?onlyActiveTags=true&specificTag=%s",
+		d.Image, tag)
+	body, _, err := d.getManifestPage(url, "Bearer "+queryToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var tagJSON quayTagJSON
+	if err = json.Unmarshal(body, &tagJSON); err != nil {
+		return nil, fmt.Errorf("unmarshal of tag failed: %w", err)
+	}
+	// Quay will give a 200 even when the tag doesn't exist
+	if len(tagJSON.Tags) == 0 {
+		return nil, fmt.Errorf("tag not found")
+	}
+	manifest := &dockerManifest{
+		Digest: tagJSON.Tags[0].ManifestDigest,
+		isList: tagJSON.Tags[0].IsManifestList}
+	if !wantPlatforms || !manifest.isList {
+		return manifest, nil
+	}
+
+	url = fmt.Sprintf("https://quay.io/api/v1/repository/%s/manifest/%s",
+		d.Image, manifest.Digest)
+	if body, _, err = d.getManifestPage(url, "Bearer "+queryToken, ""); err != nil {
+		return nil, err
+	}
+	var manifestData quayManifestJSON
+	if err = json.Unmarshal(body, &manifestData); err != nil {
+		return nil, fmt.Errorf("unmarshal of manifest failed: %w", err)
+	}
+	var list manifestJSON
+	if err = json.Unmarshal([]byte(manifestData.ManifestData), &list); err != nil {
+		return nil, fmt.Errorf("unmarshal of manifest list failed: %w", err)
+	}
+	manifest.Platforms = list.platforms()
+	return manifest, nil
+}
+
+// getManifestRegistry resolves Image:tag on the OCI distribution (v2) registry at registryURL.
+//
+// The platform of an image manifest is read from its config.
+func (d *DockerCheck) getManifestRegistry(registryURL string, tag string, wantPlatforms bool) (*dockerManifest, error) {
+	registryURL = strings.TrimSuffix(registryURL, "/")
+	if registryURL == "" {
+		return nil, fmt.Errorf("no registry to query")
+	}
+
+	queryToken, err := d.getQueryToken()
+	if err != nil {
+		return nil, err
+	}
+	var authorization string
+	if queryToken != "" {
+		authorization = "Bearer " + queryToken
+	}
+
+	url := fmt.Sprintf("%s/v2/%s/manifests/%s",
+		registryURL, d.Image, tag)
+	body, resp, err := d.getManifestPage(url, authorization, manifestAccept)
+	// Unauthorized, so answer the challenge and retry.
+	if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+		if authorization, err = d.registryAuthorization(resp.Header.Get("WWW-Authenticate")); err != nil {
+			return nil, err
+		}
+		body, resp, err = d.getManifestPage(url, authorization, manifestAccept)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var manifestData manifestJSON
+	if err = json.Unmarshal(body, &manifestData); err != nil {
+		return nil, fmt.Errorf("unmarshal of manifest failed: %w", err)
+	}
+	manifest := &dockerManifest{
+		Digest: resp.Header.Get("Docker-Content-Digest"),
+		isList: manifestData.Config == nil}
+	// Registries don't have to give the digest, so compute it.
+	if manifest.Digest == "" {
+		hash := sha256.Sum256(body)
+		manifest.Digest = "sha256:" + hex.EncodeToString(hash[:])
+	}
+	if !wantPlatforms {
+		return manifest, nil
+	}
+
+	if manifest.isList {
+		manifest.Platforms = manifestData.platforms()
+		return manifest, nil
+	}
+	// Image manifest, so get the platform from its config.
+	url = fmt.Sprintf("%s/v2/%s/blobs/%s",
+		registryURL, d.Image, manifestData.Config.Digest)
+	if body, _, err = d.getManifestPage(url, authorization, ""); err != nil {
+		return nil, err
+	}
+	var config manifestPlatformJSON
+	if err = json.Unmarshal(body, &config); err != nil {
+		return nil, fmt.Errorf("unmarshal of image config failed: %w", err)
+	}
+	manifest.Platforms = []string{config.String()}
+	return manifest, nil
+}
+
+// platforms of the images in the manifest list/image index.
+func (m *manifestJSON) platforms() (platforms []string) {
+	for _, image := range m.Manifests {
+		// e.g. attestations
+		if image.Platform == nil || image.Platform.OS == "unknown" {
+			continue
+		}
+		platforms = append(platforms, image.Platform.String())
+	}
+	return
+}
+
+// checkPlatforms returns an error listing the `platforms` that the manifest doesn't have.
+//
+// A platform without a variant matches any variant, e.g. linux/arm matches linux/arm/v7.
+func (m *dockerManifest) checkPlatforms(platforms []string) error {
+	if len(platforms) == 0 {
+		return nil
+	}
+	// Quay doesn't give the platform of an image manifest.
+	if !m.isList && len(m.Platforms) == 0 {
+		return fmt.Errorf("not a multi-platform image, so doesn't have the platforms %v",
+			platforms)
+	}
+
+	var missing []string
+	for _, platform := range platforms {
+		found := false
+		for _, have := range m.Platforms {
+			if have == platform || strings.HasPrefix(have, platform+"/") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, platform)
+		}
+	}
+	if len(missing) != 0 {
+		return fmt.Errorf("missing platforms %v (has %v)",
+			missing, m.Platforms)
+	}
+	return nil
+}
+
+// getManifestPage returns the body of a GET on url with the Authorization and Accept headers given.
+//
+// resp is returned on a 401 for the WWW-Authenticate header.
+func (d *DockerCheck) getManifestPage(url string, authorization string, accept string) (body []byte, resp *http.Response, err error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("manifest request, creation failed: %w", err)
+	}
+	if authorization != "" && authorization != "Bearer " {
+		req.Header.Set("Authorization", authorization)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	req.Header.Set("Connection", "close")
+
+	// Do the request
+	client := &http.Client{}
+	resp, err = client.Do(req)
+	if err != nil {
+		return nil, nil, err //nolint:wrapcheck
+	}
+
+	// Parse the body
+	defer resp.Body.Close()
+	body, _ = io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("%s", body)
+	}
+	return
+}
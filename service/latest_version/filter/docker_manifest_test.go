@@ -0,0 +1,217 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//go:build unit
+
+package filter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/release-argus/Argus/util"
+)
+
+// testManifestRegistry returns a fake OCI distribution registry serving the manifests of "team/app",
+// requiring a Bearer token.
+//
+// 1.0.0 = index of linux/amd64 + linux/arm64/v8 (+ an attestation)
+// 1.1.0 = index of linux/amd64
+// 1.2.0 = image manifest of linux/arm/v7, without a Docker-Content-Digest header
+func testManifestRegistry(t *testing.T) *httptest.Server {
+	manifests := map[string]string{
+		"1.0.0": `{
+			"mediaType":"application/vnd.oci.image.index.v1+json",
+			"manifests":[
+				{"digest":"sha256:a1","platform":{"architecture":"amd64","os":"linux"}},
+				{"digest":"sha256:a2","platform":{"architecture":"arm64","os":"linux","variant":"v8"}},
+				{"digest":"sha256:a3","platform":{"architecture":"unknown","os":"unknown"}}]}`,
+		"1.1.0": `{
+			"mediaType":"application/vnd.oci.image.index.v1+json",
+			"manifests":[
+				{"digest":"sha256:b1","platform":{"architecture":"amd64","os":"linux"}}]}`,
+		"1.2.0": `{
+			"mediaType":"application/vnd.oci.image.manifest.v1+json",
+			"config":{"digest":"sha256:c0"}}`}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			fmt.Fprint(w, `{"token":"query-token","expires_in":300}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer query-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`,
+				server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/team/app/manifests/1.0.0", "/v2/team/app/manifests/1.1.0":
+			tag := r.URL.Path[len("/v2/team/app/manifests/"):]
+			w.Header().Set("Docker-Content-Digest", "sha256:index-"+tag)
+			fmt.Fprint(w, manifests[tag])
+		case "/v2/team/app/manifests/1.2.0":
+			fmt.Fprint(w, manifests["1.2.0"])
+		case "/v2/team/app/blobs/sha256:c0":
+			fmt.Fprint(w, `{"architecture":"arm","os":"linux","variant":"v7"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRequire_DockerTagCheckRegistry(t *testing.T) {
+	// GIVEN a Require with a DockerCheck on a registry
+	manifest120 := sha256.Sum256([]byte(`{
+			"mediaType":"application/vnd.oci.image.manifest.v1+json",
+			"config":{"digest":"sha256:c0"}}`))
+	tests := map[string]struct {
+		tag        string
+		platforms  []string
+		wantDigest string
+		errRegex   string
+	}{
+		"tag exists": {
+			tag:        "{{ version }}",
+			wantDigest: "sha256:index-1.0.0",
+		},
+		"tag doesn't exist": {
+			tag:      "{{ version }}-beta",
+			errRegex: `^team/app:1.0.0-beta - {"errors":.*manifest unknown`,
+		},
+		"has all the platforms": {
+			tag:        "1.0.0",
+			platforms:  []string{"linux/amd64", "linux/arm64"},
+			wantDigest: "sha256:index-1.0.0",
+		},
+		"has the platform with that variant": {
+			tag:        "1.0.0",
+			platforms:  []string{"linux/arm64/v8"},
+			wantDigest: "sha256:index-1.0.0",
+		},
+		"doesn't have a platform": {
+			tag:       "1.1.0",
+			platforms: []string{"linux/amd64", "linux/arm64"},
+			errRegex:  `^team/app:1.1.0 - missing platforms \[linux/arm64\] \(has \[linux/amd64\]\)$`,
+		},
+		"image manifest has the platform from its config": {
+			tag:        "1.2.0",
+			platforms:  []string{"linux/arm"},
+			wantDigest: "sha256:" + hex.EncodeToString(manifest120[:]),
+		},
+		"image manifest doesn't have the platform": {
+			tag:       "1.2.0",
+			platforms: []string{"linux/amd64"},
+			errRegex:  `^team/app:1.2.0 - missing platforms \[linux/amd64\] \(has \[linux/arm/v7\]\)$`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require := Require{Docker: &DockerCheck{
+				Type:      "registry",
+				Registry:  testManifestRegistry(t).URL,
+				Image:     "team/app",
+				Tag:       tc.tag,
+				Platforms: tc.platforms}}
+
+			// WHEN DockerTagCheck is called on it
+			err := require.DockerTagCheck("1.0.0")
+
+			// THEN the err is expected
+			e := util.ErrorToString(err)
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the digest is resolved for that version
+			if got := require.DockerDigest("1.0.0"); got != tc.wantDigest {
+				t.Errorf("DockerDigest - want %q, got %q",
+					tc.wantDigest, got)
+			}
+		})
+	}
+}
+
+func TestDockerManifest_CheckPlatforms(t *testing.T) {
+	// GIVEN a dockerManifest
+	tests := map[string]struct {
+		manifest  dockerManifest
+		platforms []string
+		errRegex  string
+	}{
+		"no platforms wanted": {
+			manifest: dockerManifest{isList: true},
+		},
+		"exact matches": {
+			manifest:  dockerManifest{isList: true, Platforms: []string{"linux/amd64", "linux/arm/v7"}},
+			platforms: []string{"linux/amd64", "linux/arm/v7"},
+		},
+		"platform without a variant matches any variant": {
+			manifest:  dockerManifest{isList: true, Platforms: []string{"linux/arm/v6"}},
+			platforms: []string{"linux/arm"},
+		},
+		"platform with a variant needs that variant": {
+			manifest:  dockerManifest{isList: true, Platforms: []string{"linux/arm/v6"}},
+			platforms: []string{"linux/arm/v7"},
+			errRegex:  `^missing platforms \[linux/arm/v7\] \(has \[linux/arm/v6\]\)$`,
+		},
+		"arch isn't a prefix match": {
+			manifest:  dockerManifest{isList: true, Platforms: []string{"linux/arm64"}},
+			platforms: []string{"linux/arm"},
+			errRegex:  `^missing platforms \[linux/arm\]`,
+		},
+		"image manifest without a platform": {
+			manifest:  dockerManifest{},
+			platforms: []string{"linux/amd64"},
+			errRegex:  `^not a multi-platform image`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN checkPlatforms is called on it
+			err := tc.manifest.checkPlatforms(tc.platforms)
+
+			// THEN the err is expected
+			e := util.ErrorToString(err)
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
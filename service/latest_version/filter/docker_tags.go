@@ -0,0 +1,342 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	net_url "net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/release-argus/Argus/util"
+)
+
+// maxTagPages is the maximum number of pages of tags to fetch for an image.
+//
+// The pages are followed to the end, so this only guards against a registry
+// that never stops giving a next page.
+const maxTagPages = 1000
+
+var (
+	// challengeParamRegex matches the key="value" pairs of a WWW-Authenticate header.
+	challengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+	// linkNextRegex matches the URL of the next page in a Link header.
+	linkNextRegex = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+)
+
+// registryTagsJSON is the format of /v2/<name>/tags/list on an OCI distribution registry.
+type registryTagsJSON struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// hubTagsJSON is the format of /v2/repositories/<name>/tags on the Docker Hub API.
+type hubTagsJSON struct {
+	Next    string `json:"next"`
+	Results []struct {
+		Name string `json:"name"`
+	} `json:"results"`
+}
+
+// quayTagsJSON is the format of /api/v1/repository/<name>/tag/ on the Quay API.
+type quayTagsJSON struct {
+	HasAdditional bool `json:"has_additional"`
+	Tags          []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+}
+
+// registryTokenJSON is the format of a token from the realm of a WWW-Authenticate Bearer challenge.
+type registryTokenJSON struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// ListTags will return the tags of Image.
+//
+// registryURL is the OCI distribution (v2) registry to query when the Type isn't
+// hub/quay/ghcr, e.g. "https://registry.example.com" (defaulting to Registry).
+func (d *DockerCheck) ListTags(registryURL string, logFrom *util.LogFrom) (tags []string, err error) {
+	var complete bool
+	switch d.GetType() {
+	case "hub":
+		tags, complete, err = d.listTagsHub()
+	case "quay":
+		tags, complete, err = d.listTagsQuay()
+	case "ghcr":
+		tags, complete, err = d.listTagsRegistry("https://ghcr.io")
+	default:
+		if registryURL == "" {
+			registryURL = d.registryURL()
+		}
+		tags, complete, err = d.listTagsRegistry(registryURL)
+	}
+	if err != nil {
+		err = fmt.Errorf("%s - %w",
+			d.Image, err)
+		return
+	}
+
+	if !complete {
+		jLog.Warn(
+			fmt.Sprintf("%s - stopped listing tags after %d pages (%d tags), newer tags may have been missed",
+				d.Image, maxTagPages, len(tags)),
+			logFrom, true)
+	}
+	return
+}
+
+// listTagsHub lists the tags of Image on the Docker Hub API.
+//
+// complete is false if there were more than maxTagPages pages.
+func (d *DockerCheck) listTagsHub() (tags []string, complete bool, err error) {
+	queryToken, err := d.getQueryToken()
+	if err != nil {
+		return
+	}
+
+	url := fmt.Sprintf("https://registry.hub.docker.com/v2/repositories/%s/tags?page_size=100",
+		d.Image)
+	for page := 0; url != "" && page < maxTagPages; page++ {
+		var body []byte
+		if body, _, err = d.getTagsPage(url, "Bearer "+queryToken); err != nil {
+			return
+		}
+
+		var pageJSON hubTagsJSON
+		if err = json.Unmarshal(body, &pageJSON); err != nil {
+			return nil, false, fmt.Errorf("unmarshal of tags failed: %w", err)
+		}
+		for _, result := range pageJSON.Results {
+			tags = append(tags, result.Name)
+		}
+		url = pageJSON.Next
+	}
+	complete = url == ""
+	return
+}
+
+// listTagsQuay lists the tags of Image on the Quay API.
+//
+// complete is false if there were more than maxTagPages pages.
+func (d *DockerCheck) listTagsQuay() (tags []string, complete bool, err error) {
+	queryToken, err := d.getQueryToken()
+	if err != nil {
+		return
+	}
+
+	for page := 1; page <= maxTagPages; page++ {
+		url := fmt.Sprintf("https://quay.io/api/v1/repository/%s/tag/?onlyActiveTags=true&limit=100&page=%d",
+			d.Image, page)
+		var body []byte
+		if body, _, err = d.getTagsPage(url, "Bearer "+queryToken); err != nil {
+			return
+		}
+
+		var pageJSON quayTagsJSON
+		if err = json.Unmarshal(body, &pageJSON); err != nil {
+			return nil, false, fmt.Errorf("unmarshal of tags failed: %w", err)
+		}
+		for _, tag := range pageJSON.Tags {
+			tags = append(tags, tag.Name)
+		}
+		if !pageJSON.HasAdditional {
+			complete = true
+			break
+		}
+	}
+	return
+}
+
+// listTagsRegistry lists the tags of Image on the OCI distribution (v2) registry at registryURL.
+//
+// Authorization is negotiated from the WWW-Authenticate challenge of the registry,
+// using Username/Token for Basic auth, or to get a Bearer token from the realm.
+// The pages are followed with the Link header, and complete is false if there
+// were more than maxTagPages pages.
+func (d *DockerCheck) listTagsRegistry(registryURL string) (tags []string, complete bool, err error) {
+	registryURL = strings.TrimSuffix(registryURL, "/")
+	if registryURL == "" {
+		return nil, false, fmt.Errorf("no registry to query")
+	}
+
+	queryToken, err := d.getQueryToken()
+	if err != nil {
+		return
+	}
+	var authorization string
+	if queryToken != "" {
+		authorization = "Bearer " + queryToken
+	}
+
+	url := fmt.Sprintf("%s/v2/%s/tags/list?n=1000",
+		registryURL, d.Image)
+	for page := 0; url != "" && page < maxTagPages; page++ {
+		var (
+			body []byte
+			resp *http.Response
+		)
+		body, resp, err = d.getTagsPage(url, authorization)
+		// Unauthorized, so answer the challenge and retry.
+		if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+			if authorization, err = d.registryAuthorization(resp.Header.Get("WWW-Authenticate")); err != nil {
+				return
+			}
+			body, resp, err = d.getTagsPage(url, authorization)
+		}
+		if err != nil {
+			return
+		}
+
+		var pageJSON registryTagsJSON
+		if err = json.Unmarshal(body, &pageJSON); err != nil {
+			return nil, false, fmt.Errorf("unmarshal of tags failed: %w", err)
+		}
+		tags = append(tags, pageJSON.Tags...)
+
+		// Next page
+		url = ""
+		if match := linkNextRegex.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+			url = registryURL + match[1]
+			if strings.Contains(match[1], "://") {
+				url = match[1]
+			}
+		}
+	}
+	complete = url == ""
+	return
+}
+
+// registryAuthorization returns the Authorization header value that answers the
+// WWW-Authenticate `challenge`.
+func (d *DockerCheck) registryAuthorization(challenge string) (authorization string, err error) {
+	username := d.getUsername()
+	token := d.getToken()
+
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if token == "" {
+			return "", fmt.Errorf("registry requires basic auth, but no token was given")
+		}
+		req := http.Request{Header: http.Header{}}
+		req.SetBasicAuth(username, token)
+		authorization = req.Header.Get("Authorization")
+	case "bearer":
+		var queryToken string
+		if queryToken, err = d.refreshRegistryToken(params); err != nil {
+			return
+		}
+		authorization = "Bearer " + queryToken
+	default:
+		err = fmt.Errorf("unsupported auth challenge %q", challenge)
+	}
+	return
+}
+
+// refreshRegistryToken gets a new token from the realm of a WWW-Authenticate Bearer challenge.
+func (d *DockerCheck) refreshRegistryToken(challengeParams string) (queryToken string, err error) {
+	params := map[string]string{}
+	for _, match := range challengeParamRegex.FindAllStringSubmatch(challengeParams, -1) {
+		params[match[1]] = match[2]
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("no realm in the auth challenge")
+	}
+
+	query := net_url.Values{}
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", d.Image)
+	}
+	query.Set("scope", scope)
+	req, err := http.NewRequest(http.MethodGet, params["realm"]+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("registry token request, creation failed: %w", err)
+	}
+	req.Header.Set("Connection", "close")
+	token := d.getToken()
+	if token != "" {
+		req.SetBasicAuth(d.getUsername(), token)
+	}
+
+	// Do the request
+	resp, err := d.client().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("registry token refresh fail: %w", err)
+	}
+
+	// Parse the body
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry token request failed: %s", body)
+	}
+	var tokenJSON registryTokenJSON
+	if err = json.Unmarshal(body, &tokenJSON); err != nil {
+		return "", fmt.Errorf("unmarshal of registry token failed: %w", err)
+	}
+
+	queryToken = tokenJSON.Token
+	if queryToken == "" {
+		queryToken = tokenJSON.AccessToken
+	}
+	validFor := time.Duration(tokenJSON.ExpiresIn) * time.Second
+	if validFor == 0 {
+		validFor = time.Minute
+	}
+	validUntil := time.Now().UTC().Add(validFor)
+	// Give the Token/ValidUntil to this struct
+	// and to the source of the Token
+	d.SetQueryToken(&token, &queryToken, &validUntil)
+	return
+}
+
+// getTagsPage returns the body of a GET on url with the Authorization header given.
+//
+// resp is returned on a 401 for the WWW-Authenticate header.
+func (d *DockerCheck) getTagsPage(url string, authorization string) (body []byte, resp *http.Response, err error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("tags request, creation failed: %w", err)
+	}
+	if authorization != "" && authorization != "Bearer " {
+		req.Header.Set("Authorization", authorization)
+	}
+	req.Header.Set("Connection", "close")
+
+	// Do the request
+	resp, err = d.client().Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("tags request fail: %w", err)
+	}
+
+	// Parse the body
+	defer resp.Body.Close()
+	body, _ = io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("tags request failed: %s %s",
+			resp.Status, strings.TrimSpace(string(body)))
+	}
+	return
+}
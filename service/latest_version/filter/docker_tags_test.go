@@ -0,0 +1,250 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package filter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/release-argus/Argus/util"
+)
+
+// testRegistry returns a fake OCI distribution registry serving the tags of "team/app"
+// with the `auth` challenge ("", "basic" or "bearer").
+func testRegistry(t *testing.T, auth string) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			if user, pass, _ := r.BasicAuth(); user != "user" || pass != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"errors":[{"code":"UNAUTHORIZED"}]}`)
+				return
+			}
+			if r.URL.Query().Get("scope") != "repository:team/app:pull" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			fmt.Fprint(w, `{"token":"query-token","expires_in":300}`)
+			return
+		case "/v2/team/app/tags/list":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[{"code":"NAME_UNKNOWN"}]}`)
+			return
+		}
+
+		// Auth
+		switch auth {
+		case "basic":
+			if user, pass, _ := r.BasicAuth(); user != "user" || pass != "pass" {
+				w.Header().Set("WWW-Authenticate", `Basic realm="Registry"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		case "bearer":
+			if r.Header.Get("Authorization") != "Bearer query-token" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:team/app:pull"`,
+					server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+
+		// Paginate
+		if r.URL.Query().Get("last") == "" {
+			w.Header().Set("Link", `</v2/team/app/tags/list?last=1.1.0&n=2>; rel="next"`)
+			fmt.Fprint(w, `{"name":"team/app","tags":["1.0.0","1.1.0"]}`)
+			return
+		}
+		fmt.Fprint(w, `{"name":"team/app","tags":["1.2.0","latest"]}`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDockerCheck_ListTags(t *testing.T) {
+	// GIVEN a DockerCheck on a registry
+	tests := map[string]struct {
+		auth, image       string
+		username, token   string
+		noRegistry        bool
+		want              []string
+		wantQueryTokenSet bool
+		errRegex          string
+	}{
+		"anonymous": {
+			image: "team/app",
+			want:  []string{"1.0.0", "1.1.0", "1.2.0", "latest"},
+		},
+		"basic auth": {
+			auth:  "basic",
+			image: "team/app", username: "user", token: "pass",
+			want: []string{"1.0.0", "1.1.0", "1.2.0", "latest"},
+		},
+		"basic auth without a token": {
+			auth:     "basic",
+			image:    "team/app",
+			errRegex: `^team/app - registry requires basic auth, but no token was given$`,
+		},
+		"basic auth with invalid credentials": {
+			auth:  "basic",
+			image: "team/app", username: "user", token: "invalid",
+			errRegex: `^team/app - tags request failed: 401 Unauthorized`,
+		},
+		"bearer token": {
+			auth:  "bearer",
+			image: "team/app", username: "user", token: "pass",
+			want:              []string{"1.0.0", "1.1.0", "1.2.0", "latest"},
+			wantQueryTokenSet: true,
+		},
+		"bearer token with invalid credentials": {
+			auth:  "bearer",
+			image: "team/app", username: "user", token: "invalid",
+			errRegex: `^team/app - registry token request failed: {"errors"`,
+		},
+		"unknown image": {
+			image:    "team/unknown",
+			errRegex: `^team/unknown - tags request failed: 404 Not Found {"errors":\[{"code":"NAME_UNKNOWN"}\]}$`,
+		},
+		"no registry": {
+			image:      "team/app",
+			noRegistry: true,
+			errRegex:   `^team/app - no registry to query$`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			registryURL := testRegistry(t, tc.auth).URL
+			if tc.noRegistry {
+				registryURL = ""
+			}
+			dockerCheck := NewDockerCheck(
+				"", tc.image, "", tc.username, tc.token, "", time.Time{}, nil)
+
+			// WHEN ListTags is called on it
+			got, err := dockerCheck.ListTags(registryURL, &util.LogFrom{})
+
+			// THEN the err is expected
+			e := util.ErrorToString(err)
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the tags are returned
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("want: %v\ngot:  %v",
+					tc.want, got)
+			}
+			// AND the queryToken is stored if one was fetched
+			queryToken, validUntil := dockerCheck.CopyQueryToken()
+			if tc.wantQueryTokenSet {
+				if queryToken != "query-token" {
+					t.Errorf("queryToken not stored, got %q", queryToken)
+				}
+				if validUntil.Before(time.Now().Add(4 * time.Minute)) {
+					t.Errorf("validUntil should be ~5m from now, got %v", validUntil)
+				}
+			} else if queryToken != "" {
+				t.Errorf("queryToken should not be set, got %q", queryToken)
+			}
+		})
+	}
+}
+
+func TestDockerCheck_ListTagsReusesQueryToken(t *testing.T) {
+	// GIVEN a DockerCheck on a registry that needs a Bearer token
+	var tokenRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenRequests++
+			fmt.Fprint(w, `{"access_token":"query-token"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer query-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="registry"`,
+				r.Host))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"name":"team/app","tags":["1.0.0"]}`)
+	}))
+	defer server.Close()
+	dockerCheck := NewDockerCheck(
+		"", "team/app", "", "", "", "", time.Time{}, nil)
+
+	// WHEN ListTags is called on it twice
+	for i := 0; i < 2; i++ {
+		got, err := dockerCheck.ListTags(server.URL, &util.LogFrom{})
+		if err != nil || len(got) != 1 {
+			t.Fatalf("ListTags #%d failed: %v, %v",
+				i, got, err)
+		}
+	}
+
+	// THEN the token was only requested once
+	if tokenRequests != 1 {
+		t.Errorf("want 1 token request, got %d",
+			tokenRequests)
+	}
+}
+
+func TestDockerCheck_ListTagsFollowsAllPages(t *testing.T) {
+	// GIVEN a DockerCheck on a registry with many pages of tags
+	pages := 25
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var page int
+		fmt.Sscanf(r.URL.Query().Get("last"), "%d.0.0", &page)
+		if page+1 < pages {
+			w.Header().Set("Link", fmt.Sprintf(`</v2/team/app/tags/list?last=%d.0.0&n=1>; rel="next"`,
+				page+1))
+		}
+		fmt.Fprintf(w, `{"name":"team/app","tags":["%d.0.0"]}`, page)
+	}))
+	defer server.Close()
+	dockerCheck := NewDockerCheck(
+		"", "team/app", "", "", "", "", time.Time{}, nil)
+
+	// WHEN ListTags is called on it
+	got, err := dockerCheck.ListTags(server.URL, &util.LogFrom{})
+
+	// THEN the tags of every page are returned
+	if err != nil {
+		t.Fatalf("ListTags failed: %v", err)
+	}
+	if len(got) != pages {
+		t.Fatalf("want %d tags, got %d: %v",
+			pages, len(got), got)
+	}
+	if last := fmt.Sprintf("%d.0.0", pages-1); got[len(got)-1] != last {
+		t.Errorf("want the last tag to be %q, not %q",
+			last, got[len(got)-1])
+	}
+}
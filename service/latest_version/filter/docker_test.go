@@ -1820,6 +1820,53 @@ func TestDockerCheck_CheckValues(t *testing.T) {
 				"1.2.3",
 				"", "", "", time.Now(), nil),
 		},
+		"registry type with a registry": {
+			errRegex: "^$",
+			dockerCheck: &DockerCheck{
+				Type:     "registry",
+				Registry: "registry.example.com:5000",
+				Image:    "team/app",
+				Tag:      "1.2.3"},
+		},
+		"registry type without a registry": {
+			errRegex: `^-registry: <required>`,
+			dockerCheck: &DockerCheck{
+				Type:  "registry",
+				Image: "team/app",
+				Tag:   "1.2.3"},
+		},
+		"registry type with an invalid registry": {
+			errRegex: `^-registry: "https://registry example.com" <invalid>`,
+			dockerCheck: &DockerCheck{
+				Type:     "registry",
+				Registry: "https://registry example.com",
+				Image:    "team/app",
+				Tag:      "1.2.3"},
+		},
+		"registry on another type": {
+			errRegex: `^-registry: "registry.example.com" <invalid> \(only used by the "registry" type\)`,
+			dockerCheck: &DockerCheck{
+				Type:     "ghcr",
+				Registry: "registry.example.com",
+				Image:    "team/app",
+				Tag:      "1.2.3"},
+		},
+		"valid platforms": {
+			errRegex: "^$",
+			dockerCheck: &DockerCheck{
+				Type:      "ghcr",
+				Image:     "release-argus/argus",
+				Tag:       "1.2.3",
+				Platforms: []string{"linux/amd64", "linux/arm/v7"}},
+		},
+		"invalid platforms": {
+			errRegex: `^-platforms:\\-  item_1: "arm64" <invalid>.*\\-  item_2: "Linux/amd64" <invalid>.*\\$`,
+			dockerCheck: &DockerCheck{
+				Type:      "ghcr",
+				Image:     "release-argus/argus",
+				Tag:       "1.2.3",
+				Platforms: []string{"linux/amd64", "arm64", "Linux/amd64"}},
+		},
 	}
 
 	for name, tc := range tests {
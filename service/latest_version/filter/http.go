@@ -0,0 +1,298 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	net_url "net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/release-argus/Argus/util"
+)
+
+var httpCheckMethods = []string{
+	"HEAD", "GET"}
+
+// HTTPCheck will verify that a URL of the version responds as expected,
+// e.g. that the download of the release exists.
+type HTTPCheck struct {
+	URL               string         `yaml:"url,omitempty" json:"url,omitempty"`                                 // "https://example.com/myapp_{{ version }}.tar.gz" URL to request
+	Method            string         `yaml:"method,omitempty" json:"method,omitempty"`                           // HEAD (default) or GET
+	AllowInvalidCerts bool           `yaml:"allow_invalid_certs,omitempty" json:"allow_invalid_certs,omitempty"` // Allow invalid HTTPS certificates
+	BasicAuth         *HTTPBasicAuth `yaml:"basic_auth,omitempty" json:"basic_auth,omitempty"`                   // Basic Auth credentials
+	Headers           []HTTPHeader   `yaml:"headers,omitempty" json:"headers,omitempty"`                         // Request Headers
+	StatusCode        int            `yaml:"status_code,omitempty" json:"status_code,omitempty"`                 // Status code the response must have (default 200)
+	ContentType       string         `yaml:"content_type,omitempty" json:"content_type,omitempty"`               // RegEx the Content-Type of the response must match
+	MinSize           int64          `yaml:"min_size,omitempty" json:"min_size,omitempty"`                       // Minimum size (in bytes) of the response body
+	MaxSize           int64          `yaml:"max_size,omitempty" json:"max_size,omitempty"`                       // Maximum size (in bytes) of the response body
+}
+
+// HTTPBasicAuth to use on the HTTP(s) request.
+type HTTPBasicAuth struct {
+	Username string `yaml:"username" json:"username"`
+	Password string `yaml:"password" json:"password"`
+}
+
+// HTTPHeader to use in the HTTP request.
+type HTTPHeader struct {
+	Key   string `yaml:"key" json:"key"`     // Header key, e.g. X-Sig
+	Value string `yaml:"value" json:"value"` // Value to give the key
+}
+
+// String returns a string representation of the HTTPCheck.
+func (h *HTTPCheck) String(prefix string) (str string) {
+	if h != nil {
+		str = util.ToYAMLString(h, prefix)
+	}
+	return
+}
+
+// CheckValues of the HTTPCheck.
+func (h *HTTPCheck) CheckValues(prefix string) (errs error) {
+	if h == nil {
+		return
+	}
+
+	// URL
+	if h.URL == "" {
+		errs = fmt.Errorf("%s%surl: <required> (URL to request)\\",
+			util.ErrorToString(errs), prefix)
+	} else if !util.CheckTemplate(h.URL) {
+		errs = fmt.Errorf("%s%surl: %q <invalid> (didn't pass templating)\\",
+			util.ErrorToString(errs), prefix, h.URL)
+	}
+
+	// Method
+	if h.Method != "" {
+		h.Method = strings.ToUpper(h.Method)
+		if !util.Contains(httpCheckMethods, h.Method) {
+			errs = fmt.Errorf("%s%smethod: %q <invalid> (supported methods = [%s])\\",
+				util.ErrorToString(errs), prefix, h.Method, strings.Join(httpCheckMethods, ", "))
+		}
+	}
+
+	// Headers
+	var headerErrs error
+	for i, header := range h.Headers {
+		if header.Key == "" {
+			headerErrs = fmt.Errorf("%s%s  item_%d:\\%s    key: <required> (header key)\\",
+				util.ErrorToString(headerErrs), prefix, i, prefix)
+		} else if !util.CheckTemplate(header.Value) {
+			headerErrs = fmt.Errorf("%s%s  item_%d:\\%s    value: %q <invalid> (didn't pass templating)\\",
+				util.ErrorToString(headerErrs), prefix, i, prefix, header.Value)
+		}
+	}
+	if headerErrs != nil {
+		errs = fmt.Errorf("%s%sheaders:\\%w",
+			util.ErrorToString(errs), prefix, headerErrs)
+	}
+
+	// Status code
+	if h.StatusCode != 0 && (h.StatusCode < 100 || h.StatusCode > 599) {
+		errs = fmt.Errorf("%s%sstatus_code: %d <invalid> (HTTP status codes are 100-599)\\",
+			util.ErrorToString(errs), prefix, h.StatusCode)
+	}
+
+	// Content-Type
+	if h.ContentType != "" {
+		if _, err := regexp.Compile(h.ContentType); err != nil {
+			errs = fmt.Errorf("%s%scontent_type: %q <invalid> (Invalid RegEx)\\",
+				util.ErrorToString(errs), prefix, h.ContentType)
+		}
+	}
+
+	// Size
+	if h.MinSize < 0 {
+		errs = fmt.Errorf("%s%smin_size: %d <invalid> (can't be negative)\\",
+			util.ErrorToString(errs), prefix, h.MinSize)
+	}
+	if h.MaxSize < 0 {
+		errs = fmt.Errorf("%s%smax_size: %d <invalid> (can't be negative)\\",
+			util.ErrorToString(errs), prefix, h.MaxSize)
+	} else if h.MaxSize != 0 && h.MaxSize < h.MinSize {
+		errs = fmt.Errorf("%s%smax_size: %d <invalid> (less than the min_size of %d)\\",
+			util.ErrorToString(errs), prefix, h.MaxSize, h.MinSize)
+	}
+
+	return
+}
+
+// GiveSecrets from `oldHTTP` to the password and header values that reference them with "<secret>".
+//
+// A header value is only given the old value of the header with the same key at the same index.
+func (h *HTTPCheck) GiveSecrets(oldHTTP *HTTPCheck) {
+	if h == nil || oldHTTP == nil {
+		return
+	}
+
+	if h.BasicAuth != nil && h.BasicAuth.Password == "<secret>" &&
+		oldHTTP.BasicAuth != nil {
+		h.BasicAuth.Password = oldHTTP.BasicAuth.Password
+	}
+
+	for i := range h.Headers {
+		if h.Headers[i].Value == "<secret>" &&
+			i < len(oldHTTP.Headers) && oldHTTP.Headers[i].Key == h.Headers[i].Key {
+			h.Headers[i].Value = oldHTTP.Headers[i].Value
+		}
+	}
+}
+
+// GetMethod to use for the request.
+func (h *HTTPCheck) GetMethod() string {
+	return util.FirstNonDefault(h.Method, "HEAD")
+}
+
+// GetStatusCode the response must have.
+func (h *HTTPCheck) GetStatusCode() int {
+	return util.FirstNonDefault(h.StatusCode, http.StatusOK)
+}
+
+// GetURL for the version.
+func (h *HTTPCheck) GetURL(version string) string {
+	return util.TemplateString(
+		util.EvalEnvVars(h.URL),
+		util.ServiceInfo{LatestVersion: version})
+}
+
+// HTTPResponseCheck will request the HTTP URL of `version` and return an error
+// if the response doesn't have the expected status code, content-type and size.
+func (r *Require) HTTPResponseCheck(version string, logFrom *util.LogFrom) error {
+	if r == nil || r.HTTP == nil {
+		return nil
+	}
+
+	url := r.HTTP.GetURL(version)
+	method := r.HTTP.GetMethod()
+	err := r.HTTP.check(url, version)
+	if err != nil {
+		err = fmt.Errorf("%s %s - %w",
+			method, url, err)
+		jLog.Warn(err, logFrom, true)
+		return err
+	}
+
+	jLog.Info(
+		fmt.Sprintf("%s %s - got the expected response for version %q",
+			method, url, version),
+		logFrom, true)
+	return nil
+}
+
+// check will do the request to `url` and verify the response.
+func (h *HTTPCheck) check(url string, version string) error {
+	req, err := http.NewRequest(h.GetMethod(), url, nil)
+	if err != nil {
+		return fmt.Errorf("request creation failed: %w", err)
+	}
+	// Set headers
+	req.Header.Set("Connection", "close")
+	serviceInfo := util.ServiceInfo{LatestVersion: version}
+	for _, header := range h.Headers {
+		req.Header.Set(
+			util.EvalEnvVars(header.Key),
+			util.TemplateString(util.EvalEnvVars(header.Value), serviceInfo))
+	}
+	// Basic auth
+	if h.BasicAuth != nil {
+		req.SetBasicAuth(util.EvalEnvVars(h.BasicAuth.Username), util.EvalEnvVars(h.BasicAuth.Password))
+	}
+
+	// HTTPS insecure skip verify.
+	customTransport := &http.Transport{}
+	if h.AllowInvalidCerts {
+		customTransport = http.DefaultTransport.(*http.Transport).Clone()
+		//#nosec G402 -- explicitly wanted InsecureSkipVerify
+		customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	// Do the request
+	client := &http.Client{Transport: customTransport}
+	resp, err := client.Do(req)
+	if err != nil {
+		// Don't print the method and URL again.
+		var urlErr *net_url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return err //nolint:wrapcheck
+	}
+	defer resp.Body.Close()
+
+	// Status code
+	if resp.StatusCode != h.GetStatusCode() {
+		return fmt.Errorf("got status code %d, want %d",
+			resp.StatusCode, h.GetStatusCode())
+	}
+
+	// Content-Type
+	if h.ContentType != "" {
+		contentType := resp.Header.Get("Content-Type")
+		if !regexp.MustCompile(h.ContentType).MatchString(contentType) {
+			return fmt.Errorf("got Content-Type %q, want a match for %q",
+				contentType, h.ContentType)
+		}
+	}
+
+	// Size
+	if h.MinSize != 0 || h.MaxSize != 0 {
+		size, err := h.responseSize(resp)
+		if err != nil {
+			return err
+		}
+		if size < h.MinSize {
+			return fmt.Errorf("got a size of %d bytes, want at least %d",
+				size, h.MinSize)
+		}
+		if h.MaxSize != 0 && size > h.MaxSize {
+			return fmt.Errorf("got a size of %d bytes, want at most %d",
+				size, h.MaxSize)
+		}
+	}
+
+	return nil
+}
+
+// responseSize returns the size of the body of `resp`.
+//
+// The Content-Length is used if given, otherwise the body of a GET is read (up to just past the max_size).
+func (h *HTTPCheck) responseSize(resp *http.Response) (int64, error) {
+	if contentLength := resp.Header.Get("Content-Length"); contentLength != "" {
+		size, err := strconv.ParseInt(contentLength, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid Content-Length %q", contentLength)
+		}
+		return size, nil
+	}
+	if resp.Request.Method == http.MethodHead {
+		return 0, fmt.Errorf("no Content-Length to check the size with")
+	}
+
+	var reader io.Reader = resp.Body
+	if h.MaxSize != 0 {
+		reader = io.LimitReader(resp.Body, h.MaxSize+1)
+	}
+	size, err := io.Copy(io.Discard, reader)
+	if err != nil {
+		return 0, fmt.Errorf("reading the body failed: %w", err)
+	}
+	return size, nil
+}
@@ -0,0 +1,344 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//go:build unit
+
+package filter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/release-argus/Argus/util"
+)
+
+func TestHTTPCheck_CheckValues(t *testing.T) {
+	// GIVEN a HTTPCheck
+	tests := map[string]struct {
+		httpCheck  *HTTPCheck
+		wantMethod string
+		errRegex   []string
+	}{
+		"nil HTTPCheck": {
+			errRegex: []string{`^$`},
+		},
+		"valid": {
+			httpCheck: &HTTPCheck{
+				URL:         "https://example.com/myapp_{{ version }}.tar.gz",
+				Method:      "get",
+				Headers:     []HTTPHeader{{Key: "X-Version", Value: "{{ version }}"}},
+				StatusCode:  204,
+				ContentType: "^application/(gzip|octet-stream)$",
+				MinSize:     1,
+				MaxSize:     2},
+			wantMethod: "GET",
+			errRegex:   []string{`^$`},
+		},
+		"no url": {
+			httpCheck: &HTTPCheck{},
+			errRegex: []string{
+				`^url: <required>`},
+		},
+		"invalid url templating": {
+			httpCheck: &HTTPCheck{
+				URL: "https://example.com/{{ version }"},
+			errRegex: []string{
+				`^url: "[^"]+" <invalid> \(didn't pass templating\)$`},
+		},
+		"invalid method": {
+			httpCheck: &HTTPCheck{
+				URL:    "https://example.com",
+				Method: "post"},
+			errRegex: []string{
+				`^method: "POST" <invalid> \(supported methods = \[HEAD, GET\]\)$`},
+		},
+		"invalid headers": {
+			httpCheck: &HTTPCheck{
+				URL: "https://example.com",
+				Headers: []HTTPHeader{
+					{Key: "X-Fine", Value: "fine"},
+					{Value: "no key"},
+					{Key: "X-Version", Value: "{{ version }"}}},
+			errRegex: []string{
+				`^headers:$`,
+				`^  item_1:$`,
+				`^    key: <required>`,
+				`^  item_2:$`,
+				`^    value: "{{ version }" <invalid> \(didn't pass templating\)$`},
+		},
+		"invalid status_code": {
+			httpCheck: &HTTPCheck{
+				URL:        "https://example.com",
+				StatusCode: 99},
+			errRegex: []string{
+				`^status_code: 99 <invalid>`},
+		},
+		"invalid content_type": {
+			httpCheck: &HTTPCheck{
+				URL:         "https://example.com",
+				ContentType: "[0-"},
+			errRegex: []string{
+				`^content_type: "\[0-" <invalid> \(Invalid RegEx\)$`},
+		},
+		"negative sizes": {
+			httpCheck: &HTTPCheck{
+				URL:     "https://example.com",
+				MinSize: -1,
+				MaxSize: -1},
+			errRegex: []string{
+				`^min_size: -1 <invalid>`,
+				`^max_size: -1 <invalid>`},
+		},
+		"max_size less than min_size": {
+			httpCheck: &HTTPCheck{
+				URL:     "https://example.com",
+				MinSize: 10,
+				MaxSize: 5},
+			errRegex: []string{
+				`^max_size: 5 <invalid> \(less than the min_size of 10\)$`},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN CheckValues is called on it
+			err := tc.httpCheck.CheckValues("")
+
+			// THEN the err is what we expect
+			e := util.ErrorToString(err)
+			lines := strings.Split(e, `\`)
+			for i := range tc.errRegex {
+				re := regexp.MustCompile(tc.errRegex[i])
+				found := false
+				for j := range lines {
+					match := re.MatchString(lines[j])
+					if match {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Fatalf("want match for: %q\ngot:  %q",
+						tc.errRegex[i], strings.ReplaceAll(e, `\`, "\n"))
+				}
+			}
+			// AND the method is uppercased
+			if tc.wantMethod != "" && tc.httpCheck.Method != tc.wantMethod {
+				t.Errorf("want Method %q, got %q",
+					tc.wantMethod, tc.httpCheck.Method)
+			}
+		})
+	}
+}
+
+func TestRequire_HTTPResponseCheck(t *testing.T) {
+	// GIVEN a Require with a HTTPCheck and a server that has the 1.2.3 download
+	body := strings.Repeat("a", 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/myapp_1.2.3.tar.gz":
+			w.Header().Set("Content-Type", "application/gzip")
+			fmt.Fprint(w, body)
+		case "/chunked/myapp_1.2.3.tar.gz":
+			w.Header().Set("Content-Type", "application/gzip")
+			w.(http.Flusher).Flush()
+			fmt.Fprint(w, body)
+		case "/private/myapp_1.2.3.tar.gz":
+			if user, pass, _ := r.BasicAuth(); user != "user" || pass != "pass" ||
+				r.Header.Get("X-Version") != "1.2.3" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	tests := map[string]struct {
+		httpCheck *HTTPCheck
+		version   string
+		errRegex  string
+	}{
+		"nil HTTPCheck": {},
+		"download exists": {
+			httpCheck: &HTTPCheck{
+				URL: server.URL + "/myapp_{{ version }}.tar.gz"},
+		},
+		"download doesn't exist yet": {
+			httpCheck: &HTTPCheck{
+				URL: server.URL + "/myapp_{{ version }}.tar.gz"},
+			version:  "1.2.4",
+			errRegex: `^HEAD http://[^ ]+/myapp_1.2.4.tar.gz - got status code 404, want 200$`,
+		},
+		"expected status code": {
+			httpCheck: &HTTPCheck{
+				URL:        server.URL + "/myapp_{{ version }}.tar.gz",
+				StatusCode: 404},
+			version: "1.2.4",
+		},
+		"basic auth and headers": {
+			httpCheck: &HTTPCheck{
+				URL:       server.URL + "/private/myapp_{{ version }}.tar.gz",
+				Method:    "GET",
+				BasicAuth: &HTTPBasicAuth{Username: "user", Password: "pass"},
+				Headers:   []HTTPHeader{{Key: "X-Version", Value: "{{ version }}"}}},
+		},
+		"invalid basic auth": {
+			httpCheck: &HTTPCheck{
+				URL:       server.URL + "/private/myapp_{{ version }}.tar.gz",
+				BasicAuth: &HTTPBasicAuth{Username: "user", Password: "invalid"},
+				Headers:   []HTTPHeader{{Key: "X-Version", Value: "{{ version }}"}}},
+			errRegex: `got status code 401, want 200$`,
+		},
+		"content_type matches": {
+			httpCheck: &HTTPCheck{
+				URL:         server.URL + "/myapp_{{ version }}.tar.gz",
+				ContentType: "^application/gzip$"},
+		},
+		"content_type doesn't match": {
+			httpCheck: &HTTPCheck{
+				URL:         server.URL + "/myapp_{{ version }}.tar.gz",
+				ContentType: "^text/html"},
+			errRegex: `got Content-Type "application/gzip", want a match for "\^text/html"$`,
+		},
+		"size within the limits": {
+			httpCheck: &HTTPCheck{
+				URL:     server.URL + "/myapp_{{ version }}.tar.gz",
+				MinSize: 100,
+				MaxSize: 100},
+		},
+		"too small": {
+			httpCheck: &HTTPCheck{
+				URL:     server.URL + "/myapp_{{ version }}.tar.gz",
+				MinSize: 101},
+			errRegex: `got a size of 100 bytes, want at least 101$`,
+		},
+		"too big": {
+			httpCheck: &HTTPCheck{
+				URL:     server.URL + "/myapp_{{ version }}.tar.gz",
+				MaxSize: 99},
+			errRegex: `got a size of 100 bytes, want at most 99$`,
+		},
+		"size of a GET without a Content-Length is read": {
+			httpCheck: &HTTPCheck{
+				URL:     server.URL + "/chunked/myapp_{{ version }}.tar.gz",
+				Method:  "GET",
+				MaxSize: 50},
+			errRegex: `got a size of 51 bytes, want at most 50$`,
+		},
+		"size of a HEAD without a Content-Length": {
+			httpCheck: &HTTPCheck{
+				URL:     server.URL + "/chunked/myapp_{{ version }}.tar.gz",
+				MinSize: 1},
+			errRegex: `no Content-Length to check the size with$`,
+		},
+		"request fails": {
+			httpCheck: &HTTPCheck{
+				URL: "http://localhost:1/myapp_{{ version }}.tar.gz"},
+			errRegex: `^HEAD http://localhost:1/myapp_1.2.3.tar.gz - dial tcp .*refused$`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require := &Require{HTTP: tc.httpCheck}
+			if tc.version == "" {
+				tc.version = "1.2.3"
+			}
+
+			// WHEN HTTPResponseCheck is called on it
+			err := require.HTTPResponseCheck(tc.version, &util.LogFrom{})
+
+			// THEN the err is expected
+			e := util.ErrorToString(err)
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestHTTPCheck_GiveSecrets(t *testing.T) {
+	// GIVEN a HTTPCheck that may reference the secrets of another
+	tests := map[string]struct {
+		httpCheck, oldHTTP *HTTPCheck
+		want               *HTTPCheck
+	}{
+		"nil old HTTPCheck": {
+			httpCheck: &HTTPCheck{
+				BasicAuth: &HTTPBasicAuth{Username: "user", Password: "<secret>"}},
+			want: &HTTPCheck{
+				BasicAuth: &HTTPBasicAuth{Username: "user", Password: "<secret>"}},
+		},
+		"new secrets kept": {
+			httpCheck: &HTTPCheck{
+				BasicAuth: &HTTPBasicAuth{Username: "user", Password: "new"},
+				Headers:   []HTTPHeader{{Key: "X-Token", Value: "new"}}},
+			oldHTTP: &HTTPCheck{
+				BasicAuth: &HTTPBasicAuth{Username: "user", Password: "old"},
+				Headers:   []HTTPHeader{{Key: "X-Token", Value: "old"}}},
+			want: &HTTPCheck{
+				BasicAuth: &HTTPBasicAuth{Username: "user", Password: "new"},
+				Headers:   []HTTPHeader{{Key: "X-Token", Value: "new"}}},
+		},
+		"old secrets given": {
+			httpCheck: &HTTPCheck{
+				BasicAuth: &HTTPBasicAuth{Username: "user", Password: "<secret>"},
+				Headers:   []HTTPHeader{{Key: "X-Token", Value: "<secret>"}}},
+			oldHTTP: &HTTPCheck{
+				BasicAuth: &HTTPBasicAuth{Username: "user", Password: "old"},
+				Headers:   []HTTPHeader{{Key: "X-Token", Value: "old"}}},
+			want: &HTTPCheck{
+				BasicAuth: &HTTPBasicAuth{Username: "user", Password: "old"},
+				Headers:   []HTTPHeader{{Key: "X-Token", Value: "old"}}},
+		},
+		"header with a different key isn't given the old value": {
+			httpCheck: &HTTPCheck{
+				Headers: []HTTPHeader{{Key: "X-Other", Value: "<secret>"}}},
+			oldHTTP: &HTTPCheck{
+				Headers: []HTTPHeader{{Key: "X-Token", Value: "old"}}},
+			want: &HTTPCheck{
+				Headers: []HTTPHeader{{Key: "X-Other", Value: "<secret>"}}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN GiveSecrets is called on it
+			tc.httpCheck.GiveSecrets(tc.oldHTTP)
+
+			// THEN the secrets are as expected
+			if got, want := tc.httpCheck.String(""), tc.want.String(""); got != want {
+				t.Errorf("want:\n%s\ngot:\n%s",
+					want, got)
+			}
+		})
+	}
+}
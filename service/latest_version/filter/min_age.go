@@ -0,0 +1,54 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/release-argus/Argus/util"
+)
+
+// GetMinAge returns the minimum age a release must be before it's used.
+func (r *Require) GetMinAge() time.Duration {
+	if r == nil || r.MinAge == "" {
+		return 0
+	}
+
+	minAge, _ := time.ParseDuration(r.MinAge)
+	return minAge
+}
+
+// AgeCheck returns an error if the release of `version` published (or first seen) at `since`
+// hasn't reached the min_age yet.
+func (r *Require) AgeCheck(
+	version string,
+	since time.Time,
+	logFrom *util.LogFrom,
+) error {
+	minAge := r.GetMinAge()
+	if minAge == 0 {
+		return nil
+	}
+
+	if until := since.Add(minAge); time.Now().Before(until) {
+		err := fmt.Errorf("version %q is pending until %s (min_age %s)",
+			version, until.UTC().Format(time.RFC3339), r.MinAge)
+		jLog.Verbose(err, logFrom, true)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package filter
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/release-argus/Argus/util"
+)
+
+func TestRequire_GetMinAge(t *testing.T) {
+	// GIVEN a Require
+	tests := map[string]struct {
+		require *Require
+		want    time.Duration
+	}{
+		"nil require": {
+			require: nil,
+			want:    0},
+		"empty min_age": {
+			require: &Require{},
+			want:    0},
+		"min_age": {
+			require: &Require{MinAge: "1h30m"},
+			want:    90 * time.Minute},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN GetMinAge is called on it
+			got := tc.require.GetMinAge()
+
+			// THEN the duration is what we expect
+			if got != tc.want {
+				t.Errorf("want: %s\ngot:  %s",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRequire_AgeCheck(t *testing.T) {
+	// GIVEN a Require and a release published/first seen at `since`
+	tests := map[string]struct {
+		require  *Require
+		since    time.Time
+		errRegex string
+	}{
+		"nil require": {
+			require:  nil,
+			since:    time.Now(),
+			errRegex: "^$"},
+		"empty min_age": {
+			require:  &Require{},
+			since:    time.Now(),
+			errRegex: "^$"},
+		"older than min_age": {
+			require:  &Require{MinAge: "48h"},
+			since:    time.Now().Add(-49 * time.Hour),
+			errRegex: "^$"},
+		"younger than min_age": {
+			require:  &Require{MinAge: "48h"},
+			since:    time.Now().Add(-47 * time.Hour),
+			errRegex: `^version "1.2.3" is pending until [0-9-]+T[0-9:]+Z \(min_age 48h\)$`},
+		"unknown publish time": {
+			require:  &Require{MinAge: "48h"},
+			since:    time.Time{},
+			errRegex: "^$"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN AgeCheck is called on it
+			err := tc.require.AgeCheck("1.2.3", tc.since, &util.LogFrom{})
+
+			// THEN the err is what we expect
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
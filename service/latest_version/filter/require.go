@@ -18,7 +18,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	command "github.com/release-argus/Argus/commands"
 	svcstatus "github.com/release-argus/Argus/service/status"
@@ -70,7 +72,11 @@ type Require struct {
 	RegexContent string            `yaml:"regex_content,omitempty" json:"regex_content,omitempty"` // "abc-[a-z]+-{{ version }}_amd64.deb" This regex must exist in the body of the URL to trigger new version actions
 	RegexVersion string            `yaml:"regex_version,omitempty" json:"regex_version,omitempty"` // "v*[0-9.]+" The version found must match this release to trigger new version actions
 	Command      command.Command   `yaml:"command,omitempty" json:"command,omitempty"`             // Require Command to pass
+	HTTP         *HTTPCheck        `yaml:"http,omitempty" json:"http,omitempty"`                   // HTTP response requirements, e.g. the download exists
 	Docker       *DockerCheck      `yaml:"docker,omitempty" json:"docker,omitempty"`               // Docker image tag requirements
+	MinAge       string            `yaml:"min_age,omitempty" json:"min_age,omitempty"`             // "48h" The release must have been published (or first seen) this long ago
+	Assets       []string          `yaml:"assets,omitempty" json:"assets,omitempty"`               // "myapp_{{ version }}_linux_amd64.tar.gz" These regexes must all match an asset name of the release
+	Verify       *VerifyCheck      `yaml:"verify,omitempty" json:"verify,omitempty"`               // Checksum/signature verification of a release asset
 }
 
 // String returns a string representation of the Require.
@@ -131,11 +137,40 @@ func (r *Require) CheckValues(prefix string) (errs error) {
 		}
 	}
 
+	if err := r.HTTP.CheckValues(prefix + "    "); err != nil {
+		errs = fmt.Errorf("%s%s  http:\\%w",
+			util.ErrorToString(errs), prefix, err)
+	}
+
 	if err := r.Docker.CheckValues(prefix + "    "); err != nil {
 		errs = fmt.Errorf("%s%s  docker:\\%w",
 			util.ErrorToString(errs), prefix, err)
 	}
 
+	// Assets
+	if err := checkAssets(r.Assets, prefix); err != nil {
+		errs = fmt.Errorf("%s%w",
+			util.ErrorToString(errs), err)
+	}
+
+	// Verify
+	if err := r.Verify.CheckValues(prefix + "    "); err != nil {
+		errs = fmt.Errorf("%s%s  verify:\\%w",
+			util.ErrorToString(errs), prefix, err)
+	}
+
+	// Minimum age
+	if r.MinAge != "" {
+		// Default to seconds when an integer is provided
+		if _, err := strconv.Atoi(r.MinAge); err == nil {
+			r.MinAge += "s"
+		}
+		if minAge, err := time.ParseDuration(r.MinAge); err != nil || minAge < 0 {
+			errs = fmt.Errorf("%s%s  min_age: %q <invalid> (Use 'AhBmCs' duration format)\\",
+				util.ErrorToString(errs), prefix, r.MinAge)
+		}
+	}
+
 	if errs != nil {
 		errs = fmt.Errorf("%srequire:\\%s",
 			prefix, util.ErrorToString(errs))
@@ -177,6 +212,18 @@ func RequireFromStr(jsonStr *string, previous *Require, logFrom *util.LogFrom) (
 		if !util.Contains(jsonKeys, "command") {
 			require.Command = previous.Command
 		}
+		if !util.Contains(jsonKeys, "http") {
+			require.HTTP = previous.HTTP
+		}
+		if !util.Contains(jsonKeys, "min_age") {
+			require.MinAge = previous.MinAge
+		}
+		if !util.Contains(jsonKeys, "assets") {
+			require.Assets = previous.Assets
+		}
+		if !util.Contains(jsonKeys, "verify") {
+			require.Verify = previous.Verify
+		}
 
 		// Default the Docker params
 		if previous.Docker != nil {
@@ -190,6 +237,10 @@ func RequireFromStr(jsonStr *string, previous *Require, logFrom *util.LogFrom) (
 					require.Docker.Type = previous.Docker.Type
 					sameDockerImageAndCredentials++
 				}
+				if !util.Contains(jsonKeys, "docker.registry") {
+					require.Docker.Registry = previous.Docker.Registry
+					sameDockerImageAndCredentials++
+				}
 				if !util.Contains(jsonKeys, "docker.image") {
 					require.Docker.Image = previous.Docker.Image
 					sameDockerImageAndCredentials++
@@ -197,6 +248,9 @@ func RequireFromStr(jsonStr *string, previous *Require, logFrom *util.LogFrom) (
 				if !util.Contains(jsonKeys, "docker.tag") {
 					require.Docker.Tag = previous.Docker.Tag
 				}
+				if !util.Contains(jsonKeys, "docker.platforms") {
+					require.Docker.Platforms = previous.Docker.Platforms
+				}
 				if !util.Contains(jsonKeys, "docker.username") {
 					require.Docker.Username = previous.Docker.Username
 					sameDockerImageAndCredentials++
@@ -206,7 +260,7 @@ func RequireFromStr(jsonStr *string, previous *Require, logFrom *util.LogFrom) (
 					sameDockerImageAndCredentials++
 				}
 
-				if sameDockerImageAndCredentials == 4 {
+				if sameDockerImageAndCredentials == 5 {
 					require.Docker.queryToken = previous.Docker.queryToken
 					require.Docker.validUntil = previous.Docker.validUntil
 				}
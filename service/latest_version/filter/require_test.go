@@ -221,6 +221,90 @@ func TestRequire_CheckValues(t *testing.T) {
 				`^  docker:$`,
 				`^    type: .* <invalid>`},
 		},
+		"valid min_age": {
+			require: &Require{
+				MinAge: "48h"},
+			errRegex: []string{`^$`},
+		},
+		"valid min_age - integer seconds": {
+			require: &Require{
+				MinAge: "3600"},
+			errRegex: []string{`^$`},
+		},
+		"invalid min_age": {
+			require: &Require{
+				MinAge: "2d"},
+			errRegex: []string{
+				`^require:$`,
+				`^  min_age: "2d" <invalid>`},
+		},
+		"invalid min_age - negative": {
+			require: &Require{
+				MinAge: "-1h"},
+			errRegex: []string{
+				`^require:$`,
+				`^  min_age: "-1h" <invalid>`},
+		},
+		"valid assets": {
+			require: &Require{
+				Assets: []string{
+					`myapp_{{ version }}_linux_amd64\.tar\.gz`,
+					`checksums\.txt`}},
+			errRegex: []string{`^$`},
+		},
+		"invalid assets": {
+			require: &Require{
+				Assets: []string{
+					`checksums\.txt`,
+					"[0-",
+					"myapp_{{ version }",
+					""}},
+			errRegex: []string{
+				`^require:$`,
+				`^  assets:$`,
+				`^    item_1: "\[0-" <invalid> \(Invalid RegEx\)$`,
+				`^    item_2: "myapp_{{ version }" <invalid> \(didn't pass templating\)$`,
+				`^    item_3: <required>`},
+		},
+		"valid verify": {
+			require: &Require{
+				Verify: &VerifyCheck{
+					Asset:     `myapp_{{ version }}_linux_amd64\.tar\.gz`,
+					Checksums: "SHA256SUMS"}},
+			errRegex: []string{`^$`},
+		},
+		"invalid verify": {
+			require: &Require{
+				Verify: &VerifyCheck{
+					Checksums: "SHA256SUMS",
+					Signature: &VerifySignature{
+						Type:  "gpg",
+						Asset: `SHA256SUMS\.asc`}}},
+			errRegex: []string{
+				`^require:$`,
+				`^  verify:$`,
+				`^    asset: <required>`,
+				`^    signature:$`,
+				`^      public_key: <required>`},
+		},
+		"valid http": {
+			require: &Require{
+				HTTP: &HTTPCheck{
+					URL: "https://example.com/myapp_{{ version }}.tar.gz"}},
+			errRegex: []string{`^$`},
+		},
+		"invalid http": {
+			require: &Require{
+				HTTP: &HTTPCheck{
+					Method:     "POST",
+					StatusCode: 1000}},
+			errRegex: []string{
+				`^require:$`,
+				`^  http:$`,
+				`^    url: <required>`,
+				`^    method: "POST" <invalid>`,
+				`^    status_code: 1000 <invalid>`},
+		},
 		"all possible errors": {
 			require: &Require{
 				RegexContent: "[0-",
@@ -373,6 +457,87 @@ func TestRequire_FromStr(t *testing.T) {
 				RegexVersion: "foo",
 				Command:      []string{}},
 		},
+		"MinAge defined": {
+			jsonStr: test.StringPtr(`{
+				"min_age": "48h"}`),
+			want: &Require{
+				MinAge: "48h"},
+		},
+		"No MinAge JSON uses default": {
+			jsonStr: test.StringPtr(`{
+				"regex_version": "foo"}`),
+			dflt: &Require{
+				MinAge: "1h"},
+			want: &Require{
+				RegexVersion: "foo",
+				MinAge:       "1h"},
+		},
+		"Assets defined": {
+			jsonStr: test.StringPtr(`{
+				"assets": ["checksums\\.txt"]}`),
+			dflt: &Require{
+				Assets: []string{"foo"}},
+			want: &Require{
+				Assets: []string{`checksums\.txt`}},
+		},
+		"No Assets JSON uses default": {
+			jsonStr: test.StringPtr(`{
+				"regex_version": "foo"}`),
+			dflt: &Require{
+				Assets: []string{"foo"}},
+			want: &Require{
+				RegexVersion: "foo",
+				Assets:       []string{"foo"}},
+		},
+		"Verify defined": {
+			jsonStr: test.StringPtr(`{
+				"verify": {
+					"asset": "myapp",
+					"checksums": "SHA256SUMS"}}`),
+			dflt: &Require{
+				Verify: &VerifyCheck{
+					Asset:     "foo",
+					Checksums: "bar"}},
+			want: &Require{
+				Verify: &VerifyCheck{
+					Asset:     "myapp",
+					Checksums: "SHA256SUMS"}},
+		},
+		"No Verify JSON uses default": {
+			jsonStr: test.StringPtr(`{
+				"regex_version": "foo"}`),
+			dflt: &Require{
+				Verify: &VerifyCheck{
+					Asset:     "foo",
+					Checksums: "bar"}},
+			want: &Require{
+				RegexVersion: "foo",
+				Verify: &VerifyCheck{
+					Asset:     "foo",
+					Checksums: "bar"}},
+		},
+		"HTTP defined": {
+			jsonStr: test.StringPtr(`{
+				"http": {
+					"url": "https://example.com/{{ version }}"}}`),
+			dflt: &Require{
+				HTTP: &HTTPCheck{
+					URL: "https://example.com/foo"}},
+			want: &Require{
+				HTTP: &HTTPCheck{
+					URL: "https://example.com/{{ version }}"}},
+		},
+		"No HTTP JSON uses default": {
+			jsonStr: test.StringPtr(`{
+				"regex_version": "foo"}`),
+			dflt: &Require{
+				HTTP: &HTTPCheck{
+					URL: "https://example.com/foo"}},
+			want: &Require{
+				RegexVersion: "foo",
+				HTTP: &HTTPCheck{
+					URL: "https://example.com/foo"}},
+		},
 		"Only Docker.Type sent": {
 			jsonStr: test.StringPtr(`{
 				"docker": {
@@ -20,7 +20,11 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xpath"
 	"github.com/release-argus/Argus/util"
+	"golang.org/x/net/html"
 )
 
 // URLCommandSlice to be used to filter version from the URL Content.
@@ -36,13 +40,17 @@ func (s *URLCommandSlice) String() (str string) {
 
 // URLCommand is a command to be ran to filter version from the URL body.
 type URLCommand struct {
-	Type     string  `yaml:"type" json:"type"`                             // regex/replace/split
-	Regex    *string `yaml:"regex,omitempty" json:"regex,omitempty"`       // regex: regexp.MustCompile(Regex)
-	Index    int     `yaml:"index,omitempty" json:"index,omitempty"`       // regex/split: re.FindAllString(URL_content, -1)[Index]  /  strings.Split("text")[Index]
-	Template *string `yaml:"template,omitempty" json:"template,omitempty"` // regex: template
-	Text     *string `yaml:"text,omitempty" json:"text,omitempty"`         // split: strings.Split(tgtString, "Text")
-	New      *string `yaml:"new,omitempty" json:"new,omitempty"`           // replace: strings.ReplaceAll(tgtString, "Old", "New")
-	Old      *string `yaml:"old,omitempty" json:"old,omitempty"`           // replace: strings.ReplaceAll(tgtString, "Old", "New")
+	Type      string  `yaml:"type" json:"type"`                               // html/json/regex/replace/split/yaml
+	Key       *string `yaml:"key,omitempty" json:"key,omitempty"`             // json/yaml: key path of the value(s), e.g. "items[*].tag_name"
+	Selector  *string `yaml:"selector,omitempty" json:"selector,omitempty"`   // html: CSS selector of the element(s), e.g. "a.download"
+	XPath     *string `yaml:"xpath,omitempty" json:"xpath,omitempty"`         // html: XPath of the element(s), e.g. "//a[@class='download']"
+	Attribute *string `yaml:"attribute,omitempty" json:"attribute,omitempty"` // html: attribute to use instead of the text, e.g. "href"
+	Regex     *string `yaml:"regex,omitempty" json:"regex,omitempty"`         // regex: regexp.MustCompile(Regex)
+	Index     int     `yaml:"index,omitempty" json:"index,omitempty"`         // html/regex/split: matches[Index]  /  re.FindAllString(URL_content, -1)[Index]  /  strings.Split("text")[Index]
+	Template  *string `yaml:"template,omitempty" json:"template,omitempty"`   // regex: template
+	Text      *string `yaml:"text,omitempty" json:"text,omitempty"`           // split: strings.Split(tgtString, "Text")
+	New       *string `yaml:"new,omitempty" json:"new,omitempty"`             // replace: strings.ReplaceAll(tgtString, "Old", "New")
+	Old       *string `yaml:"old,omitempty" json:"old,omitempty"`             // replace: strings.ReplaceAll(tgtString, "Old", "New")
 }
 
 // String returns a string representation of the URLCommand.
@@ -93,7 +101,78 @@ func (s *URLCommandSlice) Run(text string, logFrom *util.LogFrom) (string, error
 	return text, nil
 }
 
+// RunAll of the URLCommand(s) in this URLCommandSlice, returning every value found.
+//
+// json/yaml commands with wildcards in their key may select multiple values,
+// with the following commands ran on each of them (dropping those that fail).
+// If `allMatches`, the final command will return every match rather than the one at its index.
+func (s *URLCommandSlice) RunAll(text string, allMatches bool, logFrom *util.LogFrom) ([]string, error) {
+	if s == nil {
+		return []string{text}, nil
+	}
+
+	urlCommandLogFrom := &util.LogFrom{Primary: logFrom.Primary, Secondary: "url_commands"}
+	texts := []string{text}
+	for commandIndex := range *s {
+		var (
+			err       error
+			nextTexts []string
+		)
+		all := allMatches && commandIndex == len(*s)-1
+		for _, text := range texts {
+			values, runErr := (*s)[commandIndex].runAll(text, all, urlCommandLogFrom)
+			if runErr != nil {
+				err = runErr
+				continue
+			}
+			nextTexts = append(nextTexts, values...)
+		}
+		if len(nextTexts) == 0 {
+			return nil, err
+		}
+		texts = nextTexts
+	}
+	return texts, nil
+}
+
+// runAll runs this URLCommand on `text`, returning every value found.
+//
+// html commands return every match, unless an index is given.
+// html/regex/split commands return every match when `all`.
+func (c *URLCommand) runAll(text string, all bool, logFrom *util.LogFrom) (values []string, err error) {
+	var msg string
+	switch {
+	case c.Type == "html" && (all || c.Index == 0):
+		msg = fmt.Sprintf("Selecting %s", c.htmlQuery())
+		values, err = c.html(text, logFrom)
+	case c.Type == "regex" && all:
+		msg = fmt.Sprintf("Regexing %q for all matches", *c.Regex)
+		values, err = c.regexAll(text, logFrom)
+	case c.Type == "split" && all:
+		msg = fmt.Sprintf("Splitting on %q for all parts", *c.Text)
+		values, err = c.splitAll(text, logFrom)
+	case c.Type == "json", c.Type == "yaml":
+		msg = fmt.Sprintf("Parsing %s for %q", c.Type, *c.Key)
+		values, err = c.parse(text, logFrom)
+	default:
+		text, err = c.run(text, logFrom)
+		if err != nil {
+			return nil, err
+		}
+		return []string{text}, nil
+	}
+
+	if err == nil && jLog.IsLevel("DEBUG") {
+		jLog.Debug(
+			fmt.Sprintf("%s\nResolved to %q", msg, values),
+			logFrom, true)
+	}
+	return
+}
+
 // run this URLCommand on `text`
+//
+// json/yaml commands return the first value selected.
 func (c *URLCommand) run(text string, logFrom *util.LogFrom) (string, error) {
 	var err error
 	// Iterate through the commands to filter the text.
@@ -106,6 +185,15 @@ func (c *URLCommand) run(text string, logFrom *util.LogFrom) (string, error) {
 
 	var msg string
 	switch c.Type {
+	case "json", "yaml":
+		msg = fmt.Sprintf("Parsing %s for %q", c.Type, *c.Key)
+		var values []string
+		if values, err = c.parse(text, logFrom); err == nil {
+			text = values[0]
+		}
+	case "html":
+		msg = fmt.Sprintf("Selecting %s with index %d", c.htmlQuery(), c.Index)
+		text, err = c.htmlIndex(text, logFrom)
 	case "split":
 		msg = fmt.Sprintf("Splitting on %q with index %d", *c.Text, c.Index)
 		text, err = c.split(text, logFrom)
@@ -130,6 +218,151 @@ func (c *URLCommand) run(text string, logFrom *util.LogFrom) (string, error) {
 	return text, err
 }
 
+// parse `text` as JSON/YAML and return the value(s) at the URLCommand's key.
+func (c *URLCommand) parse(text string, logFrom *util.LogFrom) (values []string, err error) {
+	var data interface{}
+	if c.Type == "json" {
+		data, err = util.DecodeJSON([]byte(text))
+	} else {
+		data, err = util.DecodeYAML([]byte(text))
+	}
+	if err != nil {
+		err = fmt.Errorf("%s failed to unmarshal: %w",
+			c.Type, err)
+		jLog.Warn(err, logFrom, true)
+		return
+	}
+
+	if values, err = util.GetValuesByKey(data, *c.Key); err != nil {
+		err = fmt.Errorf("%s %w",
+			c.Type, err)
+		jLog.Warn(err, logFrom, true)
+	}
+	return
+}
+
+// htmlQuery returns a description of the selector/xpath of this html URLCommand.
+func (c *URLCommand) htmlQuery() (query string) {
+	if c.XPath != nil {
+		query = fmt.Sprintf("xpath %q", *c.XPath)
+	} else {
+		query = fmt.Sprintf("selector %q", util.DefaultIfNil(c.Selector))
+	}
+	if c.Attribute != nil {
+		query = fmt.Sprintf("%s attribute %q", query, *c.Attribute)
+	}
+	return
+}
+
+// html parses `text` as HTML and returns the text (or attribute) of every element
+// matching the URLCommand's selector/xpath.
+func (c *URLCommand) html(text string, logFrom *util.LogFrom) (values []string, err error) {
+	doc, err := html.Parse(strings.NewReader(text))
+	if err != nil {
+		err = fmt.Errorf("%s failed to parse: %w",
+			c.Type, err)
+		jLog.Warn(err, logFrom, true)
+		return
+	}
+
+	var nodes []*html.Node
+	if c.XPath != nil {
+		nodes, err = htmlquery.QueryAll(doc, *c.XPath)
+	} else {
+		var selector cascadia.SelectorGroup
+		if selector, err = cascadia.ParseGroup(util.DefaultIfNil(c.Selector)); err == nil {
+			nodes = cascadia.QueryAll(doc, selector)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("%s %s failed: %w",
+			c.Type, c.htmlQuery(), err)
+		jLog.Warn(err, logFrom, true)
+		return
+	}
+
+	for _, node := range nodes {
+		value := strings.TrimSpace(htmlquery.InnerText(node))
+		if c.Attribute != nil {
+			// Ignore the elements without this attribute.
+			if !htmlHasAttr(node, *c.Attribute) {
+				continue
+			}
+			value = strings.TrimSpace(htmlquery.SelectAttr(node, *c.Attribute))
+		}
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+
+	if len(values) == 0 {
+		err = fmt.Errorf("%s %s didn't return any matches",
+			c.Type, c.htmlQuery())
+		jLog.Warn(err, logFrom, true)
+	}
+	return
+}
+
+// htmlIndex returns the match at the URLCommand's index of the html selector/xpath on `text`.
+func (c *URLCommand) htmlIndex(text string, logFrom *util.LogFrom) (string, error) {
+	values, err := c.html(text, logFrom)
+	if err != nil {
+		return text, err
+	}
+
+	index := c.Index
+	// Handle negative indices.
+	if index < 0 {
+		index = len(values) + index
+	}
+
+	// Index out of range.
+	if index < 0 || (len(values)-index) < 1 {
+		err := fmt.Errorf("%s (%s) returned %d elements, but the index wants element number %d",
+			c.Type, c.htmlQuery(), len(values), (index + 1))
+		jLog.Warn(err, logFrom, true)
+
+		return text, err
+	}
+
+	return values[index], nil
+}
+
+// htmlHasAttr returns whether `node` has the attribute `key`.
+func htmlHasAttr(node *html.Node, key string) bool {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// regexAll returns every match of the URLCommand's regex on `text`.
+func (c *URLCommand) regexAll(text string, logFrom *util.LogFrom) ([]string, error) {
+	re := regexp.MustCompile(*c.Regex)
+
+	texts := re.FindAllStringSubmatch(text, -1)
+	// No matches.
+	if len(texts) == 0 {
+		err := fmt.Errorf("%s %q didn't return any matches",
+			c.Type, *c.Regex)
+		if len(text) < 20 {
+			err = fmt.Errorf("%w on %q",
+				err, text)
+		}
+		jLog.Warn(err, logFrom, true)
+
+		return nil, err
+	}
+
+	values := make([]string, len(texts))
+	for i := range texts {
+		values[i] = util.RegexTemplate(texts[i], c.Template)
+	}
+	return values, nil
+}
+
 // regex `text` with the URLCommand's regex.
 func (c *URLCommand) regex(text string, logFrom *util.LogFrom) (string, error) {
 	re := regexp.MustCompile(*c.Regex)
@@ -195,6 +428,27 @@ func (c *URLCommand) split(text string, logFrom *util.LogFrom) (string, error) {
 	return texts[index], nil
 }
 
+// splitAll returns every non-empty part of `text` split on the URLCommand's text.
+func (c *URLCommand) splitAll(text string, logFrom *util.LogFrom) ([]string, error) {
+	texts := strings.Split(text, *c.Text)
+
+	if len(texts) == 1 {
+		err := fmt.Errorf("%s didn't find any %q to split on",
+			c.Type, *c.Text)
+		jLog.Warn(err, logFrom, true)
+
+		return nil, err
+	}
+
+	values := make([]string, 0, len(texts))
+	for _, value := range texts {
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
 // CheckValues of the URLCommand(s) in the URLCommandSlice.
 func (s *URLCommandSlice) CheckValues(prefix string) (errs error) {
 	if s == nil {
@@ -235,6 +489,33 @@ func (c *URLCommand) CheckValues(prefix string) (errs error) {
 		if util.DefaultIfNil(c.Template) == "" {
 			c.Template = nil
 		}
+	case "html":
+		switch {
+		case c.Selector == nil && c.XPath == nil:
+			errs = fmt.Errorf("%s%sselector: <required> (CSS selector of the element(s), or an xpath)\\",
+				util.ErrorToString(errs), prefix)
+		case c.Selector != nil && c.XPath != nil:
+			errs = fmt.Errorf("%s%sxpath: %q <invalid> (only one of selector/xpath can be used)\\",
+				util.ErrorToString(errs), prefix, *c.XPath)
+		case c.Selector != nil:
+			if _, err := cascadia.ParseGroup(*c.Selector); err != nil {
+				errs = fmt.Errorf("%s%sselector: %q <invalid> (%s)\\",
+					util.ErrorToString(errs), prefix, *c.Selector, err)
+			}
+		default:
+			if _, err := xpath.Compile(*c.XPath); err != nil {
+				errs = fmt.Errorf("%s%sxpath: %q <invalid> (%s)\\",
+					util.ErrorToString(errs), prefix, *c.XPath, err)
+			}
+		}
+	case "json", "yaml":
+		if c.Key == nil || *c.Key == "" {
+			errs = fmt.Errorf("%s%skey: <required> (key of the value(s), e.g. 'items[*].tag_name')\\",
+				util.ErrorToString(errs), prefix)
+		} else if _, err := util.ParseKeys(strings.ReplaceAll(*c.Key, "[*]", ".*")); err != nil {
+			errs = fmt.Errorf("%s%skey: %q <invalid> (%s)\\",
+				util.ErrorToString(errs), prefix, *c.Key, err)
+		}
 	case "replace":
 		if c.New == nil {
 			errs = fmt.Errorf("%s%snew: <required> (text you want to replace with)\\",
@@ -251,7 +532,7 @@ func (c *URLCommand) CheckValues(prefix string) (errs error) {
 		}
 	default:
 		validType = false
-		errs = fmt.Errorf("%s%stype: %q <invalid> is not a valid url_command (regex/replace/split)\\",
+		errs = fmt.Errorf("%s%stype: %q <invalid> is not a valid url_command (html/json/regex/replace/split/yaml)\\",
 			util.ErrorToString(errs), prefix, c.Type)
 	}
 
@@ -285,6 +285,95 @@ func TestURLCommandSlice_Run(t *testing.T) {
 			errRegex: `split .* returned \d elements on "[^']+", but the index wants element number \d`,
 			want:     testText,
 		},
+		"html selector": {
+			slice: &URLCommandSlice{
+				{Type: "html", Selector: test.StringPtr("li > a")}},
+			text:     `<ul><li><a href="/v1.0.0">1.0.0</a></li><li><a href="/v1.1.0"> 1.1.0 </a></li></ul>`,
+			errRegex: "^$",
+			want:     "1.0.0",
+		},
+		"html selector with negative index on attribute": {
+			slice: &URLCommandSlice{
+				{Type: "html", Selector: test.StringPtr("li > a"), Attribute: test.StringPtr("href"), Index: -1}},
+			text:     `<ul><li><a href="/v1.0.0">1.0.0</a></li><li><a href="/v1.1.0">1.1.0</a></li></ul>`,
+			errRegex: "^$",
+			want:     "/v1.1.0",
+		},
+		"html xpath": {
+			slice: &URLCommandSlice{
+				{Type: "html", XPath: test.StringPtr("//a[@id='latest']")}},
+			text:     `<a>0.9.0</a><a id="latest">1.0.0</a>`,
+			errRegex: "^$",
+			want:     "1.0.0",
+		},
+		"html no matches": {
+			slice: &URLCommandSlice{
+				{Type: "html", Selector: test.StringPtr("a.download")}},
+			errRegex: `html selector "a.download" didn't return any matches`,
+			want:     testText,
+		},
+		"html index out of bounds": {
+			slice: &URLCommandSlice{
+				{Type: "html", Selector: test.StringPtr("a"), Index: 1}},
+			text:     `<a>1.0.0</a>`,
+			errRegex: `html \(selector "a"\) returned 1 elements, but the index wants element number 2`,
+			want:     `<a>1.0.0</a>`,
+		},
+		"json": {
+			slice: &URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("items[*].tag_name")}},
+			text:     `{"items": [{"tag_name": "1.0.0"}, {"tag_name": "1.1.0"}]}`,
+			errRegex: "^$",
+			want:     "1.0.0",
+		},
+		"json invalid": {
+			slice: &URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("foo")}},
+			errRegex: "json failed to unmarshal",
+			want:     testText,
+		},
+		"json integer build number": {
+			slice: &URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("build")}},
+			text:     `{"build": 20240101}`,
+			errRegex: "^$",
+			want:     "20240101",
+		},
+		"json float with a trailing zero": {
+			slice: &URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("version")}},
+			text:     `{"version": 1.10}`,
+			errRegex: "^$",
+			want:     "1.10",
+		},
+		"yaml": {
+			slice: &URLCommandSlice{
+				{Type: "yaml", Key: test.StringPtr("versions[-1]")}},
+			text:     "versions:\n  - 1.0.0\n  - 1.1.0\n",
+			errRegex: "^$",
+			want:     "1.1.0",
+		},
+		"yaml integer build number": {
+			slice: &URLCommandSlice{
+				{Type: "yaml", Key: test.StringPtr("build")}},
+			text:     "build: 20240101\n",
+			errRegex: "^$",
+			want:     "20240101",
+		},
+		"yaml float with a trailing zero": {
+			slice: &URLCommandSlice{
+				{Type: "yaml", Key: test.StringPtr("version")}},
+			text:     "version: 1.10\n",
+			errRegex: "^$",
+			want:     "1.10",
+		},
+		"yaml key not found": {
+			slice: &URLCommandSlice{
+				{Type: "yaml", Key: test.StringPtr("foo")}},
+			text:     "bar: baz\n",
+			errRegex: `yaml key "foo" not found`,
+			want:     "bar: baz\n",
+		},
 		"all types": {
 			slice: &URLCommandSlice{
 				{Type: "regex", Regex: test.StringPtr("([a-z]+)[0-9]+"), Index: 1},
@@ -322,6 +411,154 @@ func TestURLCommandSlice_Run(t *testing.T) {
 	}
 }
 
+func TestURLCommandSlice_RunAll(t *testing.T) {
+	// GIVEN a URLCommandSlice
+	tests := map[string]struct {
+		slice      *URLCommandSlice
+		text       string
+		allMatches bool
+		want       []string
+		errRegex   string
+	}{
+		"nil slice": {
+			slice: nil,
+			text:  "abc123",
+			want:  []string{"abc123"},
+		},
+		"regex": {
+			slice: &URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr("([a-z]+)[0-9]+"), Index: 1}},
+			text: "abc123-def456",
+			want: []string{"def"},
+		},
+		"html returns all matches": {
+			slice: &URLCommandSlice{
+				{Type: "html", Selector: test.StringPtr("td.version")}},
+			text: `<table><tr><td class="version">1.2.0</td></tr><tr><td class="version">1.10.0</td></tr></table>`,
+			want: []string{"1.2.0", "1.10.0"},
+		},
+		"html with index returns that match": {
+			slice: &URLCommandSlice{
+				{Type: "html", Selector: test.StringPtr("td.version"), Index: -1}},
+			text: `<table><tr><td class="version">1.2.0</td></tr><tr><td class="version">1.10.0</td></tr></table>`,
+			want: []string{"1.10.0"},
+		},
+		"html attribute ignores elements without it": {
+			slice: &URLCommandSlice{
+				{Type: "html", XPath: test.StringPtr("//a"), Attribute: test.StringPtr("data-version")}},
+			text: `<a data-version="1.0.0">a</a><a>b</a><a data-version="2.0.0">c</a>`,
+			want: []string{"1.0.0", "2.0.0"},
+		},
+		"html xpath on attributes": {
+			slice: &URLCommandSlice{
+				{Type: "html", XPath: test.StringPtr("//a/@href")},
+				{Type: "split", Text: test.StringPtr("/"), Index: -1}},
+			text: `<a href="/download/1.0.0">a</a><a href="/download/2.0.0">b</a>`,
+			want: []string{"1.0.0", "2.0.0"},
+		},
+		"regex all matches": {
+			slice: &URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`v([0-9.]+)`), Index: 1}},
+			text:       "v1.2.0 v1.10.0 v1.9.0",
+			allMatches: true,
+			want:       []string{"1.2.0", "1.10.0", "1.9.0"},
+		},
+		"regex all matches with template": {
+			slice: &URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`([0-9]+)_([0-9]+)`), Template: test.StringPtr("$1.$2")}},
+			text:       "app-1_2.zip app-1_3.zip",
+			allMatches: true,
+			want:       []string{"1.2", "1.3"},
+		},
+		"regex all matches, none found": {
+			slice: &URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`v([0-9.]+)`)}},
+			text:       "none",
+			allMatches: true,
+			errRegex:   `regex "v\(\[0-9.\]\+\)" didn't return any matches on "none"`,
+		},
+		"split all parts": {
+			slice: &URLCommandSlice{
+				{Type: "split", Text: test.StringPtr(","), Index: 1}},
+			text:       "1.0.0,,1.1.0,",
+			allMatches: true,
+			want:       []string{"1.0.0", "1.1.0"},
+		},
+		"only the final command returns all matches": {
+			slice: &URLCommandSlice{
+				{Type: "split", Text: test.StringPtr("|"), Index: 1},
+				{Type: "regex", Regex: test.StringPtr(`v([0-9.]+)`)}},
+			text:       "v0.1.0 v0.2.0|v1.0.0 v1.1.0",
+			allMatches: true,
+			want:       []string{"1.0.0", "1.1.0"},
+		},
+		"html with index returns all matches when allMatches": {
+			slice: &URLCommandSlice{
+				{Type: "html", Selector: test.StringPtr("b"), Index: 1}},
+			text:       "<b>1.0.0</b><b>1.1.0</b>",
+			allMatches: true,
+			want:       []string{"1.0.0", "1.1.0"},
+		},
+		"json wildcard": {
+			slice: &URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("items[*].tag_name")}},
+			text: `{"items": [{"tag_name": "v1.0.0"}, {"tag_name": "v1.1.0"}]}`,
+			want: []string{"v1.0.0", "v1.1.0"},
+		},
+		"yaml wildcard": {
+			slice: &URLCommandSlice{
+				{Type: "yaml", Key: test.StringPtr("entries.*.version")}},
+			text: "entries:\n  foo:\n    version: 1.0.0\n  bar:\n    version: 2.0.0\n",
+			want: []string{"2.0.0", "1.0.0"},
+		},
+		"json wildcard, then commands ran on each": {
+			slice: &URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("items[*].tag_name")},
+				{Type: "regex", Regex: test.StringPtr(`^v([0-9.]+)$`)}},
+			text: `{"items": [{"tag_name": "v1.0.0"}, {"tag_name": "nightly"}, {"tag_name": "v1.1.0"}]}`,
+			want: []string{"1.0.0", "1.1.0"},
+		},
+		"json wildcard, then commands fail on all": {
+			slice: &URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("items[*].tag_name")},
+				{Type: "regex", Regex: test.StringPtr(`^v([0-9.]+)$`)}},
+			text:     `{"items": [{"tag_name": "nightly"}]}`,
+			errRegex: `regex .* didn't return any matches`,
+		},
+		"json no values found": {
+			slice: &URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("items[*].tag_name")}},
+			text:     `{"items": []}`,
+			errRegex: `json failed to find any values for`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN RunAll is called on it
+			got, err := tc.slice.RunAll(tc.text, tc.allMatches, &util.LogFrom{})
+
+			// THEN the expected values were returned
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("Should have got %q, not %q",
+					tc.want, got)
+			}
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
 func TestURLCommand_String(t *testing.T) {
 	// GIVEN a URLCommand
 	regex := testURLCommandRegex()
@@ -433,6 +670,56 @@ func TestURLCommandSlice_CheckValues(t *testing.T) {
 				{Type: "split"}},
 			errRegex: []string{`^    text: <required>`},
 		},
+		"valid html selector": {
+			slice: &URLCommandSlice{
+				{Type: "html", Selector: test.StringPtr("a.download, td > span"), Attribute: test.StringPtr("href")}},
+			errRegex: []string{`^$`},
+		},
+		"valid html xpath": {
+			slice: &URLCommandSlice{
+				{Type: "html", XPath: test.StringPtr("//a[@class='download']/@href")}},
+			errRegex: []string{`^$`},
+		},
+		"html without selector or xpath": {
+			slice: &URLCommandSlice{
+				{Type: "html"}},
+			errRegex: []string{`^    type: html$`, `^    selector: <required>`},
+		},
+		"html with selector and xpath": {
+			slice: &URLCommandSlice{
+				{Type: "html", Selector: test.StringPtr("a"), XPath: test.StringPtr("//a")}},
+			errRegex: []string{`^    xpath: "//a" <invalid> \(only one of selector/xpath`},
+		},
+		"html with invalid selector": {
+			slice: &URLCommandSlice{
+				{Type: "html", Selector: test.StringPtr("a[")}},
+			errRegex: []string{`^    selector: "a\[" <invalid>`},
+		},
+		"html with invalid xpath": {
+			slice: &URLCommandSlice{
+				{Type: "html", XPath: test.StringPtr("//a[")}},
+			errRegex: []string{`^    xpath: "//a\[" <invalid>`},
+		},
+		"valid json": {
+			slice: &URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("items[*].tag_name")}},
+			errRegex: []string{`^$`},
+		},
+		"valid yaml": {
+			slice: &URLCommandSlice{
+				{Type: "yaml", Key: test.StringPtr("entries.*[0].version")}},
+			errRegex: []string{`^$`},
+		},
+		"json without key": {
+			slice: &URLCommandSlice{
+				{Type: "json"}},
+			errRegex: []string{`^  item_0:$`, `^    type: json$`, `^    key: <required>`},
+		},
+		"yaml with invalid key": {
+			slice: &URLCommandSlice{
+				{Type: "yaml", Key: test.StringPtr("foo[bar")}},
+			errRegex: []string{`^    key: "foo\[bar" <invalid>`},
+		},
 		"invalid type": {
 			slice: &URLCommandSlice{
 				{Type: "something"}},
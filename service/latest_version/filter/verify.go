@@ -0,0 +1,449 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/util"
+	metric "github.com/release-argus/Argus/web/metrics"
+)
+
+var verifySignatureTypes = []string{
+	"cosign", "gpg", "minisign"}
+
+// maxVerifyFileSize is the largest checksums/signature file that will be downloaded.
+const maxVerifyFileSize = 1 << 20 // 1 MiB
+
+// maxSignedSize is the largest asset that will be read into memory
+// for signatures of the data itself (rather than of a hash of it).
+const maxSignedSize = 128 << 20 // 128 MiB
+
+// VerifyCheck downloads an asset of the release and verifies it
+// with a checksums file and/or a detached signature.
+type VerifyCheck struct {
+	Asset     string           `yaml:"asset,omitempty" json:"asset,omitempty"`         // "myapp_{{ version }}_linux_amd64\.tar\.gz" RegEx of the asset to verify
+	Checksums string           `yaml:"checksums,omitempty" json:"checksums,omitempty"` // "SHA256SUMS" RegEx of the asset holding the checksums of the other assets
+	Signature *VerifySignature `yaml:"signature,omitempty" json:"signature,omitempty"` // Detached signature of the checksums (or of the asset when there are no checksums)
+
+	verified string     // Version that last passed verification
+	mutex    sync.Mutex // Mutex for verified
+}
+
+// AssetDownloader downloads the assets of releases to verify.
+type AssetDownloader struct {
+	Client     *http.Client                                           // Client for the downloads (e.g. with a timeout and the TLS settings of the lookup)
+	NewRequest func(asset *github_types.Asset) (*http.Request, error) // Request to download an asset (e.g. with the credentials of the lookup), NewAssetRequest if nil
+}
+
+// VerifySignature is a detached signature to verify with a local public key.
+type VerifySignature struct {
+	Type      string `yaml:"type,omitempty" json:"type,omitempty"`             // "cosign"/"gpg"/"minisign"
+	Asset     string `yaml:"asset,omitempty" json:"asset,omitempty"`           // "SHA256SUMS\.asc" RegEx of the signature asset
+	PublicKey string `yaml:"public_key,omitempty" json:"public_key,omitempty"` // Path to the public key(s) to verify the signature with
+}
+
+// String returns a string representation of the VerifyCheck.
+func (v *VerifyCheck) String(prefix string) (str string) {
+	if v != nil {
+		str = util.ToYAMLString(v, prefix)
+	}
+	return
+}
+
+// CheckValues of the VerifyCheck.
+func (v *VerifyCheck) CheckValues(prefix string) (errs error) {
+	if v == nil {
+		return
+	}
+
+	// Asset
+	if v.Asset == "" {
+		errs = fmt.Errorf("%s%sasset: <required> (RegEx of the asset to verify)\\",
+			util.ErrorToString(errs), prefix)
+	} else if err := checkVerifyRegex(v.Asset); err != "" {
+		errs = fmt.Errorf("%s%sasset: %q <invalid> (%s)\\",
+			util.ErrorToString(errs), prefix, v.Asset, err)
+	}
+
+	// Checksums
+	if v.Checksums != "" {
+		if err := checkVerifyRegex(v.Checksums); err != "" {
+			errs = fmt.Errorf("%s%schecksums: %q <invalid> (%s)\\",
+				util.ErrorToString(errs), prefix, v.Checksums, err)
+		}
+	} else if v.Signature == nil {
+		errs = fmt.Errorf("%s%schecksums: <required> (checksums and/or a signature are needed to verify the asset)\\",
+			util.ErrorToString(errs), prefix)
+	}
+
+	// Signature
+	if err := v.Signature.CheckValues(prefix + "  "); err != nil {
+		errs = fmt.Errorf("%s%ssignature:\\%w",
+			util.ErrorToString(errs), prefix, err)
+	}
+
+	return
+}
+
+// CheckValues of the VerifySignature.
+func (s *VerifySignature) CheckValues(prefix string) (errs error) {
+	if s == nil {
+		return
+	}
+
+	// Type
+	if !util.Contains(verifySignatureTypes, s.Type) {
+		errType := "<required>"
+		if s.Type != "" {
+			errType = fmt.Sprintf("%q <invalid>", s.Type)
+		}
+		errs = fmt.Errorf("%s%stype: %s (supported types = [%s])\\",
+			util.ErrorToString(errs), prefix, errType, strings.Join(verifySignatureTypes, ", "))
+	}
+
+	// Asset
+	if s.Asset == "" {
+		errs = fmt.Errorf("%s%sasset: <required> (RegEx of the signature asset)\\",
+			util.ErrorToString(errs), prefix)
+	} else if err := checkVerifyRegex(s.Asset); err != "" {
+		errs = fmt.Errorf("%s%sasset: %q <invalid> (%s)\\",
+			util.ErrorToString(errs), prefix, s.Asset, err)
+	}
+
+	// Public Key
+	if s.PublicKey == "" {
+		errs = fmt.Errorf("%s%spublic_key: <required> (path to the public key)\\",
+			util.ErrorToString(errs), prefix)
+	} else if errs == nil {
+		if _, err := s.verifier(); err != nil {
+			errs = fmt.Errorf("%s%spublic_key: %q <invalid> (%s)\\",
+				util.ErrorToString(errs), prefix, s.PublicKey, err)
+		}
+	}
+
+	return
+}
+
+// checkVerifyRegex returns why `re` isn't a valid templated RegEx (empty if it is).
+func checkVerifyRegex(re string) string {
+	if !util.CheckTemplate(re) {
+		return "didn't pass templating"
+	}
+	if _, err := regexp.Compile(re); err != nil {
+		return "Invalid RegEx"
+	}
+	return ""
+}
+
+// signatureVerifier verifies detached signatures with the public key(s) it was created with.
+type signatureVerifier interface {
+	Verify(signed io.Reader, signature []byte) error
+}
+
+// verifier reads the PublicKey and returns a signatureVerifier for this Type of signature.
+func (s *VerifySignature) verifier() (signatureVerifier, error) {
+	publicKey, err := os.ReadFile(s.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read: %w", err)
+	}
+
+	switch s.Type {
+	case "cosign":
+		return newCosignVerifier(publicKey)
+	case "gpg":
+		return newGPGVerifier(publicKey)
+	case "minisign":
+		return newMinisignVerifier(publicKey)
+	}
+	return nil, fmt.Errorf("unsupported signature type %q", s.Type)
+}
+
+// VerifyRelease downloads the `verify.asset` of the release of `version` with `downloader`
+// and returns an error if it doesn't match its checksum or signature.
+func (r *Require) VerifyRelease(
+	version string,
+	assets []github_types.Asset,
+	downloader *AssetDownloader,
+	logFrom *util.LogFrom,
+) error {
+	if r == nil || r.Verify == nil {
+		return nil
+	}
+
+	// Already verified this version.
+	r.Verify.mutex.Lock()
+	verified := r.Verify.verified
+	r.Verify.mutex.Unlock()
+	if verified == version {
+		return nil
+	}
+
+	err := r.Verify.verify(version, assets, downloader)
+	result := "SUCCESS"
+	if err != nil {
+		result = "FAIL"
+		err = fmt.Errorf("verification of version %q failed: %w",
+			version, err)
+		jLog.Warn(err, logFrom, true)
+	} else {
+		r.Verify.mutex.Lock()
+		r.Verify.verified = version
+		r.Verify.mutex.Unlock()
+		jLog.Info(
+			fmt.Sprintf("verified %q of version %q", r.Verify.assetName(version, assets), version),
+			logFrom, true)
+	}
+	if r.Status != nil && r.Status.ServiceID != nil {
+		metric.IncreasePrometheusCounter(metric.LatestVersionVerifyMetric,
+			*r.Status.ServiceID,
+			"",
+			"",
+			result)
+	}
+
+	return err
+}
+
+// verify the asset of the release of `version`.
+func (v *VerifyCheck) verify(version string, assets []github_types.Asset, downloader *AssetDownloader) error {
+	asset, err := findAsset(v.Asset, version, assets)
+	if err != nil {
+		return err
+	}
+
+	// The checksums file is signed if there is one, otherwise the asset is.
+	var checksums []byte
+	if v.Checksums != "" {
+		checksumsAsset, err := findAsset(v.Checksums, version, assets)
+		if err != nil {
+			return err
+		}
+		if checksums, err = downloader.download(checksumsAsset, maxVerifyFileSize); err != nil {
+			return err
+		}
+		if err := downloader.verifyChecksum(asset, checksums); err != nil {
+			return err
+		}
+	}
+
+	if v.Signature == nil {
+		return nil
+	}
+	signatureAsset, err := findAsset(v.Signature.Asset, version, assets)
+	if err != nil {
+		return err
+	}
+	signature, err := downloader.download(signatureAsset, maxVerifyFileSize)
+	if err != nil {
+		return err
+	}
+	verifier, err := v.Signature.verifier()
+	if err != nil {
+		return fmt.Errorf("public_key %q - %w",
+			v.Signature.PublicKey, err)
+	}
+
+	var signed io.Reader
+	if checksums != nil {
+		signed = bytes.NewReader(checksums)
+	} else {
+		resp, err := downloader.get(asset)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		signed = resp.Body
+	}
+	if err := verifier.Verify(signed, signature); err != nil {
+		return fmt.Errorf("%s signature %q - %w",
+			v.Signature.Type, signatureAsset.Name, err)
+	}
+
+	return nil
+}
+
+// assetName returns the name of the asset to verify for `version`.
+func (v *VerifyCheck) assetName(version string, assets []github_types.Asset) string {
+	asset, err := findAsset(v.Asset, version, assets)
+	if err != nil {
+		return v.Asset
+	}
+	return asset.Name
+}
+
+// findAsset returns the asset whose name matches the (templated) regex `re`.
+func findAsset(re string, version string, assets []github_types.Asset) (*github_types.Asset, error) {
+	for i := range assets {
+		if util.RegexCheckWithParams(re, assets[i].Name, version) {
+			return &assets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no asset matching %q",
+		util.TemplateString(re, util.ServiceInfo{LatestVersion: version}))
+}
+
+// NewAssetRequest returns a GET request for the download URL of `asset`.
+func NewAssetRequest(asset *github_types.Asset) (*http.Request, error) {
+	url := util.FirstNonDefault(asset.BrowserDownloadURL, asset.URL)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating http request for %q: %w",
+			url, err)
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+	return req, nil
+}
+
+// get returns the response of a GET on the download URL of `asset`.
+func (d *AssetDownloader) get(asset *github_types.Asset) (*http.Response, error) {
+	newRequest := d.NewRequest
+	if newRequest == nil {
+		newRequest = NewAssetRequest
+	}
+	req, err := newRequest(asset)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %q: %w",
+			asset.Name, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to download %q: %s",
+			asset.Name, resp.Status)
+	}
+	return resp, nil
+}
+
+// download returns the contents of `asset`,
+// failing if it's larger than `maxSize`.
+func (d *AssetDownloader) download(asset *github_types.Asset, maxSize int64) ([]byte, error) {
+	resp, err := d.get(asset)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := readAllLimited(resp.Body, maxSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %q: %w",
+			asset.Name, err)
+	}
+	return data, nil
+}
+
+// readAllLimited returns the contents of `r`,
+// failing if it's larger than `maxSize`.
+func readAllLimited(r io.Reader, maxSize int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("larger than %d bytes",
+			maxSize)
+	}
+	return data, nil
+}
+
+// bsdChecksumRegex matches a line of a BSD style checksums file.
+//
+// e.g. SHA256 (myapp.tar.gz) = 0123...
+var bsdChecksumRegex = regexp.MustCompile(`^[A-Z0-9-]+ \((.+)\) = ([0-9a-fA-F]+)$`)
+
+// findChecksum returns the checksum of `name` in `checksums`.
+//
+// Lines may be either GNU style ('<checksum>  <name>', '<checksum> *<name>')
+// or BSD style ('SHA256 (<name>) = <checksum>').
+func findChecksum(name string, checksums []byte) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		var checksum, file string
+		if match := bsdChecksumRegex.FindStringSubmatch(line); match != nil {
+			file, checksum = match[1], match[2]
+		} else {
+			var found bool
+			checksum, file, found = strings.Cut(line, " ")
+			if !found {
+				continue
+			}
+			file = strings.TrimPrefix(strings.TrimSpace(file), "*")
+		}
+
+		if path.Base(file) == name {
+			return strings.ToLower(checksum), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum for %q in the checksums file",
+		name)
+}
+
+// verifyChecksum downloads `asset` and returns an error if its checksum
+// doesn't match the one listed in `checksums`.
+func (d *AssetDownloader) verifyChecksum(asset *github_types.Asset, checksums []byte) error {
+	want, err := findChecksum(asset.Name, checksums)
+	if err != nil {
+		return err
+	}
+
+	var hasher hash.Hash
+	switch len(want) {
+	case sha256.Size * 2:
+		hasher = sha256.New()
+	case sha512.Size384 * 2:
+		hasher = sha512.New384()
+	case sha512.Size * 2:
+		hasher = sha512.New()
+	default:
+		return fmt.Errorf("unsupported checksum %q for %q (want SHA256/SHA384/SHA512)",
+			want, asset.Name)
+	}
+
+	resp, err := d.get(asset)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(hasher, resp.Body); err != nil {
+		return fmt.Errorf("failed to download %q: %w",
+			asset.Name, err)
+	}
+
+	if got := hex.EncodeToString(hasher.Sum(nil)); got != want {
+		return fmt.Errorf("checksum mismatch for %q (want %s, got %s)",
+			asset.Name, want, got)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// cosignVerifier verifies `cosign sign-blob` signatures.
+type cosignVerifier struct {
+	publicKey crypto.PublicKey
+}
+
+// newCosignVerifier from the PEM encoded `publicKey` (e.g. cosign.pub).
+func newCosignVerifier(publicKey []byte) (*cosignVerifier, error) {
+	block, _ := pem.Decode(publicKey)
+	if block == nil {
+		return nil, errors.New("no PEM encoded public key found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the public key: %w", err)
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey, *rsa.PublicKey:
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+
+	return &cosignVerifier{publicKey: key}, nil
+}
+
+// Verify the base64 encoded `signature` of `signed`.
+func (c *cosignVerifier) Verify(signed io.Reader, signature []byte) error {
+	sig, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+	if err != nil {
+		return fmt.Errorf("failed to decode the signature: %w", err)
+	}
+
+	// Ed25519 signs the data, the others sign its SHA256 hash.
+	if key, ok := c.publicKey.(ed25519.PublicKey); ok {
+		data, err := readAllLimited(signed, maxSignedSize)
+		if err != nil {
+			return err
+		}
+		if !ed25519.Verify(key, data, sig) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	}
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, signed); err != nil {
+		return err
+	}
+	digest := hasher.Sum(nil)
+
+	var valid bool
+	switch key := c.publicKey.(type) {
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(key, digest, sig)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, sig) == nil
+	}
+	if !valid {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
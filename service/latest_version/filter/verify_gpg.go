@@ -0,0 +1,76 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+)
+
+// gpgVerifier verifies detached OpenPGP signatures (e.g. `gpg --detach-sign`)
+// with the keys of a public keyring (e.g. `gpg --export`).
+type gpgVerifier struct {
+	keyring openpgp.EntityList
+}
+
+// newGPGVerifier from the armored or binary `publicKey` keyring.
+func newGPGVerifier(publicKey []byte) (*gpgVerifier, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(publicKey))
+	if err != nil {
+		keyring, err = openpgp.ReadKeyRing(bytes.NewReader(publicKey))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the public key: %w", err)
+	}
+
+	return &gpgVerifier{keyring: keyring}, nil
+}
+
+// Verify the armored or binary `signature` of `signed`.
+func (g *gpgVerifier) Verify(signed io.Reader, signature []byte) error {
+	if block, err := armor.Decode(bytes.NewReader(signature)); err == nil {
+		if block.Type != openpgp.SignatureType {
+			return errors.New("no signature found")
+		}
+		if signature, err = io.ReadAll(block.Body); err != nil {
+			return fmt.Errorf("failed to decode the signature: %w", err)
+		}
+	}
+	p, err := packet.NewReader(bytes.NewReader(signature)).Next()
+	sig, ok := p.(*packet.Signature)
+	if err != nil || !ok {
+		return errors.New("no signature found")
+	}
+
+	_, err = openpgp.CheckDetachedSignature(g.keyring, signed, bytes.NewReader(signature), nil)
+	var signatureErr pgperrors.SignatureError
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, pgperrors.ErrUnknownIssuer) && sig.IssuerKeyId != nil:
+		return fmt.Errorf("signed with key %016X, which isn't in the public_key",
+			*sig.IssuerKeyId)
+	case errors.As(err, &signatureErr):
+		return errors.New("invalid signature")
+	}
+	return err
+}
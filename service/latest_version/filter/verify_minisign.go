@@ -0,0 +1,125 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// minisignVerifier verifies minisign signatures.
+type minisignVerifier struct {
+	keyID     [8]byte
+	publicKey ed25519.PublicKey
+}
+
+// minisignLines returns the lines of a minisign file, without the untrusted comment.
+func minisignLines(file []byte) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(string(file), "\r\n", "\n"), "\n") {
+		if line == "" || strings.HasPrefix(line, "untrusted comment:") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// newMinisignVerifier from the minisign `publicKey` (e.g. minisign.pub).
+func newMinisignVerifier(publicKey []byte) (*minisignVerifier, error) {
+	lines := minisignLines(publicKey)
+	if len(lines) == 0 {
+		return nil, errors.New("no minisign public key found")
+	}
+	key, err := base64.StdEncoding.DecodeString(lines[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the public key: %w", err)
+	}
+	// "Ed" + key ID + public key
+	if len(key) != 2+8+ed25519.PublicKeySize || string(key[:2]) != "Ed" {
+		return nil, errors.New("not a minisign Ed25519 public key")
+	}
+
+	verifier := &minisignVerifier{
+		publicKey: ed25519.PublicKey(key[10:])}
+	copy(verifier.keyID[:], key[2:10])
+	return verifier, nil
+}
+
+// Verify the minisign `signature` of `signed`.
+func (m *minisignVerifier) Verify(signed io.Reader, signature []byte) error {
+	// signature, trusted comment, global signature
+	lines := minisignLines(signature)
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "trusted comment: ") {
+		return errors.New("invalid minisign signature file")
+	}
+	sig, err := base64.StdEncoding.DecodeString(lines[0])
+	if err != nil || len(sig) != 2+8+ed25519.SignatureSize {
+		return errors.New("invalid minisign signature")
+	}
+	globalSig, err := base64.StdEncoding.DecodeString(lines[2])
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return errors.New("invalid minisign global signature")
+	}
+	if !bytes.Equal(sig[2:10], m.keyID[:]) {
+		return fmt.Errorf("signed with key ID %X, not %X",
+			reverseBytes(sig[2:10]), reverseBytes(m.keyID[:]))
+	}
+
+	// "Ed" signs the data, "ED" signs the BLAKE2b-512 hash of it.
+	var message []byte
+	switch string(sig[:2]) {
+	case "Ed":
+		if message, err = readAllLimited(signed, maxSignedSize); err != nil {
+			return err
+		}
+	case "ED":
+		hasher, _ := blake2b.New512(nil)
+		if _, err := io.Copy(hasher, signed); err != nil {
+			return err
+		}
+		message = hasher.Sum(nil)
+	default:
+		return fmt.Errorf("unsupported signature algorithm %q", sig[:2])
+	}
+	if !ed25519.Verify(m.publicKey, message, sig[10:]) {
+		return errors.New("invalid signature")
+	}
+
+	// The global signature covers the signature and the trusted comment.
+	trustedComment := strings.TrimPrefix(lines[1], "trusted comment: ")
+	signedComment := append(append([]byte{}, sig[10:]...), trustedComment...)
+	if !ed25519.Verify(m.publicKey, signedComment, globalSig) {
+		return errors.New("invalid global signature (trusted comment)")
+	}
+	return nil
+}
+
+// reverseBytes returns a reversed copy of `b`
+// (minisign displays its little-endian key IDs reversed).
+func reverseBytes(b []byte) []byte {
+	reversed := make([]byte, len(b))
+	for i := range b {
+		reversed[len(b)-1-i] = b[i]
+	}
+	return reversed
+}
@@ -0,0 +1,577 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//go:build unit
+
+package filter
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	svcstatus "github.com/release-argus/Argus/service/status"
+	"github.com/release-argus/Argus/util"
+	metric "github.com/release-argus/Argus/web/metrics"
+)
+
+// verifyTestData is the directory holding the checksums/signature fixtures.
+var verifyTestData = filepath.Join("testdata", "verify")
+
+func readVerifyTestData(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(verifyTestData, name))
+	if err != nil {
+		t.Fatalf("failed to read %q: %v", name, err)
+	}
+	return data
+}
+
+func TestVerifySignature_Verify(t *testing.T) {
+	// GIVEN a public key, a signature and the data it's for
+	tests := map[string]struct {
+		sigType   string
+		publicKey string
+		signature string
+		data      string
+		tamper    bool
+		errRegex  string
+	}{
+		"gpg - ed25519 armored": {
+			sigType:   "gpg",
+			publicKey: "gpg_ed25519.asc",
+			signature: "SHA256SUMS.asc",
+			data:      "SHA256SUMS"},
+		"gpg - ed25519 binary": {
+			sigType:   "gpg",
+			publicKey: "gpg_ed25519.asc",
+			signature: "SHA256SUMS.sig",
+			data:      "SHA256SUMS"},
+		"gpg - rsa signing subkey": {
+			sigType:   "gpg",
+			publicKey: "gpg_rsa.asc",
+			signature: "SHA256SUMS.rsa.asc",
+			data:      "SHA256SUMS"},
+		"gpg - signature of the asset": {
+			sigType:   "gpg",
+			publicKey: "gpg_ed25519.asc",
+			signature: "myapp_1.2.3_linux_amd64.tar.gz.asc",
+			data:      "myapp_1.2.3_linux_amd64.tar.gz"},
+		"gpg - tampered data": {
+			sigType:   "gpg",
+			publicKey: "gpg_ed25519.asc",
+			signature: "SHA256SUMS.asc",
+			data:      "SHA256SUMS",
+			tamper:    true,
+			errRegex:  `^invalid signature$`},
+		"gpg - signed by another key": {
+			sigType:   "gpg",
+			publicKey: "gpg_ed25519.asc",
+			signature: "SHA256SUMS.rsa.asc",
+			data:      "SHA256SUMS",
+			errRegex:  `^signed with key [0-9A-F]{16}, which isn't in the public_key$`},
+		"gpg - not a signature": {
+			sigType:   "gpg",
+			publicKey: "gpg_ed25519.asc",
+			signature: "gpg_rsa.asc",
+			data:      "SHA256SUMS",
+			errRegex:  `^no signature found$`},
+		"minisign - prehashed": {
+			sigType:   "minisign",
+			publicKey: "minisign.pub",
+			signature: "SHA256SUMS.minisig",
+			data:      "SHA256SUMS"},
+		"minisign - legacy": {
+			sigType:   "minisign",
+			publicKey: "minisign.pub",
+			signature: "myapp_1.2.3_linux_amd64.tar.gz.minisig",
+			data:      "myapp_1.2.3_linux_amd64.tar.gz"},
+		"minisign - tampered data": {
+			sigType:   "minisign",
+			publicKey: "minisign.pub",
+			signature: "SHA256SUMS.minisig",
+			data:      "SHA256SUMS",
+			tamper:    true,
+			errRegex:  `^invalid signature$`},
+		"minisign - not a minisign signature": {
+			sigType:   "minisign",
+			publicKey: "minisign.pub",
+			signature: "SHA256SUMS.asc",
+			data:      "SHA256SUMS",
+			errRegex:  `^invalid minisign signature`},
+		"cosign - ecdsa": {
+			sigType:   "cosign",
+			publicKey: "cosign.pub",
+			signature: "SHA256SUMS.cosign.sig",
+			data:      "SHA256SUMS"},
+		"cosign - tampered data": {
+			sigType:   "cosign",
+			publicKey: "cosign.pub",
+			signature: "SHA256SUMS.cosign.sig",
+			data:      "SHA256SUMS",
+			tamper:    true,
+			errRegex:  `^invalid signature$`},
+		"cosign - not base64": {
+			sigType:   "cosign",
+			publicKey: "cosign.pub",
+			signature: "SHA256SUMS.asc",
+			data:      "SHA256SUMS",
+			errRegex:  `^failed to decode the signature`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			signature := VerifySignature{
+				Type:      tc.sigType,
+				PublicKey: filepath.Join(verifyTestData, tc.publicKey)}
+			verifier, err := signature.verifier()
+			if err != nil {
+				t.Fatalf("failed to read the public key: %v", err)
+			}
+			data := readVerifyTestData(t, tc.data)
+			if tc.tamper {
+				data = append(data, '\n')
+			}
+
+			// WHEN Verify is called with the signature of the data
+			err = verifier.Verify(bytes.NewReader(data), readVerifyTestData(t, tc.signature))
+
+			// THEN the err is what we expect
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestVerifyCheck_CheckValues(t *testing.T) {
+	// GIVEN a VerifyCheck
+	tests := map[string]struct {
+		verify   *VerifyCheck
+		errRegex []string
+	}{
+		"nil": {
+			verify:   nil,
+			errRegex: []string{`^$`}},
+		"valid checksums": {
+			verify: &VerifyCheck{
+				Asset:     `myapp_{{ version }}_linux_amd64\.tar\.gz`,
+				Checksums: "SHA256SUMS"},
+			errRegex: []string{`^$`}},
+		"valid signature": {
+			verify: &VerifyCheck{
+				Asset: `myapp_{{ version }}_linux_amd64\.tar\.gz`,
+				Signature: &VerifySignature{
+					Type:      "minisign",
+					Asset:     `\.minisig$`,
+					PublicKey: filepath.Join(verifyTestData, "minisign.pub")}},
+			errRegex: []string{`^$`}},
+		"no asset": {
+			verify: &VerifyCheck{
+				Checksums: "SHA256SUMS"},
+			errRegex: []string{
+				`^asset: <required>`}},
+		"invalid regexes": {
+			verify: &VerifyCheck{
+				Asset:     "[0-",
+				Checksums: "{{ version }"},
+			errRegex: []string{
+				`^asset: "\[0-" <invalid> \(Invalid RegEx\)$`,
+				`^checksums: "{{ version }" <invalid> \(didn't pass templating\)$`}},
+		"no checksums or signature": {
+			verify: &VerifyCheck{
+				Asset: "myapp"},
+			errRegex: []string{
+				`^checksums: <required> \(checksums and/or a signature`}},
+		"invalid signature": {
+			verify: &VerifyCheck{
+				Asset:     "myapp",
+				Signature: &VerifySignature{Type: "pgp"}},
+			errRegex: []string{
+				`^signature:$`,
+				`^  type: "pgp" <invalid> \(supported types = \[cosign, gpg, minisign\]\)$`,
+				`^  asset: <required>`,
+				`^  public_key: <required>`}},
+		"missing public key": {
+			verify: &VerifyCheck{
+				Asset: "myapp",
+				Signature: &VerifySignature{
+					Type:      "gpg",
+					Asset:     `\.asc$`,
+					PublicKey: filepath.Join(verifyTestData, "missing.asc")}},
+			errRegex: []string{
+				`^signature:$`,
+				`^  public_key: "[^"]+" <invalid> \(failed to read`}},
+		"wrong type of public key": {
+			verify: &VerifyCheck{
+				Asset: "myapp",
+				Signature: &VerifySignature{
+					Type:      "cosign",
+					Asset:     `\.sig$`,
+					PublicKey: filepath.Join(verifyTestData, "minisign.pub")}},
+			errRegex: []string{
+				`^signature:$`,
+				`^  public_key: "[^"]+" <invalid> \(no PEM encoded public key found\)$`}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN CheckValues is called on it
+			err := tc.verify.CheckValues("")
+
+			// THEN err is expected
+			e := util.ErrorToString(err)
+			lines := strings.Split(e, `\`)
+			for i := range tc.errRegex {
+				re := regexp.MustCompile(tc.errRegex[i])
+				found := false
+				for j := range lines {
+					if re.MatchString(lines[j]) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("want match for: %q\ngot:  %q",
+						tc.errRegex[i], strings.ReplaceAll(e, `\`, "\n"))
+				}
+			}
+		})
+	}
+}
+
+func TestFindChecksum(t *testing.T) {
+	// GIVEN a checksums file
+	tests := map[string]struct {
+		checksums string
+		want      string
+		errRegex  string
+	}{
+		"GNU style": {
+			checksums: "abc123  other.tar.gz\nDEF456  myapp.tar.gz\n",
+			want:      "def456"},
+		"GNU binary style": {
+			checksums: "def456 *myapp.tar.gz",
+			want:      "def456"},
+		"BSD style": {
+			checksums: "SHA256 (other.tar.gz) = abc123\nSHA256 (myapp.tar.gz) = def456",
+			want:      "def456"},
+		"path prefix": {
+			checksums: "def456  ./dist/myapp.tar.gz",
+			want:      "def456"},
+		"not listed": {
+			checksums: "abc123  myapp.tar.gz.sig",
+			errRegex:  `^no checksum for "myapp.tar.gz" in the checksums file$`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN findChecksum is called for an asset
+			got, err := findChecksum("myapp.tar.gz", []byte(tc.checksums))
+
+			// THEN the err is what we expect
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the checksum is found
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestReadAllLimited(t *testing.T) {
+	// GIVEN some data and a max size
+	tests := map[string]struct {
+		data     string
+		maxSize  int64
+		errRegex string
+	}{
+		"smaller than the max": {
+			data:    "abc",
+			maxSize: 4},
+		"exactly the max": {
+			data:    "abcd",
+			maxSize: 4},
+		"larger than the max": {
+			data:     "abcde",
+			maxSize:  4,
+			errRegex: `^larger than 4 bytes$`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN readAllLimited is called on it
+			got, err := readAllLimited(strings.NewReader(tc.data), tc.maxSize)
+
+			// THEN the err is what we expect
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the data is returned if it's not too large
+			if err == nil && string(got) != tc.data {
+				t.Errorf("want %q, not %q",
+					tc.data, string(got))
+			}
+		})
+	}
+}
+
+func TestRequire_VerifyRelease(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir(verifyTestData)))
+	t.Cleanup(server.Close)
+	downloader := &AssetDownloader{Client: server.Client()}
+	asset := func(name string, file string) github_types.Asset {
+		return github_types.Asset{
+			Name:               name,
+			BrowserDownloadURL: server.URL + "/" + file}
+	}
+	assets := []github_types.Asset{
+		asset("myapp_1.2.3_linux_amd64.tar.gz", "myapp_1.2.3_linux_amd64.tar.gz"),
+		asset("myapp_1.2.3_linux_amd64.tar.gz.minisig", "myapp_1.2.3_linux_amd64.tar.gz.minisig"),
+		asset("SHA256SUMS", "SHA256SUMS"),
+		asset("SHA256SUMS.asc", "SHA256SUMS.asc"),
+		asset("SHA256SUMS.minisig", "SHA256SUMS.minisig"),
+		asset("SHA256SUMS.cosign.sig", "SHA256SUMS.cosign.sig")}
+	// GIVEN a Require with a verify
+	tests := map[string]struct {
+		verify   *VerifyCheck
+		assets   []github_types.Asset
+		errRegex string
+	}{
+		"nil verify": {
+			verify: nil,
+			assets: assets},
+		"checksum": {
+			verify: &VerifyCheck{
+				Asset:     `myapp_{{ version }}_linux_amd64\.tar\.gz$`,
+				Checksums: `^SHA256SUMS$`},
+			assets: assets},
+		"checksum mismatch": {
+			verify: &VerifyCheck{
+				Asset:     `myapp_{{ version }}_linux_amd64\.tar\.gz$`,
+				Checksums: `^SHA256SUMS$`},
+			assets: []github_types.Asset{
+				asset("myapp_1.2.3_linux_amd64.tar.gz", "myapp_1.2.3_darwin_arm64.tar.gz"),
+				asset("SHA256SUMS", "SHA256SUMS")},
+			errRegex: `checksum mismatch for "myapp_1.2.3_linux_amd64.tar.gz" \(want 4bdda7c3[0-9a-f]+, got 1c7a6635[0-9a-f]+\)$`},
+		"checksums and gpg signature": {
+			verify: &VerifyCheck{
+				Asset:     `myapp_{{ version }}_linux_amd64\.tar\.gz$`,
+				Checksums: `^SHA256SUMS$`,
+				Signature: &VerifySignature{
+					Type:      "gpg",
+					Asset:     `^SHA256SUMS\.asc$`,
+					PublicKey: filepath.Join(verifyTestData, "gpg_ed25519.asc")}},
+			assets: assets},
+		"checksums and minisign signature": {
+			verify: &VerifyCheck{
+				Asset:     `myapp_{{ version }}_linux_amd64\.tar\.gz$`,
+				Checksums: `^SHA256SUMS$`,
+				Signature: &VerifySignature{
+					Type:      "minisign",
+					Asset:     `^SHA256SUMS\.minisig$`,
+					PublicKey: filepath.Join(verifyTestData, "minisign.pub")}},
+			assets: assets},
+		"checksums and cosign signature": {
+			verify: &VerifyCheck{
+				Asset:     `myapp_{{ version }}_linux_amd64\.tar\.gz$`,
+				Checksums: `^SHA256SUMS$`,
+				Signature: &VerifySignature{
+					Type:      "cosign",
+					Asset:     `^SHA256SUMS\.cosign\.sig$`,
+					PublicKey: filepath.Join(verifyTestData, "cosign.pub")}},
+			assets: assets},
+		"signature of the asset": {
+			verify: &VerifyCheck{
+				Asset: `myapp_{{ version }}_linux_amd64\.tar\.gz$`,
+				Signature: &VerifySignature{
+					Type:      "minisign",
+					Asset:     `myapp_{{ version }}_linux_amd64\.tar\.gz\.minisig$`,
+					PublicKey: filepath.Join(verifyTestData, "minisign.pub")}},
+			assets: assets},
+		"signature by another key": {
+			verify: &VerifyCheck{
+				Asset:     `myapp_{{ version }}_linux_amd64\.tar\.gz$`,
+				Checksums: `^SHA256SUMS$`,
+				Signature: &VerifySignature{
+					Type:      "gpg",
+					Asset:     `^SHA256SUMS\.asc$`,
+					PublicKey: filepath.Join(verifyTestData, "gpg_rsa.asc")}},
+			assets:   assets,
+			errRegex: `gpg signature "SHA256SUMS.asc" - signed with key [0-9A-F]+, which isn't in the public_key$`},
+		"signature of other data": {
+			verify: &VerifyCheck{
+				Asset: `myapp_{{ version }}_linux_amd64\.tar\.gz$`,
+				Signature: &VerifySignature{
+					Type:      "minisign",
+					Asset:     `^SHA256SUMS\.minisig$`,
+					PublicKey: filepath.Join(verifyTestData, "minisign.pub")}},
+			assets:   assets,
+			errRegex: `minisign signature "SHA256SUMS.minisig" - invalid signature$`},
+		"asset not on the release": {
+			verify: &VerifyCheck{
+				Asset:     `myapp_{{ version }}_windows_amd64\.zip$`,
+				Checksums: `^SHA256SUMS$`},
+			assets:   assets,
+			errRegex: `no asset matching "myapp_1.2.3_windows_amd64\\\\.zip\$"$`},
+		"asset not downloadable": {
+			verify: &VerifyCheck{
+				Asset:     `myapp_{{ version }}_linux_amd64\.tar\.gz$`,
+				Checksums: `^SHA256SUMS$`},
+			assets: []github_types.Asset{
+				asset("myapp_1.2.3_linux_amd64.tar.gz", "myapp_1.2.3_linux_amd64.tar.gz"),
+				asset("SHA256SUMS", "missing")},
+			errRegex: `failed to download "SHA256SUMS": 404 Not Found$`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			serviceID := "TestRequire_VerifyRelease_" + name
+			require := &Require{
+				Status: &svcstatus.Status{ServiceID: &serviceID},
+				Verify: tc.verify}
+
+			// WHEN VerifyRelease is called on it
+			err := require.VerifyRelease("1.2.3", tc.assets, downloader, &util.LogFrom{})
+
+			// THEN the err is what we expect
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if tc.verify == nil {
+				return
+			}
+			// AND the result is counted
+			wantResult := map[bool]string{true: "SUCCESS", false: "FAIL"}[err == nil]
+			if got := testutil.ToFloat64(metric.LatestVersionVerifyMetric.WithLabelValues(serviceID, wantResult)); got != 1 {
+				t.Errorf("want %s metric of 1, not %f",
+					wantResult, got)
+			}
+			// AND a verified version isn't downloaded again
+			if err == nil {
+				require.VerifyRelease("1.2.3", nil, downloader, &util.LogFrom{})
+				if got := testutil.ToFloat64(metric.LatestVersionVerifyMetric.WithLabelValues(serviceID, wantResult)); got != 1 {
+					t.Errorf("want %s metric of 1 after verifying again, not %f",
+						wantResult, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRequire_VerifyRelease_Downloader(t *testing.T) {
+	// GIVEN a Require with a verify of assets that are slow to download
+	unblock := make(chan struct{})
+	var gotHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+		gotHeader = r.Header.Get("Authorization")
+		http.FileServer(http.Dir(verifyTestData)).ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	downloader := &AssetDownloader{
+		Client: server.Client(),
+		NewRequest: func(asset *github_types.Asset) (*http.Request, error) {
+			req, err := NewAssetRequest(asset)
+			if err == nil {
+				req.Header.Set("Authorization", "token secret")
+			}
+			return req, err
+		}}
+	assets := []github_types.Asset{
+		{Name: "SHA256SUMS", BrowserDownloadURL: server.URL + "/SHA256SUMS"},
+		{Name: "myapp_1.2.3_linux_amd64.tar.gz", BrowserDownloadURL: server.URL + "/myapp_1.2.3_linux_amd64.tar.gz"}}
+	serviceID := "TestRequire_VerifyRelease_Downloader"
+	require := &Require{
+		Status: &svcstatus.Status{ServiceID: &serviceID},
+		Verify: &VerifyCheck{
+			Asset:     `myapp_{{ version }}_linux_amd64\.tar\.gz$`,
+			Checksums: `^SHA256SUMS$`,
+			verified:  "1.2.2"}}
+
+	// WHEN VerifyRelease is downloading the assets of a new version
+	done := make(chan error)
+	go func() {
+		done <- require.VerifyRelease("1.2.3", assets, downloader, &util.LogFrom{})
+	}()
+
+	// THEN the verified version can still be checked
+	checked := make(chan error)
+	go func() {
+		checked <- require.VerifyRelease("1.2.2", assets, downloader, &util.LogFrom{})
+	}()
+	select {
+	case err := <-checked:
+		if err != nil {
+			t.Errorf("unexpected err: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("VerifyRelease of the verified version was blocked by the download of another")
+	}
+	close(unblock)
+	// AND the new version is verified with the requests of the downloader
+	if err := <-done; err != nil {
+		t.Errorf("unexpected err: %v", err)
+	}
+	if gotHeader != "token secret" {
+		t.Errorf("want the Authorization of the downloader, not %q",
+			gotHeader)
+	}
+}
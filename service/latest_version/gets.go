@@ -16,6 +16,7 @@ package latestver
 
 import (
 	"fmt"
+	net_url "net/url"
 	"strings"
 
 	"github.com/release-argus/Argus/util"
@@ -28,6 +29,16 @@ func (l *Lookup) GetAccessToken() *string {
 		l.HardDefaults.AccessToken)
 }
 
+// accessToken returns the access token to send with queries of this Lookup's type.
+//
+// The access_token in the defaults is for GitHub, so the other types only use the service's.
+func (l *Lookup) accessToken() string {
+	if l.Type == "github" {
+		return util.DefaultIfNil(l.GetAccessToken())
+	}
+	return util.DefaultIfNil(util.FirstNonNilPtrWithEnv(l.AccessToken))
+}
+
 func (l *Lookup) GetAllowInvalidCerts() bool {
 	return *util.FirstNonNilPtr(
 		l.AllowInvalidCerts,
@@ -35,8 +46,52 @@ func (l *Lookup) GetAllowInvalidCerts() bool {
 		l.HardDefaults.AllowInvalidCerts)
 }
 
+// GetBaseURL returns the base URL of the GitHub API for the github type,
+// the base URL of the Gitea/GitLab instance for the gitea/gitlab types,
+// or the base URL of the registry for the crates/go/npm/pypi types.
+func (l *Lookup) GetBaseURL() (baseURL string) {
+	switch l.Type {
+	case "github":
+		var defaultBaseURL, hardDefaultBaseURL string
+		if l.Defaults != nil {
+			defaultBaseURL = l.Defaults.GitHubBaseURL
+		}
+		if l.HardDefaults != nil {
+			hardDefaultBaseURL = l.HardDefaults.GitHubBaseURL
+		}
+		baseURL = util.FirstNonDefaultWithEnv(
+			l.BaseURL,
+			defaultBaseURL,
+			hardDefaultBaseURL,
+			githubBaseURL)
+	case "gitlab":
+		baseURL = util.FirstNonDefaultWithEnv(
+			l.BaseURL,
+			gitlabBaseURL)
+	default:
+		// "" if not a package registry.
+		baseURL = util.FirstNonDefaultWithEnv(
+			l.BaseURL,
+			packageRegistryBaseURLs[l.Type])
+	}
+	return strings.TrimSuffix(baseURL, "/")
+}
+
+// githubWebURL returns the base URL of the GitHub web interface for the GitHub API in use.
+//
+// e.g. https://api.github.com = https://github.com
+// and https://github.example.com/api/v3 = https://github.example.com
+func (l *Lookup) githubWebURL() string {
+	baseURL := l.GetBaseURL()
+	if baseURL == githubBaseURL {
+		return "https://github.com"
+	}
+	return strings.TrimSuffix(baseURL, "/api/v3")
+}
+
 // ServiceURL (handles the github type where the URL may be `owner/repo`
-// and adds the github.com/ prefix in that case).
+// and adds the github.com/ (or GitHub Enterprise Server) prefix in that case, the gitea/gitlab types where
+// the URL is the repo/project path on the BaseURL, and the container type where the URL is an image).
 func (l *Lookup) ServiceURL(ignoreWebURL bool) (serviceURL string) {
 	if !ignoreWebURL && *l.Status.WebURL != "" {
 		// Don't use this template if `LatestVersion` hasn't been found and is used in `WebURL`.
@@ -54,12 +109,23 @@ func (l *Lookup) ServiceURL(ignoreWebURL bool) (serviceURL string) {
 	if l.Type == "github" {
 		// If it's "owner/repo" rather than a full path.
 		if strings.Count(serviceURL, "/") == 1 {
-			serviceURL = fmt.Sprintf("https://github.com/%s", serviceURL)
+			serviceURL = fmt.Sprintf("%s/%s", l.githubWebURL(), serviceURL)
 		}
+	} else if l.Type == "gitea" || l.Type == "gitlab" {
+		serviceURL = fmt.Sprintf("%s/%s", l.GetBaseURL(), serviceURL)
+	} else if l.Type == "container" {
+		serviceURL = l.containerWebURL()
+	} else if l.isPackageRegistry() {
+		serviceURL = l.packageRegistryWebURL()
 	}
 	return
 }
 
+// GetAllMatches returns whether every match of the final url_command should be considered.
+func (l *Lookup) GetAllMatches() bool {
+	return util.DefaultIfNil(l.AllMatches)
+}
+
 // Get UsePreRelease will return whether GitHub PreReleases are considered valid for new versions.
 func (l *Lookup) GetUsePreRelease() bool {
 	return *util.FirstNonDefault(
@@ -68,18 +134,51 @@ func (l *Lookup) GetUsePreRelease() bool {
 		l.HardDefaults.UsePreRelease)
 }
 
-// GetURL will ensure `url` is a valid GitHub API URL if `urlType` is 'github'
+// GetVersionConstraint returns the semantic version range that versions must satisfy.
+func (l *Lookup) GetVersionConstraint() string {
+	return util.FirstNonDefault(
+		l.VersionConstraint,
+		l.Defaults.VersionConstraint,
+		l.HardDefaults.VersionConstraint)
+}
+
+// GetURL will ensure `url` is a valid GitHub/Gitea/GitLab/package registry API URL if `urlType` is 'github'/'gitea'/'gitlab'/'crates'/'go'/'npm'/'pypi'
 func (l *Lookup) GetURL() string {
 	url := util.EvalEnvVars(l.URL)
-	if l.Type == "github" {
+	switch l.Type {
+	case "github":
 		// Convert "owner/repo" to the API path.
 		if strings.Count(url, "/") == 1 {
 			apiTarget := "releases"
 			if l.GitHubData.TagFallback() {
 				apiTarget = "tags"
 			}
-			url = fmt.Sprintf("https://api.github.com/repos/%s/%s",
-				url, apiTarget)
+			url = fmt.Sprintf("%s/repos/%s/%s",
+				l.GetBaseURL(), url, apiTarget)
+		}
+	case "gitea":
+		// Convert "owner/repo" to the API path.
+		apiTarget := "releases"
+		if l.GitHubData.TagFallback() {
+			apiTarget = "tags"
+		}
+		url = fmt.Sprintf("%s/api/v1/repos/%s/%s",
+			l.GetBaseURL(), url, apiTarget)
+	case "gitlab":
+		// Convert "group/project" to the API path.
+		apiTarget := "releases"
+		if l.GitHubData.TagFallback() {
+			apiTarget = "repository/tags"
+		}
+		url = fmt.Sprintf("%s/api/v4/projects/%s/%s",
+			l.GetBaseURL(), net_url.PathEscape(url), apiTarget)
+	case "crates", "go", "npm", "pypi":
+		// Convert the package name to the API path.
+		url = l.packageRegistryURL(url)
+	case "helm":
+		// Add the index.yaml to the chart repository.
+		if !l.isHelmOCI() {
+			url = helmIndexURL(url)
 		}
 	}
 	return url
@@ -95,6 +95,64 @@ func TestLookup_GetAccessToken(t *testing.T) {
 	}
 }
 
+func TestLookup_accessToken(t *testing.T) {
+	// GIVEN a Lookup
+	tests := map[string]struct {
+		lookupType string
+		root       *string
+		dfault     *string
+		want       string
+	}{
+		"github uses root": {
+			lookupType: "github",
+			root:       test.StringPtr("this"),
+			dfault:     test.StringPtr("not_this"),
+			want:       "this",
+		},
+		"github falls back to the defaults": {
+			lookupType: "github",
+			dfault:     test.StringPtr("this"),
+			want:       "this",
+		},
+		"gitlab uses root": {
+			lookupType: "gitlab",
+			root:       test.StringPtr("this"),
+			dfault:     test.StringPtr("not_this"),
+			want:       "this",
+		},
+		"gitlab doesn't use the defaults": {
+			lookupType: "gitlab",
+			dfault:     test.StringPtr("not_this"),
+			want:       "",
+		},
+		"container doesn't use the defaults": {
+			lookupType: "container",
+			dfault:     test.StringPtr("not_this"),
+			want:       "",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookup(false, false)
+			lookup.Type = tc.lookupType
+			lookup.AccessToken = tc.root
+			lookup.Defaults.AccessToken = tc.dfault
+
+			// WHEN accessToken is called
+			got := lookup.accessToken()
+
+			// THEN the function returns the correct result
+			if got != tc.want {
+				t.Errorf("want: %q, got:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
 func TestLookup_GetAllowInvalidCerts(t *testing.T) {
 	// GIVEN a Lookup
 	tests := map[string]struct {
@@ -143,6 +201,7 @@ func TestLookup_ServiceURL(t *testing.T) {
 	tests := map[string]struct {
 		serviceType   string
 		url           string
+		baseURL       string
 		webURL        string
 		ignoreWebURL  bool
 		latestVersion string
@@ -155,6 +214,14 @@ func TestLookup_ServiceURL(t *testing.T) {
 			webURL:       "foo",
 			ignoreWebURL: true,
 		},
+		"github enterprise - want repo url address": {
+			want:         "https://github.example.com/release-argus/Argus",
+			serviceType:  "github",
+			url:          "release-argus/Argus",
+			baseURL:      "https://github.example.com/api/v3",
+			webURL:       "foo",
+			ignoreWebURL: true,
+		},
 		"github - want web_url address": {
 			want:         "foo",
 			serviceType:  "github",
@@ -178,6 +245,29 @@ func TestLookup_ServiceURL(t *testing.T) {
 			latestVersion: "",
 			ignoreWebURL:  false,
 		},
+		"gitlab - want project url address": {
+			want:         "https://gitlab.com/group/project",
+			serviceType:  "gitlab",
+			url:          "group/project",
+			webURL:       "foo",
+			ignoreWebURL: true,
+		},
+		"gitlab - want project url address on base_url": {
+			want:         "https://gitlab.example.com/group/project",
+			serviceType:  "gitlab",
+			url:          "group/project",
+			baseURL:      "https://gitlab.example.com/",
+			webURL:       "foo",
+			ignoreWebURL: true,
+		},
+		"gitea - want repo url address on base_url": {
+			want:         "https://gitea.example.com/owner/repo",
+			serviceType:  "gitea",
+			url:          "owner/repo",
+			baseURL:      "https://gitea.example.com",
+			webURL:       "foo",
+			ignoreWebURL: true,
+		},
 		"url - want query url": {
 			want:         "https://release-argus.io",
 			serviceType:  "url",
@@ -220,7 +310,7 @@ func TestLookup_ServiceURL(t *testing.T) {
 				test.StringPtr("http://example.com"))
 			status.SetLatestVersion(tc.latestVersion, false)
 			status.WebURL = &tc.webURL
-			lookup := Lookup{Type: tc.serviceType, URL: tc.url, Status: &status}
+			lookup := Lookup{Type: tc.serviceType, URL: tc.url, BaseURL: tc.baseURL, Status: &status}
 
 			// WHEN GetAllowInvalidCerts is called
 			got := lookup.ServiceURL(tc.ignoreWebURL)
@@ -277,12 +367,61 @@ func TestLookup_GetUsePreRelease(t *testing.T) {
 	}
 }
 
+func TestLookup_GetVersionConstraint(t *testing.T) {
+	// GIVEN a Lookup
+	tests := map[string]struct {
+		root        string
+		dfault      string
+		hardDefault string
+		want        string
+	}{
+		"root overrides all": {
+			want:        "~1.18",
+			root:        "~1.18",
+			dfault:      "^2",
+			hardDefault: "^3"},
+		"default overrides hardDefault": {
+			want:        "^2",
+			dfault:      "^2",
+			hardDefault: "^3"},
+		"hardDefault is last resort": {
+			want:        "^3",
+			hardDefault: "^3"},
+		"no constraint": {
+			want: ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookup(false, false)
+			lookup.VersionConstraint = tc.root
+			lookup.Defaults.VersionConstraint = tc.dfault
+			lookup.HardDefaults.VersionConstraint = tc.hardDefault
+
+			// WHEN GetVersionConstraint is called
+			got := lookup.GetVersionConstraint()
+
+			// THEN the function returns the correct result
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
 func TestLookup_GetURL(t *testing.T) {
 	// GIVEN a Lookup
 	tests := map[string]struct {
 		env         map[string]string
 		urlType     bool
+		giteaType   bool
+		gitlabType  bool
 		url         string
+		baseURL     string
+		defaults    *LookupDefaults
 		tagFallback bool
 		want        string
 	}{
@@ -300,6 +439,59 @@ func TestLookup_GetURL(t *testing.T) {
 			tagFallback: true,
 			want:        "https://api.github.com/repos/release-argus/Argus/tags",
 		},
+		"type=github, base_url": {
+			url:     "release-argus/Argus",
+			baseURL: "https://github.example.com/api/v3/",
+			want:    "https://github.example.com/api/v3/repos/release-argus/Argus/releases",
+		},
+		"type=github, default github_base_url": {
+			url: "release-argus/Argus",
+			defaults: &LookupDefaults{
+				GitHubBaseURL: "https://github.example.com/api/v3"},
+			want: "https://github.example.com/api/v3/repos/release-argus/Argus/releases",
+		},
+		"type=github, base_url overrides default github_base_url": {
+			url:     "release-argus/Argus",
+			baseURL: "https://github.example.com/api/v3",
+			defaults: &LookupDefaults{
+				GitHubBaseURL: "https://github.other.com/api/v3"},
+			want: "https://github.example.com/api/v3/repos/release-argus/Argus/releases",
+		},
+		"type=gitea": {
+			giteaType: true,
+			url:       "owner/repo",
+			baseURL:   "https://gitea.example.com/",
+			want:      "https://gitea.example.com/api/v1/repos/owner/repo/releases",
+		},
+		"type=gitea, tagFallback": {
+			giteaType:   true,
+			url:         "owner/repo",
+			baseURL:     "https://gitea.example.com",
+			tagFallback: true,
+			want:        "https://gitea.example.com/api/v1/repos/owner/repo/tags",
+		},
+		"type=gitlab": {
+			gitlabType: true,
+			url:        "group/subgroup/project",
+			want:       "https://gitlab.com/api/v4/projects/group%2Fsubgroup%2Fproject/releases",
+		},
+		"type=gitlab, tagFallback": {
+			gitlabType:  true,
+			url:         "group/project",
+			tagFallback: true,
+			want:        "https://gitlab.com/api/v4/projects/group%2Fproject/repository/tags",
+		},
+		"type=gitlab, project id": {
+			gitlabType: true,
+			url:        "123",
+			want:       "https://gitlab.com/api/v4/projects/123/releases",
+		},
+		"type=gitlab, base_url": {
+			gitlabType: true,
+			url:        "group/project",
+			baseURL:    "https://gitlab.example.com/",
+			want:       "https://gitlab.example.com/api/v4/projects/group%2Fproject/releases",
+		},
 		"env var is used": {
 			env:     map[string]string{"TESTLOOKUP_LV_GETURL_ONE": "https://release-argus.io"},
 			urlType: true,
@@ -335,6 +527,16 @@ func TestLookup_GetURL(t *testing.T) {
 			}
 			lookup := testLookup(tc.urlType, false)
 			lookup.URL = tc.url
+			lookup.BaseURL = tc.baseURL
+			if tc.defaults != nil {
+				lookup.Defaults = tc.defaults
+			}
+			if tc.giteaType {
+				lookup.Type = "gitea"
+			}
+			if tc.gitlabType {
+				lookup.Type = "gitlab"
+			}
 			if !tc.urlType {
 				lookup.GitHubData.tagFallback = tc.tagFallback
 			}
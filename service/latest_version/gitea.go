@@ -0,0 +1,51 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"encoding/json"
+	"fmt"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/util"
+)
+
+// checkGiteaReleasesBody will check that the body is of the expected API format for a successful query
+// and convert the Gitea/Forgejo releases/tags to Releases (excluding drafts).
+func (l *Lookup) checkGiteaReleasesBody(body *[]byte, logFrom *util.LogFrom) (releases []github_types.Release, err error) {
+	if msg, isError := apiErrorMessage(*body); isError {
+		err = fmt.Errorf("gitea api error at %s - %s",
+			l.GetURL(), msg)
+		jLog.Error(err, logFrom, true)
+		return
+	}
+
+	var giteaReleases []github_types.GiteaRelease
+	if err = json.Unmarshal(*body, &giteaReleases); err != nil {
+		err = fmt.Errorf("unmarshal of Gitea API data failed\n%w",
+			err)
+		jLog.Error(err, logFrom, true)
+		return
+	}
+
+	releases = make([]github_types.Release, 0, len(giteaReleases))
+	for i := range giteaReleases {
+		if giteaReleases[i].Draft {
+			continue
+		}
+		releases = append(releases, giteaReleases[i].Release)
+	}
+	return
+}
@@ -0,0 +1,277 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync/atomic"
+	"testing"
+
+	"github.com/release-argus/Argus/service/latest_version/filter"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+func testLookupGitea(baseURL string) *Lookup {
+	lookup := testLookup(false, false)
+	lookup.Type = "gitea"
+	lookup.URL = "owner/repo"
+	lookup.BaseURL = baseURL
+	lookup.AccessToken = nil
+	lookup.GitHubData = lookup.newGitHubData("", nil)
+	lookup.URLCommands = filter.URLCommandSlice{
+		{Type: "regex", Regex: test.StringPtr(`v?([0-9.]+.*)`)}}
+	return lookup
+}
+
+func TestLookup_CheckGiteaReleasesBody(t *testing.T) {
+	// GIVEN a body
+	tests := map[string]struct {
+		body     string
+		wantTags []string
+		errRegex string
+	}{
+		"error message": {
+			body:     `{"errors":null,"message":"The target couldn't be found.","url":"https://gitea.example.com/api/swagger"}`,
+			errRegex: `gitea api error at .* - The target couldn't be found.$`,
+		},
+		"invalid json": {
+			body:     `[{"tag_name":1}]`,
+			errRegex: `unmarshal of Gitea API data failed`,
+		},
+		"releases": {
+			body: `[
+				{"tag_name":"v1.2.0","prerelease":true},
+				{"tag_name":"v1.1.0"}]`,
+			wantTags: []string{"v1.2.0", "v1.1.0"},
+		},
+		"drafts are excluded": {
+			body: `[
+				{"tag_name":"v1.2.0","draft":true},
+				{"tag_name":"v1.1.0"}]`,
+			wantTags: []string{"v1.1.0"},
+		},
+		"tags": {
+			body: `[
+				{"name":"v1.2.0"},
+				{"name":"v1.1.0"}]`,
+			wantTags: []string{"v1.2.0", "v1.1.0"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookupGitea("https://gitea.example.com")
+			body := []byte(tc.body)
+
+			// WHEN checkGiteaReleasesBody is called on it
+			releases, err := lookup.checkGiteaReleasesBody(&body, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the releases are converted correctly
+			if len(releases) != len(tc.wantTags) {
+				t.Fatalf("want %d releases, not %d:\n%v",
+					len(tc.wantTags), len(releases), releases)
+			}
+			for i := range releases {
+				// /tags only have the Name
+				tag := util.FirstNonDefault(releases[i].TagName, releases[i].Name)
+				if tag != tc.wantTags[i] {
+					t.Errorf("release %d: want tag %q, not %q",
+						i, tc.wantTags[i], tag)
+				}
+			}
+		})
+	}
+}
+
+func TestLookup_QueryGitea(t *testing.T) {
+	// GIVEN a Gitea API
+	tests := map[string]struct {
+		releases          string
+		tags              string
+		accessToken       *string
+		usePreRelease     bool
+		require           *filter.Require
+		wantLatestVersion string
+		wantTagFallback   bool
+		errRegex          string
+	}{
+		"releases": {
+			releases: `[
+				{"tag_name":"v1.0.0"},
+				{"tag_name":"v1.2.0"},
+				{"tag_name":"v1.1.0"}]`,
+			wantLatestVersion: "1.2.0",
+		},
+		"releases with access token": {
+			releases: `[
+				{"tag_name":"v1.0.0"}]`,
+			accessToken:       test.StringPtr("secret"),
+			wantLatestVersion: "1.0.0",
+		},
+		"releases ignore prereleases and drafts": {
+			releases: `[
+				{"tag_name":"v1.3.0","draft":true},
+				{"tag_name":"v1.2.0","prerelease":true},
+				{"tag_name":"v1.1.0"}]`,
+			wantLatestVersion: "1.1.0",
+		},
+		"releases use_prerelease": {
+			releases: `[
+				{"tag_name":"v1.2.0","prerelease":true},
+				{"tag_name":"v1.1.0"}]`,
+			usePreRelease:     true,
+			wantLatestVersion: "1.2.0",
+		},
+		"releases require assets": {
+			releases: `[
+				{"tag_name":"v1.2.0","assets":[{"id":1,"name":"argus-1.2.0.darwin-amd64"}]},
+				{"tag_name":"v1.1.0","assets":[{"id":2,"name":"argus-1.1.0.linux-amd64"}]}]`,
+			require: &filter.Require{
+				RegexContent: `argus-{{ version }}\.linux-amd64`},
+			wantLatestVersion: "1.1.0",
+		},
+		"no releases falls back to tags": {
+			releases: `[]`,
+			tags: `[
+				{"name":"v0.9.0"},
+				{"name":"v0.10.0"}]`,
+			wantLatestVersion: "0.10.0",
+			wantTagFallback:   true,
+		},
+		"repo not found": {
+			releases: `{"errors":null,"message":"The target couldn't be found."}`,
+			errRegex: "The target couldn't be found.",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				wantAuth := ""
+				if tc.accessToken != nil {
+					wantAuth = "token " + *tc.accessToken
+				}
+				if got := r.Header.Get("Authorization"); got != wantAuth {
+					t.Errorf("want Authorization %q, not %q",
+						wantAuth, got)
+				}
+				switch r.URL.Path {
+				case "/api/v1/repos/owner/repo/releases":
+					w.Write([]byte(tc.releases))
+				case "/api/v1/repos/owner/repo/tags":
+					w.Write([]byte(tc.tags))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+			lookup := testLookupGitea(server.URL)
+			lookup.AccessToken = tc.accessToken
+			lookup.UsePreRelease = &tc.usePreRelease
+			if tc.require != nil {
+				lookup.Require = tc.require
+				lookup.Require.Status = lookup.Status
+			}
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the latest version is found
+			if got := lookup.Status.LatestVersion(); got != tc.wantLatestVersion {
+				t.Errorf("want LatestVersion %q, not %q",
+					tc.wantLatestVersion, got)
+			}
+			// AND the /tags fallback is used when there are no releases
+			if got := lookup.GitHubData.TagFallback(); got != tc.wantTagFallback {
+				t.Errorf("want TagFallback %t, not %t",
+					tc.wantTagFallback, got)
+			}
+		})
+	}
+}
+
+func TestLookup_QueryGiteaETag(t *testing.T) {
+	// GIVEN a Gitea API that supports conditional requests
+	eTag := `"abc123"`
+	var requests, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == eTag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", "W/"+eTag)
+		w.Write([]byte(`[{"tag_name":"v1.2.3"}]`))
+	}))
+	defer server.Close()
+	lookup := testLookupGitea(server.URL)
+
+	// WHEN Query is called on it multiple times
+	attempts := 3
+	for i := 0; i < attempts; i++ {
+		_, err := lookup.Query(false, &util.LogFrom{})
+		if err != nil {
+			t.Fatalf("unexpected error on query %d: %v",
+				i, err)
+		}
+	}
+
+	// THEN the ETag is stored
+	if got := lookup.GitHubData.ETag(); got != eTag {
+		t.Errorf("want ETag %q, not %q",
+			eTag, got)
+	}
+	// AND every request after the first is conditional (and the cached releases are used)
+	if got := notModified.Load(); got != requests.Load()-1 {
+		t.Errorf("want %d 304 responses from %d requests, not %d",
+			requests.Load()-1, requests.Load(), got)
+	}
+	if got := lookup.Status.LatestVersion(); got != "1.2.3" {
+		t.Errorf("want LatestVersion %q, not %q",
+			"1.2.3", got)
+	}
+}
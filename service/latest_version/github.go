@@ -23,15 +23,16 @@ import (
 
 	"github.com/Masterminds/semver/v3"
 	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	opt "github.com/release-argus/Argus/service/options"
 	"github.com/release-argus/Argus/util"
 )
 
-// filterGitHubReleases will filter releases that fail the URLCommands, aren't semantic (if wanted),
+// filterGitHubReleases will filter releases that fail the URLCommands, don't follow the version_scheme (if wanted),
 // or are pre_release's (when they're not wanted). This list will be returned and be sorted descending.
 func (l *Lookup) filterGitHubReleases(
 	logFrom *util.LogFrom,
 ) (filteredReleases []github_types.Release) {
-	semanticVerioning := l.Options.GetSemanticVersioning()
+	versionScheme := l.Options.GetVersionScheme()
 	usePreReleases := l.GetUsePreRelease()
 
 	releases := l.GitHubData.Releases()
@@ -60,18 +61,16 @@ func (l *Lookup) filterGitHubReleases(
 		release := releases[i]
 		release.TagName = tagName
 
-		// If SemVer isn't wanted, add without any sorting
-		if !semanticVerioning {
+		// If versions aren't compared, add without any sorting
+		if versionScheme == "" {
 			filteredReleases = append(filteredReleases, release)
 			continue
 		}
 
 		// Else, sort the versions
-		semVer, err := semver.NewVersion(tagName)
-		if err != nil {
+		if err = setReleaseVersion(&release, versionScheme, tagName); err != nil {
 			continue
 		}
-		release.SemanticVersion = semVer
 		// If there's no other versions, just add it without insertion sort
 		if len(filteredReleases) == 0 {
 			filteredReleases = append(filteredReleases, release)
@@ -83,14 +82,59 @@ func (l *Lookup) filterGitHubReleases(
 	return
 }
 
+// setReleaseVersion parses `version` with the `versionScheme` onto the `release`.
+func setReleaseVersion(release *github_types.Release, versionScheme string, version string) error {
+	schemeVersion, err := opt.ParseVersion(versionScheme, version)
+	if err != nil {
+		return err
+	}
+	release.SchemeVersion = schemeVersion
+	if versionScheme == opt.VersionSchemeSemVer {
+		//nolint:errcheck // Parsed above.
+		release.SemanticVersion, _ = semver.NewVersion(version)
+	}
+	return nil
+}
+
+// isPreRelease returns whether `version` is a pre-release in the version_scheme of this Lookup.
+//
+// When versions aren't compared, or `version` isn't in that scheme,
+// the scheme of the source is used instead (PEP 440 for PyPI, else semantic).
+func (l *Lookup) isPreRelease(version string) bool {
+	sourceScheme := opt.VersionSchemeSemVer
+	if l.Type == "pypi" {
+		sourceScheme = opt.VersionSchemePEP440
+	}
+
+	for _, versionScheme := range []string{l.Options.GetVersionScheme(), sourceScheme} {
+		if versionScheme == "" {
+			continue
+		}
+		if schemeVersion, err := opt.ParseVersion(versionScheme, version); err == nil {
+			return schemeVersion.PreRelease()
+		}
+	}
+	return false
+}
+
+// releaseLessThan returns whether `a` is an older version than `b`.
+//
+// Both releases must have been parsed with the same version scheme.
+func releaseLessThan(a, b *github_types.Release) bool {
+	if a.SchemeVersion != nil && b.SchemeVersion != nil {
+		return a.SchemeVersion.Compare(b.SchemeVersion) < 0
+	}
+	return a.SemanticVersion.LessThan(b.SemanticVersion)
+}
+
 // insertionSort will do an insertion sort of release on filteredReleases.
 //
-// Every GitHubRelease must be follow SemanticVersioning for this insertion
+// Every GitHubRelease must have been parsed with the same version scheme for this insertion
 func insertionSort(release github_types.Release, filteredReleases *[]github_types.Release) {
 	n := len(*filteredReleases)
 	// find the insertion point
 	i := sort.Search(n, func(index int) bool {
-		return (*filteredReleases)[index].SemanticVersion.LessThan(release.SemanticVersion)
+		return releaseLessThan(&(*filteredReleases)[index], &release)
 	})
 
 	// append an empty release to the end of the slice
@@ -17,6 +17,9 @@
 package latestver
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"regexp"
 	"strings"
 	"testing"
@@ -118,6 +121,7 @@ func TestLookup_FilterGitHubReleases(t *testing.T) {
 	tests := map[string]struct {
 		releases           []github_types.Release
 		semanticVersioning bool
+		versionScheme      string
 		usePreReleases     bool
 		want               []string
 	}{
@@ -192,6 +196,19 @@ func TestLookup_FilterGitHubReleases(t *testing.T) {
 				{TagName: "0.0.1"},
 			}, want: []string{"0.3.0", "0.2.0", "0.0.2", "0.0.1", "0.0.0"},
 		},
+		"sorts with version_scheme": {
+			usePreReleases:     true,
+			semanticVersioning: true,
+			versionScheme:      "pep440",
+			releases: []github_types.Release{
+				{TagName: "1.0rc1"},
+				{TagName: "1.0.post1"},
+				{TagName: "1.0"},
+				{TagName: "1.0.dev1"},
+				{TagName: "not-pep440"},
+				{TagName: "0.9"},
+			}, want: []string{"1.0.post1", "1.0", "1.0rc1", "1.0.dev1", "0.9"},
+		},
 	}
 
 	for name, tc := range tests {
@@ -202,6 +219,7 @@ func TestLookup_FilterGitHubReleases(t *testing.T) {
 			lv.URLCommands = nil
 			lv.UsePreRelease = &tc.usePreReleases
 			lv.Options.SemanticVersioning = &tc.semanticVersioning
+			lv.Options.VersionScheme = tc.versionScheme
 			lv.GitHubData.SetReleases(tc.releases)
 
 			// WHEN filterGitHubReleases is called on this body
@@ -221,3 +239,68 @@ func TestLookup_FilterGitHubReleases(t *testing.T) {
 		})
 	}
 }
+
+func TestLookup_QueryGitHubEnterprise(t *testing.T) {
+	// GIVEN a GitHub Enterprise Server API with no releases, only tags
+	emptyListETagTestMutex.Lock()
+	defer emptyListETagTestMutex.Unlock()
+	startingEmptyListETag := getEmptyListETag(githubBaseURL)
+	emptyETag := `"ghes-empty-list"`
+	var tags []string
+	for i := 0; i < 10; i++ {
+		tags = append(tags,
+			fmt.Sprintf(`{"name":"v1.%d.0","zipball_url":"https://github.example.com/api/v3/repos/owner/repo/zipball/refs/tags/v1.%d.0"}`,
+				i, i))
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/owner/repo/releases":
+			w.Header().Set("ETag", emptyETag)
+			w.Write([]byte("[]"))
+		case "/api/v3/repos/owner/repo/tags":
+			w.Header().Set("ETag", `"ghes-tags"`)
+			w.Write([]byte("[" + strings.Join(tags, ",") + "]"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	baseURL := server.URL + "/api/v3"
+	lookup := testLookup(false, false)
+	lookup.URL = "owner/repo"
+	lookup.AccessToken = nil
+	lookup.Defaults.GitHubBaseURL = baseURL
+	lookup.GitHubData = lookup.newGitHubData("", nil)
+
+	// WHEN Query is called on it
+	_, err := lookup.Query(false, &util.LogFrom{})
+
+	// THEN the latest version is found from the tags
+	if err != nil {
+		t.Fatalf("unexpected error: %v",
+			err)
+	}
+	if got := lookup.Status.LatestVersion(); got != "1.9.0" {
+		t.Errorf("want LatestVersion %q, not %q",
+			"1.9.0", got)
+	}
+	// AND the empty list ETag is cached for that host only
+	if got := getEmptyListETag(baseURL); got != emptyETag {
+		t.Errorf("want empty list ETag %q for %q, not %q",
+			emptyETag, baseURL, got)
+	}
+	if got := getEmptyListETag(githubBaseURL); got != startingEmptyListETag {
+		t.Errorf("empty list ETag for %q changed from %q to %q",
+			githubBaseURL, startingEmptyListETag, got)
+	}
+	// AND new Lookups on that host start with that ETag
+	if got := lookup.newGitHubData("", nil).ETag(); got != emptyETag {
+		t.Errorf("want new GitHubData to have ETag %q, not %q",
+			emptyETag, got)
+	}
+	// AND the service URL is on the enterprise host
+	if got := lookup.ServiceURL(true); got != server.URL+"/owner/repo" {
+		t.Errorf("want ServiceURL %q, not %q",
+			server.URL+"/owner/repo", got)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/util"
+)
+
+// gitlabBaseURL is the default base URL for the gitlab type.
+const gitlabBaseURL = "https://gitlab.com"
+
+// apiErrorJSON is the format of an error on the GitLab/Gitea APIs.
+type apiErrorJSON struct {
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+// apiErrorMessage returns the error message from `body` if it is a JSON object
+// rather than the list of releases/tags expected.
+func apiErrorMessage(body []byte) (msg string, isError bool) {
+	// An object is returned on errors, e.g. {"message":"404 Project Not Found"}
+	if !strings.HasPrefix(strings.TrimSpace(string(body)), "{") {
+		return "", false
+	}
+
+	var errJSON apiErrorJSON
+	//nolint:errcheck // Fall back to the body if it's not the expected format.
+	json.Unmarshal(body, &errJSON)
+	return util.FirstNonDefault(errJSON.Message, errJSON.Error, string(body)), true
+}
+
+// checkGitLabReleasesBody will check that the body is of the expected API format for a successful query
+// and convert the GitLab releases/tags to Releases.
+func (l *Lookup) checkGitLabReleasesBody(body *[]byte, logFrom *util.LogFrom) (releases []github_types.Release, err error) {
+	if msg, isError := apiErrorMessage(*body); isError {
+		err = fmt.Errorf("gitlab api error at %s - %s",
+			l.GetURL(), msg)
+		jLog.Error(err, logFrom, true)
+		return
+	}
+
+	// /repository/tags
+	if l.GitHubData.TagFallback() {
+		var tags []github_types.GitLabTag
+		if err = json.Unmarshal(*body, &tags); err != nil {
+			err = fmt.Errorf("unmarshal of GitLab API data failed\n%w",
+				err)
+			jLog.Error(err, logFrom, true)
+			return
+		}
+		releases = make([]github_types.Release, len(tags))
+		for i := range tags {
+			releases[i] = tags[i].Release()
+			releases[i].PreRelease = l.isPreRelease(releases[i].TagName)
+		}
+		return
+	}
+
+	// /releases
+	var gitlabReleases []github_types.GitLabRelease
+	if err = json.Unmarshal(*body, &gitlabReleases); err != nil {
+		err = fmt.Errorf("unmarshal of GitLab API data failed\n%w",
+			err)
+		jLog.Error(err, logFrom, true)
+		return
+	}
+	releases = make([]github_types.Release, len(gitlabReleases))
+	for i := range gitlabReleases {
+		releases[i] = gitlabReleases[i].Release()
+		// GitLab has no pre-release flag, so use the tag.
+		releases[i].PreRelease = releases[i].PreRelease ||
+			l.isPreRelease(releases[i].TagName)
+	}
+	return
+}
@@ -0,0 +1,249 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/release-argus/Argus/service/latest_version/filter"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+func testLookupGitLab(baseURL string) *Lookup {
+	lookup := testLookup(false, false)
+	lookup.Type = "gitlab"
+	lookup.URL = "group/project"
+	lookup.BaseURL = baseURL
+	lookup.AccessToken = nil
+	lookup.GitHubData = &GitHubData{}
+	return lookup
+}
+
+func TestLookup_CheckGitLabReleasesBody(t *testing.T) {
+	// GIVEN a body
+	tests := map[string]struct {
+		tagFallback bool
+		body        string
+		wantTags    []string
+		wantPre     []bool
+		errRegex    string
+	}{
+		"error message": {
+			body:     `{"message":"404 Project Not Found"}`,
+			errRegex: `gitlab api error at .* - 404 Project Not Found$`,
+		},
+		"error": {
+			body:     `{"error":"invalid_token"}`,
+			errRegex: `gitlab api error at .* - invalid_token$`,
+		},
+		"invalid json": {
+			body:     `[{"tag_name":1}]`,
+			errRegex: `unmarshal of GitLab API data failed`,
+		},
+		"releases": {
+			body: `[
+				{"name":"v1.2.0-rc.1","tag_name":"v1.2.0-rc.1"},
+				{"name":"v1.1.0","tag_name":"v1.1.0","upcoming_release":true},
+				{"name":"v1.0.0","tag_name":"v1.0.0"}]`,
+			wantTags: []string{"v1.2.0-rc.1", "v1.1.0", "v1.0.0"},
+			wantPre:  []bool{true, true, false},
+		},
+		"tags": {
+			tagFallback: true,
+			body: `[
+				{"name":"v1.2.0-beta"},
+				{"name":"v1.1.0"}]`,
+			wantTags: []string{"v1.2.0-beta", "v1.1.0"},
+			wantPre:  []bool{true, false},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookupGitLab("")
+			lookup.GitHubData.tagFallback = tc.tagFallback
+			body := []byte(tc.body)
+
+			// WHEN checkGitLabReleasesBody is called on it
+			releases, err := lookup.checkGitLabReleasesBody(&body, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the releases are converted correctly
+			if len(releases) != len(tc.wantTags) {
+				t.Fatalf("want %d releases, not %d:\n%v",
+					len(tc.wantTags), len(releases), releases)
+			}
+			for i := range releases {
+				if releases[i].TagName != tc.wantTags[i] {
+					t.Errorf("release %d: want TagName %q, not %q",
+						i, tc.wantTags[i], releases[i].TagName)
+				}
+				if releases[i].PreRelease != tc.wantPre[i] {
+					t.Errorf("release %d: want PreRelease %t, not %t",
+						i, tc.wantPre[i], releases[i].PreRelease)
+				}
+			}
+		})
+	}
+}
+
+func TestLookup_QueryGitLab(t *testing.T) {
+	// GIVEN a GitLab API
+	tests := map[string]struct {
+		releases          string
+		tags              string
+		accessToken       *string
+		usePreRelease     bool
+		require           *filter.Require
+		wantLatestVersion string
+		wantTagFallback   bool
+		errRegex          string
+	}{
+		"releases": {
+			releases: `[
+				{"tag_name":"v1.0.0"},
+				{"tag_name":"v1.2.0"},
+				{"tag_name":"v1.1.0"}]`,
+			wantLatestVersion: "1.2.0",
+		},
+		"releases with access token": {
+			releases: `[
+				{"tag_name":"v1.0.0"}]`,
+			accessToken:       test.StringPtr("secret"),
+			wantLatestVersion: "1.0.0",
+		},
+		"releases ignore prereleases": {
+			releases: `[
+				{"tag_name":"v1.3.0-rc.1"},
+				{"tag_name":"v1.2.0","upcoming_release":true},
+				{"tag_name":"v1.1.0"}]`,
+			wantLatestVersion: "1.1.0",
+		},
+		"releases use_prerelease": {
+			releases: `[
+				{"tag_name":"v1.3.0-rc.1"},
+				{"tag_name":"v1.1.0"}]`,
+			usePreRelease:     true,
+			wantLatestVersion: "1.3.0-rc.1",
+		},
+		"releases require assets": {
+			releases: `[
+				{"tag_name":"v1.2.0","assets":{"links":[{"id":1,"name":"argus-1.2.0.darwin-amd64"}]}},
+				{"tag_name":"v1.1.0","assets":{"links":[{"id":2,"name":"argus-1.1.0.linux-amd64"}]}}]`,
+			require: &filter.Require{
+				RegexContent: `argus-{{ version }}\.linux-amd64`},
+			wantLatestVersion: "1.1.0",
+		},
+		"no releases falls back to tags": {
+			releases: `[]`,
+			tags: `[
+				{"name":"v0.9.0"},
+				{"name":"v0.10.0"}]`,
+			wantLatestVersion: "0.10.0",
+			wantTagFallback:   true,
+		},
+		"no releases or tags": {
+			releases: `[]`,
+			tags:     `[]`,
+			errRegex: "no releases were found matching the url_commands",
+		},
+		"project not found": {
+			releases: `{"message":"404 Project Not Found"}`,
+			errRegex: "404 Project Not Found",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				wantToken := util.DefaultIfNil(tc.accessToken)
+				if got := r.Header.Get("PRIVATE-TOKEN"); got != wantToken {
+					t.Errorf("want PRIVATE-TOKEN %q, not %q",
+						wantToken, got)
+				}
+				switch r.URL.EscapedPath() {
+				case "/api/v4/projects/group%2Fproject/releases":
+					w.Write([]byte(tc.releases))
+				case "/api/v4/projects/group%2Fproject/repository/tags":
+					w.Write([]byte(tc.tags))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"message":"404 Not found"}`))
+				}
+			}))
+			defer server.Close()
+			lookup := testLookupGitLab(server.URL)
+			lookup.AccessToken = tc.accessToken
+			lookup.UsePreRelease = &tc.usePreRelease
+			lookup.URLCommands = filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`v?([0-9.]+.*)`)}}
+			if tc.require != nil {
+				lookup.Require = tc.require
+				lookup.Require.Status = lookup.Status
+			}
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the latest version is found
+			if got := lookup.Status.LatestVersion(); got != tc.wantLatestVersion {
+				t.Errorf("want LatestVersion %q, not %q",
+					tc.wantLatestVersion, got)
+			}
+			// AND the /tags fallback is used when there are no releases
+			if got := lookup.GitHubData.TagFallback(); got != tc.wantTagFallback {
+				t.Errorf("want TagFallback %t, not %t",
+					tc.wantTagFallback, got)
+			}
+			// AND the service URL is on the base URL
+			if got := lookup.ServiceURL(true); !strings.HasPrefix(got, server.URL+"/") {
+				t.Errorf("want ServiceURL on %q, not %q",
+					server.URL, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"fmt"
+	net_url "net/url"
+	"strings"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	opt "github.com/release-argus/Argus/service/options"
+	"github.com/release-argus/Argus/util"
+	"gopkg.in/yaml.v3"
+)
+
+// isHelmOCI returns whether the Lookup is on an OCI-hosted helm chart.
+func (l *Lookup) isHelmOCI() bool {
+	return l.Type == "helm" && strings.HasPrefix(util.EvalEnvVars(l.URL), "oci://")
+}
+
+// GetAppVersion returns whether the appVersion of the helm chart should be used rather than its version.
+func (l *Lookup) GetAppVersion() bool {
+	return util.DefaultIfNil(l.AppVersion)
+}
+
+// helmIndexURL returns the URL of the index.yaml of the chart repository at `repoURL`.
+func helmIndexURL(repoURL string) string {
+	if strings.HasSuffix(repoURL, ".yaml") || strings.HasSuffix(repoURL, ".yml") {
+		return repoURL
+	}
+	return strings.TrimSuffix(repoURL, "/") + "/index.yaml"
+}
+
+// checkHelmIndexBody will convert the versions of Chart in the body to Releases.
+//
+// The body is the index.yaml of the repository, or the tags of the chart if it's OCI-hosted.
+func (l *Lookup) checkHelmIndexBody(body *[]byte, logFrom *util.LogFrom) (releases []github_types.Release, err error) {
+	// OCI chart versions are the tags of the image ('+' isn't allowed in tags, so is '_').
+	if l.isHelmOCI() {
+		if releases, err = l.checkContainerTagsBody(body, logFrom); err != nil {
+			return
+		}
+		for i := range releases {
+			releases[i].TagName = strings.ReplaceAll(releases[i].TagName, "_", "+")
+		}
+		return
+	}
+
+	var index github_types.HelmIndex
+	if err = yaml.Unmarshal(*body, &index); err != nil {
+		err = fmt.Errorf("unmarshal of helm repository index failed\n%w",
+			err)
+		jLog.Error(err, logFrom, true)
+		return
+	}
+	chart := util.EvalEnvVars(l.Chart)
+	versions, found := index.Entries[chart]
+	if !found {
+		err = fmt.Errorf("chart %q not found in %s",
+			chart, l.GetURL())
+		jLog.Error(err, logFrom, true)
+		return
+	}
+
+	useAppVersion := l.GetAppVersion()
+	indexURL, _ := net_url.Parse(l.GetURL())
+	releases = make([]github_types.Release, 0, len(versions))
+	for i := range versions {
+		release := versions[i].Release(useAppVersion)
+		if release.TagName == "" {
+			continue
+		}
+		// Chart versions are always semantic.
+		release.PreRelease = l.isPreRelease(release.TagName)
+		if schemeVersion, err := opt.ParseVersion(opt.VersionSchemeSemVer, versions[i].Version); err == nil {
+			release.PreRelease = release.PreRelease || schemeVersion.PreRelease()
+		}
+		// Chart URLs may be relative to the index.
+		for j := range release.Assets {
+			if assetURL, err := net_url.Parse(release.Assets[j].URL); err == nil && indexURL != nil {
+				release.Assets[j].URL = indexURL.ResolveReference(assetURL).String()
+				release.Assets[j].BrowserDownloadURL = release.Assets[j].URL
+			}
+		}
+		releases = append(releases, release)
+	}
+	return
+}
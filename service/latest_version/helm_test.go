@@ -0,0 +1,272 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/release-argus/Argus/service/latest_version/filter"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+var testHelmIndex = `apiVersion: v1
+entries:
+  argus:
+  - version: 1.2.0-rc.1
+    appVersion: 0.19.0
+    urls:
+    - charts/argus-1.2.0-rc.1.tgz
+  - version: 1.1.0
+    appVersion: 0.18.0
+    urls:
+    - https://charts.example.com/charts/argus-1.1.0.tgz
+  - version: 1.0.1
+    appVersion: 0.17.1
+    urls:
+    - charts/argus-1.0.1.tgz
+  other:
+  - version: 9.9.9
+    appVersion: 9.9.9
+generated: "2024-01-01T00:00:00Z"
+`
+
+func testLookupHelm(url string, chart string) *Lookup {
+	lookup := testLookup(false, false)
+	lookup.Type = "helm"
+	lookup.URL = url
+	lookup.Chart = chart
+	lookup.AccessToken = nil
+	lookup.URLCommands = nil
+	lookup.GitHubData = &GitHubData{}
+	return lookup
+}
+
+func TestLookup_CheckHelmIndexBody(t *testing.T) {
+	// GIVEN a helm repository index
+	tests := map[string]struct {
+		url        string
+		chart      string
+		appVersion *bool
+		body       string
+		wantTags   []string
+		wantPre    []bool
+		wantAsset  string
+		errRegex   string
+	}{
+		"chart versions": {
+			chart:     "argus",
+			body:      testHelmIndex,
+			wantTags:  []string{"1.2.0-rc.1", "1.1.0", "1.0.1"},
+			wantPre:   []bool{true, false, false},
+			wantAsset: "https://charts.example.com/stable/charts/argus-1.2.0-rc.1.tgz",
+		},
+		"app versions": {
+			chart:      "argus",
+			appVersion: test.BoolPtr(true),
+			body:       testHelmIndex,
+			wantTags:   []string{"0.19.0", "0.18.0", "0.17.1"},
+			wantPre:    []bool{true, false, false},
+			wantAsset:  "https://charts.example.com/stable/charts/argus-1.2.0-rc.1.tgz",
+		},
+		"chart not found": {
+			chart:    "unknown",
+			body:     testHelmIndex,
+			errRegex: `chart "unknown" not found in https://charts.example.com/stable/index.yaml`,
+		},
+		"invalid index": {
+			chart:    "argus",
+			body:     `entries: [`,
+			errRegex: `unmarshal of helm repository index failed`,
+		},
+		"oci tags": {
+			url:      "oci://ghcr.io/release-argus/charts",
+			chart:    "argus",
+			body:     `{"tags":["1.0.0","1.1.0_build.1","1.2.0-rc.1"]}`,
+			wantTags: []string{"1.0.0", "1.1.0+build.1", "1.2.0-rc.1"},
+			wantPre:  []bool{false, false, true},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.url == "" {
+				tc.url = "https://charts.example.com/stable/"
+			}
+			lookup := testLookupHelm(tc.url, tc.chart)
+			lookup.AppVersion = tc.appVersion
+			body := []byte(tc.body)
+
+			// WHEN checkHelmIndexBody is called on it
+			releases, err := lookup.checkHelmIndexBody(&body, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the chart versions are converted to releases
+			if len(releases) != len(tc.wantTags) {
+				t.Fatalf("want %d releases, not %d:\n%v",
+					len(tc.wantTags), len(releases), releases)
+			}
+			for i := range releases {
+				if releases[i].TagName != tc.wantTags[i] {
+					t.Errorf("release %d: want TagName %q, not %q",
+						i, tc.wantTags[i], releases[i].TagName)
+				}
+				if releases[i].PreRelease != tc.wantPre[i] {
+					t.Errorf("release %d: want PreRelease %t, not %t",
+						i, tc.wantPre[i], releases[i].PreRelease)
+				}
+			}
+			// AND relative chart URLs are resolved against the index
+			if tc.wantAsset != "" && releases[0].Assets[0].BrowserDownloadURL != tc.wantAsset {
+				t.Errorf("want asset %q, not %q",
+					tc.wantAsset, releases[0].Assets[0].BrowserDownloadURL)
+			}
+		})
+	}
+}
+
+func TestLookup_QueryHelm(t *testing.T) {
+	// GIVEN a helm chart repository
+	tests := map[string]struct {
+		chart             string
+		appVersion        *bool
+		username          string
+		accessToken       *string
+		usePreRelease     bool
+		require           *filter.Require
+		wantLatestVersion string
+		errRegex          string
+	}{
+		"chart version": {
+			chart:             "argus",
+			wantLatestVersion: "1.1.0",
+		},
+		"app version": {
+			chart:             "argus",
+			appVersion:        test.BoolPtr(true),
+			wantLatestVersion: "0.18.0",
+		},
+		"use_prerelease": {
+			chart:             "argus",
+			usePreRelease:     true,
+			wantLatestVersion: "1.2.0-rc.1",
+		},
+		"require chart url": {
+			chart: "argus",
+			require: &filter.Require{
+				RegexContent: `charts/argus-{{ version }}\.tgz$`},
+			wantLatestVersion: "1.1.0",
+		},
+		"basic auth": {
+			chart:             "argus",
+			username:          "user",
+			accessToken:       test.StringPtr("pass"),
+			wantLatestVersion: "1.1.0",
+		},
+		"basic auth invalid": {
+			chart:       "argus",
+			username:    "user",
+			accessToken: test.StringPtr("invalid"),
+			errRegex:    `unmarshal of helm repository index failed|chart "argus" not found`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.username != "" {
+					if user, pass, _ := r.BasicAuth(); user != "user" || pass != "pass" {
+						w.WriteHeader(http.StatusUnauthorized)
+						w.Write([]byte("401 Unauthorized"))
+						return
+					}
+				}
+				if r.URL.Path != "/stable/index.yaml" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Write([]byte(testHelmIndex))
+			}))
+			defer server.Close()
+			lookup := testLookupHelm(server.URL+"/stable", tc.chart)
+			lookup.AppVersion = tc.appVersion
+			lookup.Username = tc.username
+			lookup.AccessToken = tc.accessToken
+			lookup.UsePreRelease = &tc.usePreRelease
+			if tc.require != nil {
+				lookup.Require = tc.require
+				lookup.Require.Status = lookup.Status
+			}
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the latest version is found
+			if got := lookup.Status.LatestVersion(); got != tc.wantLatestVersion {
+				t.Errorf("want LatestVersion %q, not %q",
+					tc.wantLatestVersion, got)
+			}
+		})
+	}
+}
+
+func TestLookup_HelmOCIImage(t *testing.T) {
+	// GIVEN an OCI-hosted helm chart
+	lookup := testLookupHelm("oci://registry-1.docker.io/bitnamicharts/", "nginx")
+
+	// WHEN containerImage is called on it
+	registryType, registryURL, image := lookup.containerImage()
+
+	// THEN the chart is the image on the registry
+	if registryType != "hub" || registryURL != "" || image != "bitnamicharts/nginx" {
+		t.Errorf("want hub image %q, not %q %q %q",
+			"bitnamicharts/nginx", registryType, registryURL, image)
+	}
+	// AND the URL isn't an index.yaml
+	if got := lookup.GetURL(); got != lookup.URL {
+		t.Errorf("want URL %q, not %q",
+			lookup.URL, got)
+	}
+}
@@ -37,7 +37,7 @@ func TestMain(m *testing.M) {
 	jLog.Testing = true
 	LogInit(jLog)
 	FindEmptyListETag(os.Getenv("GITHUB_TOKEN"))
-	initialEmptyListETag = getEmptyListETag()
+	initialEmptyListETag = getEmptyListETag(githubBaseURL)
 
 	// run other tests
 	exitCode := m.Run()
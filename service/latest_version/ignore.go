@@ -0,0 +1,142 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	opt "github.com/release-argus/Argus/service/options"
+	"github.com/release-argus/Argus/util"
+)
+
+// Prefixes of the ignore_versions entries that aren't exact versions.
+const (
+	ignoreVersionRegexPrefix  = "regex:"
+	ignoreVersionSemVerPrefix = "semver:"
+)
+
+// GetIgnoreVersions returns the versions that should never be considered as the latest version.
+func (l *Lookup) GetIgnoreVersions() []string {
+	l.ignoreVersionsMutex.RLock()
+	defer l.ignoreVersionsMutex.RUnlock()
+
+	return l.IgnoreVersions
+}
+
+// IgnoreVersion adds `version` to the ignore_versions, returning false if it was already ignored.
+func (l *Lookup) IgnoreVersion(version string) bool {
+	if version == "" {
+		return false
+	}
+
+	l.ignoreVersionsMutex.Lock()
+	defer l.ignoreVersionsMutex.Unlock()
+	if _, ignored := ignoredBy(l.IgnoreVersions, version); ignored {
+		return false
+	}
+	// Copy so that readers of the old slice aren't affected.
+	ignoreVersions := make([]string, len(l.IgnoreVersions), len(l.IgnoreVersions)+1)
+	copy(ignoreVersions, l.IgnoreVersions)
+	l.IgnoreVersions = append(ignoreVersions, version)
+	return true
+}
+
+// ignoredBy returns the entry of `ignoreVersions` that `version` matches, if any.
+//
+// Entries are either:
+//   - an exact version, e.g. "1.2.3"
+//   - a RegEx prefixed with "regex:", e.g. "regex:^1\.2\."
+//   - a semantic version range prefixed with "semver:", e.g. "semver:>=1.2.0 <1.2.5"
+func ignoredBy(ignoreVersions []string, version string) (entry string, ignored bool) {
+	for _, entry := range ignoreVersions {
+		switch {
+		case strings.HasPrefix(entry, ignoreVersionRegexPrefix):
+			//nolint:errcheck // Validated in CheckValues.
+			if match, _ := regexp.MatchString(strings.TrimPrefix(entry, ignoreVersionRegexPrefix), version); match {
+				return entry, true
+			}
+		case strings.HasPrefix(entry, ignoreVersionSemVerPrefix):
+			constraint, err := semver.NewConstraint(strings.TrimPrefix(entry, ignoreVersionSemVerPrefix))
+			if err != nil {
+				continue
+			}
+			semVer, err := semver.NewVersion(version)
+			if err != nil {
+				continue
+			}
+			// Ranges exclude pre-releases unless they include one themselves,
+			// so also check pre-releases against their release.
+			release, _ := semVer.SetPrerelease("")
+			if constraint.Check(semVer) || (semVer.Prerelease() != "" && constraint.Check(&release)) {
+				return entry, true
+			}
+		case entry == version:
+			return entry, true
+		}
+	}
+	return "", false
+}
+
+// checkIgnoredVersion returns an error if `version` is in the ignore_versions.
+func (l *Lookup) checkIgnoredVersion(version string, logFrom *util.LogFrom) error {
+	entry, ignored := ignoredBy(l.GetIgnoreVersions(), version)
+	if !ignored {
+		return nil
+	}
+
+	err := fmt.Errorf("version %q is ignored (ignore_versions entry %q)",
+		version, entry)
+	jLog.Verbose(err, logFrom, true)
+	return err
+}
+
+// checkIgnoreVersions returns an error if any of the `ignoreVersions` are invalid
+// for versions compared with `versionScheme`.
+func checkIgnoreVersions(ignoreVersions []string, versionScheme string, prefix string) (errs error) {
+	for i, entry := range ignoreVersions {
+		var err error
+		switch {
+		case entry == "":
+			err = fmt.Errorf("%s    item_%d: <required> (version, 'regex:<RegEx>' or 'semver:<range>')\\",
+				prefix, i)
+		case strings.HasPrefix(entry, ignoreVersionRegexPrefix):
+			if _, regexErr := regexp.Compile(strings.TrimPrefix(entry, ignoreVersionRegexPrefix)); regexErr != nil {
+				err = fmt.Errorf("%s    item_%d: %q <invalid> (%s)\\",
+					prefix, i, entry, regexErr)
+			}
+		case strings.HasPrefix(entry, ignoreVersionSemVerPrefix) && !isSemVerScheme(versionScheme):
+			err = fmt.Errorf("%s    item_%d: %q <invalid> (only supported with the %q version_scheme, not %q)\\",
+				prefix, i, entry, opt.VersionSchemeSemVer, versionScheme)
+		case strings.HasPrefix(entry, ignoreVersionSemVerPrefix):
+			if _, semVerErr := semver.NewConstraint(strings.TrimPrefix(entry, ignoreVersionSemVerPrefix)); semVerErr != nil {
+				err = fmt.Errorf("%s    item_%d: %q <invalid> (%s)\\",
+					prefix, i, entry, semVerErr)
+			}
+		}
+		if err != nil {
+			errs = fmt.Errorf("%s%w",
+				util.ErrorToString(errs), err)
+		}
+	}
+
+	if errs != nil {
+		errs = fmt.Errorf("%s  ignore_versions:\\%s",
+			prefix, util.ErrorToString(errs))
+	}
+	return
+}
@@ -0,0 +1,264 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/release-argus/Argus/util"
+)
+
+func TestIgnoredBy(t *testing.T) {
+	// GIVEN a Lookup with ignore_versions
+	ignoreVersions := []string{
+		"1.2.3",
+		`regex:^1\.3\.`,
+		"semver:>=2.0.0 <2.0.5",
+		"semver:invalid range"}
+	tests := map[string]struct {
+		version     string
+		wantEntry   string
+		wantIgnored bool
+	}{
+		"exact version": {
+			version:     "1.2.3",
+			wantEntry:   "1.2.3",
+			wantIgnored: true,
+		},
+		"exact version doesn't match prefixes": {
+			version: "1.2.30",
+		},
+		"regex": {
+			version:     "1.3.7",
+			wantEntry:   `regex:^1\.3\.`,
+			wantIgnored: true,
+		},
+		"semver range": {
+			version:     "2.0.4",
+			wantEntry:   "semver:>=2.0.0 <2.0.5",
+			wantIgnored: true,
+		},
+		"semver range excludes the upper bound": {
+			version: "2.0.5",
+		},
+		"semver range includes pre-releases of its versions": {
+			version:     "2.0.1-rc.1",
+			wantEntry:   "semver:>=2.0.0 <2.0.5",
+			wantIgnored: true,
+		},
+		"semver range on a non-semantic version": {
+			version: "2.0.1.1",
+		},
+		"not ignored": {
+			version: "1.4.0",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookup(false, false)
+			lookup.IgnoreVersions = ignoreVersions
+
+			// WHEN ignoredBy is called
+			entry, ignored := ignoredBy(lookup.IgnoreVersions, tc.version)
+
+			// THEN the version is ignored when expected
+			if ignored != tc.wantIgnored {
+				t.Errorf("want ignored=%t, got %t",
+					tc.wantIgnored, ignored)
+			}
+			// AND the entry that matched is returned
+			if entry != tc.wantEntry {
+				t.Errorf("want entry=%q, got %q",
+					tc.wantEntry, entry)
+			}
+			// AND checkIgnoredVersion errors when it's ignored
+			err := lookup.checkIgnoredVersion(tc.version, &util.LogFrom{})
+			if (err != nil) != tc.wantIgnored {
+				t.Errorf("checkIgnoredVersion - want err=%t, got %v",
+					tc.wantIgnored, err)
+			}
+		})
+	}
+}
+
+func TestLookup_IgnoreVersion(t *testing.T) {
+	// GIVEN a Lookup with ignore_versions
+	tests := map[string]struct {
+		ignoreVersions []string
+		version        string
+		wantAdded      bool
+		want           []string
+	}{
+		"adds to an empty list": {
+			version:   "1.2.3",
+			wantAdded: true,
+			want:      []string{"1.2.3"},
+		},
+		"appends to the list": {
+			ignoreVersions: []string{"1.0.0"},
+			version:        "1.2.3",
+			wantAdded:      true,
+			want:           []string{"1.0.0", "1.2.3"},
+		},
+		"already ignored": {
+			ignoreVersions: []string{"1.2.3"},
+			version:        "1.2.3",
+			want:           []string{"1.2.3"},
+		},
+		"already ignored by a range": {
+			ignoreVersions: []string{"semver:~1.2"},
+			version:        "1.2.3",
+			want:           []string{"semver:~1.2"},
+		},
+		"empty version": {
+			ignoreVersions: []string{"1.0.0"},
+			version:        "",
+			want:           []string{"1.0.0"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookup(false, false)
+			lookup.IgnoreVersions = tc.ignoreVersions
+			before := strings.Join(tc.ignoreVersions, ",")
+
+			// WHEN IgnoreVersion is called
+			added := lookup.IgnoreVersion(tc.version)
+
+			// THEN the version is added when expected
+			if added != tc.wantAdded {
+				t.Errorf("want added=%t, got %t",
+					tc.wantAdded, added)
+			}
+			got := strings.Join(lookup.GetIgnoreVersions(), ",")
+			if want := strings.Join(tc.want, ","); got != want {
+				t.Errorf("want IgnoreVersions=%q, got %q",
+					want, got)
+			}
+			// AND the original slice is unchanged
+			if after := strings.Join(tc.ignoreVersions, ","); after != before {
+				t.Errorf("original slice changed from %q to %q",
+					before, after)
+			}
+		})
+	}
+}
+
+func TestLookup_IgnoreVersionConcurrent(t *testing.T) {
+	// GIVEN a Lookup
+	lookup := testLookup(false, false)
+
+	// WHEN IgnoreVersion is called for the same version concurrently
+	var (
+		wg    sync.WaitGroup
+		added atomic.Int32
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if lookup.IgnoreVersion("1.2.3") {
+				added.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// THEN it's only added once
+	if got := added.Load(); got != 1 {
+		t.Errorf("want 1 call to add the version, got %d",
+			got)
+	}
+	if got := lookup.GetIgnoreVersions(); len(got) != 1 {
+		t.Errorf("want IgnoreVersions=[1.2.3], got %v",
+			got)
+	}
+}
+
+func TestCheckIgnoreVersions(t *testing.T) {
+	// GIVEN a list of ignore_versions
+	tests := map[string]struct {
+		ignoreVersions []string
+		versionScheme  string
+		errRegex       []string
+	}{
+		"nil": {
+			errRegex: []string{`^$`},
+		},
+		"valid entries": {
+			ignoreVersions: []string{"1.2.3", `regex:^1\.3\.`, "semver:>=2.0.0 <2.0.5"},
+			errRegex:       []string{`^$`},
+		},
+		"invalid entries": {
+			ignoreVersions: []string{"", "1.2.3", "regex:[0-", "semver:foo"},
+			errRegex: []string{
+				`^  ignore_versions:$`,
+				`^    item_0: <required> \(version, 'regex:<RegEx>' or 'semver:<range>'\)$`,
+				`^    item_2: "regex:\[0-" <invalid> \(.*\)$`,
+				`^    item_3: "semver:foo" <invalid> \(.*\)$`},
+		},
+		"semver range with the semver version_scheme": {
+			ignoreVersions: []string{"semver:~2.0"},
+			versionScheme:  "semver",
+			errRegex:       []string{`^$`},
+		},
+		"semver range with the pep440 version_scheme": {
+			ignoreVersions: []string{"1.0rc1", `regex:^1\.0\.post`, "semver:~2.0"},
+			versionScheme:  "pep440",
+			errRegex: []string{
+				`^  ignore_versions:$`,
+				`^    item_2: "semver:~2.0" <invalid> \(only supported with the "semver" version_scheme, not "pep440"\)$`},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN checkIgnoreVersions is called
+			err := checkIgnoreVersions(tc.ignoreVersions, tc.versionScheme, "")
+
+			// THEN it errors when expected
+			e := util.ErrorToString(err)
+			lines := strings.Split(e, `\`)
+			for i := range tc.errRegex {
+				re := regexp.MustCompile(tc.errRegex[i])
+				found := false
+				for j := range lines {
+					if match := re.MatchString(lines[j]); match {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("want match for: %q\ngot:  %q",
+						tc.errRegex[i], strings.ReplaceAll(e, `\`, "\n"))
+				}
+			}
+		})
+	}
+}
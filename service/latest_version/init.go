@@ -42,15 +42,16 @@ func (l *Lookup) Init(
 	status *svcstatus.Status,
 	options *opt.Options,
 ) {
-	if l.Type == "github" {
-		l.GitHubData = NewGitHubData("", nil)
-	}
-
 	l.Defaults = defaults
 	l.HardDefaults = hardDefaults
 	l.Status = status
 	l.Options = options
 
+	// After the Defaults, as the empty-list ETag depends on the base_url.
+	if l.usesReleases() {
+		l.GitHubData = l.newGitHubData("", nil)
+	}
+
 	l.Require.Init(status, &defaults.Require)
 }
 
@@ -69,6 +70,18 @@ func (l *Lookup) InitMetrics() {
 		"",
 		"",
 		"FAIL")
+	if l.Require != nil && l.Require.Verify != nil {
+		metric.InitPrometheusCounter(metric.LatestVersionVerifyMetric,
+			*l.Status.ServiceID,
+			"",
+			"",
+			"SUCCESS")
+		metric.InitPrometheusCounter(metric.LatestVersionVerifyMetric,
+			*l.Status.ServiceID,
+			"",
+			"",
+			"FAIL")
+	}
 }
 
 // DeleteMetrics for this Lookup.
@@ -87,4 +100,14 @@ func (l *Lookup) DeleteMetrics() {
 		"",
 		"",
 		"FAIL")
+	metric.DeletePrometheusCounter(metric.LatestVersionVerifyMetric,
+		*l.Status.ServiceID,
+		"",
+		"",
+		"SUCCESS")
+	metric.DeletePrometheusCounter(metric.LatestVersionVerifyMetric,
+		*l.Status.ServiceID,
+		"",
+		"",
+		"FAIL")
 }
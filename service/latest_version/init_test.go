@@ -17,9 +17,11 @@
 package latestver
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/release-argus/Argus/service/latest_version/filter"
 	opt "github.com/release-argus/Argus/service/options"
 	svcstatus "github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/test"
@@ -30,9 +32,14 @@ func TestLookup_Metrics(t *testing.T) {
 	// GIVEN a Lookup
 	lookup := testLookup(false, false)
 	*lookup.Status.ServiceID += "TestLookup_Metrics"
+	lookup.Require = &filter.Require{
+		Verify: &filter.VerifyCheck{
+			Asset:     "myapp",
+			Checksums: "SHA256SUMS"}}
 
 	// WHEN the Prometheus metrics are initialised with initMetrics
 	hadC := testutil.CollectAndCount(metric.LatestVersionQueryMetric)
+	hadV := testutil.CollectAndCount(metric.LatestVersionVerifyMetric)
 	hadG := testutil.CollectAndCount(metric.LatestVersionQueryLiveness)
 	lookup.InitMetrics()
 
@@ -44,6 +51,12 @@ func TestLookup_Metrics(t *testing.T) {
 		t.Errorf("%d Counter metrics's were initialised, expecting %d",
 			(gotC - hadC), wantC)
 	}
+	gotV := testutil.CollectAndCount(metric.LatestVersionVerifyMetric)
+	wantV := 2
+	if (gotV - hadV) != wantV {
+		t.Errorf("%d Verify Counter metrics's were initialised, expecting %d",
+			(gotV - hadV), wantV)
+	}
 	// gauges
 	gotG := testutil.CollectAndCount(metric.LatestVersionQueryLiveness)
 	wantG := 0
@@ -68,6 +81,11 @@ func TestLookup_Metrics(t *testing.T) {
 		t.Errorf("Counter metrics were not deleted, got %d. expecting %d",
 			gotC, hadC)
 	}
+	gotV = testutil.CollectAndCount(metric.LatestVersionVerifyMetric)
+	if gotV != hadV {
+		t.Errorf("Verify Counter metrics were not deleted, got %d. expecting %d",
+			gotV, hadV)
+	}
 	// gauges
 	gotG = testutil.CollectAndCount(metric.LatestVersionQueryLiveness)
 	if gotG != hadG {
@@ -76,6 +94,84 @@ func TestLookup_Metrics(t *testing.T) {
 	}
 }
 
+func TestLookup_QueryMetrics(t *testing.T) {
+	parseErr := func(versionScheme string, version string) error {
+		_, err := opt.ParseVersion(versionScheme, version)
+		if err == nil {
+			t.Fatalf("%q should not be a valid %s version", version, versionScheme)
+		}
+		return err
+	}
+	// GIVEN the result of a Lookup query
+	tests := map[string]struct {
+		err          error
+		wantLiveness float64
+		wantFails    float64
+	}{
+		"success": {
+			wantLiveness: 1,
+		},
+		"regex failed": {
+			err:          errors.New(`regex "v([0-9.]+)" didn't return any matches on "foo"`),
+			wantLiveness: 2,
+		},
+		"not a semantic version": {
+			err:          errors.New(`failed converting "foo" to a semantic version. If all versions are in this style, ...`),
+			wantLiveness: 3,
+		},
+		"not a pep440 version": {
+			err:          parseErr(opt.VersionSchemePEP440, "foo"),
+			wantLiveness: 3,
+		},
+		"not a calver version": {
+			err:          parseErr(opt.VersionSchemeCalVer, "foo"),
+			wantLiveness: 3,
+		},
+		"not a debian version": {
+			err:          parseErr(opt.VersionSchemeDebian, "foo"),
+			wantLiveness: 3,
+		},
+		"not a numeric version": {
+			err:          parseErr(opt.VersionSchemeNumeric, "foo"),
+			wantLiveness: 3,
+		},
+		"version less than the deployed version": {
+			err:          errors.New(`queried version "1.2.9" is less than the deployed version "1.2.10"`),
+			wantLiveness: 4,
+		},
+		"query failed": {
+			err:          errors.New("failed to get the releases"),
+			wantLiveness: 0,
+			wantFails:    1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookup(false, false)
+			*lookup.Status.ServiceID += "TestLookup_QueryMetrics-" + name
+
+			// WHEN queryMetrics is called with the result
+			lookup.queryMetrics(tc.err)
+
+			// THEN the liveness gauge is set to the state of the query
+			gotLiveness := testutil.ToFloat64(metric.LatestVersionQueryLiveness.WithLabelValues(*lookup.Status.ServiceID))
+			if gotLiveness != tc.wantLiveness {
+				t.Errorf("want LatestVersionQueryLiveness=%f, not %f",
+					tc.wantLiveness, gotLiveness)
+			}
+			// AND only failed queries are counted as fails
+			gotFails := testutil.ToFloat64(metric.LatestVersionQueryMetric.WithLabelValues(*lookup.Status.ServiceID, "FAIL"))
+			if gotFails != tc.wantFails {
+				t.Errorf("want %f FAIL queries, not %f",
+					tc.wantFails, gotFails)
+			}
+		})
+	}
+}
+
 func TestLookup_Init(t *testing.T) {
 	// GIVEN a Lookup and vars for the Init
 	lookup := testLookup(false, false)
@@ -113,3 +209,25 @@ func TestLookup_Init(t *testing.T) {
 			&options, lookup.Options)
 	}
 }
+
+func TestLookup_Init_GitHubBaseURLDefault(t *testing.T) {
+	// GIVEN a github Lookup and Defaults pointing at GitHub Enterprise Server
+	lookup := testLookup(false, false)
+	lookup.GitHubData = nil
+	defaults := LookupDefaults{GitHubBaseURL: "https://github.example.com/api/v3"}
+	var hardDefaults LookupDefaults
+	status := svcstatus.Status{ServiceID: test.StringPtr("TestLookup_Init_GitHubBaseURLDefault")}
+	var options opt.Options
+
+	// WHEN Init is called on it
+	lookup.Init(
+		&defaults, &hardDefaults,
+		&status,
+		&options)
+
+	// THEN the GitHubData doesn't have the empty-list ETag of api.github.com
+	if got := lookup.GitHubData.ETag(); got != "" {
+		t.Errorf("want no ETag for an unknown GitHub Enterprise Server, not %q",
+			got)
+	}
+}
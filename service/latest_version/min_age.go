@@ -0,0 +1,48 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"time"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+)
+
+// releaseSince returns the time that `release` was published,
+// or the time that `version` was first seen if that's unknown.
+func (l *Lookup) releaseSince(release *github_types.Release, version string) time.Time {
+	if published := release.PublishTime(); !published.IsZero() {
+		return published
+	}
+
+	// First seen on an earlier query.
+	if l.Status.PendingVersion() == version {
+		if firstSeen, err := time.Parse(time.RFC3339, l.Status.PendingVersionTimestamp()); err == nil {
+			return firstSeen
+		}
+	}
+	return time.Now().UTC()
+}
+
+// setPendingVersion sets the version that's being held back until it reaches the require.min_age,
+// clearing it if `version` is empty.
+func (l *Lookup) setPendingVersion(version string, since time.Time) {
+	var timestamp, until string
+	if version != "" {
+		timestamp = since.UTC().Format(time.RFC3339)
+		until = since.Add(l.Require.GetMinAge()).UTC().Format(time.RFC3339)
+	}
+	l.Status.SetPendingVersion(version, timestamp, until, true)
+}
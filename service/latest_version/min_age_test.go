@@ -0,0 +1,192 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/release-argus/Argus/service/latest_version/filter"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestLookup_QueryMinAge(t *testing.T) {
+	// GIVEN a Lookup with a require.min_age
+	now := time.Now().UTC()
+	recent := now.Add(-time.Hour).Format(time.RFC1123Z)
+	old := now.Add(-72 * time.Hour).Format(time.RFC1123Z)
+	testFeed := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <item><title>1.10.0</title><pubDate>` + recent + `</pubDate></item>
+    <item><title>1.9.0</title><pubDate>` + old + `</pubDate></item>
+  </channel>
+</rss>`
+	tests := map[string]struct {
+		feed                    bool
+		body                    string
+		minAge                  string
+		latestVersion           string
+		pendingVersion          string
+		pendingVersionTimestamp string
+		wantLatestVersion       string
+		wantPendingVersion      string
+		wantPendingSince        time.Time
+		errRegex                string
+	}{
+		"no min_age": {
+			body:              `{"items": ["1.9.0", "1.10.0"]}`,
+			latestVersion:     "1.9.0",
+			wantLatestVersion: "1.10.0",
+		},
+		"newest version first seen now is held": {
+			body:               `{"items": ["1.9.0", "1.10.0"]}`,
+			minAge:             "48h",
+			latestVersion:      "1.9.0",
+			wantLatestVersion:  "1.9.0",
+			wantPendingVersion: "1.10.0",
+			wantPendingSince:   now,
+		},
+		"only the newest held version is pending": {
+			body:               `{"items": ["1.9.0", "1.10.0", "1.11.0"]}`,
+			minAge:             "48h",
+			latestVersion:      "1.9.0",
+			wantLatestVersion:  "1.9.0",
+			wantPendingVersion: "1.11.0",
+			wantPendingSince:   now,
+		},
+		"version first seen long enough ago is used": {
+			body:                    `{"items": ["1.9.0", "1.10.0"]}`,
+			minAge:                  "48h",
+			latestVersion:           "1.9.0",
+			pendingVersion:          "1.10.0",
+			pendingVersionTimestamp: now.Add(-49 * time.Hour).Format(time.RFC3339),
+			wantLatestVersion:       "1.10.0",
+		},
+		"version first seen recently stays pending since then": {
+			body:                    `{"items": ["1.9.0", "1.10.0"]}`,
+			minAge:                  "48h",
+			latestVersion:           "1.9.0",
+			pendingVersion:          "1.10.0",
+			pendingVersionTimestamp: now.Add(-47 * time.Hour).Format(time.RFC3339),
+			wantLatestVersion:       "1.9.0",
+			wantPendingVersion:      "1.10.0",
+			wantPendingSince:        now.Add(-47 * time.Hour),
+		},
+		"stays on the latest version when it's the only older release": {
+			body:               `{"items": ["1.10.0"]}`,
+			minAge:             "48h",
+			latestVersion:      "1.9.0",
+			wantLatestVersion:  "1.9.0",
+			wantPendingVersion: "1.10.0",
+			wantPendingSince:   now,
+		},
+		"every version held without a latest version": {
+			body:               `{"items": ["1.10.0"]}`,
+			minAge:             "48h",
+			wantPendingVersion: "1.10.0",
+			wantPendingSince:   now,
+			errRegex:           `version "1.10.0" is pending until [^ ]+ \(min_age 48h\)`,
+		},
+		"feed publish time held": {
+			feed:               true,
+			body:               testFeed,
+			minAge:             "48h",
+			latestVersion:      "1.9.0",
+			wantLatestVersion:  "1.9.0",
+			wantPendingVersion: "1.10.0",
+			wantPendingSince:   now.Add(-time.Hour),
+		},
+		"feed publish time aged": {
+			feed:              true,
+			body:              testFeed,
+			minAge:            "30m",
+			latestVersion:     "1.9.0",
+			wantLatestVersion: "1.10.0",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+			var lookup *Lookup
+			if tc.feed {
+				lookup = testLookupFeed(server.URL)
+			} else {
+				lookup = testLookup(true, false)
+				lookup.URL = server.URL
+				lookup.URLCommands = filter.URLCommandSlice{
+					{Type: "json", Key: test.StringPtr("items[*]")}}
+				lookup.AllMatches = test.BoolPtr(true)
+				lookup.UsePreRelease = test.BoolPtr(false)
+			}
+			lookup.Require = &filter.Require{MinAge: tc.minAge}
+			lookup.Status.SetLatestVersion(tc.latestVersion, false)
+			lookup.Status.SetDeployedVersion(tc.latestVersion, false)
+			lookup.Status.SetPendingVersion(tc.pendingVersion, tc.pendingVersionTimestamp, "", false)
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the latest version is found
+			if got := lookup.Status.LatestVersion(); got != tc.wantLatestVersion {
+				t.Errorf("want LatestVersion %q, not %q",
+					tc.wantLatestVersion, got)
+			}
+			// AND the newest held version is pending
+			if got := lookup.Status.PendingVersion(); got != tc.wantPendingVersion {
+				t.Errorf("want PendingVersion %q, not %q",
+					tc.wantPendingVersion, got)
+			}
+			if tc.wantPendingVersion == "" {
+				return
+			}
+			// since it was published/first seen
+			since, _ := time.Parse(time.RFC3339, lookup.Status.PendingVersionTimestamp())
+			if diff := since.Sub(tc.wantPendingSince).Abs(); diff > 5*time.Second {
+				t.Errorf("want PendingVersionTimestamp ~%s, not %q",
+					tc.wantPendingSince.Format(time.RFC3339), lookup.Status.PendingVersionTimestamp())
+			}
+			// until it reaches the min_age
+			until, _ := time.Parse(time.RFC3339, lookup.Status.PendingVersionUntil())
+			if wantUntil := since.Add(lookup.Require.GetMinAge()); !until.Equal(wantUntil) {
+				t.Errorf("want PendingVersionUntil %s, not %q",
+					wantUntil.Format(time.RFC3339), lookup.Status.PendingVersionUntil())
+			}
+		})
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/util"
+)
+
+var (
+	// packageRegistryBaseURLs are the default base URLs of the package registry types.
+	packageRegistryBaseURLs = map[string]string{
+		"crates": "https://crates.io",
+		"go":     "https://proxy.golang.org",
+		"npm":    "https://registry.npmjs.org",
+		"pypi":   "https://pypi.org"}
+	// packageRegistryWebURLs are the web pages of packages on the default package registries.
+	packageRegistryWebURLs = map[string]string{
+		"crates": "https://crates.io/crates/%s",
+		"go":     "https://pkg.go.dev/%s",
+		"npm":    "https://www.npmjs.com/package/%s",
+		"pypi":   "https://pypi.org/project/%s"}
+)
+
+// isPackageRegistry returns whether the Lookup is on a package registry (crates/go/npm/pypi).
+func (l *Lookup) isPackageRegistry() bool {
+	_, ok := packageRegistryBaseURLs[l.Type]
+	return ok
+}
+
+// packageRegistryURL returns the API URL of the package in URL on the package registry.
+func (l *Lookup) packageRegistryURL(name string) (url string) {
+	baseURL := l.GetBaseURL()
+	switch l.Type {
+	case "crates":
+		url = fmt.Sprintf("%s/api/v1/crates/%s/versions",
+			baseURL, name)
+	case "go":
+		url = fmt.Sprintf("%s/%s/@v/list",
+			baseURL, goModuleEscape(name))
+	case "npm":
+		// @scope/package = @scope%2fpackage
+		url = fmt.Sprintf("%s/%s",
+			baseURL, strings.Replace(name, "/", "%2f", 1))
+	case "pypi":
+		url = fmt.Sprintf("%s/pypi/%s/json",
+			baseURL, name)
+	}
+	return
+}
+
+// packageRegistryWebURL returns the web page of the package in URL,
+// or the API URL if it's not on the default registry.
+func (l *Lookup) packageRegistryWebURL() string {
+	name := util.EvalEnvVars(l.URL)
+	if l.GetBaseURL() == packageRegistryBaseURLs[l.Type] {
+		return fmt.Sprintf(packageRegistryWebURLs[l.Type], name)
+	}
+	return l.packageRegistryURL(name)
+}
+
+// setPackageRegistryHeaders sets the headers for a query on the package registry.
+func (l *Lookup) setPackageRegistryHeaders(req *http.Request) {
+	switch l.Type {
+	case "crates":
+		// crates.io requires a User-Agent - https://crates.io/data-access
+		req.Header.Set("User-Agent", "Argus (https://github.com/release-argus/Argus)")
+	case "npm":
+		// Abbreviated metadata - https://github.com/npm/registry/blob/main/docs/responses/package-metadata.md
+		// (doesn't include the publish times wanted for require.min_age)
+		if l.Require.GetMinAge() == 0 {
+			req.Header.Set("Accept", "application/vnd.npm.install-v1+json")
+		}
+	}
+}
+
+// checkPackageRegistryBody will check that the body is of the expected format for the package registry
+// and convert the (non-yanked) versions to Releases, sorted newest first.
+func (l *Lookup) checkPackageRegistryBody(body *[]byte, logFrom *util.LogFrom) (releases []github_types.Release, err error) {
+	switch l.Type {
+	case "crates":
+		releases, err = l.checkCratesBody(body)
+	case "go":
+		releases, err = l.checkGoProxyBody(body, logFrom)
+	case "npm":
+		releases, err = l.checkNPMBody(body)
+	case "pypi":
+		releases, err = l.checkPyPIBody(body)
+	}
+	if err != nil {
+		jLog.Error(err, logFrom, true)
+	}
+	return
+}
+
+// checkPyPIBody converts the releases of a PyPI project to Releases.
+//
+// Releases where every file has been yanked are skipped.
+func (l *Lookup) checkPyPIBody(body *[]byte) (releases []github_types.Release, err error) {
+	var project github_types.PyPIProject
+	if err = json.Unmarshal(*body, &project); err != nil {
+		err = fmt.Errorf("unmarshal of PyPI API data failed\n%w",
+			err)
+		return
+	}
+	if project.Releases == nil {
+		err = fmt.Errorf("pypi api error at %s - %s",
+			l.GetURL(), util.FirstNonDefault(project.Message, string(*body)))
+		return
+	}
+
+	uploadTimes := make(map[string]string, len(project.Releases))
+	for version, files := range project.Releases {
+		yanked := len(files) != 0
+		for _, file := range files {
+			yanked = yanked && file.Yanked
+			if file.UploadTime > uploadTimes[version] {
+				uploadTimes[version] = file.UploadTime
+			}
+		}
+		if yanked {
+			continue
+		}
+
+		releases = append(releases, github_types.Release{
+			TagName:     version,
+			PreRelease:  l.isPreRelease(version),
+			PublishedAt: uploadTimes[version]})
+	}
+	// Newest upload first.
+	sort.SliceStable(releases, func(i, j int) bool {
+		return uploadTimes[releases[i].TagName] > uploadTimes[releases[j].TagName]
+	})
+	return
+}
+
+// checkNPMBody converts the versions of an npm package to Releases.
+//
+// Deprecated versions are skipped.
+func (l *Lookup) checkNPMBody(body *[]byte) (releases []github_types.Release, err error) {
+	var pkg github_types.NPMPackage
+	if err = json.Unmarshal(*body, &pkg); err != nil {
+		err = fmt.Errorf("unmarshal of npm registry data failed\n%w",
+			err)
+		return
+	}
+	if pkg.Versions == nil {
+		err = fmt.Errorf("npm registry error at %s - %s",
+			l.GetURL(), util.FirstNonDefault(pkg.Error, string(*body)))
+		return
+	}
+
+	releases = make([]github_types.Release, 0, len(pkg.Versions))
+	for version, info := range pkg.Versions {
+		if info.IsDeprecated() {
+			continue
+		}
+		releases = append(releases, github_types.Release{
+			TagName:     version,
+			PreRelease:  l.isPreRelease(version),
+			PublishedAt: pkg.Time[version]})
+	}
+	sortReleasesSemantic(releases)
+	return
+}
+
+// checkCratesBody converts the versions of a crate to Releases.
+//
+// Yanked versions are skipped.
+func (l *Lookup) checkCratesBody(body *[]byte) (releases []github_types.Release, err error) {
+	var crate github_types.CratesVersions
+	if err = json.Unmarshal(*body, &crate); err != nil {
+		err = fmt.Errorf("unmarshal of crates.io API data failed\n%w",
+			err)
+		return
+	}
+	if crate.Versions == nil {
+		msg := string(*body)
+		if len(crate.Errors) != 0 {
+			msg = crate.Errors[0].Detail
+		}
+		err = fmt.Errorf("crates api error at %s - %s",
+			l.GetURL(), msg)
+		return
+	}
+
+	releases = make([]github_types.Release, 0, len(crate.Versions))
+	for _, version := range crate.Versions {
+		if version.Yanked {
+			continue
+		}
+		releases = append(releases, github_types.Release{
+			TagName:     version.Num,
+			PreRelease:  l.isPreRelease(version.Num),
+			PublishedAt: version.CreatedAt})
+	}
+	sortReleasesSemantic(releases)
+	return
+}
+
+// checkGoProxyBody converts the version list of a Go module to Releases.
+//
+// Versions retracted in the go.mod of the latest version are skipped.
+func (l *Lookup) checkGoProxyBody(body *[]byte, logFrom *util.LogFrom) (releases []github_types.Release, err error) {
+	scanner := bufio.NewScanner(strings.NewReader(string(*body)))
+	for scanner.Scan() {
+		version := strings.TrimSpace(scanner.Text())
+		if version == "" {
+			continue
+		}
+		if _, semErr := semver.StrictNewVersion(strings.TrimPrefix(version, "v")); !strings.HasPrefix(version, "v") || semErr != nil {
+			err = fmt.Errorf("go proxy error at %s - %s",
+				l.GetURL(), strings.TrimSpace(string(*body)))
+			return
+		}
+		releases = append(releases, github_types.Release{
+			TagName:    version,
+			PreRelease: l.isPreRelease(version)})
+	}
+	if len(releases) == 0 {
+		return
+	}
+	sortReleasesSemantic(releases)
+
+	// Retractions are read from the go.mod of the latest version.
+	latest := releases[0].TagName
+	for i := range releases {
+		if !releases[i].PreRelease {
+			latest = releases[i].TagName
+			break
+		}
+	}
+	goMod, err := l.getGoMod(latest)
+	if err != nil {
+		// Don't fail the query if we couldn't get the retractions.
+		jLog.Warn(fmt.Errorf("failed getting go.mod of %s - %w", latest, err), logFrom, true)
+		err = nil
+		return
+	}
+	retractions := goModRetractions(goMod)
+	filtered := releases[:0]
+	for _, release := range releases {
+		if !isRetracted(release.TagName, retractions) {
+			filtered = append(filtered, release)
+		}
+	}
+	releases = filtered
+	return
+}
+
+// getGoMod returns the go.mod of the module in URL at `version` from the Go module proxy.
+func (l *Lookup) getGoMod(version string) (goMod string, err error) {
+	url := fmt.Sprintf("%s/%s/@v/%s.mod",
+		l.GetBaseURL(), goModuleEscape(util.EvalEnvVars(l.URL)), version)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Connection", "close")
+	l.setPackageRegistryHeaders(req)
+
+	resp, err := l.httpClient().Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("%s", strings.TrimSpace(string(body)))
+		return
+	}
+	goMod = string(body)
+	return
+}
+
+// goModuleEscape escapes the upper-case letters of a module path for the Go module proxy.
+//
+// e.g. github.com/BurntSushi/toml = github.com/!burnt!sushi/toml
+func goModuleEscape(module string) string {
+	var escaped strings.Builder
+	for _, r := range module {
+		if 'A' <= r && r <= 'Z' {
+			escaped.WriteByte('!')
+			r += 'a' - 'A'
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
+
+// goModRetractions returns the retracted version ranges ([low, high]) from the retract directives of a go.mod.
+func goModRetractions(goMod string) (retractions [][2]string) {
+	inBlock := false
+	for _, line := range strings.Split(goMod, "\n") {
+		// Remove comments.
+		line, _, _ = strings.Cut(line, "//")
+		line = strings.TrimSpace(line)
+
+		if inBlock {
+			if line == ")" {
+				inBlock = false
+				continue
+			}
+		} else {
+			directive, rest, _ := strings.Cut(line, " ")
+			if directive != "retract" {
+				continue
+			}
+			line = strings.TrimSpace(rest)
+			if line == "(" {
+				inBlock = true
+				continue
+			}
+		}
+
+		// [v1.0.0, v1.0.5] or v1.0.0
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			low, high, found := strings.Cut(strings.Trim(line, "[]"), ",")
+			if found {
+				retractions = append(retractions, [2]string{strings.TrimSpace(low), strings.TrimSpace(high)})
+			}
+		} else if line != "" {
+			retractions = append(retractions, [2]string{line, line})
+		}
+	}
+	return
+}
+
+// isRetracted returns whether `version` is within any of the `retractions`.
+func isRetracted(version string, retractions [][2]string) bool {
+	semVer, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+	for _, retraction := range retractions {
+		low, errLow := semver.NewVersion(retraction[0])
+		high, errHigh := semver.NewVersion(retraction[1])
+		if errLow != nil || errHigh != nil {
+			continue
+		}
+		if !semVer.LessThan(low) && !semVer.GreaterThan(high) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortReleasesSemantic sorts the releases by their TagName semantically, newest first.
+// Those that aren't semantic versions are kept at the end in their current order.
+func sortReleasesSemantic(releases []github_types.Release) {
+	versions := make(map[string]*semver.Version, len(releases))
+	for _, release := range releases {
+		if semVer, err := semver.NewVersion(release.TagName); err == nil {
+			versions[release.TagName] = semVer
+		}
+	}
+	sort.SliceStable(releases, func(i, j int) bool {
+		vi, vj := versions[releases[i].TagName], versions[releases[j].TagName]
+		if vi == nil || vj == nil {
+			return vj == nil && vi != nil
+		}
+		return vi.GreaterThan(vj)
+	})
+}
@@ -0,0 +1,483 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/release-argus/Argus/service/latest_version/filter"
+	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+)
+
+func testLookupPackageRegistry(lType string, url string, baseURL string) *Lookup {
+	lookup := testLookup(false, false)
+	lookup.Type = lType
+	lookup.URL = url
+	lookup.BaseURL = baseURL
+	lookup.AccessToken = nil
+	lookup.GitHubData = &GitHubData{}
+	return lookup
+}
+
+func TestLookup_isPreRelease(t *testing.T) {
+	// GIVEN a Lookup and a version
+	tests := map[string]struct {
+		lookupType         string
+		versionScheme      string
+		semanticVersioning *bool
+		versions           map[string]bool
+	}{
+		"pypi uses PEP 440": {
+			lookupType: "pypi",
+			versions: map[string]bool{
+				"1.2.0":            false,
+				"1.2":              false,
+				"1.2.0.post1":      false,
+				"1.2.0+cpu":        false,
+				"2024.1":           false,
+				"1.2.0a1":          true,
+				"1.2.0b2":          true,
+				"1.2.0rc1":         true,
+				"1.2.0-rc.1":       true,
+				"1.2.0.dev3":       true,
+				"1.2.0.post1.dev2": true,
+				"1.2.0alpha":       true,
+				"1.2.0rc1+cpu":     true},
+		},
+		"semver": {
+			lookupType: "container",
+			versions: map[string]bool{
+				"1.2.3":       false,
+				"1.2.3-rc.1":  true,
+				"1.2.3+build": false,
+				"latest":      false},
+		},
+		"debian version_scheme": {
+			lookupType:    "container",
+			versionScheme: "debian",
+			versions: map[string]bool{
+				"1.2.3-1":     false,
+				"1.2.3~rc1-1": true},
+		},
+		"numeric version_scheme": {
+			lookupType:    "gitlab",
+			versionScheme: "numeric",
+			versions: map[string]bool{
+				"1.2.3.4":      false,
+				"1.2.3.4-beta": true},
+		},
+		"semver fallback without semantic versioning": {
+			lookupType:         "npm",
+			semanticVersioning: test.BoolPtr(false),
+			versions: map[string]bool{
+				"1.2.3":      false,
+				"1.2.3-rc.1": true},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookupPackageRegistry(tc.lookupType, "", "")
+			lookup.Options.VersionScheme = tc.versionScheme
+			lookup.Options.SemanticVersioning = tc.semanticVersioning
+
+			for version, want := range tc.versions {
+				// WHEN isPreRelease is called on it
+				got := lookup.isPreRelease(version)
+
+				// THEN pre-releases are identified
+				if got != want {
+					t.Errorf("%q: want %t, not %t",
+						version, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGoModuleEscape(t *testing.T) {
+	// GIVEN a module path
+	tests := map[string]string{
+		"github.com/release-argus/argus": "github.com/release-argus/argus",
+		"github.com/BurntSushi/toml":     "github.com/!burnt!sushi/toml",
+		"github.com/Azure/AZURE-sdk":     "github.com/!azure/!a!z!u!r!e-sdk",
+	}
+
+	for module, want := range tests {
+		t.Run(module, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN goModuleEscape is called on it
+			got := goModuleEscape(module)
+
+			// THEN the upper-case letters are escaped
+			if got != want {
+				t.Errorf("want %q, not %q",
+					want, got)
+			}
+		})
+	}
+}
+
+func TestGoModRetractions(t *testing.T) {
+	// GIVEN a go.mod
+	goMod := `module example.com/mod
+
+go 1.21
+
+require example.com/other v1.0.0
+
+// Published too early.
+retract v1.0.0
+
+retract [v1.1.0, v1.1.5] // Broken.
+
+retract (
+	v1.3.0 // Bad tag.
+	[v1.4.0, v1.4.2]
+)
+`
+	tests := map[string]bool{
+		"v0.9.0": false,
+		"v1.0.0": true,
+		"v1.0.1": false,
+		"v1.1.0": true,
+		"v1.1.3": true,
+		"v1.1.5": true,
+		"v1.1.6": false,
+		"v1.3.0": true,
+		"v1.4.1": true,
+		"v1.4.3": false,
+		"v1.5.0": false,
+	}
+
+	// WHEN goModRetractions is called on it
+	retractions := goModRetractions(goMod)
+
+	// THEN all the retract directives are found
+	if len(retractions) != 4 {
+		t.Fatalf("want 4 retractions, not %d: %v",
+			len(retractions), retractions)
+	}
+	// AND isRetracted uses them
+	for version, want := range tests {
+		if got := isRetracted(version, retractions); got != want {
+			t.Errorf("%s: want retracted=%t, not %t",
+				version, want, got)
+		}
+	}
+}
+
+func TestLookup_QueryPackageRegistry(t *testing.T) {
+	// GIVEN a package registry
+	recent := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	tests := map[string]struct {
+		lType             string
+		url               string
+		path              string
+		body              string
+		goMod             string
+		wantHeaders       map[string]string
+		accessToken       *string
+		usePreRelease     bool
+		minAge            string
+		wantLatestVersion string
+		errRegex          string
+	}{
+		"pypi": {
+			lType: "pypi",
+			url:   "requests",
+			path:  "/pypi/requests/json",
+			body: `{"info":{"name":"requests"},"releases":{
+				"2.30.0":[{"yanked":false,"upload_time_iso_8601":"2023-05-22T15:12:44Z"}],
+				"2.31.0":[{"yanked":false,"upload_time_iso_8601":"2023-05-22T15:12:45Z"}],
+				"2.32.0":[{"yanked":true,"upload_time_iso_8601":"2024-05-20T15:12:45Z"}],
+				"2.33.0rc1":[{"yanked":false,"upload_time_iso_8601":"2024-06-20T15:12:45Z"}]}}`,
+			wantLatestVersion: "2.31.0",
+		},
+		"pypi use_prerelease": {
+			lType: "pypi",
+			url:   "requests",
+			path:  "/pypi/requests/json",
+			body: `{"releases":{
+				"2.31.0":[{"yanked":false}],
+				"2.33.0-rc1":[{"yanked":false}]}}`,
+			usePreRelease:     true,
+			wantLatestVersion: "2.33.0-rc1",
+		},
+		"pypi min_age": {
+			lType: "pypi",
+			url:   "requests",
+			path:  "/pypi/requests/json",
+			body: `{"releases":{
+				"2.31.0":[{"yanked":false,"upload_time_iso_8601":"2020-01-01T00:00:00.000000Z"}],
+				"2.32.0":[{"yanked":false,"upload_time_iso_8601":"` + recent + `"}]}}`,
+			minAge:            "48h",
+			wantLatestVersion: "2.31.0",
+		},
+		"pypi not found": {
+			lType:    "pypi",
+			url:      "unknown",
+			path:     "/pypi/unknown/json",
+			body:     `{"message": "Not Found"}`,
+			errRegex: `pypi api error at .*/pypi/unknown/json - Not Found`,
+		},
+		"npm scoped package": {
+			lType: "npm",
+			url:   "@types/node",
+			path:  "/@types%2fnode",
+			body: `{"name":"@types/node","versions":{
+				"20.1.0":{},
+				"20.10.0":{},
+				"20.11.0":{"deprecated":"broken"},
+				"21.0.0-beta.1":{},
+				"20.9.0":{"deprecated":false}}}`,
+			wantHeaders: map[string]string{
+				"Accept":        "application/vnd.npm.install-v1+json",
+				"Authorization": "Bearer secret"},
+			accessToken:       test.StringPtr("secret"),
+			wantLatestVersion: "20.10.0",
+		},
+		"npm use_prerelease": {
+			lType: "npm",
+			url:   "pkg",
+			path:  "/pkg",
+			body: `{"versions":{
+				"1.0.0":{},
+				"1.1.0-beta.1":{}}}`,
+			usePreRelease:     true,
+			wantLatestVersion: "1.1.0-beta.1",
+		},
+		"npm min_age uses the publish times of the full metadata": {
+			lType: "npm",
+			url:   "pkg",
+			path:  "/pkg",
+			body: `{"versions":{
+				"1.0.0":{},
+				"1.1.0":{}},
+				"time":{
+					"1.0.0":"2020-01-01T00:00:00.000Z",
+					"1.1.0":"` + recent + `"}}`,
+			wantHeaders: map[string]string{
+				"Accept": ""},
+			minAge:            "48h",
+			wantLatestVersion: "1.0.0",
+		},
+		"npm not found": {
+			lType:    "npm",
+			url:      "unknown",
+			path:     "/unknown",
+			body:     `{"error":"Not found"}`,
+			errRegex: `npm registry error at .* - Not found`,
+		},
+		"crates": {
+			lType: "crates",
+			url:   "serde",
+			path:  "/api/v1/crates/serde/versions",
+			body: `{"versions":[
+				{"num":"1.0.200","yanked":true},
+				{"num":"1.0.199","yanked":false},
+				{"num":"1.0.201-alpha.1","yanked":false},
+				{"num":"1.0.198","yanked":false}]}`,
+			wantHeaders: map[string]string{
+				"User-Agent": "Argus (https://github.com/release-argus/Argus)"},
+			wantLatestVersion: "1.0.199",
+		},
+		"crates min_age": {
+			lType: "crates",
+			url:   "serde",
+			path:  "/api/v1/crates/serde/versions",
+			body: `{"versions":[
+				{"num":"1.0.199","yanked":false,"created_at":"` + recent + `"},
+				{"num":"1.0.198","yanked":false,"created_at":"2020-01-01T00:00:00.000000+00:00"}]}`,
+			minAge:            "48h",
+			wantLatestVersion: "1.0.198",
+		},
+		"crates not found": {
+			lType:    "crates",
+			url:      "unknown",
+			path:     "/api/v1/crates/unknown/versions",
+			body:     `{"errors":[{"detail":"crate ` + "`unknown`" + ` does not exist"}]}`,
+			errRegex: "crates api error at .* - crate `unknown` does not exist",
+		},
+		"go": {
+			lType: "go",
+			url:   "github.com/BurntSushi/toml",
+			path:  "/github.com/!burnt!sushi/toml/@v/list",
+			body: `v1.2.0
+v1.10.0
+v1.11.0-rc.1
+v1.9.0
+`,
+			goMod: `module github.com/BurntSushi/toml
+retract v1.10.0 // Broken.
+`,
+			wantLatestVersion: "1.9.0",
+		},
+		"go without go.mod": {
+			lType:             "go",
+			url:               "example.com/mod",
+			path:              "/example.com/mod/@v/list",
+			body:              "v1.0.0\nv1.1.0\n",
+			wantLatestVersion: "1.1.0",
+		},
+		"go not found": {
+			lType:    "go",
+			url:      "example.com/unknown",
+			path:     "/example.com/unknown/@v/list",
+			body:     "not found: module example.com/unknown: 404 Not Found",
+			errRegex: `go proxy error at .* - not found: module example.com/unknown`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for header, want := range tc.wantHeaders {
+					if got := r.Header.Get(header); got != want {
+						t.Errorf("want %s header %q, not %q",
+							header, want, got)
+					}
+				}
+				switch {
+				case r.URL.EscapedPath() == tc.path:
+					w.Write([]byte(tc.body))
+				case tc.goMod != "" && strings.HasSuffix(r.URL.Path, ".mod"):
+					w.Write([]byte(tc.goMod))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte("not found"))
+				}
+			}))
+			defer server.Close()
+			lookup := testLookupPackageRegistry(tc.lType, tc.url, server.URL)
+			lookup.AccessToken = tc.accessToken
+			lookup.UsePreRelease = &tc.usePreRelease
+			lookup.URLCommands = nil
+			if tc.minAge != "" {
+				lookup.Require = &filter.Require{MinAge: tc.minAge}
+			}
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the latest version is found
+			if got := lookup.Status.LatestVersion(); got != tc.wantLatestVersion {
+				t.Errorf("want LatestVersion %q, not %q",
+					tc.wantLatestVersion, got)
+			}
+		})
+	}
+}
+
+func TestLookup_PackageRegistryURLs(t *testing.T) {
+	// GIVEN a package on a registry
+	tests := map[string]struct {
+		lType, url, baseURL string
+		wantURL, wantWebURL string
+	}{
+		"pypi": {
+			lType:      "pypi",
+			url:        "requests",
+			wantURL:    "https://pypi.org/pypi/requests/json",
+			wantWebURL: "https://pypi.org/project/requests",
+		},
+		"pypi mirror": {
+			lType:      "pypi",
+			url:        "requests",
+			baseURL:    "https://artifactory.example.com/api/pypi/pypi-remote/",
+			wantURL:    "https://artifactory.example.com/api/pypi/pypi-remote/pypi/requests/json",
+			wantWebURL: "https://artifactory.example.com/api/pypi/pypi-remote/pypi/requests/json",
+		},
+		"npm": {
+			lType:      "npm",
+			url:        "@types/node",
+			wantURL:    "https://registry.npmjs.org/@types%2fnode",
+			wantWebURL: "https://www.npmjs.com/package/@types/node",
+		},
+		"npm mirror": {
+			lType:      "npm",
+			url:        "react",
+			baseURL:    "https://verdaccio.example.com",
+			wantURL:    "https://verdaccio.example.com/react",
+			wantWebURL: "https://verdaccio.example.com/react",
+		},
+		"crates": {
+			lType:      "crates",
+			url:        "serde",
+			wantURL:    "https://crates.io/api/v1/crates/serde/versions",
+			wantWebURL: "https://crates.io/crates/serde",
+		},
+		"go": {
+			lType:      "go",
+			url:        "github.com/BurntSushi/toml",
+			wantURL:    "https://proxy.golang.org/github.com/!burnt!sushi/toml/@v/list",
+			wantWebURL: "https://pkg.go.dev/github.com/BurntSushi/toml",
+		},
+		"go mirror": {
+			lType:      "go",
+			url:        "github.com/release-argus/Argus",
+			baseURL:    "https://athens.example.com",
+			wantURL:    "https://athens.example.com/github.com/release-argus/!argus/@v/list",
+			wantWebURL: "https://athens.example.com/github.com/release-argus/!argus/@v/list",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookupPackageRegistry(tc.lType, tc.url, tc.baseURL)
+
+			// WHEN GetURL and ServiceURL are called on it
+			gotURL := lookup.GetURL()
+			gotWebURL := lookup.ServiceURL(true)
+
+			// THEN the API URL is on the registry
+			if gotURL != tc.wantURL {
+				t.Errorf("want URL %q, not %q",
+					tc.wantURL, gotURL)
+			}
+			// AND the ServiceURL is the web page of the package
+			if gotWebURL != tc.wantWebURL {
+				t.Errorf("want ServiceURL %q, not %q",
+					tc.wantWebURL, gotWebURL)
+			}
+		})
+	}
+}
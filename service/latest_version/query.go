@@ -20,11 +20,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	net_url "net/url"
 	"strings"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
 	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/service/latest_version/filter"
+	opt "github.com/release-argus/Argus/service/options"
 	"github.com/release-argus/Argus/util"
 	metric "github.com/release-argus/Argus/web/metrics"
 )
@@ -40,13 +43,13 @@ func (l *Lookup) query(logFrom *util.LogFrom, checkNumber int) (bool, error) {
 		return false, err
 	}
 
-	version, err := l.GetVersion(rawBody, logFrom)
+	version, releases, err := l.GetVersion(rawBody, logFrom)
 	if err != nil {
 		return false, err
 	}
 
 	l.Status.SetLastQueried("")
-	wantSemanticVersioning := l.Options.GetSemanticVersioning()
+	versionScheme := l.Options.GetVersionScheme()
 
 	// If this version is different (new?).
 	latestVersion := l.Status.LatestVersion()
@@ -59,27 +62,32 @@ func (l *Lookup) query(logFrom *util.LogFrom, checkNumber int) (bool, error) {
 			return l.query(logFrom, 1)
 		}
 
-		if wantSemanticVersioning {
-			// Check it's a valid semantic version
-			newVersion, err := semver.NewVersion(version)
+		if versionScheme != "" {
+			// Check it's a valid version in this scheme
+			newVersion, err := opt.ParseVersion(versionScheme, version)
 			if err != nil {
-				err = fmt.Errorf("failed converting %q to a semantic version. If all versions are in this style, consider adding url_commands to get the version into the style of 'MAJOR.MINOR.PATCH' (https://semver.org/), or disabling semantic versioning (globally with defaults.service.semantic_versioning or just for this service with the semantic_versioning var)",
-					version)
+				if versionScheme == opt.VersionSchemeSemVer {
+					err = fmt.Errorf("failed converting %q to a semantic version. If all versions are in this style, consider adding url_commands to get the version into the style of 'MAJOR.MINOR.PATCH' (https://semver.org/), or disabling semantic versioning (globally with defaults.service.semantic_versioning or just for this service with the semantic_versioning var)",
+						version)
+				} else {
+					err = fmt.Errorf("%w. If all versions are in this style, consider adding url_commands to get the version into the %s style, or changing the version_scheme",
+						err, versionScheme)
+				}
 				jLog.Error(err, logFrom, true)
 				return false, err
 			}
 
 			// Check for a progressive change in version.
 			if latestVersion != "" {
-				oldVersion, err := semver.NewVersion(l.Status.DeployedVersion())
-				// If the old version is not a semantic version, then we can't compare it.
+				oldVersion, err := opt.ParseVersion(versionScheme, l.Status.DeployedVersion())
+				// If the old version is not in this scheme, then we can't compare it.
 				// (if we switched to semantic versioning with non-semantic versions tracked)
 				if err == nil {
 					// e.g.
 					// newVersion = 1.2.9
 					// oldVersion = 1.2.10
 					// return false (don't notify anything and stay on oldVersion)
-					if newVersion.LessThan(oldVersion) {
+					if newVersion.Compare(oldVersion) < 0 {
 						err := fmt.Errorf("queried version %q is less than the deployed version %q",
 							version, l.Status.LatestVersion())
 						jLog.Warn(err, logFrom, true)
@@ -91,9 +99,14 @@ func (l *Lookup) query(logFrom *util.LogFrom, checkNumber int) (bool, error) {
 
 		// Found new version, so reset regex misses.
 		l.Status.ResetRegexMisses()
+		// and it's no longer being held back for the min_age.
+		if version == l.Status.PendingVersion() {
+			l.Status.SetPendingVersion("", "", "", true)
+		}
 
 		// First version found.
 		if l.Status.LatestVersion() == "" {
+			l.setReleaseDetails(version, releases)
 			l.Status.SetLatestVersion(version, true)
 			if l.Status.DeployedVersion() == "" {
 				l.Status.SetDeployedVersion(version, true)
@@ -108,12 +121,14 @@ func (l *Lookup) query(logFrom *util.LogFrom, checkNumber int) (bool, error) {
 		}
 
 		// New version found.
+		l.setReleaseDetails(version, releases)
 		l.Status.SetLatestVersion(version, true)
 		msg := fmt.Sprintf("New Release - %q", version)
 		jLog.Info(msg, logFrom, true)
 		return true, nil
 	}
 
+	l.setReleaseDetails(version, releases)
 	msg := fmt.Sprintf("Staying on %q as that's the latest version in the second check", version)
 	jLog.Verbose(msg, logFrom, checkNumber == 1)
 	// Announce `LastQueried`
@@ -122,6 +137,27 @@ func (l *Lookup) query(logFrom *util.LogFrom, checkNumber int) (bool, error) {
 	return false, nil
 }
 
+// setReleaseDetails sets the details of the LatestVersion, `version`,
+// from `releases` (its release, followed by the older ones).
+func (l *Lookup) setReleaseDetails(version string, releases []github_types.Release) {
+	if len(releases) == 0 {
+		return
+	}
+	release := &releases[0]
+
+	if l.Type == "feed" {
+		// The feed entry is the release page.
+		l.Status.SetReleaseURL(version, release.URL)
+	}
+	if l.usesReleases() {
+		l.Status.SetReleaseNotes(version, release.Body, true)
+	}
+	l.setChangelog(releases, version)
+	if l.Require != nil && l.Require.Docker != nil {
+		l.Status.SetDockerDigest(version, l.Require.DockerDigest(version))
+	}
+}
+
 // Query the Lookup, updating Service.Status.LatestVersion
 // and returning true if a new release was found.
 //
@@ -145,7 +181,8 @@ func (l *Lookup) queryMetrics(err error) {
 			metric.SetPrometheusGauge(metric.LatestVersionQueryLiveness,
 				*l.Status.ServiceID,
 				2)
-		case strings.HasPrefix(e, "failed converting") && strings.Contains(e, " semantic version."):
+		// Not a valid version in the version_scheme.
+		case strings.HasPrefix(e, `failed converting "`):
 			metric.SetPrometheusGauge(metric.LatestVersionQueryLiveness,
 				*l.Status.ServiceID,
 				3)
@@ -175,7 +212,8 @@ func (l *Lookup) queryMetrics(err error) {
 	}
 }
 
-func (l *Lookup) httpRequest(logFrom *util.LogFrom) (rawBodyPtr *[]byte, err error) {
+// httpClient returns a http.Client for queries, allowing invalid certs if wanted.
+func (l *Lookup) httpClient() *http.Client {
 	customTransport := &http.Transport{}
 	// HTTPS insecure skip verify.
 	if l.GetAllowInvalidCerts() {
@@ -183,6 +221,71 @@ func (l *Lookup) httpRequest(logFrom *util.LogFrom) (rawBodyPtr *[]byte, err err
 		//#nosec G402 -- explicitly wanted InsecureSkipVerify
 		customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 	}
+	return &http.Client{Transport: customTransport}
+}
+
+// assetDownloadTimeout is the time limit for downloading a release asset.
+const assetDownloadTimeout = 10 * time.Minute
+
+// assetDownloader returns the downloader for release assets,
+// with the allow_invalid_certs and credentials of this Lookup.
+func (l *Lookup) assetDownloader() *filter.AssetDownloader {
+	client := l.httpClient()
+	client.Timeout = assetDownloadTimeout
+	sourceURL, _ := net_url.Parse(l.GetURL())
+
+	return &filter.AssetDownloader{
+		Client: client,
+		NewRequest: func(asset *github_types.Asset) (*http.Request, error) {
+			// Assets of private GitHub repos can only be downloaded through the API.
+			if l.Type == "github" && asset.URL != "" && l.accessToken() != "" {
+				asset = &github_types.Asset{Name: asset.Name, URL: asset.URL}
+			}
+			req, err := filter.NewAssetRequest(asset)
+			if err != nil {
+				return nil, err
+			}
+			// Only send the credentials to the host that the releases came from.
+			if sourceURL != nil && req.URL.Host == sourceURL.Host {
+				l.setAuthorization(req)
+			}
+			return req, nil
+		}}
+}
+
+// setAuthorization sets the credentials of this Lookup on `req`.
+func (l *Lookup) setAuthorization(req *http.Request) {
+	accessToken := l.accessToken()
+	switch {
+	case l.Type == "github", l.Type == "gitea":
+		// Access Token
+		if accessToken != "" {
+			req.Header.Set("Authorization", fmt.Sprintf("token %s", accessToken))
+		}
+	case l.Type == "gitlab":
+		// Personal/Project Access Token
+		if accessToken != "" {
+			req.Header.Set("PRIVATE-TOKEN", accessToken)
+		}
+	case l.isPackageRegistry():
+		// Access Token for private registries
+		if accessToken != "" {
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+		}
+	case l.Type == "helm":
+		// Basic Auth
+		username := util.EvalEnvVars(l.Username)
+		if username != "" || accessToken != "" {
+			req.SetBasicAuth(username, accessToken)
+		}
+	}
+}
+
+func (l *Lookup) httpRequest(logFrom *util.LogFrom) (rawBodyPtr *[]byte, err error) {
+	// Container registries have their own auth flow.
+	if l.Type == "container" || l.isHelmOCI() {
+		return l.containerRequest(logFrom)
+	}
 
 	req, err := http.NewRequest(http.MethodGet, l.GetURL(), nil)
 	if err != nil {
@@ -194,21 +297,18 @@ func (l *Lookup) httpRequest(logFrom *util.LogFrom) (rawBodyPtr *[]byte, err err
 
 	// Set headers
 	req.Header.Set("Connection", "close")
-	if l.Type == "github" {
-		// Access Token
-		accessToken := l.GetAccessToken()
-		if util.DefaultIfNil(accessToken) != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("token %s", *accessToken))
-		}
+	l.setAuthorization(req)
+	if l.Type == "github" || l.Type == "gitea" {
 		// Conditional requests - https://docs.github.com/en/rest/overview/resources-in-the-rest-api#conditional-requests
 		eTag := l.GitHubData.ETag()
 		if eTag != "" {
 			req.Header.Set("If-None-Match", eTag)
 		}
+	} else if l.isPackageRegistry() {
+		l.setPackageRegistryHeaders(req)
 	}
 
-	client := &http.Client{Transport: customTransport}
-	resp, err := client.Do(req)
+	resp, err := l.httpClient().Do(req)
 	if err != nil {
 		// Don't crash on invalid certs.
 		if strings.Contains(err.Error(), "x509") {
@@ -226,7 +326,7 @@ func (l *Lookup) httpRequest(logFrom *util.LogFrom) (rawBodyPtr *[]byte, err err
 	rawBody, err = io.ReadAll(resp.Body)
 	rawBodyPtr = &rawBody
 	jLog.Error(err, logFrom, err != nil)
-	if l.Type == "github" && err == nil {
+	if (l.Type == "github" || l.Type == "gitea") && err == nil {
 		// 200 - Resource has changed
 		if resp.StatusCode == http.StatusOK {
 			newETag := strings.TrimPrefix(resp.Header.Get("etag"), "W/")
@@ -234,7 +334,9 @@ func (l *Lookup) httpRequest(logFrom *util.LogFrom) (rawBodyPtr *[]byte, err err
 			// []byte{91, 93} == []byte("[]") == empty JSON array
 			if len(rawBody) == 2 && bytes.Equal(rawBody, []byte{91, 93}) {
 				// Update the default empty list ETag
-				setEmptyListETag(newETag)
+				if l.Type == "github" {
+					setEmptyListETag(l.GetBaseURL(), newETag)
+				}
 				// Flip the fallback flag
 				l.GitHubData.SetTagFallback()
 				if l.GitHubData.TagFallback() {
@@ -259,21 +361,45 @@ func (l *Lookup) httpRequest(logFrom *util.LogFrom) (rawBodyPtr *[]byte, err err
 				}
 			}
 		}
+	} else if l.Type == "gitlab" && err == nil &&
+		resp.StatusCode == http.StatusOK &&
+		len(rawBody) == 2 && bytes.Equal(rawBody, []byte{91, 93}) {
+		// Flip the fallback flag
+		l.GitHubData.SetTagFallback()
+		if l.GitHubData.TagFallback() {
+			jLog.Verbose(fmt.Sprintf("/releases gave %v, trying /repository/tags", string(rawBody)), logFrom, true)
+			rawBodyPtr, err = l.httpRequest(logFrom)
+		}
 	}
 	return
 }
 
 // GetVersions will filter out releases from rawBody that are preReleases (if not wanted) and will sort releases if
-// semantic versioning is wanted
+// a version_scheme is wanted
 func (l *Lookup) GetVersions(
 	rawBody *[]byte,
 	logFrom *util.LogFrom,
 ) (filteredReleases []github_types.Release, err error) {
 	var releases []github_types.Release
 	body := string(*rawBody)
-	// GitHub service.
-	if l.Type == "github" {
-		releases, err = l.checkGitHubReleasesBody(rawBody, logFrom)
+	// GitHub/Gitea/GitLab/container/feed/helm/package registry service.
+	if l.usesReleases() {
+		switch l.Type {
+		case "container":
+			releases, err = l.checkContainerTagsBody(rawBody, logFrom)
+		case "crates", "go", "npm", "pypi":
+			releases, err = l.checkPackageRegistryBody(rawBody, logFrom)
+		case "helm":
+			releases, err = l.checkHelmIndexBody(rawBody, logFrom)
+		case "feed":
+			releases, err = l.checkFeedBody(rawBody, logFrom)
+		case "github":
+			releases, err = l.checkGitHubReleasesBody(rawBody, logFrom)
+		case "gitea":
+			releases, err = l.checkGiteaReleasesBody(rawBody, logFrom)
+		case "gitlab":
+			releases, err = l.checkGitLabReleasesBody(rawBody, logFrom)
+		}
 		if err != nil {
 			return
 		}
@@ -289,19 +415,95 @@ func (l *Lookup) GetVersions(
 
 		// url service
 	} else {
-		var version string
-		version, err = l.URLCommands.Run(body, logFrom)
+		var versions []string
+		versions, err = l.URLCommands.RunAll(body, l.GetAllMatches(), logFrom)
 		if err != nil {
 			//nolint:wrapcheck
 			return
 		}
-		filteredReleases = []github_types.Release{{TagName: version}}
+		filteredReleases = l.urlReleases(versions, logFrom)
+		if len(filteredReleases) == 0 {
+			err = fmt.Errorf("no releases were found matching the url_commands")
+			jLog.Warn(err, logFrom, true)
+		}
 	}
 	return
 }
 
-// GetVersion will return the latest version from rawBody matching the URLCommands and Regex requirements
-func (l *Lookup) GetVersion(rawBody *[]byte, logFrom *util.LogFrom) (version string, err error) {
+// urlReleases converts the `versions` found by the url_commands to releases.
+//
+// When there are multiple, pre-releases are dropped if they're not wanted,
+// and if a version_scheme is wanted, they're sorted newest first.
+func (l *Lookup) urlReleases(versions []string, logFrom *util.LogFrom) (releases []github_types.Release) {
+	if len(versions) == 1 {
+		return []github_types.Release{{TagName: versions[0]}}
+	}
+
+	usePreRelease := l.GetUsePreRelease()
+	versionScheme := l.Options.GetVersionScheme()
+	seen := make(map[string]bool, len(versions))
+	for _, version := range versions {
+		if seen[version] {
+			continue
+		}
+		seen[version] = true
+
+		release := github_types.Release{TagName: version}
+		// Without a version_scheme, pre-releases are still detected with semver.
+		if err := setReleaseVersion(&release, util.FirstNonDefault(versionScheme, opt.VersionSchemeSemVer), version); err != nil {
+			if versionScheme == "" {
+				releases = append(releases, release)
+				continue
+			}
+			jLog.Debug(
+				fmt.Sprintf("Skipping %q as it is not a %s version", version, versionScheme),
+				logFrom, true)
+			continue
+		}
+		// Pre-release.
+		if !usePreRelease && release.SchemeVersion.PreRelease() {
+			jLog.Debug(
+				fmt.Sprintf("Skipping %q as it is a pre-release", version),
+				logFrom, true)
+			continue
+		}
+
+		if versionScheme != "" {
+			insertionSort(release, &releases)
+		} else {
+			releases = append(releases, release)
+		}
+	}
+	return
+}
+
+// checkVersionConstraint returns an error if `version` doesn't satisfy the `constraint`.
+func (l *Lookup) checkVersionConstraint(version string, constraint *semver.Constraints, logFrom *util.LogFrom) error {
+	if constraint == nil {
+		return nil
+	}
+
+	if semVer, err := semver.NewVersion(version); err == nil {
+		// Ranges exclude pre-releases unless they include one themselves,
+		// so check wanted pre-releases against their release.
+		if semVer.Prerelease() != "" && l.GetUsePreRelease() {
+			release, _ := semVer.SetPrerelease("")
+			semVer = &release
+		}
+		if constraint.Check(semVer) {
+			return nil
+		}
+	}
+
+	err := fmt.Errorf("version %q doesn't satisfy the version_constraint %q",
+		version, l.GetVersionConstraint())
+	jLog.Verbose(err, logFrom, true)
+	return err
+}
+
+// GetVersion will return the latest version from rawBody matching the URLCommands and Regex requirements,
+// along with its release and the releases older than it (nil if staying on the LatestVersion).
+func (l *Lookup) GetVersion(rawBody *[]byte, logFrom *util.LogFrom) (version string, releases []github_types.Release, err error) {
 	var filteredReleases []github_types.Release
 	// rawBody length = 0 if GitHub ETag is unchanged
 	if len(*rawBody) != 0 {
@@ -309,17 +511,48 @@ func (l *Lookup) GetVersion(rawBody *[]byte, logFrom *util.LogFrom) (version str
 		if err != nil {
 			return
 		}
-	} else if l.Type == "github" {
+	} else if l.usesReleases() {
 		// ReCheck this ETag's filteredReleases incase filters/releases changed
 		jLog.Verbose("Using cached releases (ETag unchanged)", logFrom, true)
 		filteredReleases = l.filterGitHubReleases(logFrom)
 	}
 
-	wantSemanticVersioning := l.Options.GetSemanticVersioning()
+	var constraint *semver.Constraints
+	if constraintStr := l.GetVersionConstraint(); constraintStr != "" {
+		if constraint, err = semver.NewConstraint(constraintStr); err != nil {
+			err = fmt.Errorf("version_constraint %q is invalid: %w",
+				constraintStr, err)
+			jLog.Error(err, logFrom, true)
+			return
+		}
+	}
+
+	versionScheme := l.Options.GetVersionScheme()
+	latestVersion := l.Status.LatestVersion()
+	var (
+		release        *github_types.Release
+		pendingVersion string
+		pendingSince   time.Time
+		waitingVersion string
+		verifyFailure  error
+		failedVersion  string
+		downloader     *filter.AssetDownloader
+	)
+	if l.Require != nil && l.Require.Verify != nil {
+		downloader = l.assetDownloader()
+	}
 	for i := range filteredReleases {
-		version = filteredReleases[i].TagName
-		if wantSemanticVersioning && l.Type != "url" {
-			version = filteredReleases[i].SemanticVersion.String()
+		release = &filteredReleases[i]
+		releases = filteredReleases[i:]
+		version = l.releaseVersion(release, versionScheme)
+
+		// Version constraint
+		if err = l.checkVersionConstraint(version, constraint, logFrom); err != nil {
+			continue
+		}
+		// Ignored versions
+		if err = l.checkIgnoredVersion(version, logFrom); err != nil {
+			continue
 		}
 
 		if l.Require == nil {
@@ -334,8 +567,8 @@ func (l *Lookup) GetVersion(rawBody *[]byte, logFrom *util.LogFrom) (version str
 
 		// Content RegEx
 		var body interface{}
-		if l.Type == "github" {
-			// GitHub service
+		if l.usesReleases() {
+			// GitHub/Gitea/GitLab/container/feed/helm/package registry service
 			body = filteredReleases[i].Assets
 			// Web service
 		} else {
@@ -346,11 +579,27 @@ func (l *Lookup) GetVersion(rawBody *[]byte, logFrom *util.LogFrom) (version str
 			continue
 		}
 
+		// If the Assets haven't all been uploaded yet
+		if l.usesReleases() {
+			if err = l.Require.AssetsCheck(version, filteredReleases[i].Assets, logFrom); err != nil {
+				// Wait for the newest release to get its assets rather than skipping it.
+				if waitingVersion == "" {
+					waitingVersion = version
+				}
+				continue
+			}
+		}
+
 		// If the Command didn't return successfully
 		if err = l.Require.ExecCommand(logFrom); err != nil {
 			continue
 		}
 
+		// If the HTTP URL didn't give the expected response
+		if err = l.Require.HTTPResponseCheck(version, logFrom); err != nil {
+			continue
+		}
+
 		// If the Docker tag doesn't exist
 		if err = l.Require.DockerTagCheck(version); err != nil {
 			if strings.HasSuffix(err.Error(), "\n") {
@@ -365,8 +614,57 @@ func (l *Lookup) GetVersion(rawBody *[]byte, logFrom *util.LogFrom) (version str
 					l.Require.Docker.GetType(), l.Require.Docker.Image, l.Require.Docker.GetTag(version)),
 				logFrom, true)
 		}
+
+		// The latest version has already been found, so isn't held back or verified again.
+		if version != latestVersion {
+			// Minimum age
+			since := l.releaseSince(release, version)
+			if err = l.Require.AgeCheck(version, since, logFrom); err != nil {
+				// Hold the newest release back until it's old enough.
+				if pendingVersion == "" {
+					pendingVersion, pendingSince = version, since
+				}
+				continue
+			}
+
+			// If the asset doesn't match its checksum/signature.
+			if err = l.Require.VerifyRelease(version, filteredReleases[i].Assets, downloader, logFrom); err != nil {
+				if failedVersion == "" {
+					failedVersion, verifyFailure = version, err
+				}
+				continue
+			}
+		}
 		break
 	}
+	// Show why the newest rejected version failed verification.
+	if failedVersion != "" {
+		l.Status.SetVerifyFailure(failedVersion, verifyFailure.Error())
+	} else if err == nil {
+		l.Status.SetVerifyFailure("", "")
+	}
+	// A new version is queried again before it becomes the latest version,
+	// so keep the time that it was first seen until then.
+	keepPending := pendingVersion == "" && err == nil &&
+		version != latestVersion && version == l.Status.PendingVersion()
+	if !keepPending {
+		l.setPendingVersion(pendingVersion, pendingSince)
+	}
+	// Stay on the latest version if every release newer than it is being held back.
+	if err != nil && latestVersion != "" {
+		switch {
+		case pendingVersion != "":
+			jLog.Verbose(
+				fmt.Sprintf("Staying on %q as %q hasn't reached the min_age of %s", latestVersion, pendingVersion, l.Require.MinAge),
+				logFrom, true)
+			return latestVersion, nil, nil
+		case waitingVersion != "":
+			jLog.Verbose(
+				fmt.Sprintf("Staying on %q as %q doesn't have all of the required assets yet", latestVersion, waitingVersion),
+				logFrom, true)
+			return latestVersion, nil, nil
+		}
+	}
 	if version == "" {
 		err = fmt.Errorf("no releases were found matching the url_commands and/or require")
 		jLog.Warn(err, logFrom, true)
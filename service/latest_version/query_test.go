@@ -17,13 +17,20 @@
 package latestver
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	net_url "net/url"
 	"os"
 	"regexp"
 	"strings"
 	"testing"
 	"time"
 
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
 	"github.com/release-argus/Argus/service/latest_version/filter"
+	svcstatus "github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/test"
 	"github.com/release-argus/Argus/util"
 )
@@ -286,6 +293,257 @@ func TestLookup_Query(t *testing.T) {
 	}
 }
 
+var testVendorPage = `<html><body>
+	<a href="/dl/app-1.2.0.zip">1.2.0</a>
+	<a href="/dl/app-1.11.0-rc.1.zip">1.11.0-rc.1</a>
+	<a href="/dl/app-1.10.0.zip">1.10.0</a>
+	<a href="/dl/app-1.9.0.zip">1.9.0</a>
+	<h2>Legacy</h2>
+	<a href="/dl/app-0.20.0.zip">0.20.0</a>
+</body></html>`
+
+func TestLookup_QueryURLValues(t *testing.T) {
+	// GIVEN a url Lookup on a JSON/YAML API
+	testJSON := `{"items": [{"tag_name": "v1.2.0"}, {"tag_name": "v1.10.0"}, {"tag_name": "nightly"}, {"tag_name": "v1.9.0"}]}`
+	tests := map[string]struct {
+		body                  string
+		urlCommands           filter.URLCommandSlice
+		allMatches            bool
+		usePreRelease         bool
+		nonSemanticVersioning bool
+		requireRegexVersion   string
+		requireCommand        []string
+		versionConstraint     string
+		versionScheme         string
+		ignoreVersions        []string
+		latestVersion         string
+		wantLatestVersion     string
+		errRegex              string
+	}{
+		"json values sorted semantically": {
+			body: testJSON,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("items[*].tag_name")}},
+			wantLatestVersion: "v1.10.0",
+		},
+		"json values with commands ran on each": {
+			body: testJSON,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("items[*].tag_name")},
+				{Type: "regex", Regex: test.StringPtr(`^v([0-9.]+)$`)}},
+			wantLatestVersion: "1.10.0",
+		},
+		"json values without semantic versioning keep their order": {
+			body: testJSON,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("items[*].tag_name")}},
+			nonSemanticVersioning: true,
+			wantLatestVersion:     "v1.2.0",
+		},
+		"json values checked against require": {
+			body: testJSON,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("items[*].tag_name")}},
+			requireRegexVersion: `^v1\.[0-9]\.`,
+			wantLatestVersion:   "v1.9.0",
+		},
+		"regex takes the match at its index": {
+			body: testVendorPage,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`app-([0-9][^"]*)\.zip`)}},
+			wantLatestVersion: "1.2.0",
+		},
+		"all_matches sorts every match of the regex": {
+			body: testVendorPage,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`app-([0-9][^"]*)\.zip`)}},
+			allMatches:        true,
+			wantLatestVersion: "1.10.0",
+		},
+		"all_matches with use_prerelease": {
+			body: testVendorPage,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`app-([0-9][^"]*)\.zip`)}},
+			allMatches:        true,
+			usePreRelease:     true,
+			wantLatestVersion: "1.11.0-rc.1",
+		},
+		"all_matches falls back to the next candidate on require.regex_version": {
+			body: testVendorPage,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`app-([0-9][^"]*)\.zip`)}},
+			allMatches:          true,
+			requireRegexVersion: `^1\.[0-9]\.`,
+			wantLatestVersion:   "1.9.0",
+		},
+		"all_matches with version_constraint": {
+			body: testVendorPage,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`app-([0-9][^"]*)\.zip`)}},
+			allMatches:        true,
+			versionConstraint: "~1.9",
+			wantLatestVersion: "1.9.0",
+		},
+		"all_matches with version_constraint and use_prerelease": {
+			body: testVendorPage,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`app-([0-9][^"]*)\.zip`)}},
+			allMatches:        true,
+			usePreRelease:     true,
+			versionConstraint: "~1.11",
+			wantLatestVersion: "1.11.0-rc.1",
+		},
+		"all_matches with version_constraint on a major line": {
+			body: testVendorPage,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`app-([0-9][^"]*)\.zip`)}},
+			allMatches:        true,
+			versionConstraint: ">=0.1 <1.0",
+			wantLatestVersion: "0.20.0",
+		},
+		"version_constraint not satisfied": {
+			body: testVendorPage,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`app-([0-9][^"]*)\.zip`)}},
+			allMatches:        true,
+			versionConstraint: "^2",
+			errRegex:          `version "[^"]+" doesn't satisfy the version_constraint "\^2"`,
+		},
+		"all_matches with every candidate failing require": {
+			body: testVendorPage,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`app-([0-9][^"]*)\.zip`)}},
+			allMatches:     true,
+			requireCommand: []string{"false"},
+			errRegex:       `exit status 1`,
+		},
+		"all_matches on the final command only": {
+			body: testVendorPage,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "split", Text: test.StringPtr("<h2>Legacy</h2>"), Index: 1},
+				{Type: "regex", Regex: test.StringPtr(`app-([0-9][^"]*)\.zip`)}},
+			allMatches:        true,
+			wantLatestVersion: "0.20.0",
+		},
+		"yaml values": {
+			body: "entries:\n  app:\n    - version: 2.0.0\n    - version: 2.1.0\n",
+			urlCommands: filter.URLCommandSlice{
+				{Type: "yaml", Key: test.StringPtr("entries.app[*].version")}},
+			wantLatestVersion: "2.1.0",
+		},
+		"no semantic versions": {
+			body: `{"items": [{"tag_name": "nightly"}, {"tag_name": "latest"}]}`,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("items[*].tag_name")}},
+			errRegex: `no releases were found matching the url_commands`,
+		},
+		"key not found": {
+			body: testJSON,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("releases[*].tag_name")}},
+			errRegex: `key "releases" not found`,
+		},
+		"ignore_versions skips the newest": {
+			body: testVendorPage,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`app-([0-9][^"]*)\.zip`)}},
+			allMatches:        true,
+			ignoreVersions:    []string{"1.10.0"},
+			wantLatestVersion: "1.9.0",
+		},
+		"ignore_versions with a range": {
+			body: testVendorPage,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`app-([0-9][^"]*)\.zip`)}},
+			allMatches:        true,
+			ignoreVersions:    []string{`regex:^1\.9\.`, "semver:>=1.10.0"},
+			wantLatestVersion: "1.2.0",
+		},
+		"ignore_versions on every version": {
+			body: testVendorPage,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`app-([0-9][^"]*)\.zip`)}},
+			allMatches:     true,
+			ignoreVersions: []string{"regex:.*"},
+			errRegex:       `version "[^"]+" is ignored \(ignore_versions entry "regex:\.\*"\)`,
+		},
+		"version_scheme sorts the values": {
+			body: `{"items": [{"tag_name": "1.0rc1"}, {"tag_name": "1.0.post1"}, {"tag_name": "1.0"}]}`,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("items[*].tag_name")}},
+			versionScheme:     "pep440",
+			wantLatestVersion: "1.0.post1",
+		},
+		"version_scheme drops its pre-releases": {
+			body: `{"items": [{"tag_name": "2.0~rc1-1"}, {"tag_name": "1.9-2"}]}`,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("items[*].tag_name")}},
+			versionScheme:     "debian",
+			wantLatestVersion: "1.9-2",
+		},
+		"version_scheme rejects a version older than the deployed version": {
+			body: `{"items": [{"tag_name": "2.0-1"}]}`,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("items[*].tag_name")}},
+			versionScheme:     "debian",
+			latestVersion:     "1:1.0-1",
+			wantLatestVersion: "1:1.0-1",
+			errRegex:          `queried version "2.0-1" is less than the deployed version "1:1.0-1"`,
+		},
+		"version_scheme rejects a version not in the scheme": {
+			body: `{"items": [{"tag_name": "1.2.3"}]}`,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "json", Key: test.StringPtr("items[*].tag_name")}},
+			versionScheme: "calver",
+			errRegex:      `"1" is not a year .* or changing the version_scheme`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+			lookup := testLookup(true, false)
+			lookup.URL = server.URL
+			lookup.URLCommands = tc.urlCommands
+			*lookup.Options.SemanticVersioning = !tc.nonSemanticVersioning
+			lookup.Require.RegexVersion = tc.requireRegexVersion
+			lookup.Require.Command = tc.requireCommand
+			lookup.AllMatches = &tc.allMatches
+			lookup.VersionConstraint = tc.versionConstraint
+			lookup.Options.VersionScheme = tc.versionScheme
+			lookup.IgnoreVersions = tc.ignoreVersions
+			lookup.UsePreRelease = &tc.usePreRelease
+			lookup.Status.SetLatestVersion(tc.latestVersion, false)
+			lookup.Status.SetDeployedVersion(tc.latestVersion, false)
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the latest version is found
+			if got := lookup.Status.LatestVersion(); got != tc.wantLatestVersion {
+				t.Errorf("want LatestVersion %q, not %q",
+					tc.wantLatestVersion, got)
+			}
+		})
+	}
+}
+
 func TestLookup_Query__EmptyListETagChanged(t *testing.T) {
 	// t.Parallel() - Cannot run in parallel since we're using stdout
 
@@ -300,7 +558,7 @@ func TestLookup_Query__EmptyListETagChanged(t *testing.T) {
 	for temporaryFailureInNameResolution != false {
 		releaseStdout := test.CaptureStdout()
 		try++
-		setEmptyListETag(invalidETag)
+		setEmptyListETag(githubBaseURL, invalidETag)
 		temporaryFailureInNameResolution = false
 		lookup := testLookup(false, false)
 		lookup.URL = "go-vikunja/api"
@@ -419,3 +677,471 @@ no releases were found matching the url_commands and/or require`},
 		})
 	}
 }
+
+func TestLookup_QueryVerify(t *testing.T) {
+	// GIVEN a Lookup with a require.verify and a release with a checksums file
+	asset := []byte("myapp 1.2.0")
+	sum := sha256.Sum256(asset)
+	tests := map[string]struct {
+		checksums         string
+		latestVersion     string
+		wantLatestVersion string
+		wantVerifyFailure string
+		errRegex          string
+	}{
+		"checksum matches": {
+			checksums:         hex.EncodeToString(sum[:]) + "  myapp_1.2.0_linux_amd64.tar.gz\n",
+			latestVersion:     "1.1.0",
+			wantLatestVersion: "1.2.0",
+		},
+		"checksum mismatch stays on the latest version": {
+			checksums:         strings.Repeat("0", 64) + "  myapp_1.2.0_linux_amd64.tar.gz\n",
+			latestVersion:     "1.1.0",
+			wantLatestVersion: "1.1.0",
+			wantVerifyFailure: `^verification of version "1.2.0" failed: checksum mismatch for "myapp_1.2.0_linux_amd64.tar.gz"`,
+		},
+		"no release verifies": {
+			checksums:         hex.EncodeToString(sum[:]) + "  other.tar.gz\n",
+			wantVerifyFailure: `no checksum for "myapp_1.2.0_linux_amd64.tar.gz" in the checksums file$`,
+			errRegex:          `^verification of version "1.1.0" failed: no asset matching`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/download/myapp_1.2.0_linux_amd64.tar.gz":
+					w.Write(asset)
+				case "/download/SHA256SUMS":
+					w.Write([]byte(tc.checksums))
+				default:
+					w.Write([]byte(`[
+						{"tag_name":"v1.2.0","assets":{"links":[
+							{"id":1,"name":"myapp_1.2.0_linux_amd64.tar.gz","url":"` + server.URL + `/download/myapp_1.2.0_linux_amd64.tar.gz"},
+							{"id":2,"name":"SHA256SUMS","url":"` + server.URL + `/download/SHA256SUMS"}]}},
+						{"tag_name":"v1.1.0"}]`))
+				}
+			}))
+			defer server.Close()
+			lookup := testLookupGitLab(server.URL)
+			lookup.UsePreRelease = test.BoolPtr(false)
+			lookup.URLCommands = filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`v?([0-9.]+.*)`)}}
+			lookup.Require = &filter.Require{
+				Status: lookup.Status,
+				Verify: &filter.VerifyCheck{
+					Asset:     `myapp_{{ version }}_linux_amd64\.tar\.gz`,
+					Checksums: `^SHA256SUMS$`}}
+			lookup.Status.SetLatestVersion(tc.latestVersion, false)
+			lookup.Status.SetDeployedVersion(tc.latestVersion, false)
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the latest version is only a verified one
+			if got := lookup.Status.LatestVersion(); got != tc.wantLatestVersion {
+				t.Errorf("want LatestVersion %q, not %q",
+					tc.wantLatestVersion, got)
+			}
+			// AND the reason the newest version failed verification is shown
+			verifyFailure := util.DefaultIfNil(lookup.Status.VerifyFailure())
+			if tc.wantVerifyFailure == "" {
+				if verifyFailure.Version != "" {
+					t.Errorf("want no VerifyFailure, not %+v",
+						verifyFailure)
+				}
+				return
+			}
+			if verifyFailure.Version != "1.2.0" ||
+				!regexp.MustCompile(tc.wantVerifyFailure).MatchString(verifyFailure.Reason) {
+				t.Errorf("want VerifyFailure of 1.2.0 matching %q, not %+v",
+					tc.wantVerifyFailure, verifyFailure)
+			}
+		})
+	}
+}
+
+func TestLookup_assetDownloader(t *testing.T) {
+	// GIVEN a Lookup and an asset of one of its releases
+	tests := map[string]struct {
+		lookupType  string
+		baseURL     string
+		url         string
+		accessToken *string
+		username    string
+		otherHost   bool
+		wantURL     string
+		wantHeaders map[string]string
+	}{
+		"github - no access_token uses the browser_download_url": {
+			lookupType: "github",
+			url:        "release-argus/Argus",
+			wantURL:    "/download/myapp.tar.gz",
+			wantHeaders: map[string]string{
+				"Accept":        "application/octet-stream",
+				"Authorization": ""}},
+		"github - access_token uses the API url": {
+			lookupType:  "github",
+			url:         "release-argus/Argus",
+			accessToken: test.StringPtr("secret"),
+			wantURL:     "/api/assets/1",
+			wantHeaders: map[string]string{
+				"Accept":        "application/octet-stream",
+				"Authorization": "token secret"}},
+		"gitea - access_token": {
+			lookupType:  "gitea",
+			baseURL:     "https://gitea.example.com",
+			url:         "owner/repo",
+			accessToken: test.StringPtr("secret"),
+			wantURL:     "/download/myapp.tar.gz",
+			wantHeaders: map[string]string{
+				"Authorization": "token secret"}},
+		"gitlab - access_token": {
+			lookupType:  "gitlab",
+			url:         "group/project",
+			accessToken: test.StringPtr("secret"),
+			wantURL:     "/download/myapp.tar.gz",
+			wantHeaders: map[string]string{
+				"PRIVATE-TOKEN": "secret"}},
+		"gitlab - access_token isn't sent to other hosts": {
+			lookupType:  "gitlab",
+			url:         "group/project",
+			accessToken: test.StringPtr("secret"),
+			otherHost:   true,
+			wantURL:     "/download/myapp.tar.gz",
+			wantHeaders: map[string]string{
+				"PRIVATE-TOKEN": ""}},
+		"helm - basic auth": {
+			lookupType:  "helm",
+			url:         "https://charts.example.com/index.yaml#chart",
+			accessToken: test.StringPtr("secret"),
+			username:    "user",
+			wantURL:     "/download/myapp.tar.gz",
+			wantHeaders: map[string]string{
+				"Authorization": "Basic dXNlcjpzZWNyZXQ="}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookup(false, false)
+			lookup.Type = tc.lookupType
+			lookup.BaseURL = tc.baseURL
+			lookup.URL = tc.url
+			lookup.AccessToken = tc.accessToken
+			lookup.Username = tc.username
+			sourceURL, _ := net_url.Parse(lookup.GetURL())
+			host := sourceURL.Scheme + "://" + sourceURL.Host
+			if tc.otherHost {
+				host = "https://downloads.example.com"
+			}
+			asset := &github_types.Asset{
+				Name:               "myapp.tar.gz",
+				URL:                host + "/api/assets/1",
+				BrowserDownloadURL: host + "/download/myapp.tar.gz"}
+
+			// WHEN assetDownloader is called on it
+			downloader := lookup.assetDownloader()
+
+			// THEN the client has the download timeout
+			if downloader.Client.Timeout != assetDownloadTimeout {
+				t.Errorf("want Timeout %s, not %s",
+					assetDownloadTimeout, downloader.Client.Timeout)
+			}
+			// AND the request for the asset is what we expect
+			req, err := downloader.NewRequest(asset)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if got := req.URL.Path; got != tc.wantURL {
+				t.Errorf("want request for %q, not %q",
+					tc.wantURL, got)
+			}
+			for header, want := range tc.wantHeaders {
+				if got := req.Header.Get(header); got != want {
+					t.Errorf("want %s header %q, not %q",
+						header, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLookup_QueryDockerDigest(t *testing.T) {
+	// GIVEN a Lookup with a require.docker on a registry where only 1.1.0 has linux/arm64
+	tests := map[string]struct {
+		platforms         []string
+		latestVersion     string
+		wantLatestVersion string
+		wantDockerDigest  string
+		errRegex          string
+	}{
+		"newest tag is used": {
+			wantLatestVersion: "1.2.0",
+			wantDockerDigest:  "sha256:index-1.2.0",
+		},
+		"newest tag without the platform is skipped": {
+			platforms:         []string{"linux/arm64"},
+			wantLatestVersion: "1.1.0",
+			wantDockerDigest:  "sha256:index-1.1.0",
+		},
+		"older version keeps the digest of the latest version": {
+			latestVersion:     "1.3.0",
+			wantLatestVersion: "1.3.0",
+			wantDockerDigest:  "sha256:index-1.3.0",
+			errRegex:          `queried version "1.2.0" is less than the deployed version "1.3.0"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v2/team/app/manifests/1.2.0":
+					w.Header().Set("Docker-Content-Digest", "sha256:index-1.2.0")
+					w.Write([]byte(`{"manifests":[
+						{"platform":{"architecture":"amd64","os":"linux"}}]}`))
+				case "/v2/team/app/manifests/1.1.0":
+					w.Header().Set("Docker-Content-Digest", "sha256:index-1.1.0")
+					w.Write([]byte(`{"manifests":[
+						{"platform":{"architecture":"amd64","os":"linux"}},
+						{"platform":{"architecture":"arm64","os":"linux"}}]}`))
+				default:
+					w.Write([]byte(`[
+						{"tag_name":"v1.2.0"},
+						{"tag_name":"v1.1.0"}]`))
+				}
+			}))
+			defer server.Close()
+			lookup := testLookupGitLab(server.URL)
+			lookup.UsePreRelease = test.BoolPtr(false)
+			lookup.URLCommands = filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`v?([0-9.]+.*)`)}}
+			lookup.Require = &filter.Require{
+				Status: lookup.Status,
+				Docker: &filter.DockerCheck{
+					Type:      "registry",
+					Registry:  server.URL,
+					Image:     "team/app",
+					Tag:       "{{ version }}",
+					Platforms: tc.platforms}}
+			if tc.latestVersion != "" {
+				lookup.Status.SetLatestVersion(tc.latestVersion, false)
+				lookup.Status.SetDeployedVersion(tc.latestVersion, false)
+				lookup.Status.SetDockerDigest(tc.latestVersion, "sha256:index-"+tc.latestVersion)
+			}
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the latest version has the platforms
+			if got := lookup.Status.LatestVersion(); got != tc.wantLatestVersion {
+				t.Errorf("want LatestVersion %q, not %q",
+					tc.wantLatestVersion, got)
+			}
+			// AND the digest of its image is recorded
+			if got := lookup.Status.DockerDigest(); got != tc.wantDockerDigest {
+				t.Errorf("want DockerDigest %q, not %q",
+					tc.wantDockerDigest, got)
+			}
+		})
+	}
+}
+
+func TestLookup_QueryReleaseNotes(t *testing.T) {
+	// GIVEN a Lookup on a GitLab project with release descriptions
+	tests := map[string]struct {
+		usePreRelease     bool
+		latestVersion     string
+		wantLatestVersion string
+		wantReleaseNotes  string
+		errRegex          string
+	}{
+		"notes of the newest release": {
+			usePreRelease:     true,
+			wantLatestVersion: "1.2.0-beta",
+			wantReleaseNotes:  "## Beta\n- Try the new thing",
+		},
+		"notes of the release used, not the newest": {
+			usePreRelease:     false,
+			wantLatestVersion: "1.1.0",
+			wantReleaseNotes:  "## Fixes\n- Fixed the old thing",
+		},
+		"older version keeps the notes of the latest version": {
+			usePreRelease:     false,
+			latestVersion:     "1.3.0",
+			wantLatestVersion: "1.3.0",
+			wantReleaseNotes:  "## 1.3.0",
+			errRegex:          `queried version "1.1.0" is less than the deployed version "1.3.0"`,
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"tag_name":"v1.2.0-beta","description":"## Beta\n- Try the new thing"},
+			{"tag_name":"v1.1.0","description":"## Fixes\n- Fixed the old thing"}]`))
+	}))
+	t.Cleanup(server.Close)
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookupGitLab(server.URL)
+			lookup.UsePreRelease = &tc.usePreRelease
+			lookup.URLCommands = filter.URLCommandSlice{
+				{Type: "regex", Regex: test.StringPtr(`v?([0-9.]+.*)`)}}
+			if tc.latestVersion != "" {
+				lookup.Status.SetLatestVersion(tc.latestVersion, false)
+				lookup.Status.SetDeployedVersion(tc.latestVersion, false)
+				lookup.Status.SetReleaseNotes(tc.latestVersion, "## "+tc.latestVersion, false)
+			}
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the latest version is as expected
+			if got := lookup.Status.LatestVersion(); got != tc.wantLatestVersion {
+				t.Errorf("want LatestVersion %q, not %q",
+					tc.wantLatestVersion, got)
+			}
+			// AND the release notes of that version are recorded
+			if got := lookup.Status.ReleaseNotes(); got != tc.wantReleaseNotes {
+				t.Errorf("want ReleaseNotes %q, not %q",
+					tc.wantReleaseNotes, got)
+			}
+		})
+	}
+}
+
+func TestLookup_QueryChangelog(t *testing.T) {
+	// GIVEN a Lookup on a GitLab project with release descriptions
+	tests := map[string]struct {
+		lookupType      string
+		latestVersion   string
+		deployedVersion string
+		wantVersions    []string
+		wantChangelog   string
+		errRegex        string
+	}{
+		"releases after the deployed version": {
+			lookupType:      "gitlab",
+			deployedVersion: "1.0.0",
+			wantVersions:    []string{"1.2.0", "1.1.0"},
+			wantChangelog:   "## 1.2.0\n- Added the new thing\n\n## 1.1.0\n- Fixed the old thing",
+		},
+		"latest version deployed": {
+			lookupType:      "gitlab",
+			deployedVersion: "1.2.0",
+		},
+		"type without release notes": {
+			lookupType:      "url",
+			deployedVersion: "1.0.0",
+		},
+		"older version keeps the changelog of the latest version": {
+			lookupType:      "gitlab",
+			latestVersion:   "1.3.0",
+			deployedVersion: "1.2.5",
+			wantVersions:    []string{"1.3.0"},
+			wantChangelog:   "## 1.3.0\n- Added the newer thing",
+			errRegex:        `queried version "1.2.0" is less than the deployed version "1.3.0"`,
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"tag_name":"v1.2.0","description":"- Added the new thing"},
+			{"tag_name":"v1.1.0","description":"- Fixed the old thing"},
+			{"tag_name":"v1.0.0","description":"- First release"}]`))
+	}))
+	t.Cleanup(server.Close)
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookupGitLab(server.URL)
+			lookup.UsePreRelease = test.BoolPtr(false)
+			if tc.lookupType == "url" {
+				lookup = testLookup(true, false)
+				lookup.URL = server.URL
+				lookup.URLCommands = filter.URLCommandSlice{
+					{Type: "regex", Regex: test.StringPtr(`v([0-9.]+)`)}}
+			} else {
+				lookup.URLCommands = filter.URLCommandSlice{
+					{Type: "regex", Regex: test.StringPtr(`v?([0-9.]+)`)}}
+			}
+			lookup.Status.SetDeployedVersion(tc.deployedVersion, false)
+			if tc.latestVersion != "" {
+				lookup.Status.SetLatestVersion(tc.latestVersion, false)
+				lookup.Status.SetChangelog(tc.latestVersion, []svcstatus.ChangelogEntry{
+					{Version: tc.latestVersion, Notes: "- Added the newer thing"}})
+			}
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the changelog has the versions after the deployed version
+			entries := lookup.Status.ChangelogEntries()
+			gotVersions := make([]string, len(entries))
+			for i, entry := range entries {
+				gotVersions[i] = entry.Version
+			}
+			if strings.Join(gotVersions, ",") != strings.Join(tc.wantVersions, ",") {
+				t.Errorf("want changelog versions %v, not %v",
+					tc.wantVersions, gotVersions)
+			}
+			if got := lookup.Status.Changelog(); got != tc.wantChangelog {
+				t.Errorf("want Changelog %q, not %q",
+					tc.wantChangelog, got)
+			}
+		})
+	}
+}
@@ -27,12 +27,14 @@ import (
 func (l *Lookup) applyOverrides(
 	accessToken *string,
 	allowInvalidCerts *string,
+	baseURL *string,
 	require *string,
 	semanticVersioning *string,
 	typeStr *string,
 	url *string,
 	urlCommands *string,
 	usePreRelease *string,
+	versionConstraint *string,
 	serviceID *string,
 	logFrom *util.LogFrom,
 ) (*Lookup, error) {
@@ -44,6 +46,8 @@ func (l *Lookup) applyOverrides(
 	if allowInvalidCerts != nil {
 		useAllowInvalidCerts = util.StringToBoolPtr(*allowInvalidCerts)
 	}
+	// base_url
+	useBaseURL := util.PtrValueOrValue(baseURL, l.BaseURL)
 	// require
 	useRequire, errRequire := filter.RequireFromStr(
 		require,
@@ -80,6 +84,9 @@ func (l *Lookup) applyOverrides(
 		useUsePreRelease = util.StringToBoolPtr(*usePreRelease)
 	}
 
+	// version_constraint
+	useVersionConstraint := util.PtrValueOrValue(versionConstraint, l.VersionConstraint)
+
 	// Create a new lookup with the overrides.
 	lookup := New(
 		useAccessToken,
@@ -96,28 +103,39 @@ func (l *Lookup) applyOverrides(
 		useUsePreRelease,
 		l.Defaults,
 		l.HardDefaults)
+	lookup.BaseURL = useBaseURL
+	lookup.Username = l.Username
+	lookup.Chart = l.Chart
+	lookup.AppVersion = l.AppVersion
+	lookup.AllMatches = l.AllMatches
+	lookup.VersionConstraint = useVersionConstraint
+	lookup.IgnoreVersions = l.GetIgnoreVersions()
 	lookup.Status = &svcstatus.Status{
 		ServiceID: serviceID}
 	lookup.Options.Defaults = l.Options.Defaults
 	lookup.Options.HardDefaults = l.Options.HardDefaults
+	lookup.Options.VersionScheme = l.Options.VersionScheme
 	lookup.Status.Init(
 		0, 0, 0,
 		serviceID,
 		nil)
 	lookup.Status.SetLatestVersion(l.Status.LatestVersion(), false)
+	lookup.Status.SetPendingVersion(
+		l.Status.PendingVersion(), l.Status.PendingVersionTimestamp(), l.Status.PendingVersionUntil(),
+		false)
 
-	if lookup.Type == "github" {
+	if lookup.usesReleases() {
 		// Use the current ETag/releases
 		// (if ETag is the same, won't count towards API limit)
-		if l.Type == "github" && l.GitHubData != nil {
+		if l.Type == lookup.Type && l.GitHubData != nil {
 			releases := l.GitHubData.Releases()
-			lookup.GitHubData = NewGitHubData(
+			lookup.GitHubData = lookup.newGitHubData(
 				l.GitHubData.ETag(),
 				&releases)
 
-			// Type changed to github (or new service)
+			// Type changed to github/gitea/gitlab (or new service)
 		} else {
-			lookup.GitHubData = NewGitHubData("", nil)
+			lookup.GitHubData = lookup.newGitHubData("", nil)
 		}
 	}
 
@@ -139,12 +157,14 @@ func (l *Lookup) applyOverrides(
 func (l *Lookup) Refresh(
 	accessToken *string,
 	allowInvalidCerts *string,
+	baseURL *string,
 	require *string,
 	semanticVersioning *string,
 	typeStr *string,
 	url *string,
 	urlCommands *string,
 	usePreRelease *string,
+	versionConstraint *string,
 ) (version string, announceUpdate bool, err error) {
 	serviceID := *l.Status.ServiceID
 	logFrom := &util.LogFrom{Primary: "latest_version/refresh", Secondary: serviceID}
@@ -153,12 +173,14 @@ func (l *Lookup) Refresh(
 	lookup, err = l.applyOverrides(
 		accessToken,
 		allowInvalidCerts,
+		baseURL,
 		require,
 		semanticVersioning,
 		typeStr,
 		url,
 		urlCommands,
 		usePreRelease,
+		versionConstraint,
 		&serviceID,
 		logFrom)
 	if err != nil {
@@ -173,11 +195,13 @@ func (l *Lookup) Refresh(
 	}
 
 	// Whether overrides were provided or not, we can update the status if not.
-	overrides := require != nil ||
+	overrides := baseURL != nil ||
+		require != nil ||
 		l.Options.GetSemanticVersioning() != lookup.Options.GetSemanticVersioning() ||
 		url != nil ||
 		urlCommands != nil ||
-		usePreRelease != nil
+		usePreRelease != nil ||
+		versionConstraint != nil
 
 	// Query the lookup.
 	_, err = lookup.Query(!overrides, logFrom)
@@ -196,9 +220,10 @@ func (l *Lookup) Refresh(
 //
 // Returns whether a new version was found and should be announced.
 func (l *Lookup) updateFromRefresh(newLookup *Lookup, changingOverrides bool) (announceUpdate bool) {
-	// Querying the same GitHub repo and the ETag has changed
-	if l.Type == "github" && newLookup.Type == "github" &&
+	// Querying the same GitHub/Gitea repo and the ETag has changed
+	if l.usesReleases() && l.Type == newLookup.Type &&
 		l.URL == newLookup.URL &&
+		l.BaseURL == newLookup.BaseURL &&
 		l.GitHubData != nil &&
 		l.GitHubData.ETag() != newLookup.GitHubData.ETag() {
 		// Update the ETag and releases
@@ -209,6 +234,7 @@ func (l *Lookup) updateFromRefresh(newLookup *Lookup, changingOverrides bool) (a
 	if l.Require != nil && l.Require.Docker != nil &&
 		newLookup.Require != nil && newLookup.Require.Docker != nil &&
 		l.Require.Docker.Type == newLookup.Require.Docker.Type &&
+		l.Require.Docker.Registry == newLookup.Require.Docker.Registry &&
 		l.Require.Docker.Token == newLookup.Require.Docker.Token &&
 		l.Require.Docker.Username == newLookup.Require.Docker.Username &&
 		l.Require.Docker.Image == newLookup.Require.Docker.Image {
@@ -231,5 +257,15 @@ func (l *Lookup) updateFromRefresh(newLookup *Lookup, changingOverrides bool) (a
 		announceUpdate = true
 		l.Status.SetLatestVersion(newLatestVersion, true)
 	}
+	// Update the version being held back for the min_age.
+	l.Status.SetPendingVersion(
+		newLookup.Status.PendingVersion(), newLookup.Status.PendingVersionTimestamp(), newLookup.Status.PendingVersionUntil(),
+		true)
+	// Update why the newest version failed the require.verify.
+	if verifyFailure := newLookup.Status.VerifyFailure(); verifyFailure != nil {
+		l.Status.SetVerifyFailure(verifyFailure.Version, verifyFailure.Reason)
+	} else {
+		l.Status.SetVerifyFailure("", "")
+	}
 	return
 }
@@ -17,6 +17,8 @@
 package latestver
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
 	"testing"
@@ -37,12 +39,14 @@ func TestLookup_ApplyOverrides(t *testing.T) {
 	tests := map[string]struct {
 		accessToken         *string
 		allowInvalidCerts   *string
+		baseURL             *string
 		require             *string
 		semanticVersioning  *string
 		typeStr             *string
 		url                 *string
 		urlCommands         *string
 		usePreRelease       *string
+		versionConstraint   *string
 		previous            *Lookup
 		gitHubData          *GitHubData
 		carryOverGitHubData bool
@@ -222,6 +226,56 @@ func TestLookup_ApplyOverrides(t *testing.T) {
 				URL:        "release-argus/Argus",
 				GitHubData: NewGitHubData("", nil)},
 		},
+		"type gitea with base_url": {
+			baseURL: test.StringPtr("https://gitea.example.com"),
+			typeStr: test.StringPtr("gitea"),
+			url:     test.StringPtr("owner/repo"),
+			previous: &Lookup{
+				Options: &opt.Options{},
+				Status: &svcstatus.Status{
+					ServiceID: test.StringPtr("test")}},
+			want: &Lookup{
+				Type:       "gitea",
+				URL:        "owner/repo",
+				BaseURL:    "https://gitea.example.com",
+				GitHubData: &GitHubData{}},
+		},
+		"type gitea without base_url": {
+			typeStr: test.StringPtr("gitea"),
+			url:     test.StringPtr("owner/repo"),
+			previous: &Lookup{
+				Options: &opt.Options{},
+				Status: &svcstatus.Status{
+					ServiceID: test.StringPtr("test")}},
+			errRegex: "base_url: <required>",
+		},
+		"type gitlab with base_url": {
+			baseURL: test.StringPtr("https://gitlab.example.com"),
+			typeStr: test.StringPtr("gitlab"),
+			url:     test.StringPtr("group/project"),
+			previous: &Lookup{
+				Options: &opt.Options{},
+				Status: &svcstatus.Status{
+					ServiceID: test.StringPtr("test")}},
+			want: &Lookup{
+				Type:       "gitlab",
+				URL:        "group/project",
+				BaseURL:    "https://gitlab.example.com",
+				GitHubData: &GitHubData{}},
+		},
+		"type gitlab with full url": {
+			typeStr: test.StringPtr("gitlab"),
+			url:     test.StringPtr("https://gitlab.example.com/group/sub/project/-/releases"),
+			previous: &Lookup{
+				Options: &opt.Options{},
+				Status: &svcstatus.Status{
+					ServiceID: test.StringPtr("test")}},
+			want: &Lookup{
+				Type:       "gitlab",
+				URL:        "group/sub/project",
+				BaseURL:    "https://gitlab.example.com",
+				GitHubData: &GitHubData{}},
+		},
 		"type github carries over Releases and ETag": {
 			url: test.StringPtr("release-argus/other"),
 			previous: New(
@@ -291,6 +345,21 @@ func TestLookup_ApplyOverrides(t *testing.T) {
 				&LookupDefaults{},
 				&LookupDefaults{}),
 		},
+		"version constraint": {
+			versionConstraint: test.StringPtr("~1.2"),
+			previous:          testLookup(true, true),
+			want: func() *Lookup {
+				lookup := testLookup(true, true)
+				lookup.VersionConstraint = "~1.2"
+				return lookup
+			}(),
+		},
+		"override with invalid version constraint": {
+			versionConstraint: test.StringPtr("~1.2 <"),
+			previous:          testLookup(true, true),
+			want:              nil,
+			errRegex:          `version_constraint: "~1.2 <" <invalid>`,
+		},
 		"override with invalid (empty) url": {
 			url:      test.StringPtr(""),
 			previous: testLookup(true, true),
@@ -313,12 +382,14 @@ func TestLookup_ApplyOverrides(t *testing.T) {
 			got, err := tc.previous.applyOverrides(
 				tc.accessToken,
 				tc.allowInvalidCerts,
+				tc.baseURL,
 				tc.require,
 				tc.semanticVersioning,
 				tc.typeStr,
 				tc.url,
 				tc.urlCommands,
 				tc.usePreRelease,
+				tc.versionConstraint,
 				&name,
 				&util.LogFrom{Primary: name})
 
@@ -364,12 +435,14 @@ func TestLookup_Refresh(t *testing.T) {
 	tests := map[string]struct {
 		accessToken        *string
 		allowInvalidCerts  *string
+		baseURL            *string
 		require            *string
 		semanticVersioning *string
 		typeStr            *string
 		url                *string
 		urlCommands        *string
 		usePreRelease      *string
+		versionConstraint  *string
 		latestVersion      string
 		previous           *Lookup
 		errRegex           string
@@ -456,12 +529,14 @@ func TestLookup_Refresh(t *testing.T) {
 			got, gotAnnounce, err := tc.previous.Refresh(
 				tc.accessToken,
 				tc.allowInvalidCerts,
+				tc.baseURL,
 				tc.require,
 				tc.semanticVersioning,
 				tc.typeStr,
 				tc.url,
 				tc.urlCommands,
-				tc.usePreRelease)
+				tc.usePreRelease,
+				tc.versionConstraint)
 
 			// THEN we get an error if expected
 			if tc.errRegex != "" || err != nil {
@@ -489,7 +564,7 @@ func TestLookup_Refresh(t *testing.T) {
 			// AND the timestamp only changes if the version changed
 			if previousStatus.LatestVersionTimestamp() != "" {
 				// If the possible query-changing overrides are nil
-				if tc.require == nil && tc.semanticVersioning == nil && tc.url == nil && tc.urlCommands == nil {
+				if tc.require == nil && tc.semanticVersioning == nil && tc.url == nil && tc.urlCommands == nil && tc.versionConstraint == nil {
 					// The timestamp should change only if the version changed
 					if previousStatus.LatestVersion() != tc.previous.Status.LatestVersion() &&
 						previousStatus.LatestVersionTimestamp() == tc.previous.Status.LatestVersionTimestamp() {
@@ -513,6 +588,106 @@ func TestLookup_Refresh(t *testing.T) {
 	}
 }
 
+func TestLookup_RefreshHTMLURLCommands(t *testing.T) {
+	// GIVEN a url Lookup on a download page
+	page := `<html><body><ul id="downloads">
+		<li><a class="download" href="/dl/app-1.2.0.tar.gz">App 1.2.0</a></li>
+		<li><a class="download" href="/dl/app-1.10.0.tar.gz">App 1.10.0</a></li>
+		<li><a class="download" href="/dl/app-1.9.0.tar.gz">App 1.9.0</a></li>
+	</ul></body></html>`
+	tests := map[string]struct {
+		urlCommands       string
+		versionConstraint *string
+		want              string
+		errRegex          string
+	}{
+		"css selector on the text": {
+			urlCommands: `[
+				{"type": "html", "selector": "a.download"},
+				{"type": "regex", "regex": "App ([0-9.]+)"}]`,
+			want: "1.10.0",
+		},
+		"css selector on an attribute, with index": {
+			urlCommands: `[
+				{"type": "html", "selector": "#downloads a", "attribute": "href", "index": -1},
+				{"type": "regex", "regex": "app-([0-9.]+)\\.tar"}]`,
+			want: "1.9.0",
+		},
+		"xpath": {
+			urlCommands: `[
+				{"type": "html", "xpath": "//a[@class='download']/@href"},
+				{"type": "regex", "regex": "app-([0-9.]+)\\.tar"}]`,
+			want: "1.10.0",
+		},
+		"css selector with version_constraint": {
+			urlCommands: `[
+				{"type": "html", "selector": "a.download"},
+				{"type": "regex", "regex": "App ([0-9.]+)"}]`,
+			versionConstraint: test.StringPtr("<1.10"),
+			want:              "1.9.0",
+		},
+		"invalid selector": {
+			urlCommands: `[
+				{"type": "html", "selector": "a["}]`,
+			errRegex: `selector: "a\[" <invalid>`,
+		},
+		"no matches": {
+			urlCommands: `[
+				{"type": "html", "selector": "a.release"}]`,
+			errRegex: `html selector "a.release" didn't return any matches`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte(page))
+			}))
+			defer server.Close()
+			lookup := testLookup(true, false)
+			lookup.URL = server.URL
+			lookup.UsePreRelease = test.BoolPtr(false)
+			lookup.Status.Init(
+				0, 0, 0,
+				&name,
+				nil)
+
+			// WHEN Refresh is called with these url_commands
+			got, _, err := lookup.Refresh(
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				&tc.urlCommands,
+				nil,
+				tc.versionConstraint)
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the expected version is found
+			if got != tc.want {
+				t.Errorf("expected version %q, not %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
 func TestLookup_updateFromRefresh(t *testing.T) {
 	// GIVEN a Lookup and a refreshed version of that Lookup
 	tests := map[string]struct {
@@ -24,21 +24,28 @@ import (
 	"github.com/release-argus/Argus/util"
 )
 
+// githubBaseURL is the default base URL of the GitHub API.
+const githubBaseURL = "https://api.github.com"
+
 var (
 	jLog               *util.JLog
+	supportedTypes     = []string{"container", "crates", "feed", "github", "gitea", "gitlab", "go", "helm", "npm", "pypi", "url"}
+	assetTypes         = []string{"gitea", "github", "gitlab", "helm"} // Types whose releases have assets
 	emptyListETagMutex sync.RWMutex
-	emptyListETag      = `"d1507206fce72fdb4c3c5bc3f7ac5886c75cf86ab707cf43d5a7530516bc9cee"`
+	emptyListETags     = map[string]string{
+		githubBaseURL: `"d1507206fce72fdb4c3c5bc3f7ac5886c75cf86ab707cf43d5a7530516bc9cee"`}
 )
 
-// get_empty_list_etag returns the ETag for an empty list query on the GitHub API.
-func getEmptyListETag() string {
+// getEmptyListETag returns the ETag for an empty list query on the GitHub API at `baseURL`.
+// (empty if not known for this host)
+func getEmptyListETag(baseURL string) string {
 	emptyListETagMutex.RLock()
 	defer emptyListETagMutex.RUnlock()
 
-	return emptyListETag
+	return emptyListETags[baseURL]
 }
 
-// FindEmptyListETag finds the ETag for an empty list query on the GitHub API.
+// FindEmptyListETag finds the ETag for an empty list query on the public GitHub API.
 func FindEmptyListETag(accessToken string) {
 	githubData := NewGitHubData("", nil)
 
@@ -57,15 +64,15 @@ func FindEmptyListETag(accessToken string) {
 	//nolint:errcheck
 	lookup.httpRequest(&util.LogFrom{Primary: "FindEmptyListETag"})
 
-	setEmptyListETag(lookup.GitHubData.ETag())
+	setEmptyListETag(githubBaseURL, lookup.GitHubData.ETag())
 }
 
-// setEmptyListETag sets the ETag for an empty list query on the GitHub API.
-func setEmptyListETag(etag string) {
+// setEmptyListETag sets the ETag for an empty list query on the GitHub API at `baseURL`.
+func setEmptyListETag(baseURL string, etag string) {
 	emptyListETagMutex.Lock()
 	defer emptyListETagMutex.Unlock()
 
-	emptyListETag = etag
+	emptyListETags[baseURL] = etag
 }
 
 // LookupBase is the base struct for a Lookup.
@@ -73,11 +80,13 @@ type LookupBase struct {
 	AccessToken       *string `yaml:"access_token,omitempty" json:"access_token,omitempty"`               // GitHub access token to use
 	AllowInvalidCerts *bool   `yaml:"allow_invalid_certs,omitempty" json:"allow_invalid_certs,omitempty"` // default - false = Disallows invalid HTTPS certificates
 	UsePreRelease     *bool   `yaml:"use_prerelease,omitempty" json:"use_prerelease,omitempty"`           // Whether the prerelease tag should be used
+	VersionConstraint string  `yaml:"version_constraint,omitempty" json:"version_constraint,omitempty"`   // Semantic version range the version must satisfy, e.g. "~1.18" or ">=2.0 <3.0"
 }
 
 // LookupDefaults are the default values for a Lookup.
 type LookupDefaults struct {
-	LookupBase `yaml:",inline" json:",inline"`
+	LookupBase    `yaml:",inline" json:",inline"`
+	GitHubBaseURL string `yaml:"github_base_url,omitempty" json:"github_base_url,omitempty"` // Base URL of the GitHub API (e.g. GitHub Enterprise Server's https://github.example.com/api/v3)
 
 	Require filter.RequireDefaults `yaml:"require" json:"require"` // Options to require before a release is considered valid
 }
@@ -102,13 +111,22 @@ func NewDefaults(
 }
 
 type Lookup struct {
-	Type        string `yaml:"type,omitempty" json:"type,omitempty"` // "github"/"URL"
-	URL         string `yaml:"url,omitempty" json:"url,omitempty"`   // type:URL - "https://example.com", type:github - "owner/repo" or "https://github.com/owner/repo".
+	Type        string `yaml:"type,omitempty" json:"type,omitempty"`               // "container"/"crates"/"feed"/"github"/"gitea"/"gitlab"/"go"/"helm"/"npm"/"pypi"/"URL"
+	URL         string `yaml:"url,omitempty" json:"url,omitempty"`                 // type:URL - "https://example.com", type:github - "owner/repo" or "https://github.com/owner/repo", type:gitea - "owner/repo" or "https://gitea.example.com/owner/repo", type:gitlab - "group/project" or "https://gitlab.com/group/project", type:container - "[registry/]image", e.g. "ghcr.io/release-argus/argus", type:crates/go/npm/pypi - the crate/module/package name, e.g. "serde"/"github.com/release-argus/Argus"/"@types/node"/"requests", type:helm - the chart repository, e.g. "https://charts.example.com" or "oci://ghcr.io/owner/charts", type:feed - the RSS/Atom feed, e.g. "https://example.com/releases.atom".
+	BaseURL     string `yaml:"base_url,omitempty" json:"base_url,omitempty"`       // type:gitea/gitlab - "https://gitea.example.com", type:github - GitHub API base URL (GitHub Enterprise Server), "https://github.example.com/api/v3", type:crates/go/npm/pypi - registry/mirror, "https://npm.example.com"
+	Username    string `yaml:"username,omitempty" json:"username,omitempty"`       // type:container/helm - Username for the registry/repository (with access_token as the password/token)
+	Chart       string `yaml:"chart,omitempty" json:"chart,omitempty"`             // type:helm - Name of the chart in the repository
+	AppVersion  *bool  `yaml:"app_version,omitempty" json:"app_version,omitempty"` // type:helm - Whether to use the appVersion of the chart rather than its version
+	AllMatches  *bool  `yaml:"all_matches,omitempty" json:"all_matches,omitempty"` // type:url - Whether to consider every match of the final url_command, choosing the newest
 	LookupBase  `yaml:",inline" json:",inline"`
 	URLCommands filter.URLCommandSlice `yaml:"url_commands,omitempty" json:"url_commands,omitempty"` // Commands to filter the release from the URL request
 	Require     *filter.Require        `yaml:"require,omitempty" json:"require,omitempty"`           // Options to require before a release is considered valid
 
-	GitHubData *GitHubData `yaml:"-" json:"-"` // GitHub Conditional Request vars
+	IgnoreVersions      []string     `yaml:"ignore_versions,omitempty" json:"ignore_versions,omitempty"` // Versions to never use, exact, 'regex:<RegEx>' or 'semver:<range>', e.g. ["1.2.3", "regex:^1\.3\.", "semver:>=2.0.0 <2.0.5"]
+	ignoreVersionsMutex sync.RWMutex // Mutex to protect the IgnoreVersions from changes made while querying
+
+	GitHubData  *GitHubData         `yaml:"-" json:"-"` // GitHub Conditional Request vars
+	dockerCheck *filter.DockerCheck // type:container/helm (OCI) - Registry query token for the image
 
 	Options *opt.Options      `yaml:"-" json:"-"` // Options
 	Status  *svcstatus.Status `yaml:"-" json:"-"` // Service Status
@@ -157,6 +175,12 @@ func New(
 	return
 }
 
+// usesReleases returns whether the Lookup queries a releases/tags API, tracking those releases in GitHubData.
+func (l *Lookup) usesReleases() bool {
+	return l.Type == "github" || l.Type == "gitea" || l.Type == "gitlab" || l.Type == "container" ||
+		l.Type == "helm" || l.Type == "feed" || l.isPackageRegistry()
+}
+
 // String returns a string representation of the Lookup.
 func (l *Lookup) String(prefix string) (str string) {
 	if l != nil {
@@ -181,7 +205,7 @@ func NewGitHubData(
 ) (githubData *GitHubData) {
 	// ETag - https://docs.github.com/en/rest/overview/resources-in-the-rest-api#conditional-requests
 	if eTag == "" {
-		eTag = getEmptyListETag()
+		eTag = getEmptyListETag(githubBaseURL)
 	}
 	// Releases
 	var releasesDeref []github_types.Release
@@ -195,6 +219,23 @@ func NewGitHubData(
 	return
 }
 
+// newGitHubData returns a new GitHubData for the type of this Lookup.
+// (only GitHub has a known ETag for an empty list, cached per host)
+func (l *Lookup) newGitHubData(
+	eTag string,
+	releases *[]github_types.Release,
+) (githubData *GitHubData) {
+	if l.Type == "github" && eTag == "" {
+		eTag = getEmptyListETag(l.GetBaseURL())
+	}
+
+	githubData = &GitHubData{eTag: eTag}
+	if releases != nil {
+		githubData.releases = *releases
+	}
+	return
+}
+
 // String returns a string representation of the Status.
 func (g *GitHubData) String() (str string) {
 	if g == nil {
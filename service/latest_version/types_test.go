@@ -39,11 +39,15 @@ func TestGetEmptyListETag(t *testing.T) {
 	defer emptyListETagMutex.RUnlock()
 
 	// WHEN getEmptyListETag is called
-	got := getEmptyListETag()
+	got := getEmptyListETag(githubBaseURL)
 
 	// THEN the emptyListETag is returned
-	if got != emptyListETag {
-		t.Errorf("getEmptyListETag() = %q, want %q", got, emptyListETag)
+	if got != emptyListETags[githubBaseURL] {
+		t.Errorf("getEmptyListETag() = %q, want %q", got, emptyListETags[githubBaseURL])
+	}
+	// AND an unknown host has no emptyListETag
+	if got := getEmptyListETag("https://github.example.com/api/v3"); got != "" {
+		t.Errorf("getEmptyListETag() = %q, want %q", got, "")
 	}
 }
 
@@ -51,15 +55,24 @@ func TestSetEmptyListETag(t *testing.T) {
 	// GIVEN emptyListETag exists
 	emptyListETagTestMutex.Lock()
 	defer emptyListETagTestMutex.Unlock()
+	startingEmptyListETag := getEmptyListETag(githubBaseURL)
+	defer setEmptyListETag(githubBaseURL, startingEmptyListETag)
 
 	// WHEN setEmptyListETag is called
 	newValue := "foo"
-	setEmptyListETag(newValue)
+	setEmptyListETag(githubBaseURL, newValue)
 
 	// THEN the emptyListETag is set
-	if emptyListETag != newValue {
+	if emptyListETags[githubBaseURL] != newValue {
 		t.Errorf("setEmptyListETag() = %q, want %q",
-			emptyListETag, newValue)
+			emptyListETags[githubBaseURL], newValue)
+	}
+	// AND it's only set for that host
+	otherHost := "https://github.example.com/api/v3"
+	setEmptyListETag(otherHost, "bar")
+	if emptyListETags[githubBaseURL] != newValue {
+		t.Errorf("setEmptyListETag() on %q changed the ETag for %q to %q",
+			otherHost, githubBaseURL, emptyListETags[githubBaseURL])
 	}
 }
 
@@ -68,16 +81,16 @@ func TestFindEmptyListETag(t *testing.T) {
 	emptyListETagTestMutex.Lock()
 	defer emptyListETagTestMutex.Unlock()
 	incorrectValue := "foo"
-	setEmptyListETag(incorrectValue)
+	setEmptyListETag(githubBaseURL, incorrectValue)
 
 	// WHEN FindEmptyListETag is called
 	FindEmptyListETag(os.Getenv("GITHUB_TOKEN"))
 
 	// THEN the emptyListETag is set
-	setTo := getEmptyListETag()
+	setTo := getEmptyListETag(githubBaseURL)
 	if setTo == incorrectValue {
 		t.Errorf("emptyListETag wasn't updated. Got %q, want %q",
-			setTo, emptyListETag)
+			setTo, initialEmptyListETag)
 	}
 	if setTo != initialEmptyListETag {
 		t.Errorf("Empty list ETag has changed from %q to %q",
@@ -88,7 +101,7 @@ func TestFindEmptyListETag(t *testing.T) {
 func TestNewGitHubData(t *testing.T) {
 	emptyListETagTestMutex.Lock()
 	defer emptyListETagTestMutex.Unlock()
-	startingEmptyListETag := getEmptyListETag()
+	startingEmptyListETag := getEmptyListETag(githubBaseURL)
 	// GIVEN a GitHubData is wanted with/without an eTag/releases
 	tests := map[string]struct {
 		eTag     string
@@ -15,14 +15,24 @@
 package latestver
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
+	opt "github.com/release-argus/Argus/service/options"
 	"github.com/release-argus/Argus/util"
 )
 
+// containerImageRegex matches a valid container image name.
+var containerImageRegex = regexp.MustCompile(`^[a-z0-9]+([\w\-\.\/]*[a-z0-9])?$`)
+
 // CheckValues of the LookupDefaults struct
 func (l *LookupDefaults) CheckValues(prefix string) (errs error) {
+	if constraintErr := checkVersionConstraint(l.VersionConstraint, "", prefix); constraintErr != nil {
+		errs = constraintErr
+	}
 	if requireErrs := l.Require.CheckValues(prefix + "  "); requireErrs != nil {
 		errs = fmt.Errorf("%s%w",
 			util.ErrorToString(errs), requireErrs)
@@ -42,20 +52,111 @@ func (l *Lookup) CheckValues(prefix string) (errs error) {
 			errs = fmt.Errorf("%s%s  url: <required> e.g. github:'release-argus/Argus' or url:'https://example.com'\\",
 				util.ErrorToString(errs), prefix)
 		}
-	} else if l.Type != "url" && l.Type != "github" {
+	} else if !util.Contains(supportedTypes, l.Type) {
 		errType := "<required>"
 		if l.Type != "" {
 			errType = fmt.Sprintf("%q <invalid>", l.Type)
 		}
-		errs = fmt.Errorf("%s%s  type: %s e.g. github or url\\",
-			util.ErrorToString(errs), prefix, errType)
+		errs = fmt.Errorf("%s%s  type: %s (only [%s] are allowed)\\",
+			util.ErrorToString(errs), prefix, errType, strings.Join(supportedTypes, ", "))
 	}
-	if l.Type == "github" && strings.Count(l.URL, "/") > 1 {
-		parts := strings.Split(l.URL, "/")
-		l.URL = strings.Join(parts[len(parts)-2:], "/")
+	switch l.Type {
+	case "github":
+		if strings.Count(l.URL, "/") > 1 {
+			// "https://github.example.com/owner/repo" -> base_url of GitHub Enterprise Server's API
+			if baseURL, _, err := projectFromURL(l.URL); err == nil &&
+				l.BaseURL == "" && baseURL != "" && !isGitHubWebURL(baseURL) {
+				l.BaseURL = baseURL + "/api/v3"
+			}
+			parts := strings.Split(l.URL, "/")
+			l.URL = strings.Join(parts[len(parts)-2:], "/")
+		}
+	case "gitea":
+		// "https://gitea.example.com/owner/repo" -> base_url + "owner/repo"
+		if strings.Contains(l.URL, "://") {
+			baseURL, repo, err := projectFromURL(l.URL)
+			if err == nil && strings.Count(repo, "/") == 0 {
+				err = errors.New("no repo found")
+			}
+			if err != nil {
+				errs = fmt.Errorf("%s%s  url: %q <invalid> (%s)\\",
+					util.ErrorToString(errs), prefix, l.URL, err)
+			} else {
+				parts := strings.Split(repo, "/")
+				l.URL = strings.Join(parts[:2], "/")
+				if l.BaseURL == "" {
+					l.BaseURL = baseURL
+				}
+			}
+		}
+		if l.BaseURL == "" {
+			errs = fmt.Errorf("%s%s  base_url: <required> e.g. 'https://gitea.example.com'\\",
+				util.ErrorToString(errs), prefix)
+		}
+	case "container":
+		if l.URL != "" {
+			if _, _, image := l.containerImage(); !containerImageRegex.MatchString(image) {
+				errs = fmt.Errorf("%s%s  url: %q <invalid> (expected an image, e.g. 'ghcr.io/release-argus/argus')\\",
+					util.ErrorToString(errs), prefix, l.URL)
+			}
+		}
+	case "helm":
+		if l.Chart == "" {
+			errs = fmt.Errorf("%s%s  chart: <required> e.g. 'nginx'\\",
+				util.ErrorToString(errs), prefix)
+		} else if l.isHelmOCI() && l.GetAppVersion() {
+			errs = fmt.Errorf("%s%s  app_version: <invalid> (not supported for OCI-hosted charts)\\",
+				util.ErrorToString(errs), prefix)
+		}
+	case "gitlab":
+		// "https://gitlab.example.com/group/project" -> base_url + "group/project"
+		if strings.Contains(l.URL, "://") {
+			baseURL, project, err := projectFromURL(l.URL)
+			if err != nil {
+				errs = fmt.Errorf("%s%s  url: %q <invalid> (%s)\\",
+					util.ErrorToString(errs), prefix, l.URL, err)
+			} else {
+				l.URL = project
+				if l.BaseURL == "" && baseURL != gitlabBaseURL {
+					l.BaseURL = baseURL
+				}
+			}
+		}
 	}
 
-	if requireErrs := l.Require.CheckValues(prefix + "  "); requireErrs != nil {
+	// version_scheme and version_constraint of the Service (once given their defaults).
+	var versionScheme string
+	if l.Options != nil && l.Options.Defaults != nil {
+		versionScheme = l.Options.GetVersionScheme()
+	}
+	versionConstraint := l.VersionConstraint
+	if l.Defaults != nil {
+		versionConstraint = l.GetVersionConstraint()
+	}
+	if constraintErr := checkVersionConstraint(versionConstraint, versionScheme, prefix); constraintErr != nil {
+		errs = fmt.Errorf("%s%w",
+			util.ErrorToString(errs), constraintErr)
+	}
+	if ignoreErrs := checkIgnoreVersions(l.GetIgnoreVersions(), versionScheme, prefix); ignoreErrs != nil {
+		errs = fmt.Errorf("%s%w",
+			util.ErrorToString(errs), ignoreErrs)
+	}
+	if l.GetAllMatches() && l.Type != "url" {
+		errs = fmt.Errorf("%s%s  all_matches: <invalid> (only supported for the url type)\\",
+			util.ErrorToString(errs), prefix)
+	}
+
+	requireErrs := l.Require.CheckValues(prefix + "  ")
+	if assetsErrs := l.checkRequireAssets(prefix + "    "); assetsErrs != nil {
+		if requireErrs == nil {
+			requireErrs = fmt.Errorf("%s  require:\\%w",
+				prefix, assetsErrs)
+		} else {
+			requireErrs = fmt.Errorf("%s%w",
+				util.ErrorToString(requireErrs), assetsErrs)
+		}
+	}
+	if requireErrs != nil {
 		errs = fmt.Errorf("%s%w",
 			util.ErrorToString(errs), requireErrs)
 	}
@@ -71,3 +172,85 @@ func (l *Lookup) CheckValues(prefix string) (errs error) {
 
 	return
 }
+
+// checkRequireAssets returns an error for each `require` that needs release assets
+// when this type of Lookup doesn't have them.
+func (l *Lookup) checkRequireAssets(prefix string) (errs error) {
+	if l.Require == nil || util.Contains(assetTypes, l.Type) {
+		return
+	}
+
+	if len(l.Require.Assets) != 0 {
+		errs = fmt.Errorf("%s%sassets: <invalid> (only supported for the [%s] types)\\",
+			util.ErrorToString(errs), prefix, strings.Join(assetTypes, ", "))
+	}
+	if l.Require.Verify != nil {
+		errs = fmt.Errorf("%s%sverify: <invalid> (only supported for the [%s] types)\\",
+			util.ErrorToString(errs), prefix, strings.Join(assetTypes, ", "))
+	}
+	return
+}
+
+// isGitHubWebURL returns whether `baseURL` is the web address of public GitHub,
+// whatever the scheme, and with or without the 'www.' prefix.
+//
+// e.g. "https://github.com", "http://www.github.com"
+func isGitHubWebURL(baseURL string) bool {
+	host := strings.ToLower(baseURL)
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	return strings.TrimPrefix(host, "www.") == "github.com"
+}
+
+// projectFromURL splits a GitLab/Gitea project URL into the base URL and the project path.
+//
+// e.g. https://gitlab.example.com/group/subgroup/project/-/releases
+// = "https://gitlab.example.com", "group/subgroup/project"
+func projectFromURL(projectURL string) (baseURL string, project string, err error) {
+	schemeIndex := strings.Index(projectURL, "://")
+	if schemeIndex == -1 {
+		return "", projectURL, nil
+	}
+
+	hostAndPath := projectURL[schemeIndex+3:]
+	slashIndex := strings.Index(hostAndPath, "/")
+	if slashIndex == -1 {
+		err = errors.New("no project path found")
+		return
+	}
+	baseURL = projectURL[:schemeIndex+3+slashIndex]
+	project = hostAndPath[slashIndex+1:]
+	// Remove any '/-/...' suffix.
+	if dashIndex := strings.Index(project, "/-/"); dashIndex != -1 {
+		project = project[:dashIndex]
+	}
+	project = strings.TrimSuffix(strings.TrimSuffix(project, "/"), ".git")
+	if project == "" {
+		err = errors.New("no project path found")
+	}
+	return
+}
+
+// checkVersionConstraint returns an error if `constraint` is not a valid semantic version range,
+// or if versions are compared with a `versionScheme` other than semver.
+func checkVersionConstraint(constraint string, versionScheme string, prefix string) error {
+	if constraint == "" {
+		return nil
+	}
+
+	if !isSemVerScheme(versionScheme) {
+		return fmt.Errorf("%s  version_constraint: %q <invalid> (only supported with the %q version_scheme, not %q)\\",
+			prefix, constraint, opt.VersionSchemeSemVer, versionScheme)
+	}
+	if _, err := semver.NewConstraint(constraint); err != nil {
+		return fmt.Errorf("%s  version_constraint: %q <invalid> (%s)\\",
+			prefix, constraint, err)
+	}
+	return nil
+}
+
+// isSemVerScheme returns whether versions of `versionScheme` can be checked against semantic version ranges.
+//
+// No scheme (semantic_versioning disabled) still checks the ranges with semver.
+func isSemVerScheme(versionScheme string) bool {
+	return versionScheme == "" || versionScheme == opt.VersionSchemeSemVer
+}
@@ -29,8 +29,9 @@ import (
 func TestLookupDefaults_CheckValues(t *testing.T) {
 	// GIVEN a LookupDefault
 	tests := map[string]struct {
-		require  filter.RequireDefaults
-		errRegex []string
+		require           filter.RequireDefaults
+		versionConstraint string
+		errRegex          []string
 	}{
 		"valid": {
 			require: *filter.NewRequireDefaults(
@@ -48,6 +49,26 @@ func TestLookupDefaults_CheckValues(t *testing.T) {
 				filter.NewDockerCheckDefaults(
 					"someType", "", "", "", "", nil)),
 		},
+		"valid version_constraint": {
+			versionConstraint: ">=2.0 <3.0",
+			errRegex:          []string{},
+		},
+		"invalid version_constraint": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  version_constraint: "[^"]+" <invalid>`},
+			versionConstraint: "2.x.y",
+		},
+		"invalid version_constraint and require": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  version_constraint: "[^"]+" <invalid>`,
+				`^  require:$`},
+			versionConstraint: "2.x.y",
+			require: *filter.NewRequireDefaults(
+				filter.NewDockerCheckDefaults(
+					"someType", "", "", "", "", nil)),
+		},
 	}
 
 	for name, tc := range tests {
@@ -56,6 +77,7 @@ func TestLookupDefaults_CheckValues(t *testing.T) {
 
 			defaults := LookupDefaults{
 				Require: tc.require}
+			defaults.VersionConstraint = tc.versionConstraint
 
 			// WHEN CheckValues is called
 			err := defaults.CheckValues("")
@@ -82,12 +104,19 @@ func TestLookupDefaults_CheckValues(t *testing.T) {
 func TestLookup_CheckValues(t *testing.T) {
 	// GIVEN a Lookup
 	tests := map[string]struct {
-		lType       *string
-		url         *string
-		wantURL     *string
-		require     *filter.Require
-		urlCommands *filter.URLCommandSlice
-		errRegex    []string
+		lType             *string
+		url               *string
+		wantURL           *string
+		wantBaseURL       string
+		chart             string
+		appVersion        *bool
+		allMatches        *bool
+		require           *filter.Require
+		versionConstraint string
+		ignoreVersions    []string
+		versionScheme     string
+		urlCommands       *filter.URLCommandSlice
+		errRegex          []string
 	}{
 		"valid": {
 			errRegex: []string{},
@@ -115,6 +144,151 @@ func TestLookup_CheckValues(t *testing.T) {
 			url:      test.StringPtr("https://github.com/release-argus/Argus"),
 			wantURL:  test.StringPtr("release-argus/Argus"),
 		},
+		"corrects github url with http": {
+			errRegex: []string{},
+			url:      test.StringPtr("http://github.com/release-argus/Argus"),
+			wantURL:  test.StringPtr("release-argus/Argus"),
+		},
+		"corrects github url with www": {
+			errRegex: []string{},
+			url:      test.StringPtr("https://www.github.com/release-argus/Argus"),
+			wantURL:  test.StringPtr("release-argus/Argus"),
+		},
+		"corrects github url with http and www": {
+			errRegex: []string{},
+			url:      test.StringPtr("http://WWW.GitHub.com/release-argus/Argus"),
+			wantURL:  test.StringPtr("release-argus/Argus"),
+		},
+		"corrects github enterprise url": {
+			errRegex:    []string{},
+			url:         test.StringPtr("https://github.example.com/release-argus/Argus"),
+			wantURL:     test.StringPtr("release-argus/Argus"),
+			wantBaseURL: "https://github.example.com/api/v3",
+		},
+		"corrects gitea url": {
+			errRegex:    []string{},
+			lType:       test.StringPtr("gitea"),
+			url:         test.StringPtr("https://gitea.example.com/owner/repo/releases"),
+			wantURL:     test.StringPtr("owner/repo"),
+			wantBaseURL: "https://gitea.example.com",
+		},
+		"gitea url without repo": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  url: "[^"]+" <invalid> \(no repo found\)`,
+				`^  base_url: <required>`},
+			lType: test.StringPtr("gitea"),
+			url:   test.StringPtr("https://gitea.example.com/owner"),
+		},
+		"gitea without base_url": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  base_url: <required>`},
+			lType: test.StringPtr("gitea"),
+			url:   test.StringPtr("owner/repo"),
+		},
+		"corrects gitlab url": {
+			errRegex:    []string{},
+			lType:       test.StringPtr("gitlab"),
+			url:         test.StringPtr("https://gitlab.example.com/group/subgroup/project/-/releases"),
+			wantURL:     test.StringPtr("group/subgroup/project"),
+			wantBaseURL: "https://gitlab.example.com",
+		},
+		"gitlab url on gitlab.com doesn't set base_url": {
+			errRegex: []string{},
+			lType:    test.StringPtr("gitlab"),
+			url:      test.StringPtr("https://gitlab.com/group/project.git"),
+			wantURL:  test.StringPtr("group/project"),
+		},
+		"gitlab url without project": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  url: "[^"]+" <invalid> \(no project path found\)`},
+			lType: test.StringPtr("gitlab"),
+			url:   test.StringPtr("https://gitlab.example.com"),
+		},
+		"container image": {
+			errRegex: []string{},
+			lType:    test.StringPtr("container"),
+			url:      test.StringPtr("ghcr.io/release-argus/argus:latest"),
+			wantURL:  test.StringPtr("ghcr.io/release-argus/argus:latest"),
+		},
+		"container image invalid": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  url: "[^"]+" <invalid> \(expected an image`},
+			lType: test.StringPtr("container"),
+			url:   test.StringPtr("ghcr.io/release-argus/Argus!"),
+		},
+		"helm without chart": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  chart: <required>`},
+			lType: test.StringPtr("helm"),
+			url:   test.StringPtr("https://charts.example.com"),
+		},
+		"helm oci with app_version": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  app_version: <invalid> \(not supported for OCI-hosted charts\)`},
+			lType:      test.StringPtr("helm"),
+			url:        test.StringPtr("oci://ghcr.io/owner/charts"),
+			chart:      "argus",
+			appVersion: test.BoolPtr(true),
+		},
+		"version_constraint": {
+			errRegex:          []string{},
+			versionConstraint: "~1.18",
+		},
+		"invalid version_constraint": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  version_constraint: "[^"]+" <invalid>`},
+			versionConstraint: ">= 1.0 <",
+		},
+		"version_constraint with the pep440 version_scheme": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  version_constraint: "~1.18" <invalid> \(only supported with the "semver" version_scheme, not "pep440"\)`},
+			versionConstraint: "~1.18",
+			versionScheme:     "pep440",
+		},
+		"semver ignore_versions with the pep440 version_scheme": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  ignore_versions:$`,
+				`^    item_1: "semver:~2.0" <invalid> \(only supported with the "semver" version_scheme, not "pep440"\)`},
+			ignoreVersions: []string{"1.0rc1", "semver:~2.0"},
+			versionScheme:  "pep440",
+		},
+		"valid ignore_versions with the pep440 version_scheme": {
+			errRegex:       []string{},
+			ignoreVersions: []string{"1.0rc1", `regex:^1\.0\.post`},
+			versionScheme:  "pep440",
+		},
+		"valid ignore_versions": {
+			errRegex:       []string{},
+			ignoreVersions: []string{"1.2.3", `regex:^1\.3\.`, "semver:~2.0"},
+		},
+		"invalid ignore_versions": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  ignore_versions:$`,
+				`^    item_1: "regex:\[0-" <invalid>`},
+			ignoreVersions: []string{"1.2.3", "regex:[0-"},
+		},
+		"all_matches on a url lookup": {
+			errRegex:   []string{},
+			lType:      test.StringPtr("url"),
+			url:        test.StringPtr("https://example.com"),
+			allMatches: test.BoolPtr(true),
+		},
+		"all_matches on a github lookup": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  all_matches: <invalid> \(only supported for the url type\)`},
+			allMatches: test.BoolPtr(true),
+		},
 		"invalid require": {
 			errRegex: []string{
 				`^latest_version:$`,
@@ -122,6 +296,42 @@ func TestLookup_CheckValues(t *testing.T) {
 				`^    regex_content: "[^"]+" <invalid>`},
 			require: &filter.Require{RegexContent: "[0-"},
 		},
+		"assets on a github lookup": {
+			errRegex: []string{},
+			require:  &filter.Require{Assets: []string{`checksums\.txt`}},
+		},
+		"assets on a url lookup": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  require:$`,
+				`^    assets: <invalid> \(only supported for the \[gitea, github, gitlab, helm\] types\)$`},
+			lType:   test.StringPtr("url"),
+			url:     test.StringPtr("https://example.com"),
+			require: &filter.Require{Assets: []string{`checksums\.txt`}},
+		},
+		"invalid assets on a url lookup": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  require:$`,
+				`^    assets:$`,
+				`^      item_0: "\[0-" <invalid>`,
+				`^    assets: <invalid> \(only supported for`},
+			lType:   test.StringPtr("url"),
+			url:     test.StringPtr("https://example.com"),
+			require: &filter.Require{Assets: []string{"[0-"}},
+		},
+		"verify on a feed lookup": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  require:$`,
+				`^    verify: <invalid> \(only supported for the \[gitea, github, gitlab, helm\] types\)$`},
+			lType: test.StringPtr("feed"),
+			url:   test.StringPtr("https://example.com/feed.xml"),
+			require: &filter.Require{
+				Verify: &filter.VerifyCheck{
+					Asset:     "myapp",
+					Checksums: "SHA256SUMS"}},
+		},
 		"invalid urlCommands": {
 			errRegex: []string{
 				`^latest_version:$`,
@@ -156,6 +366,12 @@ func TestLookup_CheckValues(t *testing.T) {
 			if tc.url != nil {
 				lookup.URL = *tc.url
 			}
+			lookup.Chart = tc.chart
+			lookup.AppVersion = tc.appVersion
+			lookup.AllMatches = tc.allMatches
+			lookup.VersionConstraint = tc.versionConstraint
+			lookup.IgnoreVersions = tc.ignoreVersions
+			lookup.Options.VersionScheme = tc.versionScheme
 			if tc.require != nil {
 				lookup.Require = tc.require
 			}
@@ -181,6 +397,86 @@ func TestLookup_CheckValues(t *testing.T) {
 						lines[i], tc.errRegex[i], e)
 				}
 			}
+			// AND the URL is corrected when expected
+			if tc.wantURL != nil && lookup.URL != *tc.wantURL {
+				t.Errorf("want URL %q, not %q",
+					*tc.wantURL, lookup.URL)
+			}
+			if lookup.BaseURL != tc.wantBaseURL {
+				t.Errorf("want BaseURL %q, not %q",
+					tc.wantBaseURL, lookup.BaseURL)
+			}
+		})
+	}
+}
+
+func TestProjectFromURL(t *testing.T) {
+	// GIVEN a GitLab URL
+	tests := map[string]struct {
+		url         string
+		wantBaseURL string
+		wantProject string
+		errRegex    string
+	}{
+		"project path": {
+			url:         "group/project",
+			wantProject: "group/project",
+		},
+		"project url": {
+			url:         "https://gitlab.com/group/project",
+			wantBaseURL: "https://gitlab.com",
+			wantProject: "group/project",
+		},
+		"project url with subgroups and a page": {
+			url:         "https://gitlab.example.com/group/sub/project/-/tags",
+			wantBaseURL: "https://gitlab.example.com",
+			wantProject: "group/sub/project",
+		},
+		"clone url": {
+			url:         "https://gitlab.example.com:8443/group/project.git",
+			wantBaseURL: "https://gitlab.example.com:8443",
+			wantProject: "group/project",
+		},
+		"no project": {
+			url:      "https://gitlab.example.com/",
+			errRegex: "no project path found",
+		},
+		"no path": {
+			url:      "https://gitlab.example.com",
+			errRegex: "no project path found",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN projectFromURL is called on it
+			baseURL, project, err := projectFromURL(tc.url)
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if err != nil {
+				return
+			}
+			// AND the base URL and project are split correctly
+			if baseURL != tc.wantBaseURL {
+				t.Errorf("want base URL %q, not %q",
+					tc.wantBaseURL, baseURL)
+			}
+			if project != tc.wantProject {
+				t.Errorf("want project %q, not %q",
+					tc.wantProject, project)
+			}
 		})
 	}
 }
@@ -24,6 +24,7 @@ import (
 	shoutrrr_vars "github.com/release-argus/Argus/notifiers/shoutrrr/types"
 	deployedver "github.com/release-argus/Argus/service/deployed_version"
 	latestver "github.com/release-argus/Argus/service/latest_version"
+	svcstatus "github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/util"
 	"github.com/release-argus/Argus/webhook"
 )
@@ -105,6 +106,14 @@ func FromPayload(
 	newService.Status.DatabaseChannel = serviceHardDefaults.Status.DatabaseChannel
 	newService.Status.SaveChannel = serviceHardDefaults.Status.SaveChannel
 
+	// Keep the deployed_versions targets if they weren't in the payload (they can't be edited in the Web UI).
+	if newService.DeployedVersionTargets == nil && oldService != nil {
+		newService.DeployedVersionTargets = oldService.DeployedVersionTargets.Copy()
+		// Removing them
+	} else if newService.DeployedVersionTargets != nil && len(newService.DeployedVersionTargets.Targets) == 0 {
+		newService.DeployedVersionTargets = nil
+	}
+
 	removeDefaults(oldService, newService, serviceDefaults)
 	newService.Init(
 		serviceDefaults, serviceHardDefaults,
@@ -154,9 +163,14 @@ func (s *Service) giveSecretsLatestVersion(oldLatestVersion *latestver.Lookup) {
 			s.LatestVersion.Require.Docker != nil && s.LatestVersion.Require.Docker.Token == "<secret>" {
 			s.LatestVersion.Require.Docker.Token = oldLatestVersion.Require.Docker.Token
 		}
+		// with the Require.HTTP referencing the oldService's HTTP secrets
+		if oldLatestVersion.Require != nil && oldLatestVersion.Require.HTTP != nil &&
+			s.LatestVersion.Require.HTTP != nil {
+			s.LatestVersion.Require.HTTP.GiveSecrets(oldLatestVersion.Require.HTTP)
+		}
 	}
-	// GitHubData
-	if s.LatestVersion.Type == "github" && oldLatestVersion.Type == "github" {
+	// GitHubData (github/gitlab)
+	if s.LatestVersion.Type == oldLatestVersion.Type && oldLatestVersion.GitHubData != nil {
 		s.LatestVersion.GitHubData = oldLatestVersion.GitHubData
 	}
 }
@@ -315,10 +329,27 @@ func (s *Service) giveSecrets(oldService *Service, secretRefs oldSecretRefs) {
 	}
 	// Keep DeployedVersion if the DeployedVersionLookup is unchanged
 	if s.DeployedVersionLookup.IsEqual(oldService.DeployedVersionLookup) &&
-		oldService.Options.SemanticVersioning == s.Options.SemanticVersioning {
+		oldService.Options.SemanticVersioning == s.Options.SemanticVersioning &&
+		oldService.Options.VersionScheme == s.Options.VersionScheme {
 		s.Status.SetDeployedVersion(oldService.Status.DeployedVersion(), false)
 		s.Status.SetDeployedVersionTimestamp(oldService.Status.DeployedVersionTimestamp())
 	}
+	// Keep the versions of the deployed targets that are unchanged
+	if s.DeployedVersionTargets != nil && oldService.DeployedVersionTargets != nil &&
+		oldService.Options.SemanticVersioning == s.Options.SemanticVersioning &&
+		oldService.Options.VersionScheme == s.Options.VersionScheme {
+		var keep []svcstatus.DeployedTarget
+		for _, target := range oldService.Status.DeployedTargets() {
+			if s.DeployedVersionTargets.Targets[target.Name].IsEqual(oldService.DeployedVersionTargets.Targets[target.Name]) {
+				keep = append(keep, target)
+			}
+		}
+		s.Status.SetDeployedTargets(keep)
+		if s.DeployedVersionTargets.GetAggregate() == oldService.DeployedVersionTargets.GetAggregate() {
+			s.Status.SetDeployedVersion(oldService.Status.DeployedVersion(), false)
+			s.Status.SetDeployedVersionTimestamp(oldService.Status.DeployedVersionTimestamp())
+		}
+	}
 }
 
 // CheckFetches will check that, if set, the LatestVersion and DeployedVersion can be fetched
@@ -369,6 +400,20 @@ func (s *Service) CheckFetches() (err error) {
 		}
 		s.Status.SetDeployedVersion(version, false)
 	}
+	// Fetch the deployed versions of the targets
+	if s.DeployedVersionTargets != nil {
+		for _, name := range util.SortedKeys(s.DeployedVersionTargets.Targets) {
+			var version string
+			version, err = s.DeployedVersionTargets.Targets[name].Query(
+				false,
+				&logFrom)
+			if err != nil {
+				err = fmt.Errorf("deployed_versions.targets.%s - %w", name, err)
+				return
+			}
+			s.Status.SetDeployedTargetVersion(name, version, false)
+		}
+	}
 
 	return
 }
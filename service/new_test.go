@@ -182,6 +182,23 @@ func TestService_GiveSecretsLatestVersion(t *testing.T) {
 						"", "", "", "", "", "", time.Now(), nil)},
 				nil, "", "", nil, nil, nil, nil),
 		},
+		"give old Require.HTTP secrets": {
+			latestVersion: &latestver.Lookup{
+				Require: &filter.Require{
+					HTTP: &filter.HTTPCheck{
+						BasicAuth: &filter.HTTPBasicAuth{Username: "user", Password: "<secret>"},
+						Headers:   []filter.HTTPHeader{{Key: "X-Token", Value: "<secret>"}}}}},
+			otherLV: &latestver.Lookup{
+				Require: &filter.Require{
+					HTTP: &filter.HTTPCheck{
+						BasicAuth: &filter.HTTPBasicAuth{Username: "user", Password: "pass"},
+						Headers:   []filter.HTTPHeader{{Key: "X-Token", Value: "token"}}}}},
+			expected: &latestver.Lookup{
+				Require: &filter.Require{
+					HTTP: &filter.HTTPCheck{
+						BasicAuth: &filter.HTTPBasicAuth{Username: "user", Password: "pass"},
+						Headers:   []filter.HTTPHeader{{Key: "X-Token", Value: "token"}}}}},
+		},
 		"GitHubData carried over if type still 'github'": {
 			latestVersion: &latestver.Lookup{
 				Type: "github"},
@@ -244,6 +261,13 @@ func TestService_GiveSecretsLatestVersion(t *testing.T) {
 					tc.expected.Require.Docker.Token, gotLV.Require.Docker.Token)
 			}
 
+			// Require.HTTP
+			if tc.expected.Require != nil && tc.expected.Require.HTTP != nil &&
+				gotLV.Require.HTTP.String("") != tc.expected.Require.HTTP.String("") {
+				t.Errorf("Expected Require.HTTP to be\n%q\ngot\n%q",
+					tc.expected.Require.HTTP.String(""), gotLV.Require.HTTP.String(""))
+			}
+
 			// GitHubData
 			if gotLV.GitHubData != tc.expected.GitHubData {
 				t.Errorf("Expected GitHubData to be %v, got %q",
@@ -17,6 +17,7 @@ package opt
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/release-argus/Argus/util"
@@ -26,6 +27,7 @@ import (
 type OptionsBase struct {
 	Interval           string `yaml:"interval,omitempty" json:"interval,omitempty"`                       // AhBmCs = Sleep A hours, B minutes and C seconds between queries.
 	SemanticVersioning *bool  `yaml:"semantic_versioning,omitempty" json:"semantic_versioning,omitempty"` // default - true = Version has to follow semantic versioning (https://semver.org/) and be greater than the previous to trigger anything.
+	VersionScheme      string `yaml:"version_scheme,omitempty" json:"version_scheme,omitempty"`           // default - semver = Scheme used to parse and compare versions when semantic_versioning is enabled.
 }
 
 // OptionsDefaults are the default values for Options.
@@ -92,10 +94,25 @@ func (o *Options) GetInterval() string {
 
 // GetSemanticVersioning will return whether Semantic Versioning should be used for this Service.
 func (o *Options) GetSemanticVersioning() bool {
-	return *util.FirstNonNilPtr(
-		o.SemanticVersioning,
-		o.Defaults.SemanticVersioning,
-		o.HardDefaults.SemanticVersioning)
+	return util.EvalNilPtr(
+		util.FirstNonNilPtr(
+			o.SemanticVersioning,
+			o.Defaults.SemanticVersioning,
+			o.HardDefaults.SemanticVersioning),
+		true)
+}
+
+// GetVersionScheme returns the version scheme used to parse and compare versions of this Service,
+// or an empty string if versions shouldn't be compared (semantic_versioning disabled).
+func (o *Options) GetVersionScheme() string {
+	if !o.GetSemanticVersioning() {
+		return ""
+	}
+	return util.FirstNonDefault(
+		o.VersionScheme,
+		o.Defaults.VersionScheme,
+		o.HardDefaults.VersionScheme,
+		VersionSchemeSemVer)
 }
 
 // GetIntervalPointer returns a pointer to the interval between queries on this Service's version.
@@ -129,6 +146,12 @@ func (o *OptionsBase) CheckValues(prefix string) (errs error) {
 		}
 	}
 
+	// VersionScheme
+	if o.VersionScheme != "" && !IsVersionScheme(o.VersionScheme) {
+		errs = fmt.Errorf("%s%s  version_scheme: %q <invalid> (only [%s] are allowed)\\",
+			util.ErrorToString(errs), prefix, o.VersionScheme, strings.Join(VersionSchemes, ", "))
+	}
+
 	if errs != nil {
 		errs = fmt.Errorf("%soptions:\\%w",
 			prefix, errs)
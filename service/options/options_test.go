@@ -134,6 +134,9 @@ func TestOptions_GetSemanticVersioning(t *testing.T) {
 			wantBool:    true,
 			hardDefault: test.BoolPtr(true),
 		},
+		"all nil defaults to true": {
+			wantBool: true,
+		},
 	}
 
 	for name, tc := range tests {
@@ -157,6 +160,64 @@ func TestOptions_GetSemanticVersioning(t *testing.T) {
 	}
 }
 
+func TestOptions_GetVersionScheme(t *testing.T) {
+	// GIVEN Options
+	tests := map[string]struct {
+		semanticVersioning *bool
+		root               string
+		dfault             string
+		hardDefault        string
+		want               string
+	}{
+		"root overrides all": {
+			want:        "pep440",
+			root:        "pep440",
+			dfault:      "debian",
+			hardDefault: "calver",
+		},
+		"default overrides hardDefault": {
+			want:        "debian",
+			dfault:      "debian",
+			hardDefault: "calver",
+		},
+		"hardDefault is last resort": {
+			want:        "calver",
+			hardDefault: "calver",
+		},
+		"semver when not set": {
+			want: "semver",
+		},
+		"empty when semantic_versioning is disabled": {
+			want:               "",
+			semanticVersioning: test.BoolPtr(false),
+			root:               "pep440",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			options := testOptions()
+			if tc.semanticVersioning != nil {
+				options.SemanticVersioning = tc.semanticVersioning
+			}
+			options.VersionScheme = tc.root
+			options.Defaults.VersionScheme = tc.dfault
+			options.HardDefaults.VersionScheme = tc.hardDefault
+
+			// WHEN GetVersionScheme is called
+			got := options.GetVersionScheme()
+
+			// THEN the function returns the correct result
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
 func TestOptions_GetIntervalPointer(t *testing.T) {
 	// GIVEN options
 	tests := map[string]struct {
@@ -235,6 +296,18 @@ func TestOptions_CheckValues(t *testing.T) {
 				test.BoolPtr(false), "10x", test.BoolPtr(false),
 				nil, nil),
 		},
+		"valid version_scheme": {
+			errRegex: `^$`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					VersionScheme: "pep440"}},
+		},
+		"invalid version_scheme": {
+			errRegex: `version_scheme: "foo" <invalid> \(only \[calver, debian, numeric, pep440, semver\] are allowed\)`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					VersionScheme: "foo"}},
+		},
 		"seconds get appended to pure decimal interval": {
 			errRegex:     `^$`,
 			wantInterval: "10s",
@@ -0,0 +1,469 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opt
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Version schemes that can be used to compare versions.
+const (
+	VersionSchemeCalVer  = "calver"
+	VersionSchemeDebian  = "debian"
+	VersionSchemeNumeric = "numeric"
+	VersionSchemePEP440  = "pep440"
+	VersionSchemeSemVer  = "semver"
+)
+
+// VersionSchemes that are supported.
+var VersionSchemes = []string{
+	VersionSchemeCalVer,
+	VersionSchemeDebian,
+	VersionSchemeNumeric,
+	VersionSchemePEP440,
+	VersionSchemeSemVer}
+
+// Version is a version parsed with a version scheme.
+type Version interface {
+	// Compare returns -1, 0 or 1 when this Version is less than, equal to, or greater than `other`.
+	//
+	// `other` must have been parsed with the same scheme.
+	Compare(other Version) int
+	// PreRelease returns whether this Version is a pre-release.
+	PreRelease() bool
+	// String returns the version that was parsed.
+	String() string
+}
+
+// IsVersionScheme returns whether `scheme` is a supported version scheme.
+func IsVersionScheme(scheme string) bool {
+	for _, s := range VersionSchemes {
+		if s == scheme {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseVersion parses `version` with the `scheme` version scheme.
+func ParseVersion(scheme string, version string) (Version, error) {
+	switch scheme {
+	case VersionSchemeCalVer:
+		return parseCalVer(version)
+	case VersionSchemeDebian:
+		return parseDebian(version)
+	case VersionSchemeNumeric:
+		return parseNumeric(version)
+	case VersionSchemePEP440:
+		return parsePEP440(version)
+	case VersionSchemeSemVer:
+		return parseSemVer(version)
+	}
+	return nil, fmt.Errorf("unknown version_scheme %q", scheme)
+}
+
+// CompareVersions returns -1, 0 or 1 when `a` is less than, equal to, or greater than `b`
+// when compared with the `scheme` version scheme.
+func CompareVersions(scheme string, a, b string) (int, error) {
+	versionA, err := ParseVersion(scheme, a)
+	if err != nil {
+		return 0, err
+	}
+	versionB, err := ParseVersion(scheme, b)
+	if err != nil {
+		return 0, err
+	}
+	return versionA.Compare(versionB), nil
+}
+
+// sign returns -1, 0 or 1 for the sign of `i`.
+func sign(i int) int {
+	switch {
+	case i < 0:
+		return -1
+	case i > 0:
+		return 1
+	}
+	return 0
+}
+
+// compareNumbers compares two strings of digits numerically, without limiting their size.
+func compareNumbers(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return sign(len(a) - len(b))
+	}
+	return strings.Compare(a, b)
+}
+
+// compareNumberSegments compares two lists of numbers, with missing trailing segments treated as 0.
+func compareNumberSegments(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		segA, segB := "0", "0"
+		if i < len(a) {
+			segA = a[i]
+		}
+		if i < len(b) {
+			segB = b[i]
+		}
+		if cmp := compareNumbers(segA, segB); cmp != 0 {
+			return cmp
+		}
+	}
+	return 0
+}
+
+// semVer is a Version following https://semver.org.
+type semVer struct {
+	version *semver.Version
+}
+
+func parseSemVer(version string) (Version, error) {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return nil, fmt.Errorf("failed converting %q to a semantic version: %w",
+			version, err)
+	}
+	return semVer{version: v}, nil
+}
+
+func (v semVer) Compare(other Version) int { return v.version.Compare(other.(semVer).version) }
+func (v semVer) PreRelease() bool          { return v.version.Prerelease() != "" }
+func (v semVer) String() string            { return v.version.Original() }
+
+// numericVersion is a Version of numeric segments, e.g. 1.2.3.4,
+// with an optional suffix that marks it as a pre-release, e.g. 1.2.3.4-beta.
+type numericVersion struct {
+	original string
+	segments []string
+	suffix   string
+}
+
+// numericRegex matches the numeric segments of a version, and any suffix.
+var numericRegex = regexp.MustCompile(`^[vV]?([0-9]+(?:[._-][0-9]+)*)(?:[._+-]?(.+))?$`)
+
+// numericSplitRegex splits the numeric segments of a version.
+var numericSplitRegex = regexp.MustCompile(`[._-]`)
+
+func parseNumeric(version string) (Version, error) {
+	trimmed := strings.TrimSpace(version)
+	parts := numericRegex.FindStringSubmatch(trimmed)
+	if parts == nil {
+		return nil, fmt.Errorf("failed converting %q to a numeric version: must start with a number",
+			version)
+	}
+	return numericVersion{
+		original: version,
+		segments: numericSplitRegex.Split(parts[1], -1),
+		suffix:   parts[2]}, nil
+}
+
+func (v numericVersion) Compare(other Version) int {
+	var o numericVersion
+	switch ov := other.(type) {
+	case numericVersion:
+		o = ov
+	case calVer:
+		o = ov.numericVersion
+	}
+	if cmp := compareNumberSegments(v.segments, o.segments); cmp != 0 {
+		return cmp
+	}
+	// A version with a suffix is older than one without.
+	switch {
+	case v.suffix == o.suffix:
+		return 0
+	case v.suffix == "":
+		return 1
+	case o.suffix == "":
+		return -1
+	}
+	return sign(strings.Compare(v.suffix, o.suffix))
+}
+func (v numericVersion) PreRelease() bool { return v.suffix != "" }
+func (v numericVersion) String() string   { return v.original }
+
+// calVer is a Version following https://calver.org, e.g. 2024.10.1 or 24.04.
+type calVer struct {
+	numericVersion
+}
+
+func parseCalVer(version string) (Version, error) {
+	parsed, err := parseNumeric(version)
+	if err != nil {
+		return nil, fmt.Errorf("failed converting %q to a calendar version: must start with the year",
+			version)
+	}
+	numeric := parsed.(numericVersion)
+	// YY, 0Y or YYYY.
+	if year := numeric.segments[0]; len(year) != 2 && len(year) != 4 {
+		return nil, fmt.Errorf("failed converting %q to a calendar version: %q is not a year (YY/YYYY)",
+			version, year)
+	}
+	return calVer{numericVersion: numeric}, nil
+}
+
+func (v calVer) Compare(other Version) int { return v.numericVersion.Compare(other) }
+
+// pep440Version is a Version following https://peps.python.org/pep-0440.
+type pep440Version struct {
+	original string
+	epoch    string
+	release  []string
+	preLabel int // 0 = none, 1 = a, 2 = b, 3 = rc
+	preN     string
+	post     *string
+	dev      *string
+	local    []string
+}
+
+// pep440Regex matches a PEP 440 version (including the alternative spellings it allows).
+var pep440Regex = regexp.MustCompile(`^v?` +
+	`(?:([0-9]+)!)?` + // epoch
+	`([0-9]+(?:\.[0-9]+)*)` + // release
+	`(?:[-_.]?(alpha|a|beta|b|preview|pre|c|rc)[-_.]?([0-9]+)?)?` + // pre-release
+	`(?:-([0-9]+)|[-_.]?(post|rev|r)[-_.]?([0-9]+)?)?` + // post-release
+	`(?:[-_.]?(dev)[-_.]?([0-9]+)?)?` + // dev-release
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`) // local
+
+func parsePEP440(version string) (Version, error) {
+	parts := pep440Regex.FindStringSubmatch(strings.ToLower(strings.TrimSpace(version)))
+	if parts == nil {
+		return nil, fmt.Errorf("failed converting %q to a PEP 440 version",
+			version)
+	}
+
+	v := pep440Version{
+		original: version,
+		epoch:    parts[1],
+		release:  strings.Split(parts[2], ".")}
+	// Pre-release
+	switch parts[3] {
+	case "a", "alpha":
+		v.preLabel = 1
+	case "b", "beta":
+		v.preLabel = 2
+	case "c", "rc", "pre", "preview":
+		v.preLabel = 3
+	}
+	v.preN = parts[4]
+	// Post-release
+	if parts[5] != "" {
+		v.post = &parts[5]
+	} else if parts[6] != "" {
+		v.post = &parts[7]
+	}
+	// Dev-release
+	if parts[8] != "" {
+		v.dev = &parts[9]
+	}
+	// Local
+	if parts[10] != "" {
+		v.local = numericSplitRegex.Split(parts[10], -1)
+	}
+	return v, nil
+}
+
+// pep440Rank returns a rank for the pre/post/dev parts of `v`, so that
+// X.devN < X.aN < X.bN < X.rcN < X < X.postN.
+func (v pep440Version) pep440Rank() int {
+	switch {
+	case v.preLabel == 0 && v.post == nil && v.dev != nil:
+		return -1
+	case v.preLabel == 0:
+		return 4
+	}
+	return v.preLabel
+}
+
+func (v pep440Version) Compare(other Version) int {
+	o := other.(pep440Version)
+	if cmp := compareNumbers(v.epoch, o.epoch); cmp != 0 {
+		return cmp
+	}
+	if cmp := compareNumberSegments(v.release, o.release); cmp != 0 {
+		return cmp
+	}
+	// Pre-release
+	if cmp := sign(v.pep440Rank() - o.pep440Rank()); cmp != 0 {
+		return cmp
+	}
+	if cmp := compareNumbers(v.preN, o.preN); cmp != 0 {
+		return cmp
+	}
+	// Post-release (none is older than any)
+	switch {
+	case v.post == nil && o.post != nil:
+		return -1
+	case v.post != nil && o.post == nil:
+		return 1
+	case v.post != nil:
+		if cmp := compareNumbers(*v.post, *o.post); cmp != 0 {
+			return cmp
+		}
+	}
+	// Dev-release (none is newer than any)
+	switch {
+	case v.dev == nil && o.dev != nil:
+		return 1
+	case v.dev != nil && o.dev == nil:
+		return -1
+	case v.dev != nil:
+		if cmp := compareNumbers(*v.dev, *o.dev); cmp != 0 {
+			return cmp
+		}
+	}
+	return comparePEP440Local(v.local, o.local)
+}
+
+// comparePEP440Local compares the local segments of two PEP 440 versions.
+//
+// Numeric segments are newer than alphanumeric ones, and a longer local is newer if all others match.
+func comparePEP440Local(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		aNumeric := strings.Trim(a[i], "0123456789") == ""
+		bNumeric := strings.Trim(b[i], "0123456789") == ""
+		var cmp int
+		switch {
+		case aNumeric && bNumeric:
+			cmp = compareNumbers(a[i], b[i])
+		case aNumeric:
+			cmp = 1
+		case bNumeric:
+			cmp = -1
+		default:
+			cmp = strings.Compare(a[i], b[i])
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+	return sign(len(a) - len(b))
+}
+func (v pep440Version) PreRelease() bool { return v.preLabel != 0 || v.dev != nil }
+func (v pep440Version) String() string   { return v.original }
+
+// debianVersion is a Version following https://www.debian.org/doc/debian-policy/ch-controlfields.html#version
+// ([epoch:]upstream_version[-debian_revision]).
+type debianVersion struct {
+	original string
+	epoch    string
+	upstream string
+	revision string
+}
+
+// debianUpstreamRegex matches a valid upstream_version.
+var debianUpstreamRegex = regexp.MustCompile(`^[0-9][A-Za-z0-9.+~:-]*$`)
+
+func parseDebian(version string) (Version, error) {
+	v := debianVersion{original: version}
+	remaining := strings.TrimSpace(version)
+	if remaining == "" {
+		return nil, errors.New("failed converting \"\" to a Debian version: empty version")
+	}
+
+	// [epoch:]
+	if i := strings.Index(remaining, ":"); i != -1 {
+		v.epoch = remaining[:i]
+		if v.epoch == "" || strings.Trim(v.epoch, "0123456789") != "" {
+			return nil, fmt.Errorf("failed converting %q to a Debian version: epoch %q is not a number",
+				version, v.epoch)
+		}
+		remaining = remaining[i+1:]
+	}
+	// [-debian_revision]
+	if i := strings.LastIndex(remaining, "-"); i != -1 {
+		v.revision = remaining[i+1:]
+		remaining = remaining[:i]
+	}
+	v.upstream = remaining
+	if !debianUpstreamRegex.MatchString(v.upstream) {
+		return nil, fmt.Errorf("failed converting %q to a Debian version: upstream_version %q must start with a digit",
+			version, v.upstream)
+	}
+	return v, nil
+}
+
+func (v debianVersion) Compare(other Version) int {
+	o := other.(debianVersion)
+	if cmp := compareNumbers(v.epoch, o.epoch); cmp != 0 {
+		return cmp
+	}
+	if cmp := debianCompare(v.upstream, o.upstream); cmp != 0 {
+		return cmp
+	}
+	return debianCompare(v.revision, o.revision)
+}
+func (v debianVersion) PreRelease() bool { return strings.Contains(v.upstream, "~") }
+func (v debianVersion) String() string   { return v.original }
+
+// debianOrder returns the sort weight of a non-digit character in a Debian version.
+//
+// '~' sorts before anything (even the end of the string), then letters, then everything else.
+func debianOrder(s string, i int) int {
+	if i >= len(s) {
+		return 0
+	}
+	c := s[i]
+	switch {
+	case c >= '0' && c <= '9':
+		return 0
+	case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		return int(c)
+	case c == '~':
+		return -1
+	}
+	return int(c) + 256
+}
+
+// isDigitAt returns whether `s` has a digit at index `i`.
+func isDigitAt(s string, i int) bool {
+	return i < len(s) && s[i] >= '0' && s[i] <= '9'
+}
+
+// debianCompare compares two upstream_version/debian_revision strings with the dpkg algorithm.
+func debianCompare(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		// Non-digit prefix
+		for (i < len(a) && !isDigitAt(a, i)) || (j < len(b) && !isDigitAt(b, j)) {
+			if cmp := debianOrder(a, i) - debianOrder(b, j); cmp != 0 {
+				return sign(cmp)
+			}
+			i++
+			j++
+		}
+		i, j = min(i, len(a)), min(j, len(b))
+		// Numeric part
+		startA := i
+		for isDigitAt(a, i) {
+			i++
+		}
+		startB := j
+		for isDigitAt(b, j) {
+			j++
+		}
+		if cmp := compareNumbers(a[startA:i], b[startB:j]); cmp != 0 {
+			return cmp
+		}
+	}
+	return 0
+}
@@ -0,0 +1,162 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package opt
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/release-argus/Argus/util"
+)
+
+func TestParseVersion(t *testing.T) {
+	// GIVEN a version and a version scheme
+	tests := map[string]struct {
+		scheme, version string
+		wantPreRelease  bool
+		errRegex        string
+	}{
+		"semver":                  {scheme: "semver", version: "v1.2.3"},
+		"semver pre-release":      {scheme: "semver", version: "1.2.3-rc.1", wantPreRelease: true},
+		"semver invalid":          {scheme: "semver", version: "foo", errRegex: `failed converting "foo" to a semantic version`},
+		"calver YYYY":             {scheme: "calver", version: "2024.10.1"},
+		"calver YY":               {scheme: "calver", version: "24.04"},
+		"calver pre-release":      {scheme: "calver", version: "2024.10-beta", wantPreRelease: true},
+		"calver not a year":       {scheme: "calver", version: "123.4", errRegex: `"123" is not a year`},
+		"calver no number":        {scheme: "calver", version: "latest", errRegex: `must start with the year`},
+		"numeric":                 {scheme: "numeric", version: "10.0.19045.3803"},
+		"numeric with suffix":     {scheme: "numeric", version: "1.2.3.4-hotfix", wantPreRelease: true},
+		"numeric invalid":         {scheme: "numeric", version: "v", errRegex: `must start with a number`},
+		"pep440":                  {scheme: "pep440", version: "1!2.0.post1+local.7"},
+		"pep440 pre-release":      {scheme: "pep440", version: "2.0rc1", wantPreRelease: true},
+		"pep440 dev-release":      {scheme: "pep440", version: "2.0.dev3", wantPreRelease: true},
+		"pep440 invalid":          {scheme: "pep440", version: "2.0-foo", errRegex: `failed converting "2.0-foo" to a PEP 440 version`},
+		"debian":                  {scheme: "debian", version: "1:2.30-1ubuntu1"},
+		"debian pre-release":      {scheme: "debian", version: "2.0~rc1-1", wantPreRelease: true},
+		"debian invalid epoch":    {scheme: "debian", version: "a:1.0", errRegex: `epoch "a" is not a number`},
+		"debian invalid upstream": {scheme: "debian", version: "v1.0", errRegex: `upstream_version "v1.0" must start with a digit`},
+		"unknown scheme":          {scheme: "foo", version: "1.0", errRegex: `unknown version_scheme "foo"`},
+		"debian empty":            {scheme: "debian", version: "", errRegex: `empty version`},
+		"numeric with a prefix V": {scheme: "numeric", version: "V2"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+
+			// WHEN ParseVersion is called
+			got, err := ParseVersion(tc.scheme, tc.version)
+
+			// THEN it errors when expected
+			e := util.ErrorToString(err)
+			if !regexp.MustCompile(tc.errRegex).MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if err != nil {
+				return
+			}
+			// AND the version is returned as given
+			if got.String() != tc.version {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.version, got.String())
+			}
+			// AND the pre-release is detected
+			if got.PreRelease() != tc.wantPreRelease {
+				t.Errorf("PreRelease() - want: %t\ngot:  %t",
+					tc.wantPreRelease, got.PreRelease())
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	// GIVEN two versions and a version scheme
+	tests := map[string]struct {
+		scheme, a, b string
+		want         int
+		errRegex     string
+	}{
+		"semver less":                   {scheme: "semver", a: "1.2.9", b: "1.2.10", want: -1},
+		"semver equal with prefix":      {scheme: "semver", a: "v1.2.3", b: "1.2.3", want: 0},
+		"semver pre-release older":      {scheme: "semver", a: "1.2.3-rc.1", b: "1.2.3", want: -1},
+		"semver invalid a":              {scheme: "semver", a: "foo", b: "1.2.3", errRegex: `"foo"`},
+		"semver invalid b":              {scheme: "semver", a: "1.2.3", b: "bar", errRegex: `"bar"`},
+		"calver greater":                {scheme: "calver", a: "2024.10.1", b: "2024.9.30", want: 1},
+		"calver missing segment is 0":   {scheme: "calver", a: "2024.10", b: "2024.10.0", want: 0},
+		"calver pre-release older":      {scheme: "calver", a: "2024.10-beta", b: "2024.10", want: -1},
+		"numeric many segments":         {scheme: "numeric", a: "10.0.19045.3803", b: "10.0.19045.3930", want: -1},
+		"numeric large numbers":         {scheme: "numeric", a: "99999999999999999999", b: "100000000000000000000", want: -1},
+		"numeric leading zeros":         {scheme: "numeric", a: "1.02", b: "1.2", want: 0},
+		"numeric suffixes":              {scheme: "numeric", a: "1.0-alpha", b: "1.0-beta", want: -1},
+		"pep440 trailing zeros":         {scheme: "pep440", a: "1.0", b: "1.0.0", want: 0},
+		"pep440 normalised spelling":    {scheme: "pep440", a: "1.0-ALPHA.1", b: "1.0a1", want: 0},
+		"pep440 dev < pre":              {scheme: "pep440", a: "1.0.dev1", b: "1.0a1", want: -1},
+		"pep440 pre < rc":               {scheme: "pep440", a: "1.0b2", b: "1.0rc1", want: -1},
+		"pep440 rc < release":           {scheme: "pep440", a: "1.0rc1", b: "1.0", want: -1},
+		"pep440 release < post":         {scheme: "pep440", a: "1.0", b: "1.0.post1", want: -1},
+		"pep440 implicit post":          {scheme: "pep440", a: "1.0-1", b: "1.0.post1", want: 0},
+		"pep440 post.dev < post":        {scheme: "pep440", a: "1.0.post1.dev1", b: "1.0.post1", want: -1},
+		"pep440 epoch wins":             {scheme: "pep440", a: "1!1.0", b: "2.0", want: 1},
+		"pep440 local > none":           {scheme: "pep440", a: "1.0+1", b: "1.0", want: 1},
+		"pep440 local numeric > alpha":  {scheme: "pep440", a: "1.0+abc", b: "1.0+1", want: -1},
+		"debian revision":               {scheme: "debian", a: "2.30-1ubuntu1", b: "2.30-1ubuntu2", want: -1},
+		"debian tilde before release":   {scheme: "debian", a: "2.0~rc1", b: "2.0", want: -1},
+		"debian tilde before tilde":     {scheme: "debian", a: "2.0~~", b: "2.0~", want: -1},
+		"debian letters before symbols": {scheme: "debian", a: "1.0a", b: "1.0+", want: -1},
+		"debian epoch wins":             {scheme: "debian", a: "1:1.0", b: "9.0", want: 1},
+		"debian numeric segments":       {scheme: "debian", a: "1.10", b: "1.9", want: 1},
+		"debian equal":                  {scheme: "debian", a: "0:1.0-1", b: "1.0-1", want: 0},
+		"debian longer is newer":        {scheme: "debian", a: "1.0", b: "1.0.1", want: -1},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+
+			// WHEN CompareVersions is called
+			got, err := CompareVersions(tc.scheme, tc.a, tc.b)
+
+			// THEN it errors when expected
+			e := util.ErrorToString(err)
+			if !regexp.MustCompile(tc.errRegex).MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the comparison is correct
+			if got != tc.want {
+				t.Errorf("want: %d\ngot:  %d",
+					tc.want, got)
+			}
+			// AND the reverse comparison is the opposite
+			if err == nil {
+				if reverse, _ := CompareVersions(tc.scheme, tc.b, tc.a); reverse != -tc.want {
+					t.Errorf("reverse - want: %d\ngot:  %d",
+						-tc.want, reverse)
+				}
+			}
+		})
+	}
+}
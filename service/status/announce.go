@@ -90,7 +90,8 @@ func (s *Status) AnnounceUpdate() {
 			ID: *s.ServiceID,
 			Status: &api_type.Status{
 				DeployedVersion:          s.DeployedVersion(),
-				DeployedVersionTimestamp: s.DeployedVersionTimestamp()}}})
+				DeployedVersionTimestamp: s.DeployedVersionTimestamp(),
+				DeployedTargets:          s.DeployedTargetsSummary()}}})
 
 	s.SendAnnounce(&payloadData)
 }
@@ -112,3 +113,47 @@ func (s *Status) announceApproved() {
 
 	s.SendAnnounce(&payloadData)
 }
+
+// announceVerifyFailure to the `s.AnnounceChannel`
+// (Broadcast to all WebSocket clients).
+func (s *Status) announceVerifyFailure() {
+	var payloadData []byte
+
+	// Empty VerifyFailure when it's been cleared
+	verifyFailure := s.verifyFailure()
+	if verifyFailure == nil {
+		verifyFailure = &api_type.VerifyFailure{}
+	}
+	payloadData, _ = json.Marshal(api_type.WebSocketMessage{
+		Page:    "APPROVALS",
+		Type:    "VERSION",
+		SubType: "VERIFY",
+		ServiceData: &api_type.ServiceSummary{
+			ID: *s.ServiceID,
+			Status: &api_type.Status{
+				VerifyFailure: verifyFailure}}})
+
+	s.SendAnnounce(&payloadData)
+}
+
+// announcePending version to the `s.AnnounceChannel`
+// (Broadcast to all WebSocket clients).
+func (s *Status) announcePending() {
+	var payloadData []byte
+
+	// Empty PendingVersion when it's been cleared
+	pendingVersion := s.pendingVersionSummary()
+	if pendingVersion == nil {
+		pendingVersion = &api_type.PendingVersion{}
+	}
+	payloadData, _ = json.Marshal(api_type.WebSocketMessage{
+		Page:    "APPROVALS",
+		Type:    "VERSION",
+		SubType: "PENDING",
+		ServiceData: &api_type.ServiceSummary{
+			ID: *s.ServiceID,
+			Status: &api_type.Status{
+				PendingVersion: pendingVersion}}})
+
+	s.SendAnnounce(&payloadData)
+}
@@ -0,0 +1,212 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcstatus
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+	opt "github.com/release-argus/Argus/service/options"
+	api_type "github.com/release-argus/Argus/web/api/types"
+)
+
+// DeployedTarget is the deployed version of a named target of a Service.
+type DeployedTarget struct {
+	Name      string `json:"name"`                // Name of the target.
+	Version   string `json:"version,omitempty"`   // Version deployed to the target.
+	Timestamp string `json:"timestamp,omitempty"` // UTC timestamp of Version being changed.
+}
+
+// InitDeployedTargets sets how the versions of the targets give the DeployedVersion,
+// and the `names` of the targets (removing any others).
+func (s *Status) InitDeployedTargets(aggregate string, names []string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.deployedAggregate = aggregate
+	targets := make([]DeployedTarget, 0, len(names))
+	for _, name := range names {
+		target := DeployedTarget{Name: name}
+		if index := s.deployedTargetIndex(name); index != -1 {
+			target = s.deployedTargets[index]
+		}
+		targets = append(targets, target)
+	}
+	slices.SortFunc(targets, func(a, b DeployedTarget) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	s.deployedTargets = targets
+}
+
+// SetDeployedTargets sets the versions of the targets initialised with InitDeployedTargets
+// to those in `targets` (e.g. from the database).
+func (s *Status) SetDeployedTargets(targets []DeployedTarget) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, target := range targets {
+		if index := s.deployedTargetIndex(target.Name); index != -1 {
+			s.deployedTargets[index].Version = target.Version
+			s.deployedTargets[index].Timestamp = target.Timestamp
+		}
+	}
+}
+
+// DeployedTargets returns the deployed versions of the targets.
+func (s *Status) DeployedTargets() []DeployedTarget {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if len(s.deployedTargets) == 0 {
+		return nil
+	}
+	return slices.Clone(s.deployedTargets)
+}
+
+// DeployedTargetVersion returns the version deployed to the target `name`.
+func (s *Status) DeployedTargetVersion(name string) string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if index := s.deployedTargetIndex(name); index != -1 {
+		return s.deployedTargets[index].Version
+	}
+	return ""
+}
+
+// SetDeployedTargetVersion sets the version deployed to the target `name`,
+// updating DeployedVersion if that changes the version the targets give.
+func (s *Status) SetDeployedTargetVersion(name string, version string, writeToDB bool) {
+	s.mutex.Lock()
+	index := s.deployedTargetIndex(name)
+	if index == -1 {
+		s.mutex.Unlock()
+		return
+	}
+	s.deployedTargets[index].Version = version
+	s.deployedTargets[index].Timestamp = time.Now().UTC().Format(time.RFC3339)
+	deployedVersion := s.aggregateDeployedVersion()
+	changed := deployedVersion != "" && deployedVersion != s.deployedVersion
+	s.mutex.Unlock()
+
+	if writeToDB {
+		s.mutex.RLock()
+		targets, _ := json.Marshal(s.deployedTargets)
+		message := dbtype.Message{
+			ServiceID: *s.ServiceID,
+			Cells: []dbtype.Cell{
+				{Column: "deployed_targets", Value: string(targets)}}}
+		s.sendDatabase(&message)
+		s.mutex.RUnlock()
+	}
+
+	if changed {
+		s.SetDeployedVersion(deployedVersion, writeToDB)
+	}
+}
+
+// DeployedTargetsSummary returns the deployed versions of the targets for the API.
+func (s *Status) DeployedTargetsSummary() *[]api_type.DeployedTarget {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if len(s.deployedTargets) == 0 {
+		return nil
+	}
+	targets := make([]api_type.DeployedTarget, len(s.deployedTargets))
+	for i, target := range s.deployedTargets {
+		targets[i] = api_type.DeployedTarget{
+			Name:      target.Name,
+			Version:   target.Version,
+			Timestamp: target.Timestamp}
+	}
+	return &targets
+}
+
+// deployedTargetIndex returns the index of the target `name` in deployedTargets (-1 if not found).
+func (s *Status) deployedTargetIndex(name string) int {
+	return slices.IndexFunc(s.deployedTargets, func(target DeployedTarget) bool {
+		return target.Name == name
+	})
+}
+
+// aggregateDeployedVersion returns the version that the deployedTargets give with the deployedAggregate:
+//
+//	all    - LatestVersion when every target is on it, otherwise the version of the first target that isn't.
+//	any    - LatestVersion when any target is on it, otherwise the newest version of the targets.
+//	oldest - The oldest version of the targets.
+//
+// Targets without a version are ignored. Without a version_scheme to compare versions,
+// any/oldest use the version of the first target that isn't on LatestVersion.
+func (s *Status) aggregateDeployedVersion() string {
+	versions := make([]string, 0, len(s.deployedTargets))
+	for _, target := range s.deployedTargets {
+		if target.Version != "" {
+			versions = append(versions, target.Version)
+		}
+	}
+	if len(versions) == 0 {
+		return ""
+	}
+
+	// The first version that isn't the LatestVersion (or LatestVersion if all are).
+	firstBehind := s.latestVersion
+	for _, version := range versions {
+		if !s.sameVersion(s.latestVersion, version) {
+			firstBehind = version
+			break
+		}
+	}
+
+	switch s.deployedAggregate {
+	case "any":
+		for _, version := range versions {
+			if s.sameVersion(s.latestVersion, version) {
+				return version
+			}
+		}
+		if newest, ok := s.extremeVersion(versions, 1); ok {
+			return newest
+		}
+	case "oldest":
+		if oldest, ok := s.extremeVersion(versions, -1); ok {
+			return oldest
+		}
+	}
+	return firstBehind
+}
+
+// extremeVersion returns the newest (`sign`=1) or oldest (`sign`=-1) of `versions`,
+// and whether they could all be compared with the version scheme.
+func (s *Status) extremeVersion(versions []string, sign int) (string, bool) {
+	if s.versionScheme == "" {
+		return "", false
+	}
+
+	extreme := versions[0]
+	for _, version := range versions[1:] {
+		cmp, err := opt.CompareVersions(s.versionScheme, version, extreme)
+		if err != nil {
+			return "", false
+		}
+		if cmp*sign > 0 {
+			extreme = version
+		}
+	}
+	return extreme, true
+}
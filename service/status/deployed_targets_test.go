@@ -0,0 +1,261 @@
+// Copyright [2024] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package svcstatus
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/test"
+	metric "github.com/release-argus/Argus/web/metrics"
+)
+
+func TestStatus_SetDeployedTargetVersion(t *testing.T) {
+	// GIVEN a Status with deployed targets
+	tests := map[string]struct {
+		aggregate           string
+		versionScheme       string
+		latestVersion       string
+		targets             map[string]string
+		wantDeployedVersion string
+		wantMetric          float64
+	}{
+		"all - every target on the latest version": {
+			aggregate:           "all",
+			latestVersion:       "1.2.3",
+			targets:             map[string]string{"eu": "1.2.3", "us": "1.2.3"},
+			wantDeployedVersion: "1.2.3",
+			wantMetric:          1,
+		},
+		"all - one target behind": {
+			aggregate:           "all",
+			latestVersion:       "1.2.3",
+			targets:             map[string]string{"eu": "1.2.3", "us": "1.2.2"},
+			wantDeployedVersion: "1.2.2",
+			wantMetric:          0,
+		},
+		"all - targets without a version are ignored": {
+			aggregate:           "all",
+			latestVersion:       "1.2.3",
+			targets:             map[string]string{"eu": "1.2.3", "us": ""},
+			wantDeployedVersion: "1.2.3",
+			wantMetric:          1,
+		},
+		"any - one target on the latest version": {
+			aggregate:           "any",
+			latestVersion:       "1.2.3",
+			targets:             map[string]string{"eu": "1.2.1", "us": "1.2.3"},
+			wantDeployedVersion: "1.2.3",
+			wantMetric:          1,
+		},
+		"any - no target on the latest version gives the newest": {
+			aggregate:           "any",
+			versionScheme:       "semver",
+			latestVersion:       "1.2.3",
+			targets:             map[string]string{"eu": "1.2.2", "us": "1.2.1"},
+			wantDeployedVersion: "1.2.2",
+			wantMetric:          0,
+		},
+		"any - no target on the latest version without a version_scheme": {
+			aggregate:           "any",
+			latestVersion:       "1.2.3",
+			targets:             map[string]string{"eu": "1.2.1", "us": "1.2.2"},
+			wantDeployedVersion: "1.2.1",
+			wantMetric:          0,
+		},
+		"oldest - gives the oldest version": {
+			aggregate:           "oldest",
+			versionScheme:       "semver",
+			latestVersion:       "1.10.0",
+			targets:             map[string]string{"ap": "1.10.0", "eu": "1.9.0", "us": "1.2.0"},
+			wantDeployedVersion: "1.2.0",
+			wantMetric:          0,
+		},
+		"oldest - every target on the latest version": {
+			aggregate:           "oldest",
+			versionScheme:       "semver",
+			latestVersion:       "1.10.0",
+			targets:             map[string]string{"eu": "1.10.0", "us": "1.10.0"},
+			wantDeployedVersion: "1.10.0",
+			wantMetric:          1,
+		},
+		"oldest - versions that can't be compared": {
+			aggregate:           "oldest",
+			versionScheme:       "semver",
+			latestVersion:       "1.10.0",
+			targets:             map[string]string{"eu": "1.10.0", "us": "latest"},
+			wantDeployedVersion: "latest",
+			wantMetric:          0,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			status := testStatus()
+			status.ServiceID = test.StringPtr(name)
+			databaseChannel := make(chan dbtype.Message, 20)
+			status.DatabaseChannel = &databaseChannel
+			status.SetVersionScheme(tc.versionScheme)
+			status.SetLatestVersion(tc.latestVersion, false)
+			status.SetDeployedVersion("", false)
+			names := make([]string, 0, len(tc.targets))
+			for target := range tc.targets {
+				names = append(names, target)
+			}
+			status.InitDeployedTargets(tc.aggregate, names)
+
+			// WHEN SetDeployedTargetVersion is called for each target
+			for target, version := range tc.targets {
+				status.SetDeployedTargetVersion(target, version, true)
+			}
+
+			// THEN the DeployedVersion is that given by the aggregate
+			if got := status.DeployedVersion(); got != tc.wantDeployedVersion {
+				t.Errorf("want DeployedVersion=%q, not %q",
+					tc.wantDeployedVersion, got)
+			}
+			// AND the latest_version_is_deployed metric uses that DeployedVersion
+			got := testutil.ToFloat64(metric.LatestVersionIsDeployed.WithLabelValues(name))
+			if got != tc.wantMetric {
+				t.Errorf("want latest_version_is_deployed=%f, not %f",
+					tc.wantMetric, got)
+			}
+			// AND the versions of the targets are stored
+			for target, version := range tc.targets {
+				if got := status.DeployedTargetVersion(target); got != version {
+					t.Errorf("want %q to be on %q, not %q",
+						target, version, got)
+				}
+			}
+			// AND the targets are sent to the database
+			var gotTargets []DeployedTarget
+			for len(*status.DatabaseChannel) != 0 {
+				message := <-*status.DatabaseChannel
+				for _, cell := range message.Cells {
+					if cell.Column == "deployed_targets" {
+						gotTargets = nil
+						json.Unmarshal([]byte(cell.Value), &gotTargets)
+					}
+				}
+			}
+			if len(gotTargets) != len(tc.targets) {
+				t.Fatalf("want %d targets in the database, not %v",
+					len(tc.targets), gotTargets)
+			}
+			for _, target := range gotTargets {
+				if target.Version != tc.targets[target.Name] {
+					t.Errorf("want %q to be on %q in the database, not %q",
+						target.Name, tc.targets[target.Name], target.Version)
+				}
+			}
+		})
+	}
+}
+
+func TestStatus_SetDeployedTargetVersion_ResetsApprovedVersion(t *testing.T) {
+	// GIVEN a Status with every deployed target needing the approved LatestVersion
+	status := testStatus()
+	status.SetLatestVersion("1.2.3", false)
+	status.SetApprovedVersion("1.2.3", false)
+	status.InitDeployedTargets("all", []string{"eu", "us"})
+	status.SetDeployedTargetVersion("eu", "1.2.2", false)
+	status.SetDeployedTargetVersion("us", "1.2.2", false)
+
+	// WHEN only some of the targets are updated to the LatestVersion
+	status.SetDeployedTargetVersion("eu", "1.2.3", false)
+
+	// THEN the LatestVersion is still approved
+	if got := status.ApprovedVersion(); got != "1.2.3" {
+		t.Errorf("want ApprovedVersion=%q, not %q",
+			"1.2.3", got)
+	}
+
+	// WHEN every target is updated to the LatestVersion
+	status.SetDeployedTargetVersion("us", "1.2.3", false)
+
+	// THEN the LatestVersion is deployed
+	if got := status.DeployedVersion(); got != "1.2.3" {
+		t.Errorf("want DeployedVersion=%q, not %q",
+			"1.2.3", got)
+	}
+	// AND the approval is cleared
+	if got := status.ApprovedVersion(); got != "" {
+		t.Errorf("want ApprovedVersion to be cleared, not %q",
+			got)
+	}
+}
+
+func TestStatus_SetLatestVersion_DeployedTargets(t *testing.T) {
+	// GIVEN a Status with deployed targets on the LatestVersion
+	status := testStatus()
+	status.SetLatestVersion("1.2.3", false)
+	status.InitDeployedTargets("all", []string{"eu", "us"})
+	status.SetDeployedTargetVersion("eu", "1.2.3", false)
+	status.SetDeployedTargetVersion("us", "1.2.3", false)
+
+	// WHEN the LatestVersion is set to a version one of the targets is on
+	status.SetDeployedTargetVersion("eu", "1.2.4", false)
+	status.SetLatestVersion("1.2.4", false)
+
+	// THEN the DeployedVersion is the version of the target not on it
+	if got := status.DeployedVersion(); got != "1.2.3" {
+		t.Errorf("want DeployedVersion=%q, not %q",
+			"1.2.3", got)
+	}
+}
+
+func TestStatus_InitDeployedTargets(t *testing.T) {
+	// GIVEN a Status with deployed targets
+	status := testStatus()
+	status.InitDeployedTargets("all", []string{"us", "eu"})
+	status.SetDeployedTargetVersion("eu", "1.2.3", false)
+	status.SetDeployedTargetVersion("us", "1.2.4", false)
+
+	// WHEN InitDeployedTargets is called with a target removed and another added
+	status.InitDeployedTargets("any", []string{"us", "ap"})
+
+	// THEN the targets are those given, sorted by name
+	got := status.DeployedTargets()
+	if len(got) != 2 || got[0].Name != "ap" || got[1].Name != "us" {
+		t.Fatalf("want targets [ap, us], not %v",
+			got)
+	}
+	// AND the versions of the targets kept are kept
+	if got[0].Version != "" || got[1].Version != "1.2.4" {
+		t.Errorf("want versions [\"\", \"1.2.4\"], not %v",
+			got)
+	}
+	// AND SetDeployedTargets only sets the versions of known targets
+	status.SetDeployedTargets([]DeployedTarget{
+		{Name: "ap", Version: "1.2.5", Timestamp: "2020-01-01T00:00:00Z"},
+		{Name: "eu", Version: "1.2.3", Timestamp: "2020-01-01T00:00:00Z"}})
+	got = status.DeployedTargets()
+	if len(got) != 2 || got[0].Version != "1.2.5" || got[0].Timestamp != "2020-01-01T00:00:00Z" {
+		t.Errorf("want ap to be on \"1.2.5\", not %v",
+			got)
+	}
+	// AND the summary is of those targets
+	summary := status.DeployedTargetsSummary()
+	if summary == nil || len(*summary) != 2 || (*summary)[0].Version != "1.2.5" {
+		t.Errorf("want summary of %v, not %v",
+			got, summary)
+	}
+}
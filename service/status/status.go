@@ -22,7 +22,9 @@ import (
 	"time"
 
 	dbtype "github.com/release-argus/Argus/db/types"
+	opt "github.com/release-argus/Argus/service/options"
 	"github.com/release-argus/Argus/util"
+	api_type "github.com/release-argus/Argus/web/api/types"
 	metric "github.com/release-argus/Argus/web/metrics"
 )
 
@@ -52,6 +54,12 @@ func NewStatusDefaults(
 			SaveChannel:     saveChannel}}
 }
 
+// ChangelogEntry is the release notes of a version in a changelog.
+type ChangelogEntry struct {
+	Version string // The version released.
+	Notes   string // Release notes of that version.
+}
+
 // Status is the current state of the Service element (version and regex misses).
 type Status struct {
 	statusBase `yaml:"-" json:"-"`
@@ -59,17 +67,33 @@ type Status struct {
 	ServiceID *string `yaml:"-" json:"-"` // ID of the Service
 	WebURL    *string `yaml:"-" json:"-"` // Web URL of the Service
 
-	approvedVersion          string       // The version that's been approved
-	deployedVersion          string       // Track the deployed version of the service from the last successful WebHook.
-	deployedVersionTimestamp string       // UTC timestamp of DeployedVersion being changed.
-	latestVersion            string       // Latest version found from query().
-	latestVersionTimestamp   string       // UTC timestamp of LatestVersion being changed.
-	lastQueried              string       // UTC timestamp that version was last queried/checked.
-	regexMissesContent       uint         // Counter for the number of regex misses on URL content.
-	regexMissesVersion       uint         // Counter for the number of regex misses on version.
-	Fails                    Fails        // Track the Notify/WebHook fails
-	deleting                 bool         // Flag to indicate the service is being deleted
-	mutex                    sync.RWMutex // Lock for the Status
+	approvedVersion          string           // The version that's been approved
+	changelog                []ChangelogEntry // Release notes of changelogVersion and the versions before it (newest first).
+	changelogVersion         string           // Version that changelog leads up to.
+	deployedAggregate        string           // How the deployedTargets give the deployedVersion ("all"/"any"/"oldest").
+	deployedTargets          []DeployedTarget // Deployed versions of the named targets (sorted by name).
+	deployedVersion          string           // Track the deployed version of the service from the last successful WebHook.
+	deployedVersionTimestamp string           // UTC timestamp of DeployedVersion being changed.
+	dockerDigest             string           // Digest of the require.docker image:tag of dockerDigestVersion.
+	dockerDigestVersion      string           // Version that dockerDigest is for.
+	latestVersion            string           // Latest version found from query().
+	latestVersionTimestamp   string           // UTC timestamp of LatestVersion being changed.
+	lastQueried              string           // UTC timestamp that version was last queried/checked.
+	pendingVersion           string           // Newest version being held back until it reaches the require.min_age.
+	pendingVersionTimestamp  string           // UTC timestamp that PendingVersion was published (or first seen).
+	pendingVersionUntil      string           // UTC timestamp that PendingVersion will reach the require.min_age.
+	regexMissesContent       uint             // Counter for the number of regex misses on URL content.
+	regexMissesVersion       uint             // Counter for the number of regex misses on version.
+	releaseNotes             string           // Release notes of releaseNotesVersion.
+	releaseNotesVersion      string           // Version that releaseNotes are for.
+	releaseURL               string           // URL of the release page of releaseURLVersion (e.g. a feed entry).
+	releaseURLVersion        string           // Version that releaseURL is for.
+	versionScheme            string           // Scheme used to compare versions (empty = compare as strings).
+	verifyFailedVersion      string           // Newest version that failed the require.verify.
+	verifyFailedReason       string           // Why VerifyFailedVersion failed the require.verify.
+	Fails                    Fails            // Track the Notify/WebHook fails
+	deleting                 bool             // Flag to indicate the service is being deleted
+	mutex                    sync.RWMutex     // Lock for the Status
 }
 
 // New Status struct.
@@ -108,6 +132,8 @@ func (s *Status) String() string {
 		{Name: "latest_version", Value: s.latestVersion},
 		{Name: "latest_version_timestamp", Value: s.latestVersionTimestamp},
 		{Name: "last_queried", Value: s.lastQueried},
+		{Name: "pending_version", Value: s.pendingVersion},
+		{Name: "pending_version_timestamp", Value: s.pendingVersionTimestamp},
 		{Name: "regex_misses_content", Value: s.regexMissesContent},
 		{Name: "regex_misses_version", Value: s.regexMissesVersion},
 		{Name: "fails", Value: &s.Fails},
@@ -286,6 +312,8 @@ func (s *Status) SetLatestVersion(version string, writeToDB bool) {
 		s.latestVersion = version
 		s.latestVersionTimestamp = s.lastQueried
 	}
+	// The version the deployed targets give may depend on the LatestVersion.
+	deployedVersion := s.aggregateDeployedVersion()
 	s.mutex.Unlock()
 
 	// Write to the database if we're not deleting and have a channel
@@ -304,6 +332,10 @@ func (s *Status) SetLatestVersion(version string, writeToDB bool) {
 		s.sendDatabase(&message)
 		s.mutex.RUnlock()
 	}
+
+	if deployedVersion != "" && deployedVersion != s.DeployedVersion() {
+		s.SetDeployedVersion(deployedVersion, writeToDB)
+	}
 }
 
 // LatestVersionTimestamp returns the timestamp of the latest version.
@@ -322,6 +354,136 @@ func (s *Status) SetLatestVersionTimestamp(timestamp string) {
 	s.mutex.Unlock()
 }
 
+// PendingVersion returns the newest version being held back until it reaches the require.min_age.
+func (s *Status) PendingVersion() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.pendingVersion
+}
+
+// PendingVersionTimestamp returns the time that the PendingVersion was published (or first seen).
+func (s *Status) PendingVersionTimestamp() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.pendingVersionTimestamp
+}
+
+// PendingVersionUntil returns the time that the PendingVersion will reach the require.min_age.
+func (s *Status) PendingVersionUntil() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.pendingVersionUntil
+}
+
+// SetPendingVersion will set PendingVersion to `version`, published (or first seen) at `timestamp`
+// and reaching the require.min_age at `until`.
+//
+// An empty `version` clears the PendingVersion.
+func (s *Status) SetPendingVersion(version string, timestamp string, until string, writeToDB bool) {
+	if version == "" {
+		timestamp, until = "", ""
+	}
+
+	s.mutex.Lock()
+	changed := s.pendingVersion != version || s.pendingVersionTimestamp != timestamp
+	if !changed && s.pendingVersionUntil == until {
+		s.mutex.Unlock()
+		return
+	}
+	s.pendingVersion = version
+	s.pendingVersionTimestamp = timestamp
+	s.pendingVersionUntil = until
+	s.mutex.Unlock()
+
+	if writeToDB {
+		s.mutex.RLock()
+		// WebSocket
+		s.announcePending()
+		// Database
+		if changed {
+			message := dbtype.Message{
+				ServiceID: *s.ServiceID,
+				Cells: []dbtype.Cell{
+					{Column: "pending_version", Value: s.pendingVersion},
+					{Column: "pending_version_timestamp", Value: s.pendingVersionTimestamp}}}
+			s.sendDatabase(&message)
+		}
+		s.mutex.RUnlock()
+	}
+}
+
+// PendingVersionSummary returns the PendingVersion with the time remaining until it reaches the require.min_age,
+// or nil if there isn't one.
+func (s *Status) PendingVersionSummary() *api_type.PendingVersion {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.pendingVersionSummary()
+}
+
+// pendingVersionSummary returns the PendingVersion with the time remaining until it reaches the require.min_age,
+// or nil if there isn't one.
+//
+// (Caller must hold the mutex)
+func (s *Status) pendingVersionSummary() *api_type.PendingVersion {
+	if s.pendingVersion == "" {
+		return nil
+	}
+
+	summary := &api_type.PendingVersion{
+		Version: s.pendingVersion,
+		Until:   s.pendingVersionUntil}
+	if until, err := time.Parse(time.RFC3339, s.pendingVersionUntil); err == nil {
+		summary.Remaining = max(time.Until(until).Round(time.Second), 0).String()
+	}
+	return summary
+}
+
+// VerifyFailure returns the newest version that failed the require.verify,
+// or nil if there isn't one.
+func (s *Status) VerifyFailure() *api_type.VerifyFailure {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.verifyFailure()
+}
+
+// verifyFailure returns the newest version that failed the require.verify,
+// or nil if there isn't one.
+//
+// (Caller must hold the mutex)
+func (s *Status) verifyFailure() *api_type.VerifyFailure {
+	if s.verifyFailedVersion == "" {
+		return nil
+	}
+
+	return &api_type.VerifyFailure{
+		Version: s.verifyFailedVersion,
+		Reason:  s.verifyFailedReason}
+}
+
+// SetVerifyFailure will record that `version` failed the require.verify because of `reason`,
+// announcing it if it changed.
+//
+// An empty `version` clears the VerifyFailure.
+func (s *Status) SetVerifyFailure(version string, reason string) {
+	if version == "" {
+		reason = ""
+	}
+
+	s.mutex.Lock()
+	if s.verifyFailedVersion == version && s.verifyFailedReason == reason {
+		s.mutex.Unlock()
+		return
+	}
+	s.verifyFailedVersion = version
+	s.verifyFailedReason = reason
+	s.mutex.Unlock()
+
+	// WebSocket
+	s.mutex.RLock()
+	s.announceVerifyFailure()
+	s.mutex.RUnlock()
+}
+
 // RegexMissContent will increment the count of RegEx misses on content.
 func (s *Status) RegexMissContent() {
 	s.mutex.Lock()
@@ -411,15 +573,176 @@ func (s *Status) SendSave() {
 	*s.SaveChannel <- true
 }
 
-// GetWebURL returns the Web URL.
+// SetReleaseURL sets the URL of the release page of `version`.
+func (s *Status) SetReleaseURL(version string, url string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.releaseURL = url
+	s.releaseURLVersion = version
+}
+
+// ReleaseURL returns the URL of the release page of the LatestVersion (if known).
+func (s *Status) ReleaseURL() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.releaseURLVersion != s.latestVersion {
+		return ""
+	}
+	return s.releaseURL
+}
+
+// SetReleaseNotes sets the release notes of `version`.
+func (s *Status) SetReleaseNotes(version string, notes string, writeToDB bool) {
+	s.mutex.Lock()
+	if s.releaseNotesVersion == version && s.releaseNotes == notes {
+		s.mutex.Unlock()
+		return
+	}
+	s.releaseNotes = notes
+	s.releaseNotesVersion = version
+	s.mutex.Unlock()
+
+	// Database
+	if writeToDB {
+		s.mutex.RLock()
+		message := dbtype.Message{
+			ServiceID: *s.ServiceID,
+			Cells: []dbtype.Cell{
+				{Column: "release_notes", Value: s.releaseNotes},
+				{Column: "release_notes_version", Value: s.releaseNotesVersion}}}
+		s.sendDatabase(&message)
+		s.mutex.RUnlock()
+	}
+}
+
+// ReleaseNotes returns the release notes of the LatestVersion (if known).
+func (s *Status) ReleaseNotes() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.releaseNotesVersion != s.latestVersion {
+		return ""
+	}
+	return s.releaseNotes
+}
+
+// ReleaseNotesVersion returns the version that the stored release notes are for.
+func (s *Status) ReleaseNotesVersion() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.releaseNotesVersion
+}
+
+// SetChangelog sets the release notes of `version` and the versions before it (newest first).
+func (s *Status) SetChangelog(version string, entries []ChangelogEntry) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.changelog = entries
+	s.changelogVersion = version
+}
+
+// ChangelogEntries returns the release notes of the versions newer than the DeployedVersion,
+// up to and including the LatestVersion (newest first).
+func (s *Status) ChangelogEntries() []ChangelogEntry {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.changelogVersion != s.latestVersion || s.deployedVersion == "" {
+		return nil
+	}
+
+	var entries []ChangelogEntry
+	for _, entry := range s.changelog {
+		// Stop at the DeployedVersion (or the first version older than it).
+		if s.sameVersion(entry.Version, s.deployedVersion) {
+			break
+		}
+		if s.versionScheme != "" {
+			if cmp, err := opt.CompareVersions(s.versionScheme, entry.Version, s.deployedVersion); err == nil && cmp < 0 {
+				break
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Changelog returns the combined release notes of the versions newer than the DeployedVersion,
+// up to and including the LatestVersion, with a heading for each version.
+func (s *Status) Changelog() string {
+	entries := s.ChangelogEntries()
+
+	var builder strings.Builder
+	for i, entry := range entries {
+		if i != 0 {
+			builder.WriteString("\n\n")
+		}
+		builder.WriteString("## " + entry.Version)
+		if entry.Notes != "" {
+			builder.WriteString("\n" + entry.Notes)
+		}
+	}
+	return builder.String()
+}
+
+// SetDockerDigest sets the digest of the require.docker image:tag of `version`.
+func (s *Status) SetDockerDigest(version string, digest string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.dockerDigest = digest
+	s.dockerDigestVersion = version
+}
+
+// DockerDigest returns the digest of the require.docker image:tag of the LatestVersion (if known).
+func (s *Status) DockerDigest() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.dockerDigestVersion != s.latestVersion {
+		return ""
+	}
+	return s.dockerDigest
+}
+
+// GetWebURL returns the Web URL,
+// falling back to the release page of the LatestVersion.
 func (s *Status) GetWebURL() string {
 	if util.DefaultIfNil(s.WebURL) == "" {
-		return ""
+		return s.ReleaseURL()
 	}
 
 	return util.TemplateString(
 		*s.WebURL,
-		util.ServiceInfo{LatestVersion: s.LatestVersion()})
+		util.ServiceInfo{
+			LatestVersion: s.LatestVersion(),
+			DockerDigest:  s.DockerDigest(),
+			ReleaseNotes:  s.ReleaseNotes(),
+			Changelog:     s.Changelog()})
+}
+
+// SetVersionScheme sets the scheme used to compare versions.
+func (s *Status) SetVersionScheme(scheme string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.versionScheme = scheme
+}
+
+// sameVersion returns whether `a` and `b` are the same version in the version scheme.
+// e.g. '1.0' and '1.0.0' with pep440.
+func (s *Status) sameVersion(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if s.versionScheme == "" || a == "" || b == "" {
+		return false
+	}
+	cmp, err := opt.CompareVersions(s.versionScheme, a, b)
+	return err == nil && cmp == 0
 }
 
 // setLatestVersionIsDeployedMetric will set the metric for whether the latest version is currently deployed.
@@ -429,7 +752,7 @@ func (s *Status) setLatestVersionIsDeployedMetric() {
 	}
 
 	value := float64(0) // Not deployed
-	if s.latestVersion == s.deployedVersion {
+	if s.sameVersion(s.latestVersion, s.deployedVersion) {
 		value = 1 // Is deployed
 
 		// Latest version isn't deployed, but has been approved/skipped, so carry that over
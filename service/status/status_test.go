@@ -17,7 +17,9 @@
 package svcstatus
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -25,6 +27,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	dbtype "github.com/release-argus/Argus/db/types"
 	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
+	api_type "github.com/release-argus/Argus/web/api/types"
 	metric "github.com/release-argus/Argus/web/metrics"
 )
 
@@ -124,12 +128,29 @@ func TestStatus_GetWebURL(t *testing.T) {
 	// GIVEN we have a Status
 	latestVersion := "1.2.3"
 	tests := map[string]struct {
-		webURL *string
-		want   string
+		webURL                        *string
+		releaseURL, releaseURLVersion string
+		dockerDigest                  string
+		want                          string
 	}{
 		"nil string": {
 			webURL: test.StringPtr(""),
 			want:   ""},
+		"empty string uses the release URL of the latest version": {
+			webURL:            test.StringPtr(""),
+			releaseURL:        "https://example.com/releases/1.2.3",
+			releaseURLVersion: latestVersion,
+			want:              "https://example.com/releases/1.2.3"},
+		"empty string ignores the release URL of another version": {
+			webURL:            test.StringPtr(""),
+			releaseURL:        "https://example.com/releases/1.2.2",
+			releaseURLVersion: "1.2.2",
+			want:              ""},
+		"string takes priority over the release URL": {
+			webURL:            test.StringPtr("https://something.com/somewhere"),
+			releaseURL:        "https://example.com/releases/1.2.3",
+			releaseURLVersion: latestVersion,
+			want:              "https://something.com/somewhere"},
 		"empty string": {
 			webURL: test.StringPtr(""),
 			want:   ""},
@@ -139,6 +160,10 @@ func TestStatus_GetWebURL(t *testing.T) {
 		"string with templating": {
 			webURL: test.StringPtr("https://something.com/somewhere/{{ version }}"),
 			want:   "https://something.com/somewhere/" + latestVersion},
+		"string with docker_digest templating": {
+			webURL:       test.StringPtr("https://something.com/somewhere@{{ docker_digest }}"),
+			dockerDigest: "sha256:abc",
+			want:         "https://something.com/somewhere@sha256:abc"},
 	}
 
 	for name, tc := range tests {
@@ -151,6 +176,8 @@ func TestStatus_GetWebURL(t *testing.T) {
 				&name,
 				tc.webURL)
 			status.SetLatestVersion(latestVersion, false)
+			status.SetReleaseURL(tc.releaseURLVersion, tc.releaseURL)
+			status.SetDockerDigest(latestVersion, tc.dockerDigest)
 
 			// WHEN GetWebURL is called
 			got := status.GetWebURL()
@@ -431,6 +458,167 @@ func TestStatus_LatestVersion(t *testing.T) {
 	}
 }
 
+func TestStatus_PendingVersion(t *testing.T) {
+	// GIVEN a Status that may already have a PendingVersion
+	tests := map[string]struct {
+		hadVersion, hadTimestamp, hadUntil string
+		version, timestamp, until          string
+		wantVersion, wantTimestamp         string
+		wantDB, wantAnnounce               bool
+	}{
+		"sets the pending version": {
+			version:       "1.2.3",
+			timestamp:     "2024-01-01T00:00:00Z",
+			until:         "2024-01-03T00:00:00Z",
+			wantVersion:   "1.2.3",
+			wantTimestamp: "2024-01-01T00:00:00Z",
+			wantDB:        true,
+			wantAnnounce:  true,
+		},
+		"unchanged pending version does nothing": {
+			hadVersion:    "1.2.3",
+			hadTimestamp:  "2024-01-01T00:00:00Z",
+			hadUntil:      "2024-01-03T00:00:00Z",
+			version:       "1.2.3",
+			timestamp:     "2024-01-01T00:00:00Z",
+			until:         "2024-01-03T00:00:00Z",
+			wantVersion:   "1.2.3",
+			wantTimestamp: "2024-01-01T00:00:00Z",
+		},
+		"changed until (min_age) is only announced": {
+			hadVersion:    "1.2.3",
+			hadTimestamp:  "2024-01-01T00:00:00Z",
+			hadUntil:      "2024-01-03T00:00:00Z",
+			version:       "1.2.3",
+			timestamp:     "2024-01-01T00:00:00Z",
+			until:         "2024-01-02T00:00:00Z",
+			wantVersion:   "1.2.3",
+			wantTimestamp: "2024-01-01T00:00:00Z",
+			wantAnnounce:  true,
+		},
+		"empty version clears the pending version": {
+			hadVersion:   "1.2.3",
+			hadTimestamp: "2024-01-01T00:00:00Z",
+			hadUntil:     "2024-01-03T00:00:00Z",
+			version:      "",
+			timestamp:    "2024-01-01T00:00:00Z",
+			wantDB:       true,
+			wantAnnounce: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			status := testStatus()
+			status.SetPendingVersion(tc.hadVersion, tc.hadTimestamp, tc.hadUntil, false)
+
+			// WHEN SetPendingVersion is called on it
+			status.SetPendingVersion(tc.version, tc.timestamp, tc.until, true)
+
+			// THEN the PendingVersion and PendingVersionTimestamp are set
+			if got := status.PendingVersion(); got != tc.wantVersion {
+				t.Errorf("PendingVersion - want %q, got %q",
+					tc.wantVersion, got)
+			}
+			if got := status.PendingVersionTimestamp(); got != tc.wantTimestamp {
+				t.Errorf("PendingVersionTimestamp - want %q, got %q",
+					tc.wantTimestamp, got)
+			}
+			// AND the change is sent to the DB if wanted
+			if got := len(*status.DatabaseChannel); got != map[bool]int{false: 0, true: 1}[tc.wantDB] {
+				t.Fatalf("DatabaseChannel - want %t, got %d messages",
+					tc.wantDB, got)
+			}
+			if tc.wantDB {
+				msg := <-*status.DatabaseChannel
+				if len(msg.Cells) != 2 ||
+					msg.Cells[0].Column != "pending_version" || msg.Cells[0].Value != tc.wantVersion ||
+					msg.Cells[1].Column != "pending_version_timestamp" || msg.Cells[1].Value != tc.wantTimestamp {
+					t.Errorf("DatabaseChannel - unexpected message %v",
+						msg)
+				}
+			}
+			// AND it's announced if wanted
+			if got := len(*status.AnnounceChannel); got != map[bool]int{false: 0, true: 1}[tc.wantAnnounce] {
+				t.Fatalf("AnnounceChannel - want %t, got %d messages",
+					tc.wantAnnounce, got)
+			}
+			if tc.wantAnnounce {
+				var got api_type.WebSocketMessage
+				json.Unmarshal(<-*status.AnnounceChannel, &got)
+				if got.SubType != "PENDING" ||
+					got.ServiceData.Status.PendingVersion == nil ||
+					got.ServiceData.Status.PendingVersion.Version != tc.wantVersion ||
+					got.ServiceData.Status.PendingVersion.Until != util.ValueIfNotDefault(tc.wantVersion, tc.until) {
+					t.Errorf("AnnounceChannel - unexpected message %+v",
+						got)
+				}
+			}
+		})
+	}
+}
+
+func TestStatus_PendingVersionSummary(t *testing.T) {
+	// GIVEN a Status with a PendingVersion
+	tests := map[string]struct {
+		version, until string
+		want           *api_type.PendingVersion
+		wantRemaining  string
+	}{
+		"no pending version": {
+			want: nil,
+		},
+		"remaining time": {
+			version:       "1.2.3",
+			until:         time.Now().Add(90 * time.Minute).UTC().Format(time.RFC3339),
+			want:          &api_type.PendingVersion{Version: "1.2.3"},
+			wantRemaining: `^1h(29m5[0-9]s|30m0s)$`,
+		},
+		"reached the min_age": {
+			version:       "1.2.3",
+			until:         "2024-01-03T00:00:00Z",
+			want:          &api_type.PendingVersion{Version: "1.2.3"},
+			wantRemaining: `^0s$`,
+		},
+		"unknown until": {
+			version:       "1.2.3",
+			want:          &api_type.PendingVersion{Version: "1.2.3"},
+			wantRemaining: `^$`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			status := testStatus()
+			status.SetPendingVersion(tc.version, "2024-01-01T00:00:00Z", tc.until, false)
+
+			// WHEN PendingVersionSummary is called on it
+			got := status.PendingVersionSummary()
+
+			// THEN the summary is as expected
+			if tc.want == nil {
+				if got != nil {
+					t.Errorf("want nil, got %+v",
+						got)
+				}
+				return
+			}
+			if got == nil || got.Version != tc.want.Version || got.Until != tc.until {
+				t.Fatalf("want version %q until %q, got %+v",
+					tc.want.Version, tc.until, got)
+			}
+			if !regexp.MustCompile(tc.wantRemaining).MatchString(got.Remaining) {
+				t.Errorf("Remaining - want match for %q, got %q",
+					tc.wantRemaining, got.Remaining)
+			}
+		})
+	}
+}
+
 func TestStatus_RegexMissesContent(t *testing.T) {
 	// GIVEN a Status
 	status := Status{}
@@ -760,6 +948,8 @@ func TestStatus_String(t *testing.T) {
 		latestVersion            string
 		latestVersionTimestamp   string
 		lastQueried              *string
+		pendingVersion           string
+		pendingVersionTimestamp  string
 		regexMissesContent       int
 		regexMissesVersion       int
 		commandFails             []*bool
@@ -812,6 +1002,8 @@ shoutrrr: {bash: false, bish: nil, bosh: true},
 			latestVersion:            "1.2.4",
 			latestVersionTimestamp:   "2022-01-01T01:01:01Z",
 			lastQueried:              test.StringPtr("2022-01-01T01:01:01Z"),
+			pendingVersion:           "1.2.5",
+			pendingVersionTimestamp:  "2022-01-01T01:01:03Z",
 			want: `
 approved_version: 1.2.4,
  deployed_version: 1.2.3,
@@ -819,6 +1011,8 @@ approved_version: 1.2.4,
  latest_version: 1.2.4,
  latest_version_timestamp: 2022-01-01T01:01:01Z,
  last_queried: 2022-01-01T01:01:01Z,
+ pending_version: 1.2.5,
+ pending_version_timestamp: 2022-01-01T01:01:03Z,
  regex_misses_content: 1,
  regex_misses_version: 2,
  fails: {
@@ -841,6 +1035,7 @@ shoutrrr: {bash: false, bish: nil, bosh: true},
 			if tc.lastQueried != nil {
 				tc.status.SetLastQueried(*tc.lastQueried)
 			}
+			tc.status.SetPendingVersion(tc.pendingVersion, tc.pendingVersionTimestamp, "", false)
 			{ // RegEz misses
 				for i := 0; i < tc.regexMissesContent; i++ {
 					tc.status.RegexMissContent()
@@ -885,6 +1080,7 @@ func TestStatus_SetLatestVersionIsDeployedMetric(t *testing.T) {
 	tests := map[string]struct {
 		latestVersion   string
 		deployedVersion string
+		versionScheme   string
 		want            float64
 	}{
 		"latest version is deployed": {
@@ -897,6 +1093,23 @@ func TestStatus_SetLatestVersionIsDeployedMetric(t *testing.T) {
 			deployedVersion: "1.2.4",
 			want:            0,
 		},
+		"equivalent version is deployed with a version_scheme": {
+			latestVersion:   "1.0",
+			deployedVersion: "1.0.0",
+			versionScheme:   "pep440",
+			want:            1,
+		},
+		"equivalent version without a version_scheme": {
+			latestVersion:   "1.0",
+			deployedVersion: "1.0.0",
+			want:            0,
+		},
+		"older version is deployed with a version_scheme": {
+			latestVersion:   "1:1.0-1",
+			deployedVersion: "1.0-1",
+			versionScheme:   "debian",
+			want:            0,
+		},
 	}
 
 	for name, tc := range tests {
@@ -908,6 +1121,7 @@ func TestStatus_SetLatestVersionIsDeployedMetric(t *testing.T) {
 				0, 0, 0,
 				&name,
 				test.StringPtr("http://example.com"))
+			status.SetVersionScheme(tc.versionScheme)
 			status.SetLatestVersion(tc.latestVersion, false)
 			status.SetDeployedVersion(tc.deployedVersion, false)
 
@@ -988,3 +1202,303 @@ func TestStatus_InitMetrics_DeleteMetrics(t *testing.T) {
 		})
 	}
 }
+
+func TestStatus_VerifyFailure(t *testing.T) {
+	// GIVEN a Status that may already have a VerifyFailure
+	tests := map[string]struct {
+		hadVersion, hadReason string
+		version, reason       string
+		want                  *api_type.VerifyFailure
+		wantAnnounce          bool
+	}{
+		"sets the verify failure": {
+			version:      "1.2.3",
+			reason:       "checksum mismatch",
+			want:         &api_type.VerifyFailure{Version: "1.2.3", Reason: "checksum mismatch"},
+			wantAnnounce: true,
+		},
+		"unchanged verify failure does nothing": {
+			hadVersion: "1.2.3",
+			hadReason:  "checksum mismatch",
+			version:    "1.2.3",
+			reason:     "checksum mismatch",
+			want:       &api_type.VerifyFailure{Version: "1.2.3", Reason: "checksum mismatch"},
+		},
+		"changed reason is announced": {
+			hadVersion:   "1.2.3",
+			hadReason:    "checksum mismatch",
+			version:      "1.2.3",
+			reason:       "invalid signature",
+			want:         &api_type.VerifyFailure{Version: "1.2.3", Reason: "invalid signature"},
+			wantAnnounce: true,
+		},
+		"empty version clears the verify failure": {
+			hadVersion:   "1.2.3",
+			hadReason:    "checksum mismatch",
+			version:      "",
+			reason:       "checksum mismatch",
+			want:         nil,
+			wantAnnounce: true,
+		},
+		"clearing nothing does nothing": {
+			want: nil,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			status := testStatus()
+			status.SetVerifyFailure(tc.hadVersion, tc.hadReason)
+			for len(*status.AnnounceChannel) != 0 {
+				<-*status.AnnounceChannel
+			}
+
+			// WHEN SetVerifyFailure is called on it
+			status.SetVerifyFailure(tc.version, tc.reason)
+
+			// THEN the VerifyFailure is as expected
+			got := status.VerifyFailure()
+			if (got == nil) != (tc.want == nil) ||
+				(got != nil && *got != *tc.want) {
+				t.Errorf("VerifyFailure - want %+v, got %+v",
+					tc.want, got)
+			}
+			// AND it's announced if it changed
+			if got := len(*status.AnnounceChannel); got != map[bool]int{false: 0, true: 1}[tc.wantAnnounce] {
+				t.Fatalf("AnnounceChannel - want %t, got %d messages",
+					tc.wantAnnounce, got)
+			}
+			if tc.wantAnnounce {
+				var got api_type.WebSocketMessage
+				json.Unmarshal(<-*status.AnnounceChannel, &got)
+				want := util.DefaultIfNil(tc.want)
+				if got.SubType != "VERIFY" ||
+					got.ServiceData.Status.VerifyFailure == nil ||
+					*got.ServiceData.Status.VerifyFailure != want {
+					t.Errorf("AnnounceChannel - unexpected message %+v",
+						got)
+				}
+			}
+			// AND it's never sent to the DB
+			if got := len(*status.DatabaseChannel); got != 0 {
+				t.Errorf("DatabaseChannel - want no messages, got %d",
+					got)
+			}
+		})
+	}
+}
+
+func TestStatus_SetReleaseNotes(t *testing.T) {
+	// GIVEN a Status with a LatestVersion and release notes
+	latestVersion := "1.2.3"
+	tests := map[string]struct {
+		version, notes   string
+		writeToDB        bool
+		wantReleaseNotes string
+		wantDB           bool
+	}{
+		"notes of the latest version": {
+			version:          latestVersion,
+			notes:            "- Fixed a bug",
+			writeToDB:        true,
+			wantReleaseNotes: "- Fixed a bug",
+			wantDB:           true,
+		},
+		"notes of another version": {
+			version:          "1.2.2",
+			notes:            "- Fixed a bug",
+			writeToDB:        true,
+			wantReleaseNotes: "",
+			wantDB:           true,
+		},
+		"unchanged notes aren't sent to the DB": {
+			version:          latestVersion,
+			notes:            "- Old notes",
+			writeToDB:        true,
+			wantReleaseNotes: "- Old notes",
+			wantDB:           false,
+		},
+		"not sent to the DB when writeToDB is false": {
+			version:          latestVersion,
+			notes:            "- Fixed a bug",
+			writeToDB:        false,
+			wantReleaseNotes: "- Fixed a bug",
+			wantDB:           false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			status := testStatus()
+			status.SetLatestVersion(latestVersion, false)
+			status.SetReleaseNotes(latestVersion, "- Old notes", false)
+
+			// WHEN SetReleaseNotes is called on it
+			status.SetReleaseNotes(tc.version, tc.notes, tc.writeToDB)
+
+			// THEN ReleaseNotes only gives the notes of the LatestVersion
+			if got := status.ReleaseNotes(); got != tc.wantReleaseNotes {
+				t.Errorf("ReleaseNotes - want %q, got %q",
+					tc.wantReleaseNotes, got)
+			}
+			if got := status.ReleaseNotesVersion(); got != tc.version {
+				t.Errorf("ReleaseNotesVersion - want %q, got %q",
+					tc.version, got)
+			}
+			// AND the change is sent to the DB if wanted
+			if got := len(*status.DatabaseChannel); got != map[bool]int{false: 0, true: 1}[tc.wantDB] {
+				t.Fatalf("DatabaseChannel - want %t, got %d messages",
+					tc.wantDB, got)
+			}
+			if tc.wantDB {
+				msg := <-*status.DatabaseChannel
+				if len(msg.Cells) != 2 ||
+					msg.Cells[0].Column != "release_notes" || msg.Cells[0].Value != tc.notes ||
+					msg.Cells[1].Column != "release_notes_version" || msg.Cells[1].Value != tc.version {
+					t.Errorf("DatabaseChannel - unexpected message %v",
+						msg)
+				}
+			}
+		})
+	}
+}
+
+func TestStatus_Changelog(t *testing.T) {
+	// GIVEN a Status with a changelog leading up to a version
+	entries := []ChangelogEntry{
+		{Version: "1.3.0", Notes: "- Added a thing"},
+		{Version: "1.2.1", Notes: "- Fixed a thing"},
+		{Version: "1.2.0"},
+		{Version: "1.1.0", Notes: "- Old thing"}}
+	tests := map[string]struct {
+		latestVersion, deployedVersion string
+		changelogVersion               string
+		versionScheme                  string
+		wantVersions                   []string
+		want                           string
+	}{
+		"versions newer than the deployed version": {
+			latestVersion:    "1.3.0",
+			deployedVersion:  "1.2.0",
+			changelogVersion: "1.3.0",
+			wantVersions:     []string{"1.3.0", "1.2.1"},
+			want:             "## 1.3.0\n- Added a thing\n\n## 1.2.1\n- Fixed a thing",
+		},
+		"version without notes only has a heading": {
+			latestVersion:    "1.3.0",
+			deployedVersion:  "1.1.0",
+			changelogVersion: "1.3.0",
+			wantVersions:     []string{"1.3.0", "1.2.1", "1.2.0"},
+			want:             "## 1.3.0\n- Added a thing\n\n## 1.2.1\n- Fixed a thing\n\n## 1.2.0",
+		},
+		"deployed version not in the changelog is compared with the version scheme": {
+			latestVersion:    "1.3.0",
+			deployedVersion:  "1.2.0-rc.1",
+			changelogVersion: "1.3.0",
+			versionScheme:    "semver",
+			wantVersions:     []string{"1.3.0", "1.2.1", "1.2.0"},
+			want:             "## 1.3.0\n- Added a thing\n\n## 1.2.1\n- Fixed a thing\n\n## 1.2.0",
+		},
+		"same version in the version scheme": {
+			latestVersion:    "1.3.0",
+			deployedVersion:  "1.2",
+			changelogVersion: "1.3.0",
+			versionScheme:    "pep440",
+			wantVersions:     []string{"1.3.0", "1.2.1"},
+			want:             "## 1.3.0\n- Added a thing\n\n## 1.2.1\n- Fixed a thing",
+		},
+		"latest version is deployed": {
+			latestVersion:    "1.3.0",
+			deployedVersion:  "1.3.0",
+			changelogVersion: "1.3.0",
+			want:             "",
+		},
+		"no deployed version": {
+			latestVersion:    "1.3.0",
+			changelogVersion: "1.3.0",
+			want:             "",
+		},
+		"changelog of another version": {
+			latestVersion:    "1.4.0",
+			deployedVersion:  "1.2.0",
+			changelogVersion: "1.3.0",
+			want:             "",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			status := testStatus()
+			status.SetVersionScheme(tc.versionScheme)
+			status.SetLatestVersion(tc.latestVersion, false)
+			status.SetDeployedVersion(tc.deployedVersion, false)
+
+			// WHEN SetChangelog is called on it
+			status.SetChangelog(tc.changelogVersion, entries)
+
+			// THEN ChangelogEntries gives the versions after the DeployedVersion
+			gotEntries := status.ChangelogEntries()
+			gotVersions := make([]string, len(gotEntries))
+			for i, entry := range gotEntries {
+				gotVersions[i] = entry.Version
+			}
+			if strings.Join(gotVersions, ",") != strings.Join(tc.wantVersions, ",") {
+				t.Errorf("ChangelogEntries - want %v, got %v",
+					tc.wantVersions, gotVersions)
+			}
+			// AND Changelog combines their notes under a heading for each
+			if got := status.Changelog(); got != tc.want {
+				t.Errorf("Changelog - want %q, got %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestStatus_DockerDigest(t *testing.T) {
+	// GIVEN a Status with a LatestVersion
+	latestVersion := "1.2.3"
+	tests := map[string]struct {
+		version, digest string
+		want            string
+	}{
+		"digest of the latest version": {
+			version: latestVersion,
+			digest:  "sha256:abc",
+			want:    "sha256:abc",
+		},
+		"digest of another version": {
+			version: "1.2.2",
+			digest:  "sha256:abc",
+			want:    "",
+		},
+		"no digest": {
+			version: latestVersion,
+			want:    "",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			status := testStatus()
+			status.SetLatestVersion(latestVersion, false)
+
+			// WHEN SetDockerDigest is called on it
+			status.SetDockerDigest(tc.version, tc.digest)
+
+			// THEN DockerDigest only gives the digest of the LatestVersion
+			if got := status.DockerDigest(); got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
@@ -69,6 +69,7 @@ func (s *Service) Track() {
 		time.Sleep(2 * time.Second) // Give LatestVersion some time to query first.
 
 		go s.DeployedVersionLookup.Track()
+		s.DeployedVersionTargets.Track()
 	}()
 
 	// Track forever.
@@ -49,19 +49,20 @@ type Defaults struct {
 // Service is a source to be serviceed and provides everything needed to extract
 // the latest version from the URL provided.
 type Service struct {
-	ID                    string              `yaml:"-" json:"name"`                                                // service_name
-	Comment               string              `yaml:"comment,omitempty" json:"comment,omitempty"`                   // Comment on the Service
-	Options               opt.Options         `yaml:"options,omitempty" json:"options,omitempty"`                   // Options to give the Service
-	LatestVersion         latestver.Lookup    `yaml:"latest_version,omitempty" json:"latest_version,omitempty"`     // Vars to getting the latest version of the Service
-	DeployedVersionLookup *deployedver.Lookup `yaml:"deployed_version,omitempty" json:"deployed_version,omitempty"` // Var to scrape the Service's current deployed version
-	Notify                shoutrrr.Slice      `yaml:"notify,omitempty" json:"notify,omitempty"`                     // Service-specific Shoutrrr vars
-	notifyFromDefaults    bool
-	CommandController     *command.Controller `yaml:"-" json:"-"`                                 // The controller for the OS Commands that tracks fails and has the announce channel
-	Command               command.Slice       `yaml:"command,omitempty" json:"command,omitempty"` // OS Commands to run on new release
-	commandFromDefaults   bool
-	WebHook               webhook.Slice `yaml:"webhook,omitempty" json:"webhook,omitempty"` // Service-specific WebHook vars
-	webhookFromDefaults   bool
-	Dashboard             DashboardOptions `yaml:"dashboard,omitempty" json:"dashboard,omitempty"` // Options for the dashboard
+	ID                     string               `yaml:"-" json:"name"`                                                  // service_name
+	Comment                string               `yaml:"comment,omitempty" json:"comment,omitempty"`                     // Comment on the Service
+	Options                opt.Options          `yaml:"options,omitempty" json:"options,omitempty"`                     // Options to give the Service
+	LatestVersion          latestver.Lookup     `yaml:"latest_version,omitempty" json:"latest_version,omitempty"`       // Vars to getting the latest version of the Service
+	DeployedVersionLookup  *deployedver.Lookup  `yaml:"deployed_version,omitempty" json:"deployed_version,omitempty"`   // Var to scrape the Service's current deployed version
+	DeployedVersionTargets *deployedver.Targets `yaml:"deployed_versions,omitempty" json:"deployed_versions,omitempty"` // Vars to scrape the deployed versions of the Service's targets
+	Notify                 shoutrrr.Slice       `yaml:"notify,omitempty" json:"notify,omitempty"`                       // Service-specific Shoutrrr vars
+	notifyFromDefaults     bool
+	CommandController      *command.Controller `yaml:"-" json:"-"`                                 // The controller for the OS Commands that tracks fails and has the announce channel
+	Command                command.Slice       `yaml:"command,omitempty" json:"command,omitempty"` // OS Commands to run on new release
+	commandFromDefaults    bool
+	WebHook                webhook.Slice `yaml:"webhook,omitempty" json:"webhook,omitempty"` // Service-specific WebHook vars
+	webhookFromDefaults    bool
+	Dashboard              DashboardOptions `yaml:"dashboard,omitempty" json:"dashboard,omitempty"` // Options for the dashboard
 
 	Status svcstatus.Status `yaml:"-" json:"-"` // Track the Status of this source (version and regex misses)
 
@@ -84,9 +85,17 @@ func (s *Service) Summary() (summary *apitype.ServiceSummary) {
 	}
 
 	icon := s.IconURL()
-	hasDeployedVersionLookup := s.DeployedVersionLookup != nil
+	hasDeployedVersionLookup := s.HasDeployedVersionLookup()
+	var deployedAggregate string
+	if s.DeployedVersionTargets != nil {
+		deployedAggregate = s.DeployedVersionTargets.GetAggregate()
+	}
 	commands := len(s.Command)
 	webhooks := len(s.WebHook)
+	var ignoreVersions *[]string
+	if ignored := s.LatestVersion.GetIgnoreVersions(); len(ignored) != 0 {
+		ignoreVersions = &ignored
+	}
 	summary = &apitype.ServiceSummary{
 		ID:                       s.ID,
 		Active:                   s.Options.Active,
@@ -95,18 +104,29 @@ func (s *Service) Summary() (summary *apitype.ServiceSummary) {
 		Icon:                     &icon,
 		IconLinkTo:               &s.Dashboard.IconLinkTo,
 		HasDeployedVersionLookup: &hasDeployedVersionLookup,
+		DeployedAggregate:        deployedAggregate,
 		Command:                  &commands,
 		WebHook:                  &webhooks,
+		IgnoreVersions:           ignoreVersions,
 		Status: &apitype.Status{
 			ApprovedVersion:          s.Status.ApprovedVersion(),
 			DeployedVersion:          s.Status.DeployedVersion(),
 			DeployedVersionTimestamp: s.Status.DeployedVersionTimestamp(),
+			DeployedTargets:          s.Status.DeployedTargetsSummary(),
 			LatestVersion:            s.Status.LatestVersion(),
 			LatestVersionTimestamp:   s.Status.LatestVersionTimestamp(),
-			LastQueried:              s.Status.LastQueried()}}
+			LastQueried:              s.Status.LastQueried(),
+			PendingVersion:           s.Status.PendingVersionSummary(),
+			VerifyFailure:            s.Status.VerifyFailure()}}
 	return
 }
 
+// HasDeployedVersionLookup returns whether the deployed version of the Service is looked up
+// (with deployed_version or deployed_versions).
+func (s *Service) HasDeployedVersionLookup() bool {
+	return s.DeployedVersionLookup != nil || s.DeployedVersionTargets != nil
+}
+
 // UsingDefaults returns whether the Service is using the Notify(s)/Command(s)/WebHook(s) from Defaults
 func (s *Service) UsingDefaults() (bool, bool, bool) {
 	if s == nil {
@@ -139,6 +139,10 @@ func TestService_Summary(t *testing.T) {
 		latestVersion            string
 		latestVersionTimestamp   string
 		lastQueried              string
+		pendingVersion           string
+		pendingVersionUntil      string
+		verifyFailedVersion      string
+		verifyFailedReason       string
 		want                     *apitype.ServiceSummary
 	}{
 		"nil": {
@@ -183,6 +187,20 @@ func TestService_Summary(t *testing.T) {
 				WebHook:                  test.IntPtr(0),
 				Status:                   &apitype.Status{}},
 		},
+		"only latest_version.ignore_versions": {
+			svc: &Service{
+				LatestVersion: latestver.Lookup{
+					IgnoreVersions: []string{"1.2.3", "semver:~2.0"}}},
+			want: &apitype.ServiceSummary{
+				Type:                     test.StringPtr(""),
+				Icon:                     test.StringPtr(""),
+				IconLinkTo:               test.StringPtr(""),
+				HasDeployedVersionLookup: test.BoolPtr(false),
+				Command:                  test.IntPtr(0),
+				WebHook:                  test.IntPtr(0),
+				IgnoreVersions:           &[]string{"1.2.3", "semver:~2.0"},
+				Status:                   &apitype.Status{}},
+		},
 		"only latest_version.type": {
 			svc: &Service{
 				LatestVersion: latestver.Lookup{
@@ -367,6 +385,10 @@ func TestService_Summary(t *testing.T) {
 			latestVersion:            "3",
 			latestVersionTimestamp:   "3-",
 			lastQueried:              "4",
+			pendingVersion:           "5",
+			pendingVersionUntil:      "2020-01-03T00:00:00Z",
+			verifyFailedVersion:      "6",
+			verifyFailedReason:       "checksum mismatch",
 			want: &apitype.ServiceSummary{
 				Type:                     test.StringPtr(""),
 				Icon:                     test.StringPtr(""),
@@ -380,7 +402,14 @@ func TestService_Summary(t *testing.T) {
 					DeployedVersionTimestamp: "2-",
 					LatestVersion:            "3",
 					LatestVersionTimestamp:   "3-",
-					LastQueried:              "4"}},
+					LastQueried:              "4",
+					PendingVersion: &apitype.PendingVersion{
+						Version:   "5",
+						Until:     "2020-01-03T00:00:00Z",
+						Remaining: "0s"},
+					VerifyFailure: &apitype.VerifyFailure{
+						Version: "6",
+						Reason:  "checksum mismatch"}}},
 		},
 	}
 
@@ -401,6 +430,8 @@ func TestService_Summary(t *testing.T) {
 					tc.svc.Status.SetLatestVersion(tc.latestVersion, false)
 					tc.svc.Status.SetLatestVersionTimestamp(tc.latestVersionTimestamp)
 					tc.svc.Status.SetLastQueried(tc.lastQueried)
+					tc.svc.Status.SetPendingVersion(tc.pendingVersion, "2020-01-01T00:00:00Z", tc.pendingVersionUntil, false)
+					tc.svc.Status.SetVerifyFailure(tc.verifyFailedVersion, tc.verifyFailedReason)
 				}
 			}
 
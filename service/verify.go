@@ -67,6 +67,14 @@ func (s *Service) CheckValues(prefix string) (errs error) {
 		errs = fmt.Errorf("%s%w",
 			util.ErrorToString(errs), deployedVersionErrs)
 	}
+	if deployedVersionsErrs := s.DeployedVersionTargets.CheckValues(errPrefix); deployedVersionsErrs != nil {
+		errs = fmt.Errorf("%s%w",
+			util.ErrorToString(errs), deployedVersionsErrs)
+	}
+	if s.DeployedVersionLookup != nil && s.DeployedVersionTargets != nil {
+		errs = fmt.Errorf("%s%sdeployed_versions: <invalid> (only one of deployed_version or deployed_versions can be used)\\",
+			util.ErrorToString(errs), errPrefix)
+	}
 	if notifyErrs := s.Notify.CheckValues(errPrefix); notifyErrs != nil {
 		errs = fmt.Errorf("%s%w",
 			util.ErrorToString(errs), notifyErrs)
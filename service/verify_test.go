@@ -195,7 +195,6 @@ func TestService_CheckValues(t *testing.T) {
 	tests := map[string]struct {
 		svc              *Service
 		options          opt.Options
-		latestVersion    latestver.Lookup
 		deployedVersion  *deployedver.Lookup
 		commands         command.Slice
 		webhooks         webhook.Slice
@@ -205,9 +204,9 @@ func TestService_CheckValues(t *testing.T) {
 	}{
 		"options with errs": {
 			svc: &Service{
-				ID: "test", Comment: "foo_comment"},
-			latestVersion: latestver.Lookup{
-				Type: "github", URL: "release-argus/Argus"},
+				ID: "test", Comment: "foo_comment",
+				LatestVersion: latestver.Lookup{
+					Type: "github", URL: "release-argus/Argus"}},
 			options: *opt.New(
 				nil, "10x", nil, nil, nil),
 			errRegex: []string{
@@ -217,11 +216,11 @@ func TestService_CheckValues(t *testing.T) {
 		},
 		"options,latest_version, with errs": {
 			svc: &Service{
-				ID: "test", Comment: "foo_comment"},
+				ID: "test", Comment: "foo_comment",
+				LatestVersion: latestver.Lookup{
+					Type: "invalid", URL: "release-argus/Argus"}},
 			options: *opt.New(
 				nil, "10x", nil, nil, nil),
-			latestVersion: latestver.Lookup{
-				Type: "invalid", URL: "release-argus/Argus"},
 			errRegex: []string{
 				`^test:$`,
 				`^  options:$`,
@@ -231,11 +230,11 @@ func TestService_CheckValues(t *testing.T) {
 		},
 		"latest_version, deployed_version with errs": {
 			svc: &Service{
-				ID: "test", Comment: "foo_comment"},
+				ID: "test", Comment: "foo_comment",
+				LatestVersion: latestver.Lookup{
+					Type: "invalid", URL: "release-argus/Argus"}},
 			options: *opt.New(
 				nil, "10x", nil, nil, nil),
-			latestVersion: latestver.Lookup{
-				Type: "invalid", URL: "release-argus/Argus"},
 			deployedVersion: &deployedver.Lookup{
 				Regex: "[0-"},
 			errRegex: []string{
@@ -248,13 +247,45 @@ func TestService_CheckValues(t *testing.T) {
 				`^    url: <required>`,
 				`^    regex: "[^"]+" <invalid>`},
 		},
+		"deployed_version and deployed_versions": {
+			svc: &Service{
+				ID: "test", Comment: "foo_comment",
+				DeployedVersionTargets: &deployedver.Targets{
+					Targets: map[string]*deployedver.Lookup{
+						"eu": {URL: "https://eu.example.com"}}},
+				LatestVersion: latestver.Lookup{
+					Type: "github", URL: "release-argus/Argus"}},
+			deployedVersion: &deployedver.Lookup{
+				URL: "https://example.com"},
+			errRegex: []string{
+				`^test:$`,
+				`^  deployed_versions: <invalid> \(only one of deployed_version or deployed_versions can be used\)$`},
+		},
+		"deployed_versions with errs": {
+			svc: &Service{
+				ID: "test", Comment: "foo_comment",
+				DeployedVersionTargets: &deployedver.Targets{
+					Aggregate: "newest",
+					Targets: map[string]*deployedver.Lookup{
+						"eu": {Regex: "[0-"}}},
+				LatestVersion: latestver.Lookup{
+					Type: "github", URL: "release-argus/Argus"}},
+			errRegex: []string{
+				`^test:$`,
+				`^  deployed_versions:$`,
+				`^    aggregate: "newest" <invalid>`,
+				`^    targets:$`,
+				`^      eu:$`,
+				`^        url: <required>`,
+				`^        regex: "[^"]+" <invalid>`},
+		},
 		"latest_version, deployed_version, command with errs": {
 			svc: &Service{
-				ID: "test", Comment: "foo_comment"},
+				ID: "test", Comment: "foo_comment",
+				LatestVersion: latestver.Lookup{
+					Type: "invalid", URL: "release-argus/Argus"}},
 			options: *opt.New(
 				nil, "10x", nil, nil, nil),
-			latestVersion: latestver.Lookup{
-				Type: "invalid", URL: "release-argus/Argus"},
 			deployedVersion: &deployedver.Lookup{
 				Regex: "[0-"},
 			errRegex: []string{
@@ -272,11 +303,11 @@ func TestService_CheckValues(t *testing.T) {
 		},
 		"latest_version, deployed_version, notify with errs": {
 			svc: &Service{
-				ID: "test", Comment: "foo_comment"},
+				ID: "test", Comment: "foo_comment",
+				LatestVersion: latestver.Lookup{
+					Type: "invalid", URL: "release-argus/Argus"}},
 			options: *opt.New(
 				nil, "10x", nil, nil, nil),
-			latestVersion: latestver.Lookup{
-				Type: "invalid", URL: "release-argus/Argus"},
 			deployedVersion: &deployedver.Lookup{
 				Regex: "[0-"},
 			notifies: shoutrrr.Slice{
@@ -305,11 +336,11 @@ func TestService_CheckValues(t *testing.T) {
 		},
 		"latest_version, deployed_version, webhook with errs": {
 			svc: &Service{
-				ID: "test", Comment: "foo_comment"},
+				ID: "test", Comment: "foo_comment",
+				LatestVersion: latestver.Lookup{
+					Type: "invalid", URL: "release-argus/Argus"}},
 			options: *opt.New(
 				nil, "10x", nil, nil, nil),
-			latestVersion: latestver.Lookup{
-				Type: "invalid", URL: "release-argus/Argus"},
 			deployedVersion: &deployedver.Lookup{
 				Regex: "[0-"},
 			commands: command.Slice{{
@@ -346,11 +377,11 @@ func TestService_CheckValues(t *testing.T) {
 		},
 		"has latest_version+deployed_version, webhook with errs": {
 			svc: &Service{
-				ID: "test", Comment: "foo_comment"},
+				ID: "test", Comment: "foo_comment",
+				LatestVersion: latestver.Lookup{
+					Type: "invalid", URL: "release-argus/Argus"}},
 			options: *opt.New(
 				nil, "10x", nil, nil, nil),
-			latestVersion: latestver.Lookup{
-				Type: "invalid", URL: "release-argus/Argus"},
 			deployedVersion: &deployedver.Lookup{
 				Regex: "[0-"},
 			commands: command.Slice{
@@ -393,7 +424,6 @@ func TestService_CheckValues(t *testing.T) {
 
 			tc.svc.ID = "test"
 			tc.svc.Options = tc.options
-			tc.svc.LatestVersion = tc.latestVersion
 			tc.svc.DeployedVersionLookup = tc.deployedVersion
 			tc.svc.Command = tc.commands
 			tc.svc.WebHook = tc.webhooks
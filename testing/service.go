@@ -88,6 +88,20 @@ func ServiceTest(
 			err == nil,
 		)
 	}
+	// DeployedVersionTargets
+	if service.DeployedVersionTargets != nil {
+		for _, name := range util.SortedKeys(service.DeployedVersionTargets.Targets) {
+			version, err := service.DeployedVersionTargets.Targets[name].Query(false, logFrom)
+			log.Info(
+				fmt.Sprintf(
+					"Deployed version of %q - %q",
+					name, version,
+				),
+				logFrom,
+				err == nil,
+			)
+		}
+	}
 
 	if !log.Testing {
 		os.Exit(0)
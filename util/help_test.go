@@ -22,5 +22,8 @@ func testServiceInfo() ServiceInfo {
 		URL:           "example.com",
 		WebURL:        "other.com",
 		LatestVersion: "NEW",
+		DockerDigest:  "sha256:abc",
+		ReleaseNotes:  "Fixed the thing that was broken",
+		Changelog:     "## NEW\nFixed the thing that was broken\n\n## OLD",
 	}
 }
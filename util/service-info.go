@@ -20,4 +20,7 @@ type ServiceInfo struct {
 	URL           string
 	WebURL        string
 	LatestVersion string
+	DockerDigest  string
+	ReleaseNotes  string
+	Changelog     string
 }
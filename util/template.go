@@ -17,6 +17,7 @@ package util
 import (
 	"strings"
 	"sync"
+	"unicode"
 
 	"github.com/flosch/pongo2/v5"
 )
@@ -29,6 +30,37 @@ import (
 //	<autogenerated>:1 +0x4d
 var pongoMutex = sync.Mutex{}
 
+func init() {
+	pongo2.RegisterFilter("truncate", filterTruncate)
+}
+
+// filterTruncate cuts the input down to at most `param` characters,
+// breaking at the last whitespace before the limit and appending "…".
+//
+// e.g. {{ release_notes | truncate:500 }}
+func filterTruncate(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	return pongo2.AsValue(Truncate(in.String(), param.Integer())), nil
+}
+
+// Truncate `str` to at most `length` characters (excluding the trailing "…"),
+// breaking at the last whitespace before the limit where there is one.
+func Truncate(str string, length int) string {
+	runes := []rune(str)
+	if length < 0 || len(runes) <= length {
+		return str
+	}
+
+	cut := string(runes[:length])
+	// Break on the last whitespace (if there is one after the first word),
+	// unless the limit already falls at the end of a word.
+	if !unicode.IsSpace(runes[length]) {
+		if i := strings.LastIndexAny(cut, " \t\n\r"); i > 0 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRight(cut, " \t\n\r") + "…"
+}
+
 // TemplateString with pongo2 and `context`.
 func TemplateString(template string, context ServiceInfo) (result string) {
 	// If the string isn't a Jinja template
@@ -48,10 +80,13 @@ func TemplateString(template string, context ServiceInfo) (result string) {
 
 	// Render the template.
 	result, err = tpl.Execute(pongo2.Context{
-		"service_id":  context.ID,
-		"service_url": context.URL,
-		"web_url":     context.WebURL,
-		"version":     context.LatestVersion})
+		"service_id":    context.ID,
+		"service_url":   context.URL,
+		"web_url":       context.WebURL,
+		"version":       context.LatestVersion,
+		"docker_digest": context.DockerDigest,
+		"release_notes": context.ReleaseNotes,
+		"changelog":     context.Changelog})
 	if err != nil {
 		panic(err)
 	}
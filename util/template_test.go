@@ -38,6 +38,18 @@ func TestTemplate_String(t *testing.T) {
 		"valid jinja template": {
 			tmpl: "-{% if 'a' == 'a' %}{{ service_id }}{% endif %}-{{ service_url }}-{{ web_url }}-{{ version }}",
 			want: "-something-example.com-other.com-NEW"},
+		"docker_digest": {
+			tmpl: "image@{{ docker_digest }}",
+			want: "image@sha256:abc"},
+		"release_notes": {
+			tmpl: "{{ release_notes }}",
+			want: "Fixed the thing that was broken"},
+		"release_notes truncated": {
+			tmpl: "{{ release_notes|truncate:15 }}",
+			want: "Fixed the thing…"},
+		"changelog": {
+			tmpl: "{{ changelog }}",
+			want: "## NEW\nFixed the thing that was broken\n\n## OLD"},
 		"invalid jinja template panic": {
 			tmpl:       "-{% 'a' == 'a' %}{{ service_id }}{% endif %}-{{ service_url }}-{{ web_url }}-{{ version }}",
 			panicRegex: test.StringPtr("Tag name must be an identifier")},
@@ -100,3 +112,52 @@ func TestCheckTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestTruncate(t *testing.T) {
+	// GIVEN a variety of strings and lengths
+	tests := map[string]struct {
+		str    string
+		length int
+		want   string
+	}{
+		"shorter than length": {
+			str: "foo bar", length: 10,
+			want: "foo bar"},
+		"equal to length": {
+			str: "foo bar", length: 7,
+			want: "foo bar"},
+		"cut at whitespace": {
+			str: "foo bar baz", length: 9,
+			want: "foo bar…"},
+		"cut at newline": {
+			str: "foo\nbar baz", length: 6,
+			want: "foo…"},
+		"no whitespace to cut at": {
+			str: "foobarbaz", length: 4,
+			want: "foob…"},
+		"multi-byte characters": {
+			str: "ééé ééé", length: 5,
+			want: "ééé…"},
+		"zero length": {
+			str: "foo", length: 0,
+			want: "…"},
+		"negative length": {
+			str: "foo", length: -1,
+			want: "foo"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN Truncate is called
+			got := Truncate(tc.str, tc.length)
+
+			// THEN the string is cut to the expected length
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
@@ -21,7 +21,9 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"os"
 	"regexp"
@@ -524,6 +526,174 @@ func GetValueByKey(rawBody []byte, key string, jsonFrom string) (string, error)
 	return navigateJSON(&jsonData, key)
 }
 
+// DecodeJSON will decode `data` for GetValuesByKey, keeping numbers as they were written.
+func DecodeJSON(data []byte) (decoded interface{}, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&decoded); err != nil {
+		//nolint:wrapcheck
+		return
+	}
+	// Only one value.
+	if _, err = decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	return decoded, nil
+}
+
+// DecodeYAML will decode `data` for GetValuesByKey, keeping scalars as they were written.
+func DecodeYAML(data []byte) (decoded interface{}, err error) {
+	var node yaml.Node
+	if err = yaml.Unmarshal(data, &node); err != nil {
+		//nolint:wrapcheck
+		return
+	}
+	return yamlNodeValue(&node, map[*yaml.Node]bool{})
+}
+
+// yamlNodeValue will return the maps/lists/strings of `node`.
+//
+// `expanding` holds the aliased nodes being expanded, to catch an alias to itself.
+func yamlNodeValue(node *yaml.Node, expanding map[*yaml.Node]bool) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return yamlNodeValue(node.Content[0], expanding)
+	case yaml.MappingNode:
+		value := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			element, err := yamlNodeValue(node.Content[i+1], expanding)
+			if err != nil {
+				return nil, err
+			}
+			value[node.Content[i].Value] = element
+		}
+		return value, nil
+	case yaml.SequenceNode:
+		value := make([]interface{}, len(node.Content))
+		for i := range node.Content {
+			element, err := yamlNodeValue(node.Content[i], expanding)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = element
+		}
+		return value, nil
+	case yaml.AliasNode:
+		if expanding[node.Alias] {
+			return nil, fmt.Errorf("anchor %q value contains itself",
+				node.Value)
+		}
+		expanding[node.Alias] = true
+		defer delete(expanding, node.Alias)
+		return yamlNodeValue(node.Alias, expanding)
+	case yaml.ScalarNode:
+		if node.ShortTag() == "!!null" {
+			return nil, nil
+		}
+		return node.Value, nil
+	}
+	return nil, nil
+}
+
+// keyWildcard is the key to select every element of a list/map, e.g. "items[*].tag_name".
+const keyWildcard = "*"
+
+// GetValuesByKey will return every value at the key in the JSON/YAML data from DecodeJSON/DecodeYAML.
+//
+// The key may contain wildcards ("[*]" or ".*") to select every element of a list/map,
+// e.g. "items[*].tag_name"
+func GetValuesByKey(data interface{}, key string) (values []string, err error) {
+	if key == "" {
+		return nil, fmt.Errorf("no key was given to navigate the data")
+	}
+	keys, err := ParseKeys(strings.ReplaceAll(key, "[*]", "."+keyWildcard))
+	if err != nil {
+		return
+	}
+
+	values, err = navigateValues(data, keys, key)
+	if err == nil && len(values) == 0 {
+		err = fmt.Errorf("failed to find any values for %q",
+			key)
+	}
+	return
+}
+
+// navigateValues will return the values at `keys` in data.
+func navigateValues(data interface{}, keys []interface{}, fullKey string) (values []string, err error) {
+	if len(keys) == 0 {
+		switch v := data.(type) {
+		case string, json.Number, bool:
+			return []string{fmt.Sprint(v)}, nil
+		case nil:
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%q is not a value (got %v)",
+			fullKey, data)
+	}
+
+	key := keys[0]
+	switch value := data.(type) {
+	case map[string]interface{}:
+		if key == keyWildcard {
+			// Every value, in key order.
+			mapKeys := make([]string, 0, len(value))
+			for k := range value {
+				mapKeys = append(mapKeys, k)
+			}
+			sort.Strings(mapKeys)
+			for _, k := range mapKeys {
+				// Ignore the elements without the key.
+				//nolint:errcheck
+				elementValues, _ := navigateValues(value[k], keys[1:], fullKey)
+				values = append(values, elementValues...)
+			}
+			return
+		}
+		keyStr, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("got a map, but the key is not a string: %v in %q",
+				key, fullKey)
+		}
+		element, found := value[keyStr]
+		if !found {
+			return nil, fmt.Errorf("key %q not found in %q",
+				keyStr, fullKey)
+		}
+		return navigateValues(element, keys[1:], fullKey)
+	case []interface{}:
+		if key == keyWildcard {
+			for _, element := range value {
+				// Ignore the elements without the key.
+				//nolint:errcheck
+				elementValues, _ := navigateValues(element, keys[1:], fullKey)
+				values = append(values, elementValues...)
+			}
+			return
+		}
+		index, ok := key.(int)
+		if !ok {
+			return nil, fmt.Errorf("got a list, but the key is not an integer index: %v in %q",
+				key, fullKey)
+		}
+		// Negative index
+		if index < 0 {
+			index = len(value) + index
+		}
+		if index >= len(value) || index < 0 {
+			return nil, fmt.Errorf("index %v out of range in %q",
+				key, fullKey)
+		}
+		return navigateValues(value[index], keys[1:], fullKey)
+	}
+
+	return nil, fmt.Errorf("got a value of %v, but there are more keys to navigate in %q",
+		data, fullKey)
+}
+
 // ToYAMLString will return a YAML string representation of the interface.
 func ToYAMLString(iface interface{}, prefix string) (str string) {
 	buf := &bytes.Buffer{}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -2209,6 +2210,226 @@ func TestGetValueByKey(t *testing.T) {
 	}
 }
 
+func TestGetValuesByKey(t *testing.T) {
+	// GIVEN a JSON string
+	tests := map[string]struct {
+		input    string
+		key      string
+		want     []string
+		errRegex string
+	}{
+		"empty key": {
+			input:    `{"foo": "bar"}`,
+			key:      "",
+			errRegex: `no key was given`,
+		},
+		"simple JSON": {
+			input: `{"foo": "bar"}`,
+			key:   "foo",
+			want:  []string{"bar"},
+		},
+		"number and bool values": {
+			input: `{"foo": [1, 2.5, true]}`,
+			key:   "foo[*]",
+			want:  []string{"1", "2.5", "true"},
+		},
+		"numbers as they were written": {
+			input: `{"build": 20240101, "version": 1.10, "big": 9007199254740993}`,
+			key:   "*",
+			want:  []string{"9007199254740993", "20240101", "1.10"},
+		},
+		"negative index": {
+			input: `{"foo": [{"bar": "baz"}, {"bar": "bish"}]}`,
+			key:   "foo[-1].bar",
+			want:  []string{"bish"},
+		},
+		"wildcard over a list": {
+			input: `{"items": [{"tag_name": "1.0.0"}, {"tag_name": "1.1.0"}]}`,
+			key:   "items[*].tag_name",
+			want:  []string{"1.0.0", "1.1.0"},
+		},
+		"wildcard over a list ignores elements without the key": {
+			input: `{"items": [{"tag_name": "1.0.0"}, {"name": "1.1.0"}, {"tag_name": null}, {"tag_name": "1.2.0"}]}`,
+			key:   "items[*].tag_name",
+			want:  []string{"1.0.0", "1.2.0"},
+		},
+		"wildcard over a map, in key order": {
+			input: `{"versions": {"b": {"v": "2.0.0"}, "a": {"v": "1.0.0"}}}`,
+			key:   "versions.*.v",
+			want:  []string{"1.0.0", "2.0.0"},
+		},
+		"nested wildcards": {
+			input: `[{"tags": ["a", "b"]}, {"tags": ["c"]}]`,
+			key:   "[*].tags[*]",
+			want:  []string{"a", "b", "c"},
+		},
+		"fail: no values found": {
+			input:    `{"items": [{"name": "1.0.0"}]}`,
+			key:      "items[*].tag_name",
+			errRegex: `failed to find any values for "items\[\*\]\.tag_name"`,
+		},
+		"fail: key not found": {
+			input:    `{"foo": "bar"}`,
+			key:      "bar",
+			errRegex: `key "bar" not found`,
+		},
+		"fail: not a value": {
+			input:    `{"foo": {"bar": "baz"}}`,
+			key:      "foo",
+			errRegex: `"foo" is not a value`,
+		},
+		"fail: index out of range": {
+			input:    `{"foo": ["bar"]}`,
+			key:      "foo[1]",
+			errRegex: `out of range`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := DecodeJSON([]byte(tc.input))
+			if err != nil {
+				t.Fatalf("invalid test input: %v", err)
+			}
+
+			// WHEN GetValuesByKey is called
+			got, err := GetValuesByKey(data, tc.key)
+
+			// THEN the values are returned correctly
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+			// AND the error is returned correctly
+			if tc.errRegex == "" {
+				tc.errRegex = `^$`
+			}
+			e := ErrorToString(err)
+			if !regexp.MustCompile(tc.errRegex).MatchString(e) {
+				t.Errorf("want error matching %q, got %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	// GIVEN some JSON
+	tests := map[string]struct {
+		input    string
+		want     interface{}
+		errRegex string
+	}{
+		"numbers are kept as they were written": {
+			input: `{"build": 20240101, "version": 1.10}`,
+			want: map[string]interface{}{
+				"build":   json.Number("20240101"),
+				"version": json.Number("1.10")},
+		},
+		"list": {
+			input: `["a", true, null]`,
+			want:  []interface{}{"a", true, nil},
+		},
+		"invalid JSON": {
+			input:    `{"foo":`,
+			errRegex: `unexpected EOF`,
+		},
+		"more than one value": {
+			input:    `{"foo": "bar"} {"foo": "baz"}`,
+			errRegex: `invalid character after top-level value`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN DecodeJSON is called
+			got, err := DecodeJSON([]byte(tc.input))
+
+			// THEN the JSON is decoded correctly
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("want: %#v\ngot:  %#v",
+					tc.want, got)
+			}
+			// AND the error is returned correctly
+			if tc.errRegex == "" {
+				tc.errRegex = `^$`
+			}
+			e := ErrorToString(err)
+			if !regexp.MustCompile(tc.errRegex).MatchString(e) {
+				t.Errorf("want error matching %q, got %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestDecodeYAML(t *testing.T) {
+	// GIVEN some YAML
+	tests := map[string]struct {
+		input    string
+		want     interface{}
+		errRegex string
+	}{
+		"scalars are kept as they were written": {
+			input: "build: 20240101\nversion: 1.10\nlatest: true\n",
+			want: map[string]interface{}{
+				"build":   "20240101",
+				"version": "1.10",
+				"latest":  "true"},
+		},
+		"list with a null": {
+			input: "- a\n- ~\n",
+			want:  []interface{}{"a", nil},
+		},
+		"alias": {
+			input: "base: &base\n  version: 1.0\nother: *base\n",
+			want: map[string]interface{}{
+				"base":  map[string]interface{}{"version": "1.0"},
+				"other": map[string]interface{}{"version": "1.0"}},
+		},
+		"alias to itself": {
+			input:    "base: &base\n  self: *base\n",
+			errRegex: `anchor "base" value contains itself`,
+		},
+		"empty": {
+			input: "",
+			want:  nil,
+		},
+		"invalid YAML": {
+			input:    "foo: [",
+			errRegex: `did not find expected node content`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN DecodeYAML is called
+			got, err := DecodeYAML([]byte(tc.input))
+
+			// THEN the YAML is decoded correctly
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("want: %#v\ngot:  %#v",
+					tc.want, got)
+			}
+			// AND the error is returned correctly
+			if tc.errRegex == "" {
+				tc.errRegex = `^$`
+			}
+			e := ErrorToString(err)
+			if !regexp.MustCompile(tc.errRegex).MatchString(e) {
+				t.Errorf("want error matching %q, got %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
 func TestTo____String(t *testing.T) {
 	// GIVEN a struct to print in YAML format
 	tests := map[string]struct {
@@ -16,6 +16,7 @@ package apitype
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -26,17 +27,19 @@ import (
 
 // ServiceSummary is the Summary of a Service.
 type ServiceSummary struct {
-	ID                       string  `json:"id,omitempty" yaml:"id,omitempty"`                                     //
-	Active                   *bool   `json:"active,omitempty" yaml:"active,omitempty"`                             // Active Service?
-	Comment                  *string `json:"comment,omitempty" yaml:"comment,omitempty"`                           // Comment on the Service
-	Type                     *string `json:"type,omitempty" yaml:"type,omitempty"`                                 // "github"/"URL"
-	WebURL                   string  `json:"url,omitempty" yaml:"url,omitempty"`                                   // URL to provide on the Web UI
-	Icon                     *string `json:"icon,omitempty" yaml:"icon,omitempty"`                                 // Service.Dashboard.Icon / Service.Notify.*.Params.Icon / Service.Notify.*.Defaults.Params.Icon
-	IconLinkTo               *string `json:"icon_link_to,omitempty" yaml:"icon_link_to,omitempty"`                 // URL to redirect Icon clicks to
-	HasDeployedVersionLookup *bool   `json:"has_deployed_version,omitempty" yaml:"has_deployed_version,omitempty"` // Whether this service has a DeployedVersionLookup
-	Command                  *int    `json:"command,omitempty" yaml:"command,omitempty"`                           // Number of Commands to send on a new release
-	WebHook                  *int    `json:"webhook,omitempty" yaml:"webhook,omitempty"`                           // Number of WebHooks to send on a new release
-	Status                   *Status `json:"status,omitempty" yaml:"status,omitempty"`                             // Track the Status of this source (version and regex misses)
+	ID                       string    `json:"id,omitempty" yaml:"id,omitempty"`                                     //
+	Active                   *bool     `json:"active,omitempty" yaml:"active,omitempty"`                             // Active Service?
+	Comment                  *string   `json:"comment,omitempty" yaml:"comment,omitempty"`                           // Comment on the Service
+	Type                     *string   `json:"type,omitempty" yaml:"type,omitempty"`                                 // "github"/"URL"
+	WebURL                   string    `json:"url,omitempty" yaml:"url,omitempty"`                                   // URL to provide on the Web UI
+	Icon                     *string   `json:"icon,omitempty" yaml:"icon,omitempty"`                                 // Service.Dashboard.Icon / Service.Notify.*.Params.Icon / Service.Notify.*.Defaults.Params.Icon
+	IconLinkTo               *string   `json:"icon_link_to,omitempty" yaml:"icon_link_to,omitempty"`                 // URL to redirect Icon clicks to
+	HasDeployedVersionLookup *bool     `json:"has_deployed_version,omitempty" yaml:"has_deployed_version,omitempty"` // Whether this service has a DeployedVersionLookup
+	DeployedAggregate        string    `json:"deployed_aggregate,omitempty" yaml:"deployed_aggregate,omitempty"`     // How the versions of the deployed_versions targets give the deployed version
+	Command                  *int      `json:"command,omitempty" yaml:"command,omitempty"`                           // Number of Commands to send on a new release
+	WebHook                  *int      `json:"webhook,omitempty" yaml:"webhook,omitempty"`                           // Number of WebHooks to send on a new release
+	IgnoreVersions           *[]string `json:"ignore_versions,omitempty" yaml:"ignore_versions,omitempty"`           // Versions that will never be used
+	Status                   *Status   `json:"status,omitempty" yaml:"status,omitempty"`                             // Track the Status of this source (version and regex misses)
 }
 
 // String returns a JSON string representation of the ServiceSummary.
@@ -84,6 +87,23 @@ func (s *ServiceSummary) RemoveUnchanged(other *ServiceSummary) {
 	if util.EvalNilPtr(other.HasDeployedVersionLookup, false) == util.EvalNilPtr(s.HasDeployedVersionLookup, false) {
 		s.HasDeployedVersionLookup = nil
 	}
+	// DeployedAggregate
+	if other.DeployedAggregate == s.DeployedAggregate {
+		s.DeployedAggregate = ""
+	}
+	// IgnoreVersions
+	var otherIgnoreVersions, ignoreVersions []string
+	if other.IgnoreVersions != nil {
+		otherIgnoreVersions = *other.IgnoreVersions
+	}
+	if s.IgnoreVersions != nil {
+		ignoreVersions = *s.IgnoreVersions
+	}
+	if slices.Equal(otherIgnoreVersions, ignoreVersions) {
+		s.IgnoreVersions = nil
+	} else if s.IgnoreVersions == nil {
+		s.IgnoreVersions = &[]string{}
+	}
 
 	// Status
 	statusSameCount := 0
@@ -98,28 +118,108 @@ func (s *ServiceSummary) RemoveUnchanged(other *ServiceSummary) {
 		s.Status.DeployedVersionTimestamp = ""
 		statusSameCount++
 	}
+	// Status.DeployedTargets
+	var otherDeployedTargets, deployedTargets []DeployedTarget
+	if other.Status.DeployedTargets != nil {
+		otherDeployedTargets = *other.Status.DeployedTargets
+	}
+	if s.Status.DeployedTargets != nil {
+		deployedTargets = *s.Status.DeployedTargets
+	}
+	if slices.Equal(otherDeployedTargets, deployedTargets) {
+		s.Status.DeployedTargets = nil
+		statusSameCount++
+	} else if s.Status.DeployedTargets == nil {
+		s.Status.DeployedTargets = &[]DeployedTarget{}
+	}
 	// Status.LatestVersion
 	if other.Status.LatestVersion == s.Status.LatestVersion {
 		s.Status.LatestVersion = ""
 		s.Status.LatestVersionTimestamp = ""
 		statusSameCount++
 	}
+	// Status.PendingVersion
+	var otherPendingVersion, pendingVersion PendingVersion
+	if other.Status.PendingVersion != nil {
+		otherPendingVersion = *other.Status.PendingVersion
+	}
+	if s.Status.PendingVersion != nil {
+		pendingVersion = *s.Status.PendingVersion
+	}
+	if otherPendingVersion.Version == pendingVersion.Version &&
+		otherPendingVersion.Until == pendingVersion.Until {
+		s.Status.PendingVersion = nil
+		statusSameCount++
+	} else if s.Status.PendingVersion == nil {
+		s.Status.PendingVersion = &PendingVersion{}
+	}
+	// Status.VerifyFailure
+	var otherVerifyFailure, verifyFailure VerifyFailure
+	if other.Status.VerifyFailure != nil {
+		otherVerifyFailure = *other.Status.VerifyFailure
+	}
+	if s.Status.VerifyFailure != nil {
+		verifyFailure = *s.Status.VerifyFailure
+	}
+	if otherVerifyFailure == verifyFailure {
+		s.Status.VerifyFailure = nil
+		statusSameCount++
+	} else if s.Status.VerifyFailure == nil {
+		s.Status.VerifyFailure = &VerifyFailure{}
+	}
 	// nil Status if all fields are the same
-	if statusSameCount == 3 {
+	if statusSameCount == 6 {
 		s.Status = nil
 	}
 }
 
 // Status is the Status of a Service.
 type Status struct {
-	ApprovedVersion          string `json:"approved_version,omitempty" yaml:"approved_version,omitempty"`                     // The version that's been approved
-	DeployedVersion          string `json:"deployed_version,omitempty" yaml:"deployed_version,omitempty"`                     // Track the deployed version of the service from the last successful WebHook
-	DeployedVersionTimestamp string `json:"deployed_version_timestamp,omitempty" yaml:"deployed_version_timestamp,omitempty"` // UTC timestamp that the deployed version change was noticed
-	LatestVersion            string `json:"latest_version,omitempty" yaml:"latest_version,omitempty"`                         // Latest version found from query()
-	LatestVersionTimestamp   string `json:"latest_version_timestamp,omitempty" yaml:"latest_version_timestamp,omitempty"`     // UTC timestamp that the latest version change was noticed
-	LastQueried              string `json:"last_queried,omitempty" yaml:"last_queried,omitempty"`                             // UTC timestamp that version was last queried/checked
-	RegexMissesContent       uint   `json:"regex_misses_content,omitempty" yaml:"regex_misses_content,omitempty"`             // Counter for the number of regex misses on URL content
-	RegexMissesVersion       uint   `json:"regex_misses_version,omitempty" yaml:"regex_misses_version,omitempty"`             // Counter for the number of regex misses on version
+	ApprovedVersion          string            `json:"approved_version,omitempty" yaml:"approved_version,omitempty"`                     // The version that's been approved
+	DeployedVersion          string            `json:"deployed_version,omitempty" yaml:"deployed_version,omitempty"`                     // Track the deployed version of the service from the last successful WebHook
+	DeployedVersionTimestamp string            `json:"deployed_version_timestamp,omitempty" yaml:"deployed_version_timestamp,omitempty"` // UTC timestamp that the deployed version change was noticed
+	DeployedTargets          *[]DeployedTarget `json:"deployed_targets,omitempty" yaml:"deployed_targets,omitempty"`                     // Deployed versions of the deployed_versions targets
+	LatestVersion            string            `json:"latest_version,omitempty" yaml:"latest_version,omitempty"`                         // Latest version found from query()
+	LatestVersionTimestamp   string            `json:"latest_version_timestamp,omitempty" yaml:"latest_version_timestamp,omitempty"`     // UTC timestamp that the latest version change was noticed
+	LastQueried              string            `json:"last_queried,omitempty" yaml:"last_queried,omitempty"`                             // UTC timestamp that version was last queried/checked
+	RegexMissesContent       uint              `json:"regex_misses_content,omitempty" yaml:"regex_misses_content,omitempty"`             // Counter for the number of regex misses on URL content
+	RegexMissesVersion       uint              `json:"regex_misses_version,omitempty" yaml:"regex_misses_version,omitempty"`             // Counter for the number of regex misses on version
+	PendingVersion           *PendingVersion   `json:"pending_version,omitempty" yaml:"pending_version,omitempty"`                       // Newest version being held back until it reaches the require.min_age
+	VerifyFailure            *VerifyFailure    `json:"verify_failure,omitempty" yaml:"verify_failure,omitempty"`                         // Newest version that failed the require.verify
+}
+
+// DeployedTarget is the deployed version of a named target of a Service.
+type DeployedTarget struct {
+	Name      string `json:"name" yaml:"name"`                               // Name of the target
+	Version   string `json:"version,omitempty" yaml:"version,omitempty"`     // Version deployed to the target
+	Timestamp string `json:"timestamp,omitempty" yaml:"timestamp,omitempty"` // UTC timestamp that the version change was noticed
+}
+
+// PendingVersion is a version that's being held back until it reaches the require.min_age.
+type PendingVersion struct {
+	Version   string `json:"version,omitempty" yaml:"version,omitempty"`     // The version being held back
+	Until     string `json:"until,omitempty" yaml:"until,omitempty"`         // UTC timestamp that the version will reach the min_age
+	Remaining string `json:"remaining,omitempty" yaml:"remaining,omitempty"` // Time remaining until the version reaches the min_age
+}
+
+// VerifyFailure is a version that failed the require.verify.
+type VerifyFailure struct {
+	Version string `json:"version,omitempty" yaml:"version,omitempty"` // The version that failed verification
+	Reason  string `json:"reason,omitempty" yaml:"reason,omitempty"`   // Why it failed
+}
+
+// ReleaseNotes are the release notes of the LatestVersion of a Service.
+type ReleaseNotes struct {
+	Version      string `json:"version,omitempty" yaml:"version,omitempty"`             // The version the notes are for
+	ReleaseNotes string `json:"release_notes,omitempty" yaml:"release_notes,omitempty"` // The release notes (e.g. GitHub release body)
+}
+
+// Changelog is the release notes of the versions newer than the DeployedVersion of a Service,
+// up to and including its LatestVersion.
+type Changelog struct {
+	DeployedVersion string         `json:"deployed_version,omitempty" yaml:"deployed_version,omitempty"` // The version currently deployed
+	LatestVersion   string         `json:"latest_version,omitempty" yaml:"latest_version,omitempty"`     // The latest version found
+	Releases        []ReleaseNotes `json:"releases,omitempty" yaml:"releases,omitempty"`                 // Release notes of each version (newest first)
 }
 
 // String returns a JSON string representation of the Status.
@@ -343,15 +443,16 @@ type ServiceSlice map[string]*Service
 // Service is a source to be serviceed and provides everything needed to extract
 // the latest version from the URL provided.
 type Service struct {
-	Comment               string                 `json:"comment,omitempty" yaml:"comment,omitempty"`                   // Comment on the Service
-	Options               *ServiceOptions        `json:"options,omitempty" yaml:"options,omitempty"`                   // Options to give the Service
-	LatestVersion         *LatestVersion         `json:"latest_version,omitempty" yaml:"latest_version,omitempty"`     // Latest version lookup for the Service
-	Command               *CommandSlice          `json:"command,omitempty" yaml:"command,omitempty"`                   // OS Commands to run on new release
-	Notify                *NotifySlice           `json:"notify,omitempty" yaml:"notify,omitempty"`                     // Service-specific Notify vars
-	WebHook               *WebHookSlice          `json:"webhook,omitempty" yaml:"webhook,omitempty"`                   // Service-specific WebHook vars
-	DeployedVersionLookup *DeployedVersionLookup `json:"deployed_version,omitempty" yaml:"deployed_version,omitempty"` // Var to scrape the Service's current deployed version
-	Dashboard             *DashboardOptions      `json:"dashboard,omitempty" yaml:"dashboard,omitempty"`               // Dashboard options
-	Status                *Status                `json:"status,omitempty" yaml:"status,omitempty"`                     // Track the Status of this source (version and regex misses)
+	Comment                string                  `json:"comment,omitempty" yaml:"comment,omitempty"`                     // Comment on the Service
+	Options                *ServiceOptions         `json:"options,omitempty" yaml:"options,omitempty"`                     // Options to give the Service
+	LatestVersion          *LatestVersion          `json:"latest_version,omitempty" yaml:"latest_version,omitempty"`       // Latest version lookup for the Service
+	Command                *CommandSlice           `json:"command,omitempty" yaml:"command,omitempty"`                     // OS Commands to run on new release
+	Notify                 *NotifySlice            `json:"notify,omitempty" yaml:"notify,omitempty"`                       // Service-specific Notify vars
+	WebHook                *WebHookSlice           `json:"webhook,omitempty" yaml:"webhook,omitempty"`                     // Service-specific WebHook vars
+	DeployedVersionLookup  *DeployedVersionLookup  `json:"deployed_version,omitempty" yaml:"deployed_version,omitempty"`   // Var to scrape the Service's current deployed version
+	DeployedVersionTargets *DeployedVersionTargets `json:"deployed_versions,omitempty" yaml:"deployed_versions,omitempty"` // Vars to scrape the deployed versions of the Service's targets
+	Dashboard              *DashboardOptions       `json:"dashboard,omitempty" yaml:"dashboard,omitempty"`                 // Dashboard options
+	Status                 *Status                 `json:"status,omitempty" yaml:"status,omitempty"`                       // Track the Status of this source (version and regex misses)
 }
 
 // String returns a string representation of the Service.
@@ -380,6 +481,7 @@ type ServiceOptions struct {
 	Active             *bool  `json:"active,omitempty" yaml:"active,omitempty"`                           // Active Service?
 	Interval           string `json:"interval,omitempty" yaml:"interval,omitempty"`                       // AhBmCs = Sleep A hours, B minutes and C seconds between queries
 	SemanticVersioning *bool  `json:"semantic_versioning,omitempty" yaml:"semantic_versioning,omitempty"` // default - true = Version has to be greater than the previous to trigger alerts/WebHooks
+	VersionScheme      string `json:"version_scheme,omitempty" yaml:"version_scheme,omitempty"`           // default - semver = Scheme used to compare versions
 }
 
 // DashboardOptions.
@@ -392,13 +494,20 @@ type DashboardOptions struct {
 
 // LatestVersion lookup of the service.
 type LatestVersion struct {
-	Type              string                `json:"type,omitempty" yaml:"type,omitempty"`                               // Service Type, github/url
+	Type              string                `json:"type,omitempty" yaml:"type,omitempty"`                               // Service Type, container/crates/feed/github/gitea/gitlab/go/helm/npm/pypi/url
 	URL               string                `json:"url,omitempty" yaml:"url,omitempty"`                                 // URL to query
+	BaseURL           string                `json:"base_url,omitempty" yaml:"base_url,omitempty"`                       // Base URL of the Gitea/GitLab instance/package registry
+	Username          string                `json:"username,omitempty" yaml:"username,omitempty"`                       // Username for the container registry/helm repository
+	Chart             string                `json:"chart,omitempty" yaml:"chart,omitempty"`                             // Name of the helm chart
+	AppVersion        *bool                 `json:"app_version,omitempty" yaml:"app_version,omitempty"`                 // Whether to use the appVersion of the helm chart
+	AllMatches        *bool                 `json:"all_matches,omitempty" yaml:"all_matches,omitempty"`                 // Whether to consider every match of the final url_command
 	AccessToken       string                `json:"access_token,omitempty" yaml:"access_token,omitempty"`               // GitHub access token to use
 	AllowInvalidCerts *bool                 `json:"allow_invalid_certs,omitempty" yaml:"allow_invalid_certs,omitempty"` // default - false = Disallows invalid HTTPS certificates
 	UsePreRelease     *bool                 `json:"use_prerelease,omitempty" yaml:"use_prerelease,omitempty"`           // Whether GitHub prereleases should be used
+	VersionConstraint string                `json:"version_constraint,omitempty" yaml:"version_constraint,omitempty"`   // Semantic version range the version must satisfy
 	URLCommands       *URLCommandSlice      `json:"url_commands,omitempty" yaml:"url_commands,omitempty"`               // Commands to filter the release from the URL request
 	Require           *LatestVersionRequire `json:"require,omitempty" yaml:"require,omitempty"`                         // Requirements for the version to be considered valid
+	IgnoreVersions    []string              `json:"ignore_versions,omitempty" yaml:"ignore_versions,omitempty"`         // Versions that will never be used
 }
 
 // String returns a string representation of the LatestVersion.
@@ -416,6 +525,8 @@ type LatestVersionDefaults struct {
 	AccessToken       string                        `json:"access_token,omitempty" yaml:"access_token,omitempty"`               // GitHub access token to use
 	AllowInvalidCerts *bool                         `json:"allow_invalid_certs,omitempty" yaml:"allow_invalid_certs,omitempty"` // default - false = Disallows invalid HTTPS certificates
 	UsePreRelease     *bool                         `json:"use_prerelease,omitempty" yaml:"use_prerelease,omitempty"`           // Whether GitHub prereleases should be used
+	VersionConstraint string                        `json:"version_constraint,omitempty" yaml:"version_constraint,omitempty"`   // Semantic version range the version must satisfy
+	GitHubBaseURL     string                        `json:"github_base_url,omitempty" yaml:"github_base_url,omitempty"`         // Base URL of the GitHub API
 	Require           *LatestVersionRequireDefaults `json:"require,omitempty" yaml:"require,omitempty"`
 }
 
@@ -434,9 +545,13 @@ func (r *LatestVersionRequireDefaults) String() string {
 // LatestVersionRequire contains commands, regex etc for the release to be considered valid.
 type LatestVersionRequire struct {
 	Command      []string            `json:"command,omitempty" yaml:"command,omitempty"`             // Require Command to pass
+	HTTP         *RequireHTTP        `json:"http,omitempty" yaml:"http,omitempty"`                   // HTTP response requirements
 	Docker       *RequireDockerCheck `json:"docker,omitempty" yaml:"docker,omitempty"`               // Docker image tag requirements
 	RegexContent string              `json:"regex_content,omitempty" yaml:"regex_content,omitempty"` // "abc-[a-z]+-{{ version }}_amd64.deb" This regex must exist in the body of the URL to trigger new version actions
 	RegexVersion string              `json:"regex_version,omitempty" yaml:"regex_version,omitempty"` // "v*[0-9.]+" The version found must match this release to trigger new version actions
+	MinAge       string              `json:"min_age,omitempty" yaml:"min_age,omitempty"`             // "48h" The release must have been published (or first seen) this long ago
+	Assets       []string            `json:"assets,omitempty" yaml:"assets,omitempty"`               // "myapp_{{ version }}_linux_amd64.tar.gz" These regexes must all match an asset name of the release
+	Verify       *RequireVerify      `json:"verify,omitempty" yaml:"verify,omitempty"`               // Checksum/signature verification of a release asset
 }
 
 // String returns a string representation of the LatestVersionRequire.
@@ -469,17 +584,66 @@ type RequireDockerCheckDefaults struct {
 }
 
 type RequireDockerCheck struct {
-	Type     string `json:"type,omitempty" yaml:"type,omitempty"`         // Where to check, e.g. hub (DockerHub), GHCR, Quay
-	Image    string `json:"image,omitempty" yaml:"image,omitempty"`       // Image to check
-	Tag      string `json:"tag,omitempty" yaml:"tag,omitempty"`           // Tag to check for
-	Username string `json:"username,omitempty" yaml:"username,omitempty"` // Username to get a new token
-	Token    string `json:"token,omitempty" yaml:"token,omitempty"`       // Token to get the token for the queries
+	Type      string   `json:"type,omitempty" yaml:"type,omitempty"`           // Where to check, e.g. hub (DockerHub), GHCR, Quay, registry
+	Registry  string   `json:"registry,omitempty" yaml:"registry,omitempty"`   // URL of the registry for the "registry" type
+	Image     string   `json:"image,omitempty" yaml:"image,omitempty"`         // Image to check
+	Tag       string   `json:"tag,omitempty" yaml:"tag,omitempty"`             // Tag to check for
+	Platforms []string `json:"platforms,omitempty" yaml:"platforms,omitempty"` // Platforms the tag must have images for
+	Username  string   `json:"username,omitempty" yaml:"username,omitempty"`   // Username to get a new token
+	Token     string   `json:"token,omitempty" yaml:"token,omitempty"`         // Token to get the token for the queries
+}
+
+// RequireHTTP is a request that must give the expected response.
+type RequireHTTP struct {
+	URL               string     `json:"url,omitempty" yaml:"url,omitempty"`                                 // URL to request
+	Method            string     `json:"method,omitempty" yaml:"method,omitempty"`                           // HEAD/GET
+	AllowInvalidCerts bool       `json:"allow_invalid_certs,omitempty" yaml:"allow_invalid_certs,omitempty"` // Allow invalid HTTPS certificates
+	BasicAuth         *BasicAuth `json:"basic_auth,omitempty" yaml:"basic_auth,omitempty"`                   // Basic Auth credentials
+	Headers           []Header   `json:"headers,omitempty" yaml:"headers,omitempty"`                         // Request Headers
+	StatusCode        int        `json:"status_code,omitempty" yaml:"status_code,omitempty"`                 // Status code the response must have
+	ContentType       string     `json:"content_type,omitempty" yaml:"content_type,omitempty"`               // RegEx the Content-Type must match
+	MinSize           int64      `json:"min_size,omitempty" yaml:"min_size,omitempty"`                       // Minimum size (in bytes) of the response body
+	MaxSize           int64      `json:"max_size,omitempty" yaml:"max_size,omitempty"`                       // Maximum size (in bytes) of the response body
+}
+
+// RequireVerify is the checksum/signature verification of a release asset.
+type RequireVerify struct {
+	Asset     string                  `json:"asset,omitempty" yaml:"asset,omitempty"`         // RegEx of the asset to verify
+	Checksums string                  `json:"checksums,omitempty" yaml:"checksums,omitempty"` // RegEx of the checksums asset
+	Signature *RequireVerifySignature `json:"signature,omitempty" yaml:"signature,omitempty"` // Detached signature of the checksums/asset
+}
+
+// RequireVerifySignature is a detached signature verified with a local public key.
+type RequireVerifySignature struct {
+	Type      string `json:"type,omitempty" yaml:"type,omitempty"`             // cosign/gpg/minisign
+	Asset     string `json:"asset,omitempty" yaml:"asset,omitempty"`           // RegEx of the signature asset
+	PublicKey string `json:"public_key,omitempty" yaml:"public_key,omitempty"` // Path to the public key
+}
+
+// DeployedVersionTargets are the named DeployedVersionLookups of the places a service is deployed to.
+type DeployedVersionTargets struct {
+	Aggregate string                            `json:"aggregate,omitempty" yaml:"aggregate,omitempty"` // How the versions of the targets give the deployed version.
+	Targets   map[string]*DeployedVersionLookup `json:"targets,omitempty" yaml:"targets,omitempty"`     // Lookups of the deployed versions, keyed by target name.
 }
 
 // DeployedVersionLookup of the service.
 type DeployedVersionLookup struct {
+	Type              string                 `json:"type,omitempty" yaml:"type,omitempty"`                               // "url"/"command"/"file"/"docker"/"kubernetes".
 	Method            string                 `json:"method,omitempty" yaml:"method,omitempty"`                           // HTTP method.
 	URL               string                 `json:"url,omitempty" yaml:"url,omitempty"`                                 // URL to query.
+	Command           []string               `json:"command,omitempty" yaml:"command,omitempty"`                         // Command to run.
+	Timeout           string                 `json:"timeout,omitempty" yaml:"timeout,omitempty"`                         // Time to wait for the command to finish.
+	Path              string                 `json:"path,omitempty" yaml:"path,omitempty"`                               // Path of the file to read.
+	Watch             *bool                  `json:"watch,omitempty" yaml:"watch,omitempty"`                             // Whether to watch the file for changes.
+	Host              string                 `json:"host,omitempty" yaml:"host,omitempty"`                               // Docker Engine API to query.
+	Kubeconfig        string                 `json:"kubeconfig,omitempty" yaml:"kubeconfig,omitempty"`                   // Path of the kubeconfig to use.
+	Kind              string                 `json:"kind,omitempty" yaml:"kind,omitempty"`                               // Kind of the workload.
+	Namespace         string                 `json:"namespace,omitempty" yaml:"namespace,omitempty"`                     // Namespace of the workload.
+	Workload          string                 `json:"workload,omitempty" yaml:"workload,omitempty"`                       // Name of the workload.
+	Container         string                 `json:"container,omitempty" yaml:"container,omitempty"`                     // Name (or ID) of the container.
+	ContainerLabel    string                 `json:"container_label,omitempty" yaml:"container_label,omitempty"`         // Label of the container.
+	VersionSource     string                 `json:"version_source,omitempty" yaml:"version_source,omitempty"`           // Where on the container to get the version from.
+	VersionKey        string                 `json:"version_key,omitempty" yaml:"version_key,omitempty"`                 // Label/env var holding the version.
 	AllowInvalidCerts *bool                  `json:"allow_invalid_certs,omitempty" yaml:"allow_invalid_certs,omitempty"` // default - false = Disallows invalid HTTPS certificates
 	BasicAuth         *BasicAuth             `json:"basic_auth,omitempty" yaml:"basic_auth,omitempty"`                   // Basic Auth credentials.
 	Headers           []Header               `json:"headers,omitempty" yaml:"headers,omitempty"`                         // Request Headers.
@@ -524,13 +688,17 @@ func (slice *URLCommandSlice) String() (str string) {
 
 // URLCommand is a command to be ran to filter version from the URL body.
 type URLCommand struct {
-	Type     string  `json:"type,omitempty" yaml:"type,omitempty"`         // regex/replace/split
-	Regex    *string `json:"regex,omitempty" yaml:"regex,omitempty"`       // regex: regexp.MustCompile(Regex)
-	Index    int     `json:"index,omitempty" yaml:"index,omitempty"`       // regex/split: re.FindAllString(URL_content, -1)[Index]  /  strings.Split("text")[Index]
-	Template *string `yaml:"template,omitempty" json:"template,omitempty"` // regex: template
-	Text     *string `json:"text,omitempty" yaml:"text,omitempty"`         // split:       strings.Split(tgtString, "Text")
-	New      *string `json:"new,omitempty" yaml:"new,omitempty"`           // replace:     strings.ReplaceAll(tgtString, "Old", "New")
-	Old      *string `json:"old,omitempty" yaml:"old,omitempty"`           // replace:     strings.ReplaceAll(tgtString, "Old", "New")
+	Type      string  `json:"type,omitempty" yaml:"type,omitempty"`           // html/json/regex/replace/split/yaml
+	Key       *string `json:"key,omitempty" yaml:"key,omitempty"`             // json/yaml: key path of the value(s)
+	Selector  *string `json:"selector,omitempty" yaml:"selector,omitempty"`   // html: CSS selector of the element(s)
+	XPath     *string `json:"xpath,omitempty" yaml:"xpath,omitempty"`         // html: XPath of the element(s)
+	Attribute *string `json:"attribute,omitempty" yaml:"attribute,omitempty"` // html: attribute to use instead of the text
+	Regex     *string `json:"regex,omitempty" yaml:"regex,omitempty"`         // regex: regexp.MustCompile(Regex)
+	Index     int     `json:"index,omitempty" yaml:"index,omitempty"`         // html/regex/split: matches[Index]  /  re.FindAllString(URL_content, -1)[Index]  /  strings.Split("text")[Index]
+	Template  *string `yaml:"template,omitempty" json:"template,omitempty"`   // regex: template
+	Text      *string `json:"text,omitempty" yaml:"text,omitempty"`           // split:       strings.Split(tgtString, "Text")
+	New       *string `json:"new,omitempty" yaml:"new,omitempty"`             // replace:     strings.ReplaceAll(tgtString, "Old", "New")
+	Old       *string `json:"old,omitempty" yaml:"old,omitempty"`             // replace:     strings.ReplaceAll(tgtString, "Old", "New")
 }
 
 type Command []string
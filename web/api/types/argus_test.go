@@ -643,6 +643,28 @@ func TestServiceSummary_RemoveUnchanged(t *testing.T) {
 			want: &ServiceSummary{
 				HasDeployedVersionLookup: test.BoolPtr(false)},
 		},
+		"same ignore_versions": {
+			old: &ServiceSummary{
+				IgnoreVersions: &[]string{"1.2.3"}},
+			new: &ServiceSummary{
+				IgnoreVersions: &[]string{"1.2.3"}},
+			want: &ServiceSummary{},
+		},
+		"different ignore_versions": {
+			old: &ServiceSummary{
+				IgnoreVersions: &[]string{"1.2.3"}},
+			new: &ServiceSummary{
+				IgnoreVersions: &[]string{"1.2.3", "1.2.4"}},
+			want: &ServiceSummary{
+				IgnoreVersions: &[]string{"1.2.3", "1.2.4"}},
+		},
+		"removed ignore_versions": {
+			old: &ServiceSummary{
+				IgnoreVersions: &[]string{"1.2.3"}},
+			new: &ServiceSummary{},
+			want: &ServiceSummary{
+				IgnoreVersions: &[]string{}},
+		},
 		"same approved_version": {
 			old: &ServiceSummary{
 				Status: &Status{
@@ -731,6 +753,134 @@ func TestServiceSummary_RemoveUnchanged(t *testing.T) {
 					LatestVersion:          "4.5.6",
 					LatestVersionTimestamp: "2020-02-02T00:00:00Z"}},
 		},
+		"same pending_version, different remaining ignored": {
+			old: &ServiceSummary{
+				Status: &Status{
+					PendingVersion: &PendingVersion{
+						Version:   "1.2.3",
+						Until:     "2020-01-03T00:00:00Z",
+						Remaining: "2h0m0s"}}},
+			new: &ServiceSummary{
+				Status: &Status{
+					PendingVersion: &PendingVersion{
+						Version:   "1.2.3",
+						Until:     "2020-01-03T00:00:00Z",
+						Remaining: "1h0m0s"}}},
+			want: &ServiceSummary{},
+		},
+		"different pending_version": {
+			old: &ServiceSummary{
+				Status: &Status{
+					PendingVersion: &PendingVersion{
+						Version: "1.2.3",
+						Until:   "2020-01-03T00:00:00Z"}}},
+			new: &ServiceSummary{
+				Status: &Status{
+					PendingVersion: &PendingVersion{
+						Version:   "1.2.4",
+						Until:     "2020-01-04T00:00:00Z",
+						Remaining: "1h0m0s"}}},
+			want: &ServiceSummary{
+				Status: &Status{
+					PendingVersion: &PendingVersion{
+						Version:   "1.2.4",
+						Until:     "2020-01-04T00:00:00Z",
+						Remaining: "1h0m0s"}}},
+		},
+		"removed pending_version": {
+			old: &ServiceSummary{
+				Status: &Status{
+					PendingVersion: &PendingVersion{
+						Version: "1.2.3",
+						Until:   "2020-01-03T00:00:00Z"}}},
+			new: &ServiceSummary{},
+			want: &ServiceSummary{
+				Status: &Status{
+					PendingVersion: &PendingVersion{}}},
+		},
+		"same verify_failure": {
+			old: &ServiceSummary{
+				Status: &Status{
+					VerifyFailure: &VerifyFailure{
+						Version: "1.2.3",
+						Reason:  "checksum mismatch"}}},
+			new: &ServiceSummary{
+				Status: &Status{
+					VerifyFailure: &VerifyFailure{
+						Version: "1.2.3",
+						Reason:  "checksum mismatch"}}},
+			want: &ServiceSummary{},
+		},
+		"different verify_failure": {
+			old: &ServiceSummary{
+				Status: &Status{
+					VerifyFailure: &VerifyFailure{
+						Version: "1.2.3",
+						Reason:  "checksum mismatch"}}},
+			new: &ServiceSummary{
+				Status: &Status{
+					VerifyFailure: &VerifyFailure{
+						Version: "1.2.4",
+						Reason:  "invalid signature"}}},
+			want: &ServiceSummary{
+				Status: &Status{
+					VerifyFailure: &VerifyFailure{
+						Version: "1.2.4",
+						Reason:  "invalid signature"}}},
+		},
+		"removed verify_failure": {
+			old: &ServiceSummary{
+				Status: &Status{
+					VerifyFailure: &VerifyFailure{
+						Version: "1.2.3",
+						Reason:  "checksum mismatch"}}},
+			new: &ServiceSummary{},
+			want: &ServiceSummary{
+				Status: &Status{
+					VerifyFailure: &VerifyFailure{}}},
+		},
+		"same deployed_targets": {
+			old: &ServiceSummary{
+				Status: &Status{
+					DeployedTargets: &[]DeployedTarget{
+						{Name: "eu", Version: "1.2.3", Timestamp: "2020-01-01T00:00:00Z"}}}},
+			new: &ServiceSummary{
+				Status: &Status{
+					DeployedTargets: &[]DeployedTarget{
+						{Name: "eu", Version: "1.2.3", Timestamp: "2020-01-01T00:00:00Z"}}}},
+			want: &ServiceSummary{},
+		},
+		"different deployed_targets": {
+			old: &ServiceSummary{
+				Status: &Status{
+					DeployedTargets: &[]DeployedTarget{
+						{Name: "eu", Version: "1.2.3", Timestamp: "2020-01-01T00:00:00Z"}}}},
+			new: &ServiceSummary{
+				Status: &Status{
+					DeployedTargets: &[]DeployedTarget{
+						{Name: "eu", Version: "1.2.4", Timestamp: "2020-01-02T00:00:00Z"}}}},
+			want: &ServiceSummary{
+				Status: &Status{
+					DeployedTargets: &[]DeployedTarget{
+						{Name: "eu", Version: "1.2.4", Timestamp: "2020-01-02T00:00:00Z"}}}},
+		},
+		"removed deployed_targets": {
+			old: &ServiceSummary{
+				Status: &Status{
+					DeployedTargets: &[]DeployedTarget{
+						{Name: "eu", Version: "1.2.3"}}}},
+			new: &ServiceSummary{},
+			want: &ServiceSummary{
+				Status: &Status{
+					DeployedTargets: &[]DeployedTarget{}}},
+		},
+		"same deployed_aggregate": {
+			old: &ServiceSummary{
+				DeployedAggregate: "any"},
+			new: &ServiceSummary{
+				DeployedAggregate: "any"},
+			want: &ServiceSummary{},
+		},
 		"mmultiple differences": {
 			old: &ServiceSummary{
 				IconLinkTo: test.StringPtr("https://release-argus.io"),
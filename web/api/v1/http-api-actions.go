@@ -87,6 +87,7 @@ type RunActionsPayload struct {
 //   - "ARGUS_ALL" - Approve all actions.
 //   - "ARGUS_FAILED" - Approve all failed actions.
 //   - "ARGUS_SKIP" - Skip this release.
+//   - "ARGUS_IGNORE" - Skip this release and add it to the ignore_versions.
 //   - "webhook_<webhook_id>" - Approve a specific WebHook.
 //   - "command_<command_id>" - Approve a specific Command.
 func (api *API) httpServiceRunActions(w http.ResponseWriter, r *http.Request) {
@@ -135,6 +136,14 @@ func (api *API) httpServiceRunActions(w http.ResponseWriter, r *http.Request) {
 		svc.HandleSkip()
 		return
 	}
+	// IGNORE this release
+	if *payload.Target == "ARGUS_IGNORE" {
+		msg := fmt.Sprintf("%q release ignore - %q",
+			targetService, svc.Status.LatestVersion())
+		jLog.Info(msg, logFrom, true)
+		svc.HandleIgnore()
+		return
+	}
 
 	if svc.WebHook == nil && svc.Command == nil {
 		jLog.Error(fmt.Sprintf("%q does not have any commands/webhooks to approve", targetService), logFrom, true)
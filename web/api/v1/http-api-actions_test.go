@@ -304,6 +304,11 @@ func TestHTTP_httpServiceRunActions(t *testing.T) {
 			target:      test.StringPtr("ARGUS_SKIP"),
 			stdoutRegex: `service "" not found`,
 		},
+		"ARGUS_IGNORE known service_id": {
+			serviceID:       "__name__",
+			target:          test.StringPtr("ARGUS_IGNORE"),
+			wantSkipMessage: true,
+		},
 		"target=nil, known service_id": {
 			serviceID:   "__name__",
 			target:      nil,
@@ -590,7 +595,7 @@ func TestHTTP_httpServiceRunActions(t *testing.T) {
 			}
 			t.Log(stdout)
 			// Check version was skipped
-			if util.DefaultIfNil(tc.target) == "ARGUS_SKIP" {
+			if target := util.DefaultIfNil(tc.target); target == "ARGUS_SKIP" || target == "ARGUS_IGNORE" {
 				if tc.wantSkipMessage &&
 					messages[0].ServiceData.Status.ApprovedVersion != "SKIP_"+svc.Status.LatestVersion() {
 					t.Errorf("LatestVersion %q wasn't skipped. approved is %q\ngot=%q",
@@ -598,6 +603,14 @@ func TestHTTP_httpServiceRunActions(t *testing.T) {
 						svc.Status.ApprovedVersion(),
 						messages[0].ServiceData.Status.ApprovedVersion)
 				}
+				// AND the version was ignored
+				if target == "ARGUS_IGNORE" {
+					ignored := svc.LatestVersion.GetIgnoreVersions()
+					if len(ignored) != 1 || ignored[0] != svc.Status.LatestVersion() {
+						t.Errorf("LatestVersion %q wasn't added to the ignore_versions, got %v",
+							svc.Status.LatestVersion(), ignored)
+					}
+				}
 			} else {
 				// expecting = commands + webhooks that have not failed=false
 				expecting := 0
@@ -71,7 +71,8 @@ func (api *API) httpConfig(w http.ResponseWriter, r *http.Request) {
 		Service: api_type.ServiceDefaults{
 			Options: &api_type.ServiceOptions{
 				Interval:           api.Config.Defaults.Service.Options.Interval,
-				SemanticVersioning: api.Config.Defaults.Service.Options.SemanticVersioning},
+				SemanticVersioning: api.Config.Defaults.Service.Options.SemanticVersioning,
+				VersionScheme:      api.Config.Defaults.Service.Options.VersionScheme},
 			DeployedVersionLookup: &api_type.DeployedVersionLookup{
 				AllowInvalidCerts: api.Config.Defaults.Service.DeployedVersionLookup.AllowInvalidCerts},
 			Dashboard: &api_type.DashboardOptions{
@@ -80,6 +81,8 @@ func (api *API) httpConfig(w http.ResponseWriter, r *http.Request) {
 				AccessToken:       util.DefaultOrValue(api.Config.Defaults.Service.LatestVersion.AccessToken, "<secret>"),
 				AllowInvalidCerts: api.Config.Defaults.Service.LatestVersion.AllowInvalidCerts,
 				UsePreRelease:     api.Config.Defaults.Service.LatestVersion.UsePreRelease,
+				VersionConstraint: api.Config.Defaults.Service.LatestVersion.VersionConstraint,
+				GitHubBaseURL:     api.Config.Defaults.Service.LatestVersion.GitHubBaseURL,
 				Require:           serviceLatestVersionRequireDefaults},
 			Notify:  serviceNotifyDefaults,
 			Command: serviceCommandDefaults,
@@ -79,13 +79,27 @@ func (api *API) httpVersionRefreshUncreated(w http.ResponseWriter, r *http.Reque
 			getParam(&queryParams, "allow_invalid_certs"),
 			getParam(&queryParams, "basic_auth"),
 			getParam(&queryParams, "body"),
+			getParam(&queryParams, "command"),
+			getParam(&queryParams, "container"),
+			getParam(&queryParams, "container_label"),
 			getParam(&queryParams, "headers"),
+			getParam(&queryParams, "host"),
 			getParam(&queryParams, "json"),
+			getParam(&queryParams, "kind"),
+			getParam(&queryParams, "kubeconfig"),
 			getParam(&queryParams, "method"),
+			getParam(&queryParams, "namespace"),
+			getParam(&queryParams, "path"),
 			getParam(&queryParams, "regex"),
 			getParam(&queryParams, "regex_template"),
 			getParam(&queryParams, "semantic_versioning"),
-			getParam(&queryParams, "url"))
+			getParam(&queryParams, "timeout"),
+			getParam(&queryParams, "type"),
+			getParam(&queryParams, "url"),
+			getParam(&queryParams, "version_key"),
+			getParam(&queryParams, "version_source"),
+			getParam(&queryParams, "watch"),
+			getParam(&queryParams, "workload"))
 	} else {
 		latestVersion := latestver.Lookup{
 			Options: &opt.Options{
@@ -98,12 +112,14 @@ func (api *API) httpVersionRefreshUncreated(w http.ResponseWriter, r *http.Reque
 		version, _, err = latestVersion.Refresh(
 			getParam(&queryParams, "access_token"),
 			getParam(&queryParams, "allow_invalid_certs"),
+			getParam(&queryParams, "base_url"),
 			getParam(&queryParams, "require"),
 			getParam(&queryParams, "semantic_versioning"),
 			getParam(&queryParams, "type"),
 			getParam(&queryParams, "url"),
 			getParam(&queryParams, "url_commands"),
-			getParam(&queryParams, "use_prerelease"))
+			getParam(&queryParams, "use_prerelease"),
+			getParam(&queryParams, "version_constraint"))
 	}
 
 	statusCode := http.StatusOK
@@ -173,13 +189,27 @@ func (api *API) httpVersionRefresh(w http.ResponseWriter, r *http.Request) {
 			getParam(&queryParams, "allow_invalid_certs"),
 			getParam(&queryParams, "basic_auth"),
 			getParam(&queryParams, "body"),
+			getParam(&queryParams, "command"),
+			getParam(&queryParams, "container"),
+			getParam(&queryParams, "container_label"),
 			getParam(&queryParams, "headers"),
+			getParam(&queryParams, "host"),
 			getParam(&queryParams, "json"),
+			getParam(&queryParams, "kind"),
+			getParam(&queryParams, "kubeconfig"),
 			getParam(&queryParams, "method"),
+			getParam(&queryParams, "namespace"),
+			getParam(&queryParams, "path"),
 			getParam(&queryParams, "regex"),
 			getParam(&queryParams, "regex_template"),
 			getParam(&queryParams, "semantic_versioning"),
+			getParam(&queryParams, "timeout"),
+			getParam(&queryParams, "type"),
 			getParam(&queryParams, "url"),
+			getParam(&queryParams, "version_key"),
+			getParam(&queryParams, "version_source"),
+			getParam(&queryParams, "watch"),
+			getParam(&queryParams, "workload"),
 		)
 
 		if announce {
@@ -190,12 +220,14 @@ func (api *API) httpVersionRefresh(w http.ResponseWriter, r *http.Request) {
 		version, announce, err = api.Config.Service[targetService].LatestVersion.Refresh(
 			getParam(&queryParams, "access_token"),
 			getParam(&queryParams, "allow_invalid_certs"),
+			getParam(&queryParams, "base_url"),
 			getParam(&queryParams, "require"),
 			getParam(&queryParams, "semantic_versioning"),
 			getParam(&queryParams, "type"),
 			getParam(&queryParams, "url"),
 			getParam(&queryParams, "url_commands"),
 			getParam(&queryParams, "use_prerelease"),
+			getParam(&queryParams, "version_constraint"),
 		)
 
 		if announce {
@@ -365,8 +397,8 @@ func (api *API) httpServiceEdit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set DeployedVersion to the LatestVersion if there's no DeployedVersionLookup
-	if newService.DeployedVersionLookup == nil {
+	// Set DeployedVersion to the LatestVersion if there's no deployed version lookup
+	if !newService.HasDeployedVersionLookup() {
 		newService.Status.SetDeployedVersion(newService.Status.LatestVersion(), false)
 		newService.Status.SetDeployedVersionTimestamp(newService.Status.LatestVersionTimestamp())
 	}
@@ -91,6 +91,23 @@ func TestHTTP_VersionRefreshUncreated(t *testing.T) {
 			wantBody:       `^\{"version":"[0-9.]+","timestamp":"[^"]+"\}\s$`,
 			wantStatusCode: http.StatusOK,
 		},
+		"deployed version, command type": {
+			deployedVersion: true,
+			params: map[string]string{
+				"type":    "command",
+				"command": `["echo", "1.2.3"]`,
+			},
+			wantBody:       `^\{"version":"1\.2\.3","timestamp":"[^"]+"\}\s$`,
+			wantStatusCode: http.StatusOK,
+		},
+		"deployed version, command type without a command": {
+			deployedVersion: true,
+			params: map[string]string{
+				"type": "command",
+			},
+			wantBody:       `"error":"values failed validity check:.*command: .*required`,
+			wantStatusCode: http.StatusBadRequest,
+		},
 		"deployed version, invalid vars": {
 			deployedVersion: true,
 			params: map[string]string{
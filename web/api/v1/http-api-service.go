@@ -22,6 +22,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/release-argus/Argus/util"
+	api_type "github.com/release-argus/Argus/web/api/types"
 )
 
 type ServiceOrderAPI struct {
@@ -60,3 +61,56 @@ func (api *API) httpServiceSummary(w http.ResponseWriter, r *http.Request) {
 	err := json.NewEncoder(w).Encode(summary)
 	jLog.Error(err, logFrom, err != nil)
 }
+
+func (api *API) httpServiceReleaseNotes(w http.ResponseWriter, r *http.Request) {
+	logFrom := &util.LogFrom{Primary: "httpServiceReleaseNotes", Secondary: getIP(r)}
+	targetService, _ := url.QueryUnescape(mux.Vars(r)["service_name"])
+	jLog.Verbose(targetService, logFrom, true)
+
+	// Check Service still exists in this ordering
+	api.Config.OrderMutex.RLock()
+	defer api.Config.OrderMutex.RUnlock()
+	service := api.Config.Service[targetService]
+	if service == nil {
+		err := fmt.Sprintf("service %q not found", targetService)
+		jLog.Error(err, logFrom, true)
+		failRequest(&w, err, http.StatusNotFound)
+		return
+	}
+
+	releaseNotes := api_type.ReleaseNotes{
+		Version:      service.Status.LatestVersion(),
+		ReleaseNotes: service.Status.ReleaseNotes()}
+
+	err := json.NewEncoder(w).Encode(releaseNotes)
+	jLog.Error(err, logFrom, err != nil)
+}
+
+func (api *API) httpServiceChangelog(w http.ResponseWriter, r *http.Request) {
+	logFrom := &util.LogFrom{Primary: "httpServiceChangelog", Secondary: getIP(r)}
+	targetService, _ := url.QueryUnescape(mux.Vars(r)["service_name"])
+	jLog.Verbose(targetService, logFrom, true)
+
+	// Check Service still exists in this ordering
+	api.Config.OrderMutex.RLock()
+	defer api.Config.OrderMutex.RUnlock()
+	service := api.Config.Service[targetService]
+	if service == nil {
+		err := fmt.Sprintf("service %q not found", targetService)
+		jLog.Error(err, logFrom, true)
+		failRequest(&w, err, http.StatusNotFound)
+		return
+	}
+
+	changelog := api_type.Changelog{
+		DeployedVersion: service.Status.DeployedVersion(),
+		LatestVersion:   service.Status.LatestVersion()}
+	for _, entry := range service.Status.ChangelogEntries() {
+		changelog.Releases = append(changelog.Releases, api_type.ReleaseNotes{
+			Version:      entry.Version,
+			ReleaseNotes: entry.Notes})
+	}
+
+	err := json.NewEncoder(w).Encode(changelog)
+	jLog.Error(err, logFrom, err != nil)
+}
@@ -29,6 +29,7 @@ import (
 	"testing"
 
 	"github.com/gorilla/mux"
+	svcstatus "github.com/release-argus/Argus/service/status"
 )
 
 func TestHTTP_httpServiceOrder(t *testing.T) {
@@ -163,3 +164,153 @@ func TestHTTP_httpServiceSummary(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTP_httpServiceReleaseNotes(t *testing.T) {
+	testSVC := testService("TestHTTP_httpServiceReleaseNotes")
+	testSVC.Status.SetLatestVersion("1.2.3", false)
+	testSVC.Status.SetReleaseNotes("1.2.3", "## Fixes\n- Fixed a bug", false)
+	// GIVEN an API and a request for the release notes of a service
+	file := "TestHTTP_httpServiceReleaseNotes.yml"
+	api := testAPI(file)
+	api.Config.Service[testSVC.ID] = testSVC
+	api.Config.Order = append(api.Config.Order, testSVC.ID)
+	defer func() {
+		os.RemoveAll(file)
+		if api.Config.Settings.Data.DatabaseFile != nil {
+			os.RemoveAll(*api.Config.Settings.Data.DatabaseFile)
+		}
+	}()
+
+	tests := map[string]struct {
+		serviceName    string
+		wantBody       string
+		wantStatusCode int
+	}{
+		"known service": {
+			serviceName:    testSVC.ID,
+			wantBody:       `^\{"version":"1\.2\.3","release_notes":"## Fixes\\n- Fixed a bug"\}\s*$`,
+			wantStatusCode: http.StatusOK,
+		},
+		"unknown service": {
+			serviceName:    "bish-bash-bosh",
+			wantBody:       `\{"message":"service .+ not found"`,
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			target := "/api/v1/service/release_notes/"
+			target += url.QueryEscape(tc.serviceName)
+
+			// WHEN that HTTP request is sent
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			vars := map[string]string{
+				"service_name": tc.serviceName}
+			req = mux.SetURLVars(req, vars)
+			w := httptest.NewRecorder()
+			api.httpServiceReleaseNotes(w, req)
+			res := w.Result()
+			defer res.Body.Close()
+
+			// THEN the expected status code is returned
+			if res.StatusCode != tc.wantStatusCode {
+				t.Errorf("Status code, expected a %d, not a %d",
+					tc.wantStatusCode, res.StatusCode)
+			}
+			// AND the expected body is returned as expected
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("unexpected error - %v",
+					err)
+			}
+			got := string(data)
+			re := regexp.MustCompile(tc.wantBody)
+			match := re.MatchString(got)
+			if !match {
+				t.Errorf("want match for %q\nnot: %q",
+					tc.wantBody, got)
+			}
+		})
+	}
+}
+
+func TestHTTP_httpServiceChangelog(t *testing.T) {
+	testSVC := testService("TestHTTP_httpServiceChangelog")
+	testSVC.Status.SetLatestVersion("1.2.3", false)
+	testSVC.Status.SetDeployedVersion("1.2.1", false)
+	testSVC.Status.SetChangelog("1.2.3", []svcstatus.ChangelogEntry{
+		{Version: "1.2.3", Notes: "- Added a thing"},
+		{Version: "1.2.2", Notes: "- Fixed a bug"},
+		{Version: "1.2.1", Notes: "- Deployed already"}})
+	// GIVEN an API and a request for the changelog of a service
+	file := "TestHTTP_httpServiceChangelog.yml"
+	api := testAPI(file)
+	api.Config.Service[testSVC.ID] = testSVC
+	api.Config.Order = append(api.Config.Order, testSVC.ID)
+	defer func() {
+		os.RemoveAll(file)
+		if api.Config.Settings.Data.DatabaseFile != nil {
+			os.RemoveAll(*api.Config.Settings.Data.DatabaseFile)
+		}
+	}()
+
+	tests := map[string]struct {
+		serviceName    string
+		wantBody       string
+		wantStatusCode int
+	}{
+		"known service": {
+			serviceName: testSVC.ID,
+			wantBody: `^\{"deployed_version":"1\.2\.1","latest_version":"1\.2\.3","releases":\[` +
+				`\{"version":"1\.2\.3","release_notes":"- Added a thing"\},` +
+				`\{"version":"1\.2\.2","release_notes":"- Fixed a bug"\}\]\}\s*$`,
+			wantStatusCode: http.StatusOK,
+		},
+		"unknown service": {
+			serviceName:    "bish-bash-bosh",
+			wantBody:       `\{"message":"service .+ not found"`,
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			target := "/api/v1/service/changelog/"
+			target += url.QueryEscape(tc.serviceName)
+
+			// WHEN that HTTP request is sent
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			vars := map[string]string{
+				"service_name": tc.serviceName}
+			req = mux.SetURLVars(req, vars)
+			w := httptest.NewRecorder()
+			api.httpServiceChangelog(w, req)
+			res := w.Result()
+			defer res.Body.Close()
+
+			// THEN the expected status code is returned
+			if res.StatusCode != tc.wantStatusCode {
+				t.Errorf("Status code, expected a %d, not a %d",
+					tc.wantStatusCode, res.StatusCode)
+			}
+			// AND the expected body is returned as expected
+			data, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("unexpected error - %v",
+					err)
+			}
+			got := string(data)
+			re := regexp.MustCompile(tc.wantBody)
+			match := re.MatchString(got)
+			if !match {
+				t.Errorf("want match for %q\nnot: %q",
+					tc.wantBody, got)
+			}
+		})
+	}
+}
@@ -80,6 +80,10 @@ func (api *API) SetupRoutesAPI() {
 	api.Router.HandleFunc("/api/v1/service/order", api.httpServiceOrder).Methods("GET")
 	//   GET, service summary
 	api.Router.HandleFunc("/api/v1/service/summary/{service_name:.+}", api.httpServiceSummary).Methods("GET")
+	//   GET, service release notes
+	api.Router.HandleFunc("/api/v1/service/release_notes/{service_name:.+}", api.httpServiceReleaseNotes).Methods("GET")
+	//   GET, service changelog (deployed_version -> latest_version)
+	api.Router.HandleFunc("/api/v1/service/changelog/{service_name:.+}", api.httpServiceChangelog).Methods("GET")
 	//   GET, service actions (webhooks/commands)
 	api.Router.HandleFunc("/api/v1/service/actions/{service_name:.+}", api.httpServiceGetActions).Methods("GET")
 	//   POST, service actions (disable=service_actions)
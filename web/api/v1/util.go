@@ -109,11 +109,14 @@ func convertAndCensorDefaults(input *config.Defaults) (defaults *api_type.Defaul
 		Service: api_type.ServiceDefaults{
 			Options: &api_type.ServiceOptions{
 				Interval:           input.Service.Options.Interval,
-				SemanticVersioning: input.Service.Options.SemanticVersioning},
+				SemanticVersioning: input.Service.Options.SemanticVersioning,
+				VersionScheme:      input.Service.Options.VersionScheme},
 			LatestVersion: &api_type.LatestVersionDefaults{
 				AccessToken:       util.DefaultOrValue(input.Service.LatestVersion.AccessToken, "<secret>"),
 				AllowInvalidCerts: input.Service.LatestVersion.AllowInvalidCerts,
 				UsePreRelease:     input.Service.LatestVersion.UsePreRelease,
+				VersionConstraint: input.Service.LatestVersion.VersionConstraint,
+				GitHubBaseURL:     input.Service.LatestVersion.GitHubBaseURL,
 				Require:           convertAndCensorLatestVersionRequireDefaults(&input.Service.LatestVersion.Require)},
 			DeployedVersionLookup: &api_type.DeployedVersionLookup{
 				AllowInvalidCerts: input.Service.DeployedVersionLookup.AllowInvalidCerts},
@@ -171,18 +174,64 @@ func convertAndCensorLatestVersionRequire(require *filter.Require) (apiRequire *
 	var docker *api_type.RequireDockerCheck
 	if require.Docker != nil {
 		docker = &api_type.RequireDockerCheck{
-			Type:     require.Docker.Type,
-			Image:    require.Docker.Image,
-			Tag:      require.Docker.Tag,
-			Username: require.Docker.Username,
-			Token:    util.ValueIfNotDefault(require.Docker.Token, "<secret>")}
+			Type:      require.Docker.Type,
+			Registry:  require.Docker.Registry,
+			Image:     require.Docker.Image,
+			Tag:       require.Docker.Tag,
+			Platforms: require.Docker.Platforms,
+			Username:  require.Docker.Username,
+			Token:     util.ValueIfNotDefault(require.Docker.Token, "<secret>")}
+	}
+
+	var requireHTTP *api_type.RequireHTTP
+	if require.HTTP != nil {
+		requireHTTP = &api_type.RequireHTTP{
+			URL:               require.HTTP.URL,
+			Method:            require.HTTP.Method,
+			AllowInvalidCerts: require.HTTP.AllowInvalidCerts,
+			StatusCode:        require.HTTP.StatusCode,
+			ContentType:       require.HTTP.ContentType,
+			MinSize:           require.HTTP.MinSize,
+			MaxSize:           require.HTTP.MaxSize}
+		// Basic auth
+		if require.HTTP.BasicAuth != nil {
+			requireHTTP.BasicAuth = &api_type.BasicAuth{
+				Username: require.HTTP.BasicAuth.Username,
+				Password: "<secret>"}
+		}
+		// Headers
+		if len(require.HTTP.Headers) != 0 {
+			requireHTTP.Headers = make([]api_type.Header, len(require.HTTP.Headers))
+			for i := range require.HTTP.Headers {
+				requireHTTP.Headers[i] = api_type.Header{
+					Key:   require.HTTP.Headers[i].Key,
+					Value: "<secret>"}
+			}
+		}
+	}
+
+	var verify *api_type.RequireVerify
+	if require.Verify != nil {
+		verify = &api_type.RequireVerify{
+			Asset:     require.Verify.Asset,
+			Checksums: require.Verify.Checksums}
+		if require.Verify.Signature != nil {
+			verify.Signature = &api_type.RequireVerifySignature{
+				Type:      require.Verify.Signature.Type,
+				Asset:     require.Verify.Signature.Asset,
+				PublicKey: require.Verify.Signature.PublicKey}
+		}
 	}
 
 	apiRequire = &api_type.LatestVersionRequire{
 		Command:      require.Command,
+		HTTP:         requireHTTP,
 		Docker:       docker,
 		RegexContent: require.RegexContent,
-		RegexVersion: require.RegexVersion}
+		RegexVersion: require.RegexVersion,
+		MinAge:       require.MinAge,
+		Assets:       require.Assets,
+		Verify:       verify}
 	return
 }
 
@@ -194,13 +243,17 @@ func convertURLCommandSlice(commands *filter.URLCommandSlice) *api_type.URLComma
 	slice := make(api_type.URLCommandSlice, len(*commands))
 	for index := range *commands {
 		slice[index] = api_type.URLCommand{
-			Type:     (*commands)[index].Type,
-			Regex:    (*commands)[index].Regex,
-			Index:    (*commands)[index].Index,
-			Template: (*commands)[index].Template,
-			Text:     (*commands)[index].Text,
-			Old:      (*commands)[index].Old,
-			New:      (*commands)[index].New}
+			Type:      (*commands)[index].Type,
+			Key:       (*commands)[index].Key,
+			Selector:  (*commands)[index].Selector,
+			XPath:     (*commands)[index].XPath,
+			Attribute: (*commands)[index].Attribute,
+			Regex:     (*commands)[index].Regex,
+			Index:     (*commands)[index].Index,
+			Template:  (*commands)[index].Template,
+			Text:      (*commands)[index].Text,
+			Old:       (*commands)[index].Old,
+			New:       (*commands)[index].New}
 	}
 	return &slice
 }
@@ -214,11 +267,18 @@ func convertAndCensorLatestVersion(lv *latestver.Lookup) (apiLV *api_type.Latest
 	apiLV = &api_type.LatestVersion{
 		Type:              lv.Type,
 		URL:               lv.URL,
+		BaseURL:           lv.BaseURL,
+		Username:          lv.Username,
+		Chart:             lv.Chart,
+		AppVersion:        lv.AppVersion,
+		AllMatches:        lv.AllMatches,
 		AccessToken:       util.DefaultOrValue(lv.AccessToken, "<secret>"),
 		AllowInvalidCerts: lv.AllowInvalidCerts,
 		UsePreRelease:     lv.UsePreRelease,
+		VersionConstraint: lv.VersionConstraint,
 		URLCommands:       convertURLCommandSlice(&lv.URLCommands),
-		Require:           convertAndCensorLatestVersionRequire(lv.Require)}
+		Require:           convertAndCensorLatestVersionRequire(lv.Require),
+		IgnoreVersions:    lv.GetIgnoreVersions()}
 
 	return
 }
@@ -227,6 +287,20 @@ func convertAndCensorLatestVersion(lv *latestver.Lookup) (apiLV *api_type.Latest
 // Deployed Version
 //
 
+// convertAndCensorDeployedVersionTargets will convert Targets to API Type and censor secrets.
+func convertAndCensorDeployedVersionTargets(targets *deployedver.Targets) (apiTargets *api_type.DeployedVersionTargets) {
+	if targets == nil {
+		return
+	}
+	apiTargets = &api_type.DeployedVersionTargets{
+		Aggregate: targets.Aggregate,
+		Targets:   make(map[string]*api_type.DeployedVersionLookup, len(targets.Targets))}
+	for name, lookup := range targets.Targets {
+		apiTargets.Targets[name] = convertAndCensorDeployedVersionLookup(lookup)
+	}
+	return
+}
+
 // convertAndCensorDeployedVersionLookup will convert Lookup to API Type and censor secrets.
 func convertAndCensorDeployedVersionLookup(dvl *deployedver.Lookup) (apiDVL *api_type.DeployedVersionLookup) {
 	if dvl == nil {
@@ -234,8 +308,22 @@ func convertAndCensorDeployedVersionLookup(dvl *deployedver.Lookup) (apiDVL *api
 	}
 	var headers []api_type.Header
 	apiDVL = &api_type.DeployedVersionLookup{
+		Type:              dvl.Type,
 		Method:            dvl.Method,
 		URL:               dvl.URL,
+		Command:           dvl.Command,
+		Timeout:           dvl.Timeout,
+		Path:              dvl.Path,
+		Watch:             dvl.Watch,
+		Host:              dvl.Host,
+		Kubeconfig:        dvl.Kubeconfig,
+		Kind:              dvl.Kind,
+		Namespace:         dvl.Namespace,
+		Workload:          dvl.Workload,
+		Container:         dvl.Container,
+		ContainerLabel:    dvl.ContainerLabel,
+		VersionSource:     dvl.VersionSource,
+		VersionKey:        dvl.VersionKey,
 		AllowInvalidCerts: dvl.AllowInvalidCerts,
 		Headers:           headers,
 		Body:              dvl.Body,
@@ -423,12 +511,15 @@ func convertAndCensorService(service *service.Service) (apiService *api_type.Ser
 	apiService.Options = &api_type.ServiceOptions{
 		Active:             service.Options.Active,
 		Interval:           service.Options.Interval,
-		SemanticVersioning: service.Options.SemanticVersioning}
+		SemanticVersioning: service.Options.SemanticVersioning,
+		VersionScheme:      service.Options.VersionScheme}
 
 	// LatestVersion
 	apiService.LatestVersion = convertAndCensorLatestVersion(&service.LatestVersion)
 	// DeployedVersionLookup
 	apiService.DeployedVersionLookup = convertAndCensorDeployedVersionLookup(service.DeployedVersionLookup)
+	// DeployedVersionTargets
+	apiService.DeployedVersionTargets = convertAndCensorDeployedVersionTargets(service.DeployedVersionTargets)
 	// Notify
 	apiService.Notify = convertAndCensorNotifySlice(&service.Notify)
 	// Command
@@ -642,6 +642,44 @@ func TestConvertAndCensorLatestVersionRequire(t *testing.T) {
 					Username: "user",
 					Token:    "<secret>"}},
 		},
+		"http censors the secrets": {
+			input: &filter.Require{
+				HTTP: &filter.HTTPCheck{
+					URL:         "https://example.com/myapp_{{ version }}.tar.gz",
+					Method:      "GET",
+					BasicAuth:   &filter.HTTPBasicAuth{Username: "user", Password: "pass"},
+					Headers:     []filter.HTTPHeader{{Key: "X-Token", Value: "token"}},
+					StatusCode:  200,
+					ContentType: "^application/gzip$",
+					MinSize:     1024,
+					MaxSize:     2048}},
+			want: &api_type.LatestVersionRequire{
+				HTTP: &api_type.RequireHTTP{
+					URL:         "https://example.com/myapp_{{ version }}.tar.gz",
+					Method:      "GET",
+					BasicAuth:   &api_type.BasicAuth{Username: "user", Password: "<secret>"},
+					Headers:     []api_type.Header{{Key: "X-Token", Value: "<secret>"}},
+					StatusCode:  200,
+					ContentType: "^application/gzip$",
+					MinSize:     1024,
+					MaxSize:     2048}},
+		},
+		"docker.registry with platforms": {
+			input: &filter.Require{
+				Docker: &filter.DockerCheck{
+					Type:      "registry",
+					Registry:  "https://registry.example.com",
+					Image:     "team/app",
+					Tag:       "{{ version }}",
+					Platforms: []string{"linux/amd64", "linux/arm64"}}},
+			want: &api_type.LatestVersionRequire{
+				Docker: &api_type.RequireDockerCheck{
+					Type:      "registry",
+					Registry:  "https://registry.example.com",
+					Image:     "team/app",
+					Tag:       "{{ version }}",
+					Platforms: []string{"linux/amd64", "linux/arm64"}}},
+		},
 		"filled": {
 			input: &filter.Require{
 				Status: svcstatus.New(
@@ -653,6 +691,15 @@ func TestConvertAndCensorLatestVersionRequire(t *testing.T) {
 				RegexContent: ".*",
 				RegexVersion: `([0-9.]+)`,
 				Command:      command.Command{"echo", "hello"},
+				MinAge:       "48h",
+				Assets:       []string{`myapp_{{ version }}_linux_amd64\.tar\.gz`},
+				Verify: &filter.VerifyCheck{
+					Asset:     `myapp_{{ version }}_linux_amd64\.tar\.gz`,
+					Checksums: "SHA256SUMS",
+					Signature: &filter.VerifySignature{
+						Type:      "minisign",
+						Asset:     `SHA256SUMS\.minisig`,
+						PublicKey: "/etc/argus/minisign.pub"}},
 				Docker: filter.NewDockerCheck(
 					"hub",
 					"release-argus/argus", "{{ version }}",
@@ -668,7 +715,16 @@ func TestConvertAndCensorLatestVersionRequire(t *testing.T) {
 					Username: "user",
 					Token:    "<secret>"},
 				RegexContent: ".*",
-				RegexVersion: `([0-9.]+)`},
+				RegexVersion: `([0-9.]+)`,
+				MinAge:       "48h",
+				Assets:       []string{`myapp_{{ version }}_linux_amd64\.tar\.gz`},
+				Verify: &api_type.RequireVerify{
+					Asset:     `myapp_{{ version }}_linux_amd64\.tar\.gz`,
+					Checksums: "SHA256SUMS",
+					Signature: &api_type.RequireVerifySignature{
+						Type:      "minisign",
+						Asset:     `SHA256SUMS\.minisig`,
+						PublicKey: "/etc/argus/minisign.pub"}}},
 		},
 	}
 
@@ -26,6 +26,7 @@ import (
 	opt "github.com/release-argus/Argus/service/options"
 	svcstatus "github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/test"
+	"github.com/release-argus/Argus/util"
 	api_type "github.com/release-argus/Argus/web/api/types"
 )
 
@@ -86,6 +87,58 @@ func TestConvertAndCensorDeployedVersionLookup(t *testing.T) {
 					{Key: "X-Test-1", Value: "<secret>"},
 				}},
 		},
+		"command": {
+			dvl: &deployedver.Lookup{
+				Type:    "command",
+				Command: command.Command{"myapp", "--version"},
+				Timeout: "30s",
+				Regex:   `v([0-9.]+)`},
+			want: &api_type.DeployedVersionLookup{
+				Type:    "command",
+				Command: []string{"myapp", "--version"},
+				Timeout: "30s",
+				Regex:   `v([0-9.]+)`},
+		},
+		"file": {
+			dvl: &deployedver.Lookup{
+				Type:  "file",
+				Path:  "/data/VERSION",
+				Watch: test.BoolPtr(true)},
+			want: &api_type.DeployedVersionLookup{
+				Type:  "file",
+				Path:  "/data/VERSION",
+				Watch: test.BoolPtr(true)},
+		},
+		"docker": {
+			dvl: &deployedver.Lookup{
+				Type:           "docker",
+				Host:           "tcp://docker:2375",
+				ContainerLabel: "com.docker.compose.service=app",
+				VersionSource:  "label",
+				VersionKey:     "org.opencontainers.image.version"},
+			want: &api_type.DeployedVersionLookup{
+				Type:           "docker",
+				Host:           "tcp://docker:2375",
+				ContainerLabel: "com.docker.compose.service=app",
+				VersionSource:  "label",
+				VersionKey:     "org.opencontainers.image.version"},
+		},
+		"kubernetes": {
+			dvl: &deployedver.Lookup{
+				Type:       "kubernetes",
+				Kubeconfig: "/config/kubeconfig",
+				Kind:       "statefulset",
+				Namespace:  "apps",
+				Workload:   "argus",
+				Container:  "argus"},
+			want: &api_type.DeployedVersionLookup{
+				Type:       "kubernetes",
+				Kubeconfig: "/config/kubeconfig",
+				Kind:       "statefulset",
+				Namespace:  "apps",
+				Workload:   "argus",
+				Container:  "argus"},
+		},
 		"full": {
 			regexMissesContent: 1,
 			regexMissesVersion: 3,
@@ -164,6 +217,65 @@ func TestConvertAndCensorDeployedVersionLookup(t *testing.T) {
 	}
 }
 
+func TestConvertAndCensorDeployedVersionTargets(t *testing.T) {
+	// GIVEN DeployedVersion Targets
+	tests := map[string]struct {
+		targets *deployedver.Targets
+		want    *api_type.DeployedVersionTargets
+	}{
+		"nil": {
+			targets: nil,
+			want:    nil,
+		},
+		"empty": {
+			targets: &deployedver.Targets{},
+			want: &api_type.DeployedVersionTargets{
+				Targets: map[string]*api_type.DeployedVersionLookup{}},
+		},
+		"censors each target": {
+			targets: &deployedver.Targets{
+				Aggregate: "oldest",
+				Targets: map[string]*deployedver.Lookup{
+					"eu": {
+						URL: "https://eu.example.com",
+						BasicAuth: &deployedver.BasicAuth{
+							Username: "alan",
+							Password: "pass123"}},
+					"us": {
+						Type:      "kubernetes",
+						Namespace: "apps",
+						Workload:  "argus"}}},
+			want: &api_type.DeployedVersionTargets{
+				Aggregate: "oldest",
+				Targets: map[string]*api_type.DeployedVersionLookup{
+					"eu": {
+						URL: "https://eu.example.com",
+						BasicAuth: &api_type.BasicAuth{
+							Username: "alan",
+							Password: "<secret>"}},
+					"us": {
+						Type:      "kubernetes",
+						Namespace: "apps",
+						Workload:  "argus"}}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN convertAndCensorDeployedVersionTargets is called on them
+			got := convertAndCensorDeployedVersionTargets(tc.targets)
+
+			// THEN the Targets are converted correctly
+			if util.ToJSONString(got) != util.ToJSONString(tc.want) {
+				t.Errorf("want:\n%q\ngot:\n%q",
+					util.ToJSONString(tc.want), util.ToJSONString(got))
+			}
+		})
+	}
+}
+
 func TestConvertURLCommandSlice(t *testing.T) {
 	// GIVEN a URL Command slice
 	tests := map[string]struct {
@@ -36,6 +36,14 @@ var (
 			"id",
 			"result",
 		})
+	// Count of the number of times each latest version has passed/failed its require.verify
+	LatestVersionVerifyMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "latest_version_verify_result_total",
+		Help: "Number of times the checksum/signature verification of a latest version has passed/failed."},
+		[]string{
+			"id",
+			"result",
+		})
 	// Lateest deployed version query successful - 0=no, 1=yes
 	DeployedVersionQueryLiveness = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "deployed_version_query_result_last",
@@ -32,6 +32,9 @@ func TestInitPrometheusCounterVec(t *testing.T) {
 		"LatestVersionQueryMetric": {
 			metric: LatestVersionQueryMetric,
 			args:   []string{"ID", "RESULT"}},
+		"LatestVersionVerifyMetric": {
+			metric: LatestVersionVerifyMetric,
+			args:   []string{"ID", "RESULT"}},
 		"DeployedVersionQueryMetric": {
 			metric: DeployedVersionQueryMetric,
 			args:   []string{"ID", "RESULT"}},
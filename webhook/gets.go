@@ -194,6 +194,10 @@ func (w *WebHook) GetURL() (url string) {
 
 	url = util.TemplateString(
 		url,
-		util.ServiceInfo{LatestVersion: w.ServiceStatus.LatestVersion()})
+		util.ServiceInfo{
+			LatestVersion: w.ServiceStatus.LatestVersion(),
+			DockerDigest:  w.ServiceStatus.DockerDigest(),
+			ReleaseNotes:  w.ServiceStatus.ReleaseNotes(),
+			Changelog:     w.ServiceStatus.Changelog()})
 	return
 }
@@ -45,7 +45,10 @@ func (w *WebHook) setCustomHeaders(req *http.Request) {
 
 	serviceInfo := util.ServiceInfo{
 		ID:            *w.ServiceStatus.ServiceID,
-		LatestVersion: w.ServiceStatus.LatestVersion()}
+		LatestVersion: w.ServiceStatus.LatestVersion(),
+		DockerDigest:  w.ServiceStatus.DockerDigest(),
+		ReleaseNotes:  w.ServiceStatus.ReleaseNotes(),
+		Changelog:     w.ServiceStatus.Changelog()}
 	for _, header := range *customHeaders {
 		key := util.EvalEnvVars(header.Key)
 		value := util.TemplateString(util.EvalEnvVars(header.Value), serviceInfo)